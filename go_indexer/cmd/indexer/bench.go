@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/bench"
+)
+
+// runBench implements "indexer bench": it replays recorded (or synthetic)
+// blocks through the decode-store pipeline and prints throughput, so
+// performance regressions are measurable without an RPC node.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	dir := fs.String("dir", "", "directory of recorded <slot>.json blocks (synthetic load when empty)")
+	blocks := fs.Int("blocks", 200, "synthetic blocks to generate when -dir is empty")
+	txs := fs.Int("txs", 50, "transactions per synthetic block")
+	concurrency := fs.Int("concurrency", 4, "worker concurrency")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var recorded []bench.RecordedBlock
+	if *dir != "" {
+		var err error
+		recorded, err = bench.LoadBlocksDir(*dir)
+		if err != nil {
+			return err
+		}
+	} else {
+		recorded = bench.GenerateBlocks(*blocks, *txs)
+	}
+
+	stats, err := bench.Replay(context.Background(), recorded, bench.Options{Concurrency: *concurrency})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("blocks=%d transactions=%d duration=%s blocks/sec=%.1f\n",
+		stats.Blocks, stats.Transactions, stats.Duration.Round(fmtPrecision), stats.BlocksPerSec())
+	return nil
+}
+
+const fmtPrecision = 1e6 // round durations to milliseconds
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/filter"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+)
+
+// runConfigValidate implements "indexer config validate": it loads the
+// environment configuration and builds everything that otherwise only
+// fails at runtime — profiles with their processor options, filters —
+// reporting every problem rather than stopping at the first. Unlike
+// doctor it touches no network, so it runs in CI.
+func runConfigValidate() error {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("[%s] config: %v\n", checkFail, err)
+		return errors.New("config validate: configuration invalid")
+	}
+
+	var problems []string
+	for _, pc := range cfg.Profiles {
+		if _, err := indexer.NewProfile(pc, nil); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if cfg.TxFilter != "" {
+		if _, err := filter.Compile(cfg.TxFilter); err != nil {
+			problems = append(problems, fmt.Sprintf("TX_FILTER: %v", err))
+		}
+	}
+
+	for _, problem := range problems {
+		fmt.Printf("[%s] %s\n", checkFail, problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("config validate: %d problems", len(problems))
+	}
+	sinks := "all"
+	if len(cfg.Sinks) > 0 {
+		sinks = strings.Join(cfg.Sinks, ", ")
+	}
+	fmt.Printf("[%s] %d profiles, sinks: %s\n", checkOK, len(cfg.Profiles), sinks)
+	return nil
+}
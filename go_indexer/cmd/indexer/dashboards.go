@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// runGenDashboards implements "indexer gen-dashboards": it writes a
+// Grafana dashboard and a Prometheus alerting rule file matched to the
+// metric names /metrics exports (see internal/api/metrics.go), so a
+// deployment gets monitoring without hand-assembling either file.
+func runGenDashboards(args []string) error {
+	fs := flag.NewFlagSet("gen-dashboards", flag.ExitOnError)
+	dir := fs.String("dir", "monitoring", "directory the files are written to")
+	datasource := fs.String("datasource", "Prometheus", "Grafana datasource name the panels query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	dashboard, err := json.MarshalIndent(grafanaDashboard(*datasource), "", "  ")
+	if err != nil {
+		return err
+	}
+	dashboardPath := filepath.Join(*dir, "grafana-indexer.json")
+	if err := os.WriteFile(dashboardPath, append(dashboard, '\n'), 0o644); err != nil {
+		return err
+	}
+	rulesPath := filepath.Join(*dir, "prometheus-rules.yaml")
+	if err := os.WriteFile(rulesPath, []byte(prometheusRules), 0o644); err != nil {
+		return err
+	}
+	fmt.Println(dashboardPath)
+	fmt.Println(rulesPath)
+	return nil
+}
+
+// grafanaDashboard builds the dashboard model: one row of slot health,
+// one of RPC health, one of database pressure, one of freshness.
+func grafanaDashboard(datasource string) map[string]any {
+	panels := []map[string]any{
+		statPanel(1, 0, 0, "Latest indexed slot", "indexer_latest_indexed_slot", datasource),
+		statPanel(2, 6, 0, "Slot lag", "indexer_slot_lag", datasource),
+		graphPanel(3, 12, 0, "Throughput (slots/s)",
+			"sum(rate(indexer_slots_processed_total[5m]))", datasource),
+		graphPanel(4, 0, 8, "RPC error rate",
+			"sum(rate(indexer_rpc_errors_total[5m])) / sum(rate(indexer_rpc_requests_total[5m]))", datasource),
+		graphPanel(5, 12, 8, "RPC latency (ms)",
+			"indexer_rpc_latency_ms", datasource),
+		graphPanel(6, 0, 16, "DB pool connections",
+			"indexer_db_pool_conns", datasource),
+		graphPanel(7, 12, 16, "DB acquire wait (s/s)",
+			"rate(indexer_db_pool_acquire_wait_seconds_total[5m])", datasource),
+		graphPanel(8, 0, 24, "E2E latency (s)",
+			`indexer_e2e_latency_seconds{basis="block_time"}`, datasource),
+		graphPanel(9, 12, 24, "Latency SLO burn rate",
+			"indexer_e2e_latency_slo_burn_rate", datasource),
+	}
+	return map[string]any{
+		"title":         "Solana Indexer",
+		"uid":           "solana-indexer",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+	}
+}
+
+func statPanel(id, x, y int, title, expr, datasource string) map[string]any {
+	p := graphPanel(id, x, y, title, expr, datasource)
+	p["type"] = "stat"
+	p["gridPos"] = map[string]int{"x": x, "y": y, "w": 6, "h": 8}
+	return p
+}
+
+func graphPanel(id, x, y int, title, expr, datasource string) map[string]any {
+	return map[string]any{
+		"id":         id,
+		"type":       "timeseries",
+		"title":      title,
+		"datasource": datasource,
+		"gridPos":    map[string]int{"x": x, "y": y, "w": 12, "h": 8},
+		"targets": []map[string]any{
+			{"expr": expr, "refId": "A"},
+		},
+	}
+}
+
+// prometheusRules alerts on the conditions operators actually page on:
+// the indexer falling behind, stalling outright, the RPC provider
+// degrading, and the database pool saturating.
+const prometheusRules = `groups:
+  - name: solana-indexer
+    rules:
+      - alert: IndexerSlotLagHigh
+        expr: indexer_slot_lag > 100
+        for: 5m
+        labels:
+          severity: warning
+        annotations:
+          summary: Indexer is {{ $value }} slots behind the cluster tip.
+      - alert: IndexerStalled
+        expr: sum(rate(indexer_slots_processed_total[5m])) == 0
+        for: 5m
+        labels:
+          severity: critical
+        annotations:
+          summary: Indexer has processed no slots for 5 minutes.
+      - alert: IndexerRPCErrorRateHigh
+        expr: >
+          sum(rate(indexer_rpc_errors_total[5m]))
+          / sum(rate(indexer_rpc_requests_total[5m])) > 0.05
+        for: 10m
+        labels:
+          severity: warning
+        annotations:
+          summary: More than 5% of RPC calls are failing.
+      - alert: IndexerLatencySLOBurn
+        expr: indexer_e2e_latency_slo_burn_rate > 2
+        for: 15m
+        labels:
+          severity: warning
+        annotations:
+          summary: Indexing freshness is burning its SLO error budget {{ $value }}x too fast.
+      - alert: IndexerDBPoolSaturated
+        expr: indexer_db_pool_conns{state="acquired"} >= indexer_db_pool_max_conns
+        for: 5m
+        labels:
+          severity: warning
+        annotations:
+          summary: Database connection pool is fully acquired.
+`
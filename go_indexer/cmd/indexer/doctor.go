@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/filter"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Outcomes of one doctor check.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+type checkResult struct {
+	name    string
+	outcome string
+	detail  string
+}
+
+// runDoctor implements "indexer doctor": it validates the configuration,
+// probes the RPC and WebSocket endpoints, and checks database
+// reachability, printing a readiness report so misconfiguration is
+// caught before deployment rather than at 3am after it.
+func runDoctor() error {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("[%s] config: %v\n", checkFail, err)
+		return errors.New("doctor: configuration invalid")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	results := []checkResult{checkConfig(cfg)}
+	results = append(results, checkRPC(ctx, cfg)...)
+	results = append(results, checkWS(cfg))
+	results = append(results, checkDatabase(cfg))
+
+	failed := false
+	for _, r := range results {
+		fmt.Printf("[%-4s] %s: %s\n", r.outcome, r.name, r.detail)
+		if r.outcome == checkFail {
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("doctor: readiness checks failed")
+	}
+	fmt.Println("ready")
+	return nil
+}
+
+// checkConfig validates the parts of the configuration that only fail at
+// runtime: profiles, the transaction filter, and rule/schema files.
+func checkConfig(cfg *config.Config) checkResult {
+	if len(cfg.Profiles) == 0 {
+		return checkResult{"config", checkFail, "no indexing profiles configured"}
+	}
+	if cfg.TxFilter != "" {
+		if _, err := filter.Compile(cfg.TxFilter); err != nil {
+			return checkResult{"config", checkFail, fmt.Sprintf("TX_FILTER does not compile: %v", err)}
+		}
+	}
+	return checkResult{"config", checkOK,
+		fmt.Sprintf("%d profiles, workers %d-%d", len(cfg.Profiles), cfg.MinConcurrency, cfg.MaxConcurrency)}
+}
+
+// checkRPC probes the RPC endpoint: getVersion and getGenesisHash verify
+// the node identity, and a short burst of getSlot calls estimates
+// latency and surfaces rate limiting.
+func checkRPC(ctx context.Context, cfg *config.Config) []checkResult {
+	client := solana.NewClient(cfg.RPCURL)
+
+	var version struct {
+		SolanaCore string `json:"solana-core"`
+	}
+	if err := client.Call(ctx, "getVersion", nil, &version); err != nil {
+		return []checkResult{{"rpc", checkFail, fmt.Sprintf("getVersion: %v", err)}}
+	}
+	var genesis string
+	if err := client.Call(ctx, "getGenesisHash", nil, &genesis); err != nil {
+		return []checkResult{{"rpc", checkFail, fmt.Sprintf("getGenesisHash: %v", err)}}
+	}
+	results := []checkResult{{"rpc", checkOK,
+		fmt.Sprintf("solana-core %s, genesis %s", version.SolanaCore, genesis)}}
+
+	// Latency and rate-limit probe: a burst sized to one batch of
+	// workers, the concurrency the indexer will actually run at.
+	burst := cfg.MaxConcurrency
+	if burst < 1 {
+		burst = 1
+	}
+	start := time.Now()
+	for i := 0; i < burst; i++ {
+		if _, err := client.GetSlot(ctx); err != nil {
+			if errors.Is(err, solana.ErrRateLimited) {
+				results = append(results, checkResult{"rpc rate limit", checkWarn,
+					fmt.Sprintf("429 after %d calls; lower MAX_CONCURRENCY or raise the provider plan", i+1)})
+				return results
+			}
+			results = append(results, checkResult{"rpc rate limit", checkFail, fmt.Sprintf("getSlot: %v", err)})
+			return results
+		}
+	}
+	avg := time.Since(start) / time.Duration(burst)
+	outcome := checkOK
+	if avg > 500*time.Millisecond {
+		outcome = checkWarn
+	}
+	results = append(results, checkResult{"rpc rate limit", outcome,
+		fmt.Sprintf("%d calls sustained, avg latency %s", burst, avg.Round(time.Millisecond))})
+	return results
+}
+
+// checkWS verifies the WebSocket port derived from the RPC URL accepts
+// connections (Solana serves PubSub on RPC port + 1 by convention).
+func checkWS(cfg *config.Config) checkResult {
+	u, err := url.Parse(cfg.RPCURL)
+	if err != nil || u.Host == "" {
+		return checkResult{"websocket", checkFail, fmt.Sprintf("cannot derive WS address from SOLANA_RPC_URL: %v", err)}
+	}
+	host := u.Hostname()
+	port := u.Port()
+	addr := net.JoinHostPort(host, "443")
+	if port != "" {
+		p := 0
+		fmt.Sscanf(port, "%d", &p)
+		addr = net.JoinHostPort(host, fmt.Sprintf("%d", p+1))
+	}
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return checkResult{"websocket", checkWarn, fmt.Sprintf("%s unreachable: %v (subscriptions unavailable)", addr, err)}
+	}
+	conn.Close()
+	return checkResult{"websocket", checkOK, addr + " accepts connections"}
+}
+
+// checkDatabase reports the storage backend and, for a configured
+// database, verifies the host is reachable.
+func checkDatabase(cfg *config.Config) checkResult {
+	if cfg.DatabaseURL == "" {
+		return checkResult{"database", checkOK, "in-memory store (no database configured)"}
+	}
+	u, err := url.Parse(cfg.DatabaseURL)
+	if err != nil || u.Host == "" {
+		return checkResult{"database", checkFail, fmt.Sprintf("DATABASE_URL does not parse: %v", err)}
+	}
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "5432"
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), 5*time.Second)
+	if err != nil {
+		return checkResult{"database", checkFail, fmt.Sprintf("%s unreachable: %v", u.Host, err)}
+	}
+	conn.Close()
+	return checkResult{"database", checkOK, u.Host + " accepts connections"}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/backfill"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/jobs"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// registerJobRunners wires the built-in job kinds into the manager.
+// price resolves mints to USD values for the tax export and may be nil.
+func registerJobRunners(m *jobs.Manager, idx *indexer.Indexer, store storage.Store, price analytics.PriceFunc, log *slog.Logger) {
+	m.Register("backfill", backfillRunner(idx))
+	m.Register("parallel_backfill", parallelBackfillRunner(idx, store, log))
+	m.Register("export_transfers", exportTransfersRunner(store))
+	m.Register("export_graph", exportGraphRunner(store))
+	m.Register("export_tax_report", exportTaxReportRunner(store, price))
+}
+
+// backfillRunner re-indexes a slot range: params from_slot and to_slot.
+func backfillRunner(idx *indexer.Indexer) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Context) error {
+		from := paramUint(job.Params, "from_slot")
+		to := paramUint(job.Params, "to_slot")
+		if to == 0 || to < from {
+			return fmt.Errorf("backfill: from_slot and to_slot required with to_slot >= from_slot")
+		}
+		for slot := from; slot <= to; slot++ {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := idx.ProcessSlot(ctx, slot); err != nil {
+				return fmt.Errorf("slot %d: %w", slot, err)
+			}
+			job.SetProgress(float64(slot-from+1) / float64(to-from+1) * 100)
+		}
+		return nil
+	}
+}
+
+// parallelBackfillRunner backfills a multi-million-slot range as leased
+// chunks persisted in backfill_ranges (params from_slot, to_slot,
+// workers, chunk_slots); resubmitting the same range after a crash
+// resumes the persisted chunks. The extrapolated ETA is published on the
+// job record as eta_seconds.
+func parallelBackfillRunner(idx *indexer.Indexer, store storage.Store, log *slog.Logger) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Context) error {
+		from := paramUint(job.Params, "from_slot")
+		to := paramUint(job.Params, "to_slot")
+		if to == 0 || to < from {
+			return fmt.Errorf("parallel_backfill: from_slot and to_slot required with to_slot >= from_slot")
+		}
+		workers := int(paramUint(job.Params, "workers"))
+		if workers == 0 {
+			workers = 4
+		}
+		coord := backfill.New(store, idx.ProcessSlot, log)
+		return coord.Run(ctx, from, to, paramUint(job.Params, "chunk_slots"), workers,
+			func(pct float64, eta time.Duration) {
+				job.SetProgress(pct)
+				job.SetResult("eta_seconds", int(eta.Seconds()))
+			})
+	}
+}
+
+// exportPageSize is how many transfer rows one export page reads.
+const exportPageSize = 10_000
+
+// exportTransfersRunner pages transfers matching the filter params
+// (address, mint, from_slot, to_slot) into a CSV file under the
+// temporary directory, named after the job ID range.
+func exportTransfersRunner(store storage.Store) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Context) error {
+		path := filepath.Join(os.TempDir(),
+			fmt.Sprintf("transfers-export-%d.csv", time.Now().UnixNano()))
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		cw := csv.NewWriter(f)
+		if err := cw.Write([]string{"signature", "slot", "block_time", "source", "destination", "mint", "amount", "decimals"}); err != nil {
+			return err
+		}
+		filter := storage.TransferFilter{
+			Address:  paramString(job.Params, "address"),
+			Mint:     paramString(job.Params, "mint"),
+			FromSlot: paramUint(job.Params, "from_slot"),
+			ToSlot:   paramUint(job.Params, "to_slot"),
+			Limit:    exportPageSize,
+		}
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			page, err := store.ListTransfers(ctx, filter)
+			if err != nil {
+				return err
+			}
+			for _, t := range page {
+				if err := cw.Write([]string{
+					t.Signature,
+					strconv.FormatUint(t.Slot, 10),
+					t.BlockTime.Format(time.RFC3339),
+					t.Source,
+					t.Destination,
+					t.Mint,
+					strconv.FormatUint(t.Amount, 10),
+					strconv.Itoa(int(t.Decimals)),
+				}); err != nil {
+					return err
+				}
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+			filter.Offset += exportPageSize
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		job.SetResult("file", path)
+		return nil
+	}
+}
+
+// graphEdge aggregates all transfers between one ordered address pair.
+type graphEdge struct {
+	source      string
+	destination string
+	volume      uint64
+	transfers   uint64
+}
+
+// exportGraphRunner builds the transfer graph over a slot window
+// (params mint, from_slot, to_slot) — nodes are addresses, edges carry
+// aggregated volume — and writes it as a CSV edge list or GraphML
+// (param format, default csv) for network analysis tooling.
+func exportGraphRunner(store storage.Store) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Context) error {
+		format := paramString(job.Params, "format")
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "graphml" {
+			return fmt.Errorf("export_graph: format must be csv or graphml")
+		}
+		filter := storage.TransferFilter{
+			Mint:     paramString(job.Params, "mint"),
+			FromSlot: paramUint(job.Params, "from_slot"),
+			ToSlot:   paramUint(job.Params, "to_slot"),
+			Limit:    exportPageSize,
+		}
+		edges := make(map[[2]string]*graphEdge)
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			page, err := store.ListTransfers(ctx, filter)
+			if err != nil {
+				return err
+			}
+			for _, t := range page {
+				if t.Source == "" || t.Destination == "" {
+					continue
+				}
+				key := [2]string{t.Source, t.Destination}
+				edge, ok := edges[key]
+				if !ok {
+					edge = &graphEdge{source: t.Source, destination: t.Destination}
+					edges[key] = edge
+				}
+				edge.volume += t.Amount
+				edge.transfers++
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+			filter.Offset += exportPageSize
+		}
+
+		path := filepath.Join(os.TempDir(),
+			fmt.Sprintf("transfer-graph-%d.%s", time.Now().UnixNano(), graphExt(format)))
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		nodes, err := writeGraph(f, format, edges)
+		if err != nil {
+			return err
+		}
+		job.SetResult("file", path)
+		job.SetResult("nodes", nodes)
+		job.SetResult("edges", len(edges))
+		return nil
+	}
+}
+
+func graphExt(format string) string {
+	if format == "graphml" {
+		return "graphml"
+	}
+	return "csv"
+}
+
+// writeGraph serializes the edge map and returns the node count.
+func writeGraph(f *os.File, format string, edges map[[2]string]*graphEdge) (int, error) {
+	sorted := make([]*graphEdge, 0, len(edges))
+	nodes := make(map[string]struct{})
+	for _, edge := range edges {
+		sorted = append(sorted, edge)
+		nodes[edge.source] = struct{}{}
+		nodes[edge.destination] = struct{}{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].volume > sorted[j].volume })
+
+	if format == "csv" {
+		cw := csv.NewWriter(f)
+		if err := cw.Write([]string{"source", "destination", "volume", "transfers"}); err != nil {
+			return 0, err
+		}
+		for _, edge := range sorted {
+			if err := cw.Write([]string{
+				edge.source,
+				edge.destination,
+				strconv.FormatUint(edge.volume, 10),
+				strconv.FormatUint(edge.transfers, 10),
+			}); err != nil {
+				return 0, err
+			}
+		}
+		cw.Flush()
+		return len(nodes), cw.Error()
+	}
+
+	type graphmlKey struct {
+		XMLName  xml.Name `xml:"key"`
+		ID       string   `xml:"id,attr"`
+		For      string   `xml:"for,attr"`
+		AttrName string   `xml:"attr.name,attr"`
+		AttrType string   `xml:"attr.type,attr"`
+	}
+	type graphmlData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type graphmlNode struct {
+		ID string `xml:"id,attr"`
+	}
+	type graphmlEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphmlData `xml:"data"`
+	}
+	type graphmlGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphmlNode `xml:"node"`
+		Edges       []graphmlEdge `xml:"edge"`
+	}
+	type graphml struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphmlKey `xml:"key"`
+		Graph   graphmlGraph `xml:"graph"`
+	}
+	doc := graphml{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphmlKey{
+			{ID: "volume", For: "edge", AttrName: "volume", AttrType: "long"},
+			{ID: "transfers", For: "edge", AttrName: "transfers", AttrType: "long"},
+		},
+		Graph: graphmlGraph{ID: "transfers", EdgeDefault: "directed"},
+	}
+	for node := range nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphmlNode{ID: node})
+	}
+	sort.Slice(doc.Graph.Nodes, func(i, j int) bool {
+		return doc.Graph.Nodes[i].ID < doc.Graph.Nodes[j].ID
+	})
+	for _, edge := range sorted {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphmlEdge{
+			Source: edge.source,
+			Target: edge.destination,
+			Data: []graphmlData{
+				{Key: "volume", Value: strconv.FormatUint(edge.volume, 10)},
+				{Key: "transfers", Value: strconv.FormatUint(edge.transfers, 10)},
+			},
+		})
+	}
+	if _, err := f.WriteString(xml.Header); err != nil {
+		return 0, err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return 0, err
+	}
+	return len(nodes), nil
+}
+
+// wrappedSOLMint prices native SOL movements through pools quoted on
+// wrapped SOL.
+const wrappedSOLMint = "So11111111111111111111111111111111111111112"
+
+// Tax export formats.
+const (
+	taxFormatKoinly      = "koinly"
+	taxFormatCoinTracker = "cointracker"
+)
+
+// exportTaxReportRunner writes a wallet's transfer activity (param
+// address, optional from_time/to_time as RFC 3339, format koinly or
+// cointracker) as a tax-style CSV under the temporary directory.
+// Each transfer becomes one sent or received row with its USD value at
+// the current pool price; network fees are paid by the fee payer, not
+// carried on transfer rows, so fee columns stay empty.
+func exportTaxReportRunner(store storage.Store, price analytics.PriceFunc) jobs.Runner {
+	return func(ctx context.Context, job *jobs.Context) error {
+		address := paramString(job.Params, "address")
+		if address == "" {
+			return fmt.Errorf("export_tax_report: address required")
+		}
+		format := paramString(job.Params, "format")
+		if format == "" {
+			format = taxFormatKoinly
+		}
+		if format != taxFormatKoinly && format != taxFormatCoinTracker {
+			return fmt.Errorf("export_tax_report: format must be koinly or cointracker")
+		}
+		var from, to time.Time
+		for _, key := range []struct {
+			name string
+			dst  *time.Time
+		}{{"from_time", &from}, {"to_time", &to}} {
+			if raw := paramString(job.Params, key.name); raw != "" {
+				t, err := time.Parse(time.RFC3339, raw)
+				if err != nil {
+					return fmt.Errorf("export_tax_report: %s must be RFC 3339", key.name)
+				}
+				*key.dst = t
+			}
+		}
+
+		path := filepath.Join(os.TempDir(),
+			fmt.Sprintf("tax-report-%s-%d.csv", format, time.Now().UnixNano()))
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		cw := csv.NewWriter(f)
+		if err := cw.Write(taxHeader(format)); err != nil {
+			return err
+		}
+
+		filter := storage.TransferFilter{Address: address, Limit: exportPageSize}
+		rows := 0
+		for {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			page, err := store.ListTransfers(ctx, filter)
+			if err != nil {
+				return err
+			}
+			for _, t := range page {
+				if !from.IsZero() && t.BlockTime.Before(from) {
+					continue
+				}
+				if !to.IsZero() && t.BlockTime.After(to) {
+					continue
+				}
+				if err := cw.Write(taxRow(format, address, t, price)); err != nil {
+					return err
+				}
+				rows++
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+			filter.Offset += exportPageSize
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		job.SetResult("file", path)
+		job.SetResult("rows", rows)
+		return nil
+	}
+}
+
+// taxHeader returns the format's column row.
+func taxHeader(format string) []string {
+	if format == taxFormatCoinTracker {
+		return []string{"Date", "Received Quantity", "Received Currency",
+			"Sent Quantity", "Sent Currency", "Fee Amount", "Fee Currency", "Tag"}
+	}
+	return []string{"Date", "Sent Amount", "Sent Currency", "Received Amount",
+		"Received Currency", "Fee Amount", "Fee Currency", "Net Worth Amount",
+		"Net Worth Currency", "Label", "Description", "TxHash"}
+}
+
+// taxRow renders one transfer from the wallet's perspective.
+func taxRow(format, address string, t models.Transfer, price analytics.PriceFunc) []string {
+	asset := t.Mint
+	priceMint := t.Mint
+	if asset == "" {
+		asset = "SOL"
+		priceMint = wrappedSOLMint
+	}
+	amount := strconv.FormatFloat(
+		float64(t.Amount)/math.Pow10(int(t.Decimals)), 'f', -1, 64)
+	var usd string
+	if price != nil {
+		if p, ok := price(priceMint); ok {
+			usd = strconv.FormatFloat(
+				p*float64(t.Amount)/math.Pow10(int(t.Decimals)), 'f', -1, 64)
+		}
+	}
+	sent := t.Source == address
+	counterparty := t.Source
+	if sent {
+		counterparty = t.Destination
+	}
+	date := t.BlockTime.UTC().Format("2006-01-02 15:04:05")
+
+	if format == taxFormatCoinTracker {
+		row := []string{date, "", "", "", "", "", "", ""}
+		if sent {
+			row[3], row[4] = amount, asset
+		} else {
+			row[1], row[2] = amount, asset
+		}
+		return row
+	}
+	row := []string{date, "", "", "", "", "", "", "", "", "", "", t.Signature}
+	if sent {
+		row[1], row[2] = amount, asset
+	} else {
+		row[3], row[4] = amount, asset
+	}
+	if usd != "" {
+		row[7], row[8] = usd, "USD"
+	}
+	row[10] = "counterparty: " + counterparty
+	return row
+}
+
+// paramString reads an optional string job parameter.
+func paramString(params map[string]any, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
+
+// paramUint reads a numeric job parameter; JSON numbers arrive as
+// float64.
+func paramUint(params map[string]any, key string) uint64 {
+	switch v := params[key].(type) {
+	case float64:
+		if v < 0 {
+			return 0
+		}
+		return uint64(v)
+	case string:
+		n, _ := strconv.ParseUint(v, 10, 64)
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,810 @@
+// Command indexer runs the Solana starter indexer: it polls the configured
+// RPC endpoint for new blocks, runs them through the configured indexing
+// profiles, and serves the REST API.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountindex"
+	"github.com/lugondev/go-indexer-solana-starter/internal/aggregate"
+	"github.com/lugondev/go-indexer-solana-starter/internal/alerts"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/api"
+	"github.com/lugondev/go-indexer-solana-starter/internal/attest"
+	"github.com/lugondev/go-indexer-solana-starter/internal/bootstrap"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/coverage"
+	"github.com/lugondev/go-indexer-solana-starter/internal/enrich"
+	"github.com/lugondev/go-indexer-solana-starter/internal/entity"
+	"github.com/lugondev/go-indexer-solana-starter/internal/fetch"
+	"github.com/lugondev/go-indexer-solana-starter/internal/filter"
+	"github.com/lugondev/go-indexer-solana-starter/internal/firehose"
+	"github.com/lugondev/go-indexer-solana-starter/internal/gateway"
+	"github.com/lugondev/go-indexer-solana-starter/internal/grpcapi"
+	"github.com/lugondev/go-indexer-solana-starter/internal/health"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/jobs"
+	"github.com/lugondev/go-indexer-solana-starter/internal/latency"
+	"github.com/lugondev/go-indexer-solana-starter/internal/mapping"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/notify"
+	"github.com/lugondev/go-indexer-solana-starter/internal/outbox"
+	"github.com/lugondev/go-indexer-solana-starter/internal/plugin"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/redact"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repair"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sched"
+	"github.com/lugondev/go-indexer-solana-starter/internal/schema"
+	"github.com/lugondev/go-indexer-solana-starter/internal/screening"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sns"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage/postgres"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watcher"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		if err := runBench(os.Args[2:]); err != nil {
+			slog.Error("bench failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		if err := runDoctor(); err != nil {
+			slog.Error("doctor failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "tui" {
+		if err := runTUI(os.Args[2:]); err != nil {
+			slog.Error("tui failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if len(os.Args) < 3 || os.Args[2] != "validate" {
+			slog.Error("usage: indexer config validate")
+			os.Exit(2)
+		}
+		if err := runConfigValidate(); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "gen-dashboards" {
+		if err := runGenDashboards(os.Args[2:]); err != nil {
+			slog.Error("gen-dashboards failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := runVerify(os.Args[2:]); err != nil {
+			slog.Error("verify failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := run(); err != nil && !errors.Is(err, context.Canceled) {
+		slog.Error("indexer exited", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	log := newLogger(cfg.LogLevel)
+	slog.SetDefault(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var store storage.Store = storage.NewMemoryStore()
+	var dbPool func() postgres.PoolStats
+	var partitions []*postgres.PartitionManager
+	var finishBulk func(context.Context) error
+	partitionManager := func(s storage.Store) {
+		if db, ok := s.(postgres.Execer); ok {
+			partitions = append(partitions, postgres.NewPartitionManager(db, postgres.PartitionOptions{
+				SlotsPerPartition: cfg.PartitionSlots,
+				PreCreate:         cfg.PartitionPreCreate,
+				RetainPartitions:  cfg.PartitionRetain,
+			}, log))
+		}
+	}
+	if cfg.DatabaseURL != "" {
+		opts := postgresOptions(cfg, cfg.DatabaseURL)
+		opts.BulkBackfill = cfg.BackfillEndSlot > 0
+		pg, err := postgres.Open(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if opts.BulkBackfill {
+			if bulk, ok := pg.(interface{ FinishBackfill(context.Context) error }); ok {
+				finishBulk = bulk.FinishBackfill
+				log.Info("bulk backfill mode enabled: secondary indexes are deferred until the backfill completes")
+			}
+		}
+		store = pg
+		if stats, ok := pg.(interface{ PoolStats() postgres.PoolStats }); ok {
+			dbPool = stats.PoolStats
+		}
+		partitionManager(pg)
+		log.Info("postgres storage enabled", "max_conns", cfg.DBMaxConns)
+		if cfg.DatabaseURLRead != "" {
+			readOpts := postgresOptions(cfg, cfg.DatabaseURLRead)
+			readOpts.ReadOnly = true
+			replica, err := postgres.Open(ctx, readOpts)
+			if err != nil {
+				return err
+			}
+			router := storage.NewReplicaRouter(pg, replica, cfg.ReplicaMaxLagSlots, log)
+			go router.Run(ctx)
+			store = router
+			log.Info("read replica routing enabled", "max_lag_slots", cfg.ReplicaMaxLagSlots)
+		}
+	}
+	if len(cfg.DatabaseURLGroups) > 0 {
+		groups := make(map[string]storage.Store, len(cfg.DatabaseURLGroups))
+		for name, url := range cfg.DatabaseURLGroups {
+			group, err := postgres.Open(ctx, postgresOptions(cfg, url))
+			if err != nil {
+				return err
+			}
+			groups[name] = group
+			partitionManager(group)
+		}
+		sharded, err := storage.NewShardedStore(store, groups)
+		if err != nil {
+			return err
+		}
+		store = sharded
+		log.Info("table group sharding enabled", "groups", len(groups))
+	}
+	defer store.Close(context.Background())
+	if cfg.CacheSlots > 0 {
+		store = storage.NewCachedStore(store, cfg.CacheSlots, cfg.CacheMaxMB<<20)
+		log.Info("columnar block cache enabled", "slots", cfg.CacheSlots, "max_mb", cfg.CacheMaxMB)
+	}
+	var tiered *storage.TieredStore
+	if cfg.ColdDir != "" {
+		var err error
+		if tiered, err = storage.NewTieredStore(store, cfg.ColdDir, cfg.ColdRetainSlots, log); err != nil {
+			return err
+		}
+		store = tiered
+		log.Info("cold storage tiering enabled", "dir", cfg.ColdDir, "retain_slots", cfg.ColdRetainSlots)
+	}
+
+	if cfg.PluginsDir != "" {
+		runtime, err := plugin.LoadDir(ctx, cfg.PluginsDir)
+		if err != nil {
+			return err
+		}
+		defer runtime.Close(context.Background())
+		plugin.SetDefault(runtime)
+		log.Info("wasm decoder plugins loaded", "programs", runtime.Programs())
+	}
+
+	client := solana.NewClient(cfg.RPCURL)
+	budget := solana.NewBudget(solana.DefaultCostTable(cfg.RPCProvider), cfg.RPCMonthlyCredits)
+	client.SetBudget(budget)
+	if cfg.ArchivalRPCURL != "" {
+		client.SetArchival(cfg.ArchivalRPCURL)
+		log.Info("archival rpc failover enabled", "url", cfg.ArchivalRPCURL)
+	}
+	var scorer *solana.Scorer
+	if urls := rpcEndpoints(cfg); len(urls) > 1 {
+		scorer = solana.NewScorer(urls)
+		client.SetScorer(scorer)
+		go scorer.Run(ctx)
+		log.Info("rpc endpoint scoring enabled", "endpoints", len(urls))
+	}
+	if cfg.RedactRules != "" {
+		red, err := redact.Parse(cfg.RedactRules, cfg.RedactSalt)
+		if err != nil {
+			return err
+		}
+		redact.SetDefault(red)
+		log.Info("redaction enabled", "rules", red.Audit())
+	}
+
+	// Firehose mode is a different program: raw blocks to a sink, no
+	// decoding, no storage, no API.
+	if cfg.FirehoseDir != "" || cfg.FirehoseURL != "" {
+		var sink firehose.Sink
+		if cfg.FirehoseDir != "" {
+			if sink, err = firehose.NewDirSink(cfg.FirehoseDir); err != nil {
+				return err
+			}
+			log.Info("firehose mode enabled", "sink", "dir", "dir", cfg.FirehoseDir)
+		} else {
+			sink = firehose.NewHTTPSink(cfg.FirehoseURL)
+			log.Info("firehose mode enabled", "sink", "http", "url", cfg.FirehoseURL)
+		}
+		return firehose.New(cfg, client, sink, log).Run(ctx)
+	}
+
+	// The vendor adapter must be registered before the profiles are
+	// built: processors capture it at construction time.
+	if provider := solana.NewProvider(cfg.RPCProvider, client); provider != nil {
+		client.SetProvider(provider)
+		solana.SetDefaultProvider(provider)
+		log.Info("rpc provider extensions enabled", "provider", provider.Name())
+	}
+
+	var profiles []*indexer.Profile
+	for _, pc := range cfg.Profiles {
+		profile, err := indexer.NewProfile(pc, store)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, profile)
+	}
+	source, err := solana.NewChainSource(cfg.Chain, client)
+	if err != nil {
+		return err
+	}
+	if cfg.Chain != "solana" {
+		log.Info("chain adapter enabled", "chain", cfg.Chain)
+	}
+	idx := indexer.New(cfg, source, store, profiles, log)
+	if finishBulk != nil {
+		idx.SetBackfillDone(func() {
+			if err := finishBulk(context.Background()); err != nil {
+				log.Error("bulk backfill finish failed", "error", err)
+				return
+			}
+			log.Info("bulk backfill flushed, secondary indexes built")
+		})
+	}
+	if len(partitions) > 0 {
+		current := func() []uint64 {
+			live, backfill := idx.Progress()
+			return []uint64{live.CurrentSlot, backfill.CurrentSlot}
+		}
+		for _, manager := range partitions {
+			// Pre-create the partitions for the configured start slots so
+			// the first batches do not race the maintenance loop's first
+			// tick.
+			for _, slot := range []uint64{cfg.StartSlot, cfg.BackfillStartSlot} {
+				if slot > 0 {
+					if err := manager.EnsureFor(ctx, slot); err != nil {
+						return err
+					}
+				}
+			}
+			go manager.Run(ctx, current)
+		}
+		log.Info("partition maintenance enabled", "databases", len(partitions),
+			"slots_per_partition", cfg.PartitionSlots,
+			"pre_create", cfg.PartitionPreCreate, "retain", cfg.PartitionRetain)
+	}
+	if cfg.SignaturesOnly {
+		log.Info("signatures-only mode enabled: instruction payloads are not stored")
+	}
+	if cfg.TxFilter != "" {
+		expr, err := filter.Compile(cfg.TxFilter)
+		if err != nil {
+			return fmt.Errorf("TX_FILTER: %w", err)
+		}
+		idx.SetTransactionFilter(expr)
+	}
+	if !cfg.StartTime.IsZero() && cfg.StartSlot == 0 && !cfg.StartAtLatest {
+		slot, err := client.FindSlotByTime(ctx, cfg.StartTime)
+		if err != nil {
+			return fmt.Errorf("resolve START_TIME: %w", err)
+		}
+		cfg.StartSlot = slot
+		log.Info("start time resolved", "time", cfg.StartTime.Format(time.RFC3339), "slot", slot)
+	}
+	if cfg.BootstrapProgram != "" {
+		baseline, err := bootstrap.New(client, store, log).Run(ctx, cfg.BootstrapProgram)
+		if err != nil {
+			return err
+		}
+		// Indexing resumes right after the snapshot unless the operator
+		// pinned an explicit start slot.
+		if cfg.StartSlot == 0 && !cfg.StartAtLatest {
+			cfg.StartSlot = baseline + 1
+		}
+	}
+
+	server := api.NewServer(store)
+	if cfg.Namespace != "" {
+		server.SetNamespace(cfg.Namespace)
+	}
+	server.SetBudget(budget)
+	if dbPool != nil {
+		server.SetPoolStats(dbPool)
+	}
+	attestor := attest.New(store)
+	server.SetAttestor(attestor)
+	server.SetProgress(idx.Progress)
+	if cfg.ReplayBackward {
+		server.SetReplayProgress(idx.ReplayProgress)
+	}
+	if scorer != nil {
+		server.SetRPCHealth(scorer)
+	}
+	if cfg.AccessLog != "" {
+		out, err := accessLogWriter(cfg.AccessLog)
+		if err != nil {
+			return err
+		}
+		server.SetAccessLog(out)
+	}
+	if len(cfg.APIKeys) > 0 || cfg.OIDCIssuer != "" {
+		auth := api.NewAuthorizer(cfg.APIKeys, log)
+		if cfg.OIDCIssuer != "" {
+			auth.SetOIDC(api.NewOIDCVerifier(cfg.OIDCIssuer, cfg.OIDCAudience,
+				cfg.OIDCRoleClaim, api.Role(cfg.OIDCDefaultRole)))
+		}
+		server.SetAuth(auth)
+		log.Info("api authentication enabled", "keys", len(cfg.APIKeys), "oidc", cfg.OIDCIssuer != "")
+	}
+
+	router, err := buildNotifyRouter(cfg, log)
+	if err != nil {
+		return err
+	}
+	// Producers append to the transactional outbox; the dispatcher fans
+	// delivered events out to the SSE stream and notifier channels, each
+	// behind its configured commitment policy.
+	dispatcher := outbox.New(store, log)
+	if cfg.SinkEnabled("stream") {
+		dispatcher.AddSink("stream", outbox.SinkPolicy{
+			MinCommitment:  cfg.StreamMinCommitment,
+			ReemitUpgrades: cfg.StreamReemitUpgrades,
+		}, server.PublishEvent)
+	}
+	if cfg.SinkEnabled("notify") {
+		dispatcher.AddSink("notify", outbox.SinkPolicy{
+			MinCommitment:  cfg.NotifyMinCommitment,
+			ReemitUpgrades: cfg.NotifyReemitUpgrades,
+		}, router.HandleEvent)
+	}
+	if cfg.GRPCPort > 0 && cfg.SinkEnabled("grpc") {
+		grpcServer := grpcapi.New(store, log)
+		grpcServer.SetCodec(cfg.GRPCCodec)
+		dispatcher.AddSink("grpc", outbox.SinkPolicy{
+			MinCommitment:  cfg.StreamMinCommitment,
+			ReemitUpgrades: cfg.StreamReemitUpgrades,
+		}, grpcServer.Publish)
+		go func() {
+			if err := grpcServer.Serve(ctx, cfg.GRPCPort); err != nil && !errors.Is(err, context.Canceled) {
+				log.Error("grpc server failed", "error", err)
+			}
+		}()
+	}
+	go dispatcher.Run(ctx)
+	publish := dispatcher.Enqueue
+	if cfg.SchemaRegistryURL != "" {
+		// Registration is not fatal: the events keep flowing and a
+		// restart retries.
+		go func() {
+			registrar := schema.NewRegistrar(cfg.SchemaRegistryURL, log)
+			if cfg.Namespace != "" {
+				registrar.SetNamespace(cfg.Namespace)
+			}
+			if err := registrar.RegisterAll(ctx); err != nil {
+				log.Error("schema registry registration failed", "error", err)
+			}
+		}()
+	}
+	if cfg.TablesFile != "" {
+		tables, err := mapping.LoadSchema(cfg.TablesFile)
+		if err != nil {
+			return err
+		}
+		mapper, err := mapping.New(ctx, store, tables, log)
+		if err != nil {
+			return err
+		}
+		log.Info("custom table mapping enabled", "tables", mapper.Tables())
+		enqueue := publish
+		publish = func(ev models.Event) {
+			mapper.HandleEvent(ev)
+			enqueue(ev)
+		}
+	}
+	idx.SetEventPublisher(publish)
+
+	alertEngine := alerts.NewEngine(publish, log)
+	alertEngine.SetRouter(router)
+	if cfg.AlertRulesFile != "" {
+		if err := loadAlertRules(alertEngine, cfg.AlertRulesFile); err != nil {
+			return err
+		}
+	}
+	for _, mint := range cfg.WatchMints {
+		if err := alertEngine.SetRule(alerts.Rule{
+			ID:                 "mint_authority:" + mint,
+			Name:               "authority change on " + mint,
+			WatchMintAuthority: mint,
+		}); err != nil {
+			return err
+		}
+	}
+	server.SetAlertEngine(alertEngine)
+
+	mintAuth := analytics.NewMintAuthorities()
+	server.SetMintAuthorities(mintAuth)
+	lendingStats := analytics.NewLending()
+	server.SetLendingAnalytics(lendingStats)
+	names := sns.NewRegistry(store, log)
+	if err := names.Load(ctx); err != nil {
+		log.Warn("sns registry load failed", "error", err)
+	}
+	server.SetNameRegistry(names)
+	multisigs := analytics.NewMultisigs()
+	server.SetMultisigs(multisigs)
+	governance := analytics.NewGovernance()
+	server.SetGovernance(governance)
+	streams := analytics.NewStreams()
+	server.SetStreams(streams)
+	entities := entity.NewResolver(store, log)
+	if err := entities.Load(ctx); err != nil {
+		log.Warn("entity link load failed", "error", err)
+	}
+	server.SetEntityResolver(entities)
+	candles := analytics.NewCandles()
+	server.SetCandles(candles)
+	var poolStats *analytics.Pools
+	if cfg.PoolsFile != "" {
+		poolConfigs, err := analytics.LoadPoolConfigs(cfg.PoolsFile)
+		if err != nil {
+			return err
+		}
+		poolStats = analytics.NewPools(poolConfigs)
+		server.SetPools(poolStats)
+		log.Info("pool tracking enabled", "pools", len(poolConfigs))
+	}
+	var poolPrice analytics.PriceFunc
+	if poolStats != nil {
+		poolPrice = poolStats.LatestPrice
+	}
+	if poolPrice != nil || cfg.FetchMetadata {
+		enricher := enrich.NewManager(store, publish, log)
+		var names []string
+		if poolPrice != nil {
+			enricher.Register(enrich.NewPriceEnricher(poolPrice))
+			names = append(names, "price")
+		}
+		if cfg.FetchMetadata {
+			fetcher := fetch.New(store, log)
+			fetcher.SetAllowList(cfg.FetchAllowDomains)
+			fetcher.SetDenyList(cfg.FetchDenyDomains)
+			if len(cfg.IPFSGateways) > 0 || len(cfg.ArweaveGateways) > 0 {
+				resolver := gateway.NewResolver()
+				if len(cfg.IPFSGateways) > 0 {
+					resolver.SetGateways("ipfs", cfg.IPFSGateways)
+				}
+				if len(cfg.ArweaveGateways) > 0 {
+					resolver.SetGateways("ar", cfg.ArweaveGateways)
+				}
+				fetcher.SetResolver(resolver)
+			}
+			if err := fetcher.Load(ctx); err != nil {
+				log.Warn("fetch cache load failed", "error", err)
+			}
+			enricher.Register(enrich.NewMetadataEnricher(fetcher))
+			names = append(names, "metadata")
+		}
+		for _, profile := range profiles {
+			profile.SetEnricher(enricher)
+		}
+		log.Info("async enrichment enabled", "enrichers", names)
+	}
+	var pnl *analytics.PnL
+	if len(cfg.PnLWallets) > 0 {
+		pnl = analytics.NewPnL(cfg.PnLWallets, poolPrice)
+		server.SetPnL(pnl)
+		log.Info("pnl tracking enabled", "wallets", len(cfg.PnLWallets))
+	}
+	programStats := analytics.NewProgramAggregator()
+	server.SetProgramAnalytics(programStats)
+	rollups := analytics.NewRollups()
+	server.SetChainAnalytics(rollups)
+	activeAddrs := analytics.NewActiveAddresses()
+	server.SetActiveAddressAnalytics(activeAddrs)
+	var validatorStats *analytics.Validators
+	if cfg.IndexVotes {
+		validatorStats = analytics.NewValidators()
+		server.SetValidatorAnalytics(validatorStats)
+		log.Info("validator vote analytics enabled")
+	}
+	var acctIndex *accountindex.Index
+	if cfg.AccountIndexSlots > 0 {
+		acctIndex = accountindex.New(cfg.AccountIndexSlots)
+		server.SetAccountIndex(acctIndex)
+		log.Info("account index enabled", "retain_slots", cfg.AccountIndexSlots)
+	}
+	var screeningEngine *screening.Engine
+	if cfg.ScreeningDenylist != "" {
+		denylist, err := screening.NewDenylist(cfg.ScreeningDenylist)
+		if err != nil {
+			return err
+		}
+		go denylist.Run(ctx, log)
+		screeningEngine = screening.NewEngine(store, publish, log)
+		screeningEngine.Add(denylist)
+		log.Info("compliance screening enabled", "addresses", denylist.Len())
+	}
+	idx.SetTransactionInspector(func(tc *processor.TxContext) {
+		alertEngine.Inspect(tc)
+		mintAuth.Observe(tc)
+		lendingStats.Observe(tc)
+		candles.Observe(tc)
+		names.Observe(tc)
+		multisigs.Observe(tc)
+		governance.Observe(tc)
+		streams.Observe(tc)
+		entities.Observe(tc)
+		programStats.Observe(tc)
+		rollups.Observe(tc)
+		activeAddrs.Observe(tc)
+		if validatorStats != nil {
+			validatorStats.Observe(tc)
+		}
+		if poolStats != nil {
+			poolStats.Observe(tc)
+		}
+		if pnl != nil {
+			pnl.Observe(tc)
+		}
+		if acctIndex != nil {
+			acctIndex.Observe(tc)
+		}
+		if screeningEngine != nil {
+			screeningEngine.Inspect(tc)
+		}
+	})
+	idx.SetOutputObserver(rollups.ObserveOutput)
+	go rollups.Backfill(ctx, store, log)
+	go activeAddrs.Run(ctx, store, log)
+
+	monitor := health.NewMonitor(health.Options{
+		CheckInterval:    cfg.HealthCheckInterval,
+		StallAfter:       cfg.HealthStallAfter,
+		MaxErrorsPerMin:  cfg.HealthMaxErrorsPerMin,
+		HaltAfter:        cfg.HealthHaltAfter,
+		LatencyBurnAlert: cfg.LatencySLOBurnAlert,
+	}, idx.Progress, storePing(store), publish, buildHealthDispatchers(cfg), log)
+	idx.SetErrorRecorder(monitor.RecordError)
+	if scorer != nil {
+		monitor.SetTipStatus(scorer.TipStatus)
+		idx.SetHaltCheck(monitor.Halted)
+	}
+	freshness := latency.NewTracker(cfg.LatencySLOTarget, cfg.LatencySLOObjective)
+	idx.SetLatencyObserver(freshness.Observe)
+	server.SetLatency(freshness.Snapshot)
+	monitor.SetLatencyStatus(freshness.BurnStatus)
+	go monitor.Run(ctx)
+
+	cov := coverage.New()
+	if err := cov.Load(ctx, store); err != nil {
+		log.Warn("coverage load failed", "error", err)
+	}
+	idx.SetCoverageRecorder(cov.Add)
+	server.SetCoverage(outbox.CommitmentConfirmed, cov)
+
+	// Repairs re-process slots already inside a covered range, but a
+	// successful repair is recorded so the map reflects it explicitly.
+	repairWorker := repair.New(store, func(ctx context.Context, slot uint64) error {
+		err := idx.ProcessSlot(ctx, slot)
+		if err == nil {
+			cov.Add(coverage.SourceRepair, slot, slot)
+		}
+		return err
+	}, log)
+	idx.SetPartialObserver(repairWorker.Enqueue)
+	server.SetRepairWorker(repairWorker)
+	go repairWorker.Run(ctx)
+
+	jobManager := jobs.NewManager(2, log)
+	registerJobRunners(jobManager, idx, store, poolPrice, log)
+	server.SetJobManager(ctx, jobManager)
+
+	scheduler := sched.New(cfg.Schedules, log)
+	if err := scheduler.Add("attest_warm", "15 * * * *", attestor.Warm); err != nil {
+		return err
+	}
+	if err := scheduler.Add("coverage_flush", "*/5 * * * *", func(ctx context.Context) error {
+		return cov.Flush(ctx, store)
+	}); err != nil {
+		return err
+	}
+	if tiered != nil {
+		if err := scheduler.Add("cold_sweep", "*/10 * * * *", tiered.Sweep); err != nil {
+			return err
+		}
+	}
+	server.SetScheduler(ctx, scheduler)
+	go scheduler.Run(ctx)
+
+	sigWatcher := watcher.New(client, publish, log)
+	server.SetSignatureWatcher(sigWatcher)
+	idx.SetTransactionObserver(sigWatcher.ObserveTransaction)
+	go sigWatcher.Run(ctx)
+
+	httpServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.ServerPort),
+		Handler: server.Handler(),
+	}
+	go func() {
+		log.Info("api server listening", "addr", httpServer.Addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("api server failed", "error", err)
+			stop()
+		}
+	}()
+
+	if len(cfg.AggregateUpstreams) > 0 {
+		// Aggregator mode: merge upstream feeds instead of indexing RPC.
+		agg := aggregate.New(store, cfg.AggregateUpstreams, cfg.AggregateConsumer, publish, log)
+		log.Info("aggregator mode", "upstreams", len(cfg.AggregateUpstreams))
+		err = agg.Run(ctx)
+	} else {
+		err = idx.Run(ctx)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+		log.Warn("api server shutdown", "error", shutdownErr)
+	}
+	return err
+}
+
+// postgresOptions builds the store options for one database URL from
+// the shared pool configuration.
+func postgresOptions(cfg *config.Config, url string) postgres.Options {
+	return postgres.Options{
+		URL:       url,
+		Namespace: cfg.Namespace,
+		Pool: postgres.PoolConfig{
+			MaxConns:           cfg.DBMaxConns,
+			MinConns:           cfg.DBMinConns,
+			MaxConnIdleTime:    cfg.DBConnIdleTime,
+			MaxConnLifetime:    cfg.DBConnLifetime,
+			HealthCheckPeriod:  cfg.DBHealthCheckPeriod,
+			StatementCacheSize: cfg.DBStmtCacheSize,
+		},
+	}
+}
+
+// accessLogWriter opens the ACCESS_LOG destination: "stdout" or an
+// append-only file.
+func accessLogWriter(dest string) (io.Writer, error) {
+	if dest == "stdout" {
+		return os.Stdout, nil
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open access log: %w", err)
+	}
+	return f, nil
+}
+
+// rpcEndpoints merges the primary RPC URL with the scored endpoint list,
+// deduplicated and primary first.
+func rpcEndpoints(cfg *config.Config) []string {
+	urls := []string{cfg.RPCURL}
+	for _, u := range cfg.RPCURLs {
+		if u != cfg.RPCURL {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// buildHealthDispatchers assembles the alert destinations enabled in config.
+func buildHealthDispatchers(cfg *config.Config) []health.Dispatcher {
+	var dispatchers []health.Dispatcher
+	if cfg.SMTPAddr != "" && cfg.SMTPFrom != "" && len(cfg.SMTPTo) > 0 {
+		dispatchers = append(dispatchers, &health.SMTPDispatcher{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			To:       cfg.SMTPTo,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		})
+	}
+	if cfg.PagerDutyRoutingKey != "" {
+		dispatchers = append(dispatchers, &health.PagerDutyDispatcher{RoutingKey: cfg.PagerDutyRoutingKey})
+	}
+	return dispatchers
+}
+
+// storePing probes the store for the health monitor; an empty store is
+// reachable, so ErrNotFound counts as healthy.
+func storePing(store storage.Store) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if _, err := store.LatestSlot(ctx); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+}
+
+// buildNotifyRouter registers every notifier channel enabled in config.
+func buildNotifyRouter(cfg *config.Config, log *slog.Logger) (*notify.Router, error) {
+	router := notify.NewRouter(log)
+	if cfg.SlackWebhookURL != "" {
+		slack, err := notify.NewSlack(cfg.SlackWebhookURL, cfg.NotifyTemplate, cfg.NotifyMinInterval)
+		if err != nil {
+			return nil, err
+		}
+		router.Add(slack)
+	}
+	if cfg.DiscordWebhookURL != "" {
+		discord, err := notify.NewDiscord(cfg.DiscordWebhookURL, cfg.NotifyTemplate, cfg.NotifyMinInterval)
+		if err != nil {
+			return nil, err
+		}
+		router.Add(discord)
+	}
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+		telegram, err := notify.NewTelegram(cfg.TelegramBotToken, cfg.TelegramChatID, cfg.NotifyTemplate, cfg.NotifyMinInterval)
+		if err != nil {
+			return nil, err
+		}
+		router.Add(telegram)
+	}
+	if router.Len() > 0 {
+		log.Info("notifier channels enabled", "channels", router.Channels())
+	}
+	return router, nil
+}
+
+// loadAlertRules seeds the engine from a JSON array of rules.
+func loadAlertRules(engine *alerts.Engine, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read alert rules file: %w", err)
+	}
+	var rules []alerts.Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("parse alert rules file %s: %w", path, err)
+	}
+	for _, rule := range rules {
+		if err := engine.SetRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newLogger(level string) *slog.Logger {
+	var lvl slog.Level
+	switch level {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl}))
+}
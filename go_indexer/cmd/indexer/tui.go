@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage/postgres"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// tuiErrorsKept is how many recent poll errors the dashboard shows.
+const tuiErrorsKept = 5
+
+// ANSI sequences the dashboard is drawn with.
+const (
+	ansiClear      = "\x1b[2J\x1b[H"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiBold       = "\x1b[1m"
+	ansiDim        = "\x1b[2m"
+	ansiReset      = "\x1b[0m"
+)
+
+// runTUI implements "indexer tui": a live ANSI terminal dashboard over a
+// running indexer's API, showing slot lag, throughput, lane progress,
+// RPC endpoint health, and database pool pressure — quick insight for
+// operators on a box without Grafana.
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	addr := fs.String("addr", "", "base URL of the indexer API (default http://localhost:$SERVER_PORT)")
+	interval := fs.Duration("interval", 2*time.Second, "refresh interval")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if *addr == "" {
+		*addr = fmt.Sprintf("http://localhost:%d", cfg.ServerPort)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	t := &tui{
+		addr:  strings.TrimSuffix(*addr, "/"),
+		rpc:   solana.NewClient(cfg.RPCURL),
+		httpc: &http.Client{Timeout: 5 * time.Second},
+	}
+	fmt.Print(ansiHideCursor)
+	defer fmt.Print(ansiShowCursor)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	t.refresh(ctx, *interval)
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+			t.refresh(ctx, *interval)
+		}
+	}
+}
+
+// tui holds the dashboard's connections and the state carried between
+// refreshes for rate computation.
+type tui struct {
+	addr  string
+	rpc   *solana.Client
+	httpc *http.Client
+
+	lastProcessed uint64
+	lastSample    time.Time
+	errors        []string
+}
+
+type tuiProgress struct {
+	Live     indexer.LaneSnapshot `json:"live"`
+	Backfill indexer.LaneSnapshot `json:"backfill"`
+}
+
+// refresh polls every source once and redraws the screen.
+func (t *tui) refresh(ctx context.Context, interval time.Duration) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sindexer tui%s  %s  %s%s (Ctrl-C to quit)%s\n\n",
+		ansiBold, ansiReset, t.addr,
+		ansiDim, time.Now().Format("15:04:05"), ansiReset)
+
+	var progress tuiProgress
+	progressErr := t.fetch(ctx, "/api/v1/progress", &progress)
+
+	var indexed struct {
+		Slot uint64 `json:"slot"`
+	}
+	if err := t.fetch(ctx, "/api/v1/slot/latest", &indexed); err != nil {
+		t.recordError(err)
+	}
+	tip, tipErr := t.rpc.GetSlot(ctx)
+	if tipErr != nil {
+		t.recordError(fmt.Errorf("rpc tip: %w", tipErr))
+	}
+
+	fmt.Fprintf(&b, "%sslots%s\n", ansiBold, ansiReset)
+	fmt.Fprintf(&b, "  indexed  %12d\n", indexed.Slot)
+	if tipErr == nil {
+		lag := int64(tip) - int64(indexed.Slot)
+		fmt.Fprintf(&b, "  tip      %12d   lag %d slots\n", tip, lag)
+	} else {
+		fmt.Fprintf(&b, "  tip      %12s\n", "unavailable")
+	}
+	if progressErr == nil {
+		now := time.Now()
+		processed := progress.Live.SlotsProcessed + progress.Backfill.SlotsProcessed
+		if !t.lastSample.IsZero() && processed >= t.lastProcessed {
+			rate := float64(processed-t.lastProcessed) / now.Sub(t.lastSample).Seconds()
+			fmt.Fprintf(&b, "  blocks/s %12.1f\n", rate)
+		}
+		t.lastProcessed, t.lastSample = processed, now
+		fmt.Fprintf(&b, "  live lane     slot %d, %d processed\n",
+			progress.Live.CurrentSlot, progress.Live.SlotsProcessed)
+		if progress.Backfill.CurrentSlot > 0 || progress.Backfill.SlotsProcessed > 0 {
+			fmt.Fprintf(&b, "  backfill lane slot %d, %d processed\n",
+				progress.Backfill.CurrentSlot, progress.Backfill.SlotsProcessed)
+		}
+	} else {
+		t.recordError(progressErr)
+	}
+
+	var endpoints []solana.EndpointHealth
+	if err := t.fetch(ctx, "/debug/rpc", &endpoints); err == nil && len(endpoints) > 0 {
+		fmt.Fprintf(&b, "\n%srpc endpoints%s\n", ansiBold, ansiReset)
+		for _, ep := range endpoints {
+			marker := " "
+			if ep.Active {
+				marker = "*"
+			}
+			fmt.Fprintf(&b, " %s %-40s score %5.2f  skew %4d  %6.0fms  %d req / %d err\n",
+				marker, trimTo(ep.URL, 40), ep.Score, ep.SlotSkew, ep.LatencyMS, ep.Requests, ep.Errors)
+		}
+	}
+
+	var pool postgres.PoolStats
+	if err := t.fetch(ctx, "/api/v1/db/pool", &pool); err == nil && pool.MaxConns > 0 {
+		fmt.Fprintf(&b, "\n%sdatabase pool%s\n", ansiBold, ansiReset)
+		fmt.Fprintf(&b, "  conns %d/%d (%d idle)\n", pool.AcquiredConns, pool.MaxConns, pool.IdleConns)
+		if pool.AcquireCount > 0 {
+			wait := time.Duration(pool.AcquireWaitNS / uint64(pool.AcquireCount))
+			fmt.Fprintf(&b, "  avg acquire wait %s over %d acquires\n", wait, pool.AcquireCount)
+		}
+	}
+
+	if len(t.errors) > 0 {
+		fmt.Fprintf(&b, "\n%srecent errors%s\n", ansiBold, ansiReset)
+		for _, msg := range t.errors {
+			fmt.Fprintf(&b, "  %s\n", msg)
+		}
+	}
+
+	fmt.Print(ansiClear + b.String())
+}
+
+// fetch GETs one API endpoint into out.
+func (t *tui) fetch(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.addr+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.httpc.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: decode: %w", path, err)
+	}
+	return nil
+}
+
+// recordError keeps the newest poll failures for the errors section.
+func (t *tui) recordError(err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	msg := time.Now().Format("15:04:05") + " " + err.Error()
+	t.errors = append([]string{msg}, t.errors...)
+	if len(t.errors) > tuiErrorsKept {
+		t.errors = t.errors[:tuiErrorsKept]
+	}
+}
+
+func trimTo(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Mismatch kinds reported by "indexer verify".
+const (
+	mismatchMissingBlock       = "missing_block"
+	mismatchBlockhash          = "blockhash_mismatch"
+	mismatchTxCount            = "tx_count_mismatch"
+	mismatchMissingTransaction = "missing_transaction"
+	mismatchPartialBlock       = "partial_block"
+)
+
+// verifyMismatch is one finding, printed as a JSON line so the output
+// pipes cleanly into scripts.
+type verifyMismatch struct {
+	Slot      uint64 `json:"slot"`
+	Kind      string `json:"kind"`
+	Detail    string `json:"detail,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Repaired  bool   `json:"repaired,omitempty"`
+}
+
+// verifySummary is the final JSON line.
+type verifySummary struct {
+	FromSlot   uint64 `json:"from_slot"`
+	ToSlot     uint64 `json:"to_slot"`
+	Checked    uint64 `json:"checked"`
+	Skipped    uint64 `json:"skipped"`
+	Mismatches int    `json:"mismatches"`
+	Repaired   int    `json:"repaired"`
+}
+
+// runVerify implements "indexer verify": for a slot range it cross-checks
+// the stored blocks and transactions against fresh RPC responses —
+// blockhash, transaction count, and per-signature presence — and reports
+// every mismatch as a JSON line. With -repair, mismatched slots are
+// re-processed through the full pipeline, which also recomputes the
+// derived rows from the fresh payloads.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	from := fs.Uint64("from", 0, "first slot to verify")
+	to := fs.Uint64("to", 0, "last slot to verify")
+	repairFlag := fs.Bool("repair", false, "re-process mismatched slots")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *to == 0 || *to < *from {
+		return fmt.Errorf("verify: -from and -to required with -to >= -from")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	log := newLogger(cfg.LogLevel)
+
+	ctx := context.Background()
+	var store storage.Store = storage.NewMemoryStore()
+	defer store.Close(context.Background())
+	client := solana.NewClient(cfg.RPCURL)
+	var profiles []*indexer.Profile
+	for _, pc := range cfg.Profiles {
+		profile, err := indexer.NewProfile(pc, store)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, profile)
+	}
+	idx := indexer.New(cfg, client, store, profiles, log)
+
+	enc := json.NewEncoder(os.Stdout)
+	summary := verifySummary{FromSlot: *from, ToSlot: *to}
+	for slot := *from; slot <= *to; slot++ {
+		fresh, err := client.GetBlock(ctx, slot)
+		if err != nil {
+			if errors.Is(err, solana.ErrSlotSkipped) {
+				summary.Skipped++
+				continue
+			}
+			return fmt.Errorf("slot %d: %w", slot, err)
+		}
+		summary.Checked++
+		for _, m := range verifySlot(ctx, store, slot, fresh) {
+			if *repairFlag {
+				if err := idx.ProcessSlot(ctx, slot); err != nil {
+					log.Warn("repair failed", "slot", slot, "error", err)
+				} else {
+					m.Repaired = true
+					summary.Repaired++
+				}
+			}
+			summary.Mismatches++
+			if err := enc.Encode(m); err != nil {
+				return err
+			}
+		}
+	}
+	return enc.Encode(summary)
+}
+
+// verifySlot compares one stored block against the fresh RPC copy.
+func verifySlot(ctx context.Context, store storage.Store, slot uint64, fresh *solana.Block) []verifyMismatch {
+	stored, err := store.GetBlock(ctx, slot)
+	if err != nil {
+		return []verifyMismatch{{Slot: slot, Kind: mismatchMissingBlock}}
+	}
+	var out []verifyMismatch
+	if stored.Partial {
+		out = append(out, verifyMismatch{Slot: slot, Kind: mismatchPartialBlock,
+			Detail: fmt.Sprintf("%d transactions missing", len(stored.FailedTransactions))})
+	}
+	if stored.BlockHash != fresh.Blockhash {
+		out = append(out, verifyMismatch{Slot: slot, Kind: mismatchBlockhash,
+			Detail: fmt.Sprintf("stored %s, rpc %s", stored.BlockHash, fresh.Blockhash)})
+	}
+	if stored.TransactionsCount != len(fresh.Transactions) {
+		out = append(out, verifyMismatch{Slot: slot, Kind: mismatchTxCount,
+			Detail: fmt.Sprintf("stored %d, rpc %d", stored.TransactionsCount, len(fresh.Transactions))})
+	}
+	for i := range fresh.Transactions {
+		sigs := fresh.Transactions[i].Transaction.Signatures
+		if len(sigs) == 0 {
+			continue
+		}
+		if _, err := store.GetTransaction(ctx, sigs[0]); errors.Is(err, storage.ErrNotFound) {
+			out = append(out, verifyMismatch{Slot: slot, Kind: mismatchMissingTransaction, Signature: sigs[0]})
+		}
+	}
+	return out
+}
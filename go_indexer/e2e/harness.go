@@ -0,0 +1,144 @@
+//go:build e2e
+
+// Package e2e contains the end-to-end test harness. The tests run against a
+// live cluster: either the endpoint in SOLANA_E2E_RPC_URL or a
+// solana-test-validator spawned from PATH. Run them with:
+//
+//	go test -tags e2e ./e2e/
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Harness wraps a validator connection for end-to-end tests.
+type Harness struct {
+	Client *solana.Client
+	RPCURL string
+
+	validator *exec.Cmd
+	ledgerDir string
+}
+
+// StartHarness connects to SOLANA_E2E_RPC_URL when set; otherwise it spawns
+// a fresh solana-test-validator. When neither is possible, the calling test
+// is skipped.
+func StartHarness(t *testing.T) *Harness {
+	t.Helper()
+	h := &Harness{}
+	if url := os.Getenv("SOLANA_E2E_RPC_URL"); url != "" {
+		h.RPCURL = url
+	} else {
+		path, err := exec.LookPath("solana-test-validator")
+		if err != nil {
+			t.Skip("solana-test-validator not in PATH and SOLANA_E2E_RPC_URL not set")
+		}
+		h.ledgerDir = t.TempDir()
+		h.validator = exec.Command(path, "--reset", "--quiet", "--ledger", h.ledgerDir)
+		if err := h.validator.Start(); err != nil {
+			t.Fatalf("start solana-test-validator: %v", err)
+		}
+		h.RPCURL = "http://localhost:8899"
+	}
+	h.Client = solana.NewClient(h.RPCURL)
+	h.awaitHealthy(t)
+	t.Cleanup(h.stop)
+	return h
+}
+
+func (h *Harness) awaitHealthy(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	for {
+		if _, err := h.Client.GetSlot(ctx); err == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("validator at %s never became healthy", h.RPCURL)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func (h *Harness) stop() {
+	if h.validator != nil && h.validator.Process != nil {
+		_ = h.validator.Process.Kill()
+		_ = h.validator.Wait()
+	}
+}
+
+// FundedKeypair creates a keypair and airdrops lamports to it, waiting for
+// the airdrop to confirm.
+func (h *Harness) FundedKeypair(t *testing.T, lamports uint64) *solana.Keypair {
+	t.Helper()
+	ctx := context.Background()
+	key, err := solana.NewKeypair()
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+	sig, err := h.Client.RequestAirdrop(ctx, key.Pubkey(), lamports)
+	if err != nil {
+		t.Fatalf("request airdrop: %v", err)
+	}
+	h.AwaitConfirmed(t, sig)
+	return key
+}
+
+// Transfer sends lamports from key to the base58 address to and returns the
+// confirmed signature and the slot range [before, after] it landed in.
+func (h *Harness) Transfer(t *testing.T, key *solana.Keypair, to string, lamports uint64) (sig string, fromSlot, toSlot uint64) {
+	t.Helper()
+	ctx := context.Background()
+	before, err := h.Client.GetSlot(ctx)
+	if err != nil {
+		t.Fatalf("get slot: %v", err)
+	}
+	blockhash, err := h.Client.GetLatestBlockhash(ctx)
+	if err != nil {
+		t.Fatalf("get blockhash: %v", err)
+	}
+	tx, err := solana.BuildTransferTransaction(key, to, lamports, blockhash)
+	if err != nil {
+		t.Fatalf("build transfer: %v", err)
+	}
+	sig, err = h.Client.SendTransaction(ctx, tx)
+	if err != nil {
+		t.Fatalf("send transaction: %v", err)
+	}
+	h.AwaitConfirmed(t, sig)
+	after, err := h.Client.GetSlot(ctx)
+	if err != nil {
+		t.Fatalf("get slot: %v", err)
+	}
+	return sig, before, after
+}
+
+// AwaitConfirmed polls until the signature is confirmed or finalized.
+func (h *Harness) AwaitConfirmed(t *testing.T, sig string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for {
+		status, err := h.Client.GetSignatureStatus(ctx, sig)
+		if err != nil {
+			t.Fatalf("signature status %s: %v", sig, err)
+		}
+		if status == "confirmed" || status == "finalized" {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal(fmt.Sprintf("signature %s never confirmed", sig))
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
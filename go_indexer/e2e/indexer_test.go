@@ -0,0 +1,63 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// TestIndexSOLTransfer sends a known SOL transfer on the test validator,
+// runs the indexer over the slot range it landed in, and asserts on the
+// stored rows.
+func TestIndexSOLTransfer(t *testing.T) {
+	h := StartHarness(t)
+	ctx := context.Background()
+
+	const amount = 1_000_000 // 0.001 SOL
+	sender := h.FundedKeypair(t, 1_000_000_000)
+	receiver := h.FundedKeypair(t, 1_000_000_000)
+	sig, fromSlot, toSlot := h.Transfer(t, sender, receiver.Pubkey(), amount)
+
+	cfg := &config.Config{BatchSize: 10, MaxConcurrency: 2}
+	store := storage.NewMemoryStore()
+	profile, err := indexer.NewProfile(config.ProfileConfig{
+		Name:       "e2e",
+		Processors: []string{"transfers"},
+	}, store)
+	if err != nil {
+		t.Fatalf("build profile: %v", err)
+	}
+	idx := indexer.New(cfg, h.Client, store, []*indexer.Profile{profile}, slog.Default())
+
+	for slot := fromSlot; slot <= toSlot; slot++ {
+		if err := idx.ProcessSlot(ctx, slot); err != nil {
+			t.Fatalf("process slot %d: %v", slot, err)
+		}
+	}
+
+	tx, err := store.GetTransaction(ctx, sig)
+	if err != nil {
+		t.Fatalf("stored transaction %s: %v", sig, err)
+	}
+	if !tx.Success {
+		t.Fatalf("transaction %s stored as failed: %s", sig, tx.Error)
+	}
+
+	transfers, err := store.ListTransfers(ctx, storage.TransferFilter{Address: sender.Pubkey()})
+	if err != nil {
+		t.Fatalf("list transfers: %v", err)
+	}
+	if len(transfers) != 1 {
+		t.Fatalf("expected 1 transfer for %s, got %d", sender.Pubkey(), len(transfers))
+	}
+	got := transfers[0]
+	if got.Signature != sig || got.Destination != receiver.Pubkey() || got.Amount != amount {
+		t.Fatalf("unexpected transfer row: %+v", got)
+	}
+}
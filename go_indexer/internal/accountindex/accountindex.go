@@ -0,0 +1,166 @@
+// Package accountindex maintains an inverted index from account address
+// to the transactions referencing it — static message keys and accounts
+// loaded through address lookup tables alike — so address-centric
+// queries resolve straight to signature sets instead of scanning the
+// transfer and event tables.
+package accountindex
+
+import (
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// Roles an account can hold in a transaction. An account gets its
+// strongest role: signer over program over the plain references.
+const (
+	RoleSigner  = "signer"
+	RoleProgram = "program"
+	// RoleLoaded marks accounts resolved through an address lookup
+	// table rather than carried in the message's static key list.
+	RoleLoaded  = "loaded"
+	RoleAccount = "account"
+)
+
+// pruneEvery is how many observed slots pass between retention sweeps.
+const pruneEvery = 256
+
+// Entry is one account's reference to one transaction.
+type Entry struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	// Position is the index of the first instruction referencing the
+	// account, or -1 when it is only listed (e.g. fee payer, loaded but
+	// untouched).
+	Position int    `json:"position"`
+	Role     string `json:"role"`
+}
+
+// Index is the in-memory inverted index over the retention window. It
+// is wired as a transaction inspector on the indexer.
+type Index struct {
+	retain uint64 // slots kept behind the newest observed
+
+	mu         sync.Mutex
+	entries    map[string][]Entry // per account, slot-ascending
+	latest     uint64
+	sincePrune int
+}
+
+// New creates an Index retaining the most recent retainSlots.
+func New(retainSlots uint64) *Index {
+	return &Index{retain: retainSlots, entries: make(map[string][]Entry)}
+}
+
+// Observe indexes one transaction's account references.
+func (ix *Index) Observe(tc *processor.TxContext) {
+	msg := &tc.Tx.Transaction.Message
+	keys := msg.AccountKeys
+	var loaded []string
+	if tc.Tx.Meta != nil {
+		loaded = append(loaded, tc.Tx.Meta.LoadedAddresses.Writable...)
+		loaded = append(loaded, tc.Tx.Meta.LoadedAddresses.Readonly...)
+	}
+	// Instruction account indices address the static keys first, then
+	// the loaded addresses in writable-then-readonly order.
+	combined := keys
+	if len(loaded) > 0 {
+		combined = append(append(make([]string, 0, len(keys)+len(loaded)), keys...), loaded...)
+	}
+
+	positions := make(map[string]int, len(combined))
+	programs := make(map[string]struct{})
+	for i, instr := range msg.Instructions {
+		programs[msg.ProgramID(instr)] = struct{}{}
+		for _, acct := range instr.Accounts {
+			if acct < 0 || acct >= len(combined) {
+				continue
+			}
+			if _, seen := positions[combined[acct]]; !seen {
+				positions[combined[acct]] = i
+			}
+		}
+	}
+
+	signers := len(tc.Tx.Transaction.Signatures)
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for i, key := range combined {
+		role := RoleAccount
+		switch {
+		case i < signers && i < len(keys):
+			role = RoleSigner
+		case memberOf(programs, key):
+			role = RoleProgram
+		case i >= len(keys):
+			role = RoleLoaded
+		}
+		position, ok := positions[key]
+		if !ok {
+			position = -1
+		}
+		ix.entries[key] = append(ix.entries[key], Entry{
+			Signature: tc.Signature,
+			Slot:      tc.Slot,
+			Position:  position,
+			Role:      role,
+		})
+	}
+	if tc.Slot > ix.latest {
+		ix.latest = tc.Slot
+	}
+	if ix.sincePrune++; ix.sincePrune >= pruneEvery {
+		ix.sincePrune = 0
+		ix.prune()
+	}
+}
+
+// prune drops entries behind the retention window. Callers hold ix.mu.
+func (ix *Index) prune() {
+	if ix.retain == 0 || ix.latest <= ix.retain {
+		return
+	}
+	floor := ix.latest - ix.retain
+	for key, entries := range ix.entries {
+		keep := entries[:0]
+		for _, e := range entries {
+			if e.Slot >= floor {
+				keep = append(keep, e)
+			}
+		}
+		if len(keep) == 0 {
+			delete(ix.entries, key)
+			continue
+		}
+		ix.entries[key] = keep
+	}
+}
+
+// Lookup returns the account's references, slot-ascending, optionally
+// narrowed to a role and slot range. limit 0 means no cap.
+func (ix *Index) Lookup(account, role string, fromSlot, toSlot uint64, limit int) []Entry {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	var out []Entry
+	for _, e := range ix.entries[account] {
+		if role != "" && e.Role != role {
+			continue
+		}
+		if fromSlot > 0 && e.Slot < fromSlot {
+			continue
+		}
+		if toSlot > 0 && e.Slot > toSlot {
+			continue
+		}
+		out = append(out, e)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
+
+func memberOf(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
@@ -0,0 +1,230 @@
+// Package aggregate implements the multi-region aggregator mode: the
+// process consumes the pull-based event feed of several upstream indexer
+// instances, deduplicates the merged stream, and writes it to its own
+// canonical store. Running one aggregator over indexers in different
+// regions (or on different RPC providers) gives high availability — any
+// single upstream can fall over without a gap in the merged data.
+package aggregate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+const (
+	// fetchLimit is the page size requested from each upstream feed.
+	fetchLimit = 256
+	// waitMS is the long-poll window passed to the upstream feed.
+	waitMS = 10_000
+	// retainSlots bounds the dedup set: entries this far behind the
+	// highest merged slot are pruned, matching how far behind a lagging
+	// upstream can usefully be.
+	retainSlots = 50_000
+)
+
+// commitmentRank orders the finalization statuses an upstream may attach
+// to an event under data.commitment; absent means confirmed.
+var commitmentRank = map[string]int{
+	"processed": 0,
+	"confirmed": 1,
+	"finalized": 2,
+}
+
+// Aggregator merges the event feeds of several upstream indexers into
+// the local store.
+type Aggregator struct {
+	store     storage.Store
+	upstreams []string
+	consumer  string // consumer name committed to each upstream
+	publish   func(models.Event)
+	httpc     *http.Client
+	log       *slog.Logger
+
+	mu        sync.Mutex
+	seen      map[string]int // dedup key -> highest commitment rank stored
+	seenSlot  map[string]uint64
+	watermark uint64
+}
+
+// New creates an aggregator over the given upstream base URLs (e.g.
+// "http://indexer-eu.internal:8080").
+func New(store storage.Store, upstreams []string, consumer string, publish func(models.Event), log *slog.Logger) *Aggregator {
+	return &Aggregator{
+		store:     store,
+		upstreams: upstreams,
+		consumer:  consumer,
+		publish:   publish,
+		httpc:     &http.Client{Timeout: (waitMS + 10_000) * time.Millisecond},
+		log:       log,
+		seen:      make(map[string]int),
+		seenSlot:  make(map[string]uint64),
+	}
+}
+
+// Run consumes every upstream until ctx is canceled.
+func (a *Aggregator) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, upstream := range a.upstreams {
+		upstream := strings.TrimRight(upstream, "/")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.consume(ctx, upstream)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// consume follows one upstream's feed, committing the cursor after each
+// page is merged so a restart resumes without loss.
+func (a *Aggregator) consume(ctx context.Context, upstream string) {
+	var cursor uint64
+	resumed := false
+	for ctx.Err() == nil {
+		entries, next, err := a.fetch(ctx, upstream, cursor, !resumed)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.log.Warn("upstream feed fetch failed", "upstream", upstream, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		resumed = true
+		for i := range entries {
+			if err := a.merge(ctx, &entries[i].Event); err != nil {
+				a.log.Error("merge event failed", "upstream", upstream, "error", err)
+			}
+		}
+		if next > cursor {
+			cursor = next
+			if err := a.commit(ctx, upstream, cursor); err != nil && ctx.Err() == nil {
+				a.log.Warn("upstream cursor commit failed", "upstream", upstream, "error", err)
+			}
+		}
+	}
+}
+
+type feedPage struct {
+	Entries    []models.OutboxEntry `json:"entries"`
+	NextCursor uint64               `json:"next_cursor"`
+}
+
+// fetch reads one feed page. The first call per upstream resumes from
+// the consumer's committed offset instead of an explicit cursor.
+func (a *Aggregator) fetch(ctx context.Context, upstream string, cursor uint64, resume bool) ([]models.OutboxEntry, uint64, error) {
+	q := url.Values{}
+	q.Set("limit", fmt.Sprint(fetchLimit))
+	q.Set("wait_ms", fmt.Sprint(waitMS))
+	if resume {
+		q.Set("consumer", a.consumer)
+	} else {
+		q.Set("after_cursor", fmt.Sprint(cursor))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		upstream+"/api/v1/events/feed?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := a.httpc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("feed: unexpected status %d", resp.StatusCode)
+	}
+	var page feedPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, 0, fmt.Errorf("feed: decode response: %w", err)
+	}
+	return page.Entries, page.NextCursor, nil
+}
+
+// commit records the merged cursor on the upstream.
+func (a *Aggregator) commit(ctx context.Context, upstream string, cursor uint64) error {
+	body, err := json.Marshal(map[string]any{"consumer": a.consumer, "cursor": cursor})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		upstream+"/api/v1/events/feed/commit", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("commit: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// merge writes an event to the canonical store unless an equal or
+// better-finalized copy was already merged from another upstream. A copy
+// arriving at a higher commitment (per data.commitment) is stored again
+// so downstream consumers see the upgrade.
+func (a *Aggregator) merge(ctx context.Context, event *models.Event) error {
+	key := fmt.Sprintf("%d/%s/%s/%s", event.Slot, event.Signature, event.Program, event.Type)
+	rank := commitmentRank["confirmed"]
+	if c, ok := event.Data["commitment"].(string); ok {
+		if r, ok := commitmentRank[c]; ok {
+			rank = r
+		}
+	}
+
+	a.mu.Lock()
+	prev, dup := a.seen[key]
+	if dup && rank <= prev {
+		a.mu.Unlock()
+		return nil
+	}
+	a.seen[key] = rank
+	a.seenSlot[key] = event.Slot
+	if event.Slot > a.watermark {
+		a.watermark = event.Slot
+		a.prune()
+	}
+	a.mu.Unlock()
+
+	if err := a.store.SaveEvent(ctx, event); err != nil {
+		return err
+	}
+	if a.publish != nil {
+		a.publish(*event)
+	}
+	return nil
+}
+
+// prune drops dedup entries far behind the watermark. Callers hold a.mu.
+func (a *Aggregator) prune() {
+	if a.watermark < retainSlots {
+		return
+	}
+	floor := a.watermark - retainSlots
+	for key, slot := range a.seenSlot {
+		if slot < floor {
+			delete(a.seenSlot, key)
+			delete(a.seen, key)
+		}
+	}
+}
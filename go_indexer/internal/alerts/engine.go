@@ -0,0 +1,248 @@
+// Package alerts implements the address activity rules engine. Rules are
+// defined in config or through the API and evaluated against every indexed
+// transaction; matches are delivered to the rule's webhook and the event
+// stream with the rule metadata attached.
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/notify"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// Rule is one alert rule. Exactly the non-zero conditions are applied; a
+// rule with several conditions fires when any of them matches.
+type Rule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Address scopes the balance conditions and, with no other condition
+	// set, alerts on any transaction the address signs or receives in.
+	Address string `json:"address,omitempty"`
+	// Program alerts on any interaction with this program ID.
+	Program string `json:"program,omitempty"`
+	// WatchMintAuthority alerts when this mint's mint or freeze
+	// authority changes — a key rug-pull indicator.
+	WatchMintAuthority string `json:"watch_mint_authority,omitempty"`
+	// MinReceivedLamports alerts when Address receives more than this.
+	MinReceivedLamports uint64 `json:"min_received_lamports,omitempty"`
+	// BalanceBelowLamports alerts when Address's balance drops below this.
+	BalanceBelowLamports uint64 `json:"balance_below_lamports,omitempty"`
+
+	// WebhookURL receives the alert payload as JSON POST.
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// Channels names notifier channels (slack, discord, telegram) that
+	// receive the alert in addition to the webhook.
+	Channels []string `json:"channels,omitempty"`
+}
+
+// Alert is one rule match.
+type Alert struct {
+	RuleID    string         `json:"rule_id"`
+	RuleName  string         `json:"rule_name"`
+	Reason    string         `json:"reason"`
+	Signature string         `json:"signature"`
+	Slot      uint64         `json:"slot"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Engine evaluates rules against indexed transactions.
+type Engine struct {
+	publish func(models.Event)
+	httpc   *http.Client
+	log     *slog.Logger
+	router  *notify.Router
+
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewEngine creates an Engine. publish, when non-nil, receives an
+// alert_triggered event for every match.
+func NewEngine(publish func(models.Event), log *slog.Logger) *Engine {
+	return &Engine{
+		publish: publish,
+		httpc:   &http.Client{Timeout: 10 * time.Second},
+		log:     log,
+		rules:   make(map[string]Rule),
+	}
+}
+
+// SetRouter wires the notifier channels rules may address by name.
+func (e *Engine) SetRouter(r *notify.Router) { e.router = r }
+
+// SetRule adds or replaces a rule.
+func (e *Engine) SetRule(rule Rule) error {
+	if rule.ID == "" {
+		return fmt.Errorf("alerts: rule id is required")
+	}
+	if rule.Address == "" && rule.Program == "" && rule.WatchMintAuthority == "" {
+		return fmt.Errorf("alerts: rule %s needs an address, program, or mint condition", rule.ID)
+	}
+	e.mu.Lock()
+	e.rules[rule.ID] = rule
+	e.mu.Unlock()
+	return nil
+}
+
+// DeleteRule removes a rule; deleting an unknown rule is a no-op.
+func (e *Engine) DeleteRule(id string) {
+	e.mu.Lock()
+	delete(e.rules, id)
+	e.mu.Unlock()
+}
+
+// Rules returns all rules sorted by ID.
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		out = append(out, rule)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Inspect evaluates every rule against one transaction. The indexer calls
+// this for each transaction it stores.
+func (e *Engine) Inspect(tx *processor.TxContext) {
+	e.mu.RLock()
+	rules := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		rules = append(rules, rule)
+	}
+	e.mu.RUnlock()
+
+	for _, rule := range rules {
+		if alert, ok := e.match(rule, tx); ok {
+			e.deliver(rule, alert)
+		}
+	}
+}
+
+func (e *Engine) match(rule Rule, tx *processor.TxContext) (Alert, bool) {
+	alert := Alert{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+	}
+	msg := &tx.Tx.Transaction.Message
+
+	if rule.Program != "" {
+		for _, ix := range msg.Instructions {
+			if msg.ProgramID(ix) == rule.Program {
+				alert.Reason = "program_interaction"
+				alert.Details = map[string]any{"program": rule.Program}
+				return alert, true
+			}
+		}
+	}
+
+	if rule.WatchMintAuthority != "" {
+		for _, change := range processor.DecodeMintAuthorityChanges(tx) {
+			if change.Mint != rule.WatchMintAuthority {
+				continue
+			}
+			alert.Reason = change.Type + "_authority_changed"
+			alert.Details = map[string]any{
+				"mint":          change.Mint,
+				"authority":     change.Authority,
+				"new_authority": change.NewAuthority,
+				"revoked":       change.NewAuthority == "",
+			}
+			return alert, true
+		}
+	}
+
+	if rule.Address == "" || tx.Tx.Meta == nil {
+		return Alert{}, false
+	}
+	index := -1
+	for i, key := range msg.AccountKeys {
+		if key == rule.Address {
+			index = i
+			break
+		}
+	}
+	if index < 0 || index >= len(tx.Tx.Meta.PreBalances) || index >= len(tx.Tx.Meta.PostBalances) {
+		return Alert{}, false
+	}
+	pre := tx.Tx.Meta.PreBalances[index]
+	post := tx.Tx.Meta.PostBalances[index]
+
+	if rule.MinReceivedLamports > 0 && post > pre && post-pre > rule.MinReceivedLamports {
+		alert.Reason = "received_above_threshold"
+		alert.Details = map[string]any{
+			"address":  rule.Address,
+			"received": post - pre,
+		}
+		return alert, true
+	}
+	if rule.BalanceBelowLamports > 0 && pre >= rule.BalanceBelowLamports && post < rule.BalanceBelowLamports {
+		alert.Reason = "balance_below_threshold"
+		alert.Details = map[string]any{
+			"address": rule.Address,
+			"balance": post,
+		}
+		return alert, true
+	}
+	if rule.MinReceivedLamports == 0 && rule.BalanceBelowLamports == 0 {
+		alert.Reason = "address_activity"
+		alert.Details = map[string]any{"address": rule.Address}
+		return alert, true
+	}
+	return Alert{}, false
+}
+
+func (e *Engine) deliver(rule Rule, alert Alert) {
+	if e.publish != nil {
+		e.publish(models.Event{
+			Signature: alert.Signature,
+			Slot:      alert.Slot,
+			Type:      "alert_triggered",
+			Data: map[string]any{
+				"rule_id":   alert.RuleID,
+				"rule_name": alert.RuleName,
+				"reason":    alert.Reason,
+				"details":   alert.Details,
+			},
+		})
+	}
+	if e.router != nil {
+		for _, channel := range rule.Channels {
+			e.router.Notify(channel, notify.Message{
+				Title: "alert: " + alert.RuleName,
+				Text:  alert.Reason,
+				Fields: map[string]any{
+					"rule_id":   alert.RuleID,
+					"signature": alert.Signature,
+					"slot":      alert.Slot,
+				},
+			})
+		}
+	}
+	if rule.WebhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return
+	}
+	resp, err := e.httpc.Post(rule.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		e.log.Warn("alert webhook delivery failed", "rule", rule.ID, "error", err)
+		return
+	}
+	resp.Body.Close()
+}
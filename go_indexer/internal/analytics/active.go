@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// PeriodWeek is the weekly rollup period (epoch-aligned 7-day windows).
+const PeriodWeek = "week"
+
+const weekRetention = 365 * 24 * time.Hour
+
+// activeSketchTable is the custom table persisted sketches live in.
+const activeSketchTable = "analytics_active_addresses"
+
+// flushInterval is how often dirty sketches are persisted.
+const flushInterval = time.Minute
+
+// ActiveStat is one cardinality estimate row for the API.
+type ActiveStat struct {
+	Period      string    `json:"period"`
+	PeriodStart time.Time `json:"period_start"`
+	// Addresses is the HyperLogLog estimate of distinct addresses seen.
+	Addresses uint64 `json:"addresses"`
+}
+
+// ActiveAddresses tracks distinct active addresses per day and week with
+// HyperLogLog sketches, updated during indexing and persisted through
+// the custom-table store so estimates survive restarts.
+type ActiveAddresses struct {
+	mu       sync.Mutex
+	sketches map[chainKey]*Sketch
+	dirty    map[chainKey]bool
+}
+
+// NewActiveAddresses creates an empty tracker.
+func NewActiveAddresses() *ActiveAddresses {
+	return &ActiveAddresses{
+		sketches: make(map[chainKey]*Sketch),
+		dirty:    make(map[chainKey]bool),
+	}
+}
+
+// Observe folds one transaction's account keys into the current day and
+// week sketches. It is wired as a transaction inspector on the indexer.
+func (a *ActiveAddresses) Observe(tc *processor.TxContext) {
+	keys := tc.Tx.Transaction.Message.AccountKeys
+	if len(keys) == 0 {
+		return
+	}
+	when := bucketTime(tc.BlockTime)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, period := range []string{PeriodDay, PeriodWeek} {
+		s := a.sketch(period, when)
+		for _, key := range keys {
+			if key != "" {
+				s.Add(key)
+			}
+		}
+		a.dirty[sketchKey(period, when)] = true
+	}
+}
+
+func sketchKey(period string, when time.Time) chainKey {
+	width := 24 * time.Hour
+	if period == PeriodWeek {
+		width = 7 * 24 * time.Hour
+	}
+	return chainKey{period: period, start: when.Truncate(width).Unix()}
+}
+
+// sketch returns the sketch for (period, when), creating it and pruning
+// expired ones on first use. Callers hold a.mu.
+func (a *ActiveAddresses) sketch(period string, when time.Time) *Sketch {
+	key := sketchKey(period, when)
+	s, ok := a.sketches[key]
+	if !ok {
+		s = NewSketch()
+		a.sketches[key] = s
+		for k := range a.sketches {
+			retention := dayRetention
+			if k.period == PeriodWeek {
+				retention = weekRetention
+			}
+			if time.Unix(k.start, 0).Before(when.Add(-retention)) {
+				delete(a.sketches, k)
+				delete(a.dirty, k)
+			}
+		}
+	}
+	return s
+}
+
+// Run loads persisted sketches, then flushes dirty ones every
+// flushInterval and once more on shutdown.
+func (a *ActiveAddresses) Run(ctx context.Context, store storage.Store, log *slog.Logger) {
+	if err := a.load(ctx, store); err != nil {
+		log.Warn("active address sketches not loaded", "error", err)
+	}
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			a.flush(context.Background(), store, log)
+			return
+		case <-ticker.C:
+			a.flush(ctx, store, log)
+		}
+	}
+}
+
+// load merges previously persisted sketches; later rows for the same
+// bucket win by merge, which is safe because sketches are monotone.
+func (a *ActiveAddresses) load(ctx context.Context, store storage.Store) error {
+	if err := store.EnsureCustomTable(ctx, activeSketchTable); err != nil {
+		return err
+	}
+	rows, err := store.ListCustomRows(ctx, activeSketchTable, 0, 0)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, row := range rows {
+		period, _ := row["period"].(string)
+		encoded, _ := row["sketch"].(string)
+		start := int64(0)
+		switch v := row["start"].(type) {
+		case int64:
+			start = v
+		case float64:
+			start = int64(v)
+		}
+		decoded, ok := DecodeSketch(encoded)
+		if !ok || (period != PeriodDay && period != PeriodWeek) {
+			continue
+		}
+		key := chainKey{period: period, start: start}
+		if existing, ok := a.sketches[key]; ok {
+			existing.Merge(decoded)
+		} else {
+			a.sketches[key] = decoded
+		}
+	}
+	return nil
+}
+
+// flush persists every dirty sketch as a new custom row.
+func (a *ActiveAddresses) flush(ctx context.Context, store storage.Store, log *slog.Logger) {
+	a.mu.Lock()
+	type pending struct {
+		key chainKey
+		row map[string]any
+	}
+	var out []pending
+	for key := range a.dirty {
+		s, ok := a.sketches[key]
+		if !ok {
+			continue
+		}
+		out = append(out, pending{key: key, row: map[string]any{
+			"period": key.period,
+			"start":  key.start,
+			"sketch": s.Encode(),
+		}})
+	}
+	a.dirty = make(map[chainKey]bool)
+	a.mu.Unlock()
+	for _, p := range out {
+		if err := store.InsertCustomRow(ctx, activeSketchTable, p.row); err != nil {
+			log.Warn("active address sketch flush failed",
+				"bucket", fmt.Sprintf("%s/%d", p.key.period, p.key.start), "error", err)
+		}
+	}
+}
+
+// Query returns cardinality estimates for the period, newest first.
+func (a *ActiveAddresses) Query(period string, limit int) []ActiveStat {
+	if period == "" {
+		period = PeriodDay
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	a.mu.Lock()
+	out := make([]ActiveStat, 0)
+	for key, s := range a.sketches {
+		if key.period != period {
+			continue
+		}
+		out = append(out, ActiveStat{
+			Period:      key.period,
+			PeriodStart: time.Unix(key.start, 0).UTC(),
+			Addresses:   s.Estimate(),
+		})
+	}
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].PeriodStart.After(out[j].PeriodStart)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
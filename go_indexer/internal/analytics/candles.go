@@ -0,0 +1,148 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Candle intervals.
+const (
+	Interval1m = "1m"
+	Interval5m = "5m"
+	Interval1h = "1h"
+	Interval1d = "1d"
+)
+
+// candleIntervals maps interval names to bucket width and retention;
+// older buckets are pruned as new ones appear.
+var candleIntervals = map[string]struct {
+	width     time.Duration
+	retention time.Duration
+}{
+	Interval1m: {time.Minute, 48 * time.Hour},
+	Interval5m: {5 * time.Minute, 7 * 24 * time.Hour},
+	Interval1h: {time.Hour, 90 * 24 * time.Hour},
+	Interval1d: {24 * time.Hour, 2 * 365 * 24 * time.Hour},
+}
+
+// ValidCandleInterval reports whether name is a supported interval.
+func ValidCandleInterval(name string) bool {
+	_, ok := candleIntervals[name]
+	return ok
+}
+
+// Candle is one OHLCV bucket for a market. Prices and volume stay in
+// the venue's native units, matching the trade feed.
+type Candle struct {
+	Start time.Time `json:"start"`
+	Open  uint64    `json:"open"`
+	High  uint64    `json:"high"`
+	Low   uint64    `json:"low"`
+	Close uint64    `json:"close"`
+	// Volume is the summed trade size, a decimal string in JSON so
+	// heavy buckets cannot wrap uint64.
+	Volume solana.Amount `json:"volume"`
+	Trades uint64        `json:"trades"`
+}
+
+type candleKey struct {
+	market   string
+	interval string
+	start    int64 // unix seconds of the bucket start
+}
+
+// Candles builds OHLCV buckets incrementally from the decoded trade
+// stream; nothing is recomputed from storage. It is wired as a
+// transaction inspector and is safe for concurrent use.
+type Candles struct {
+	mu      sync.Mutex
+	buckets map[candleKey]*Candle
+}
+
+// NewCandles creates an empty candle builder.
+func NewCandles() *Candles {
+	return &Candles{buckets: make(map[candleKey]*Candle)}
+}
+
+// Observe folds one transaction's fills into every interval's bucket.
+func (c *Candles) Observe(tc *processor.TxContext) {
+	trades := processor.DecodeTrades(tc)
+	if len(trades) == 0 {
+		return
+	}
+	when := tc.BlockTime
+	if when.IsZero() {
+		when = time.Now().UTC()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, trade := range trades {
+		for name, interval := range candleIntervals {
+			key := candleKey{
+				market:   trade.Market,
+				interval: name,
+				start:    when.Truncate(interval.width).Unix(),
+			}
+			bucket, ok := c.buckets[key]
+			if !ok {
+				bucket = &Candle{
+					Start: time.Unix(key.start, 0).UTC(),
+					Open:  trade.Price,
+					Low:   trade.Price,
+				}
+				c.buckets[key] = bucket
+				c.prune(when)
+			}
+			if trade.Price > bucket.High {
+				bucket.High = trade.Price
+			}
+			if trade.Price < bucket.Low {
+				bucket.Low = trade.Price
+			}
+			bucket.Close = trade.Price
+			bucket.Volume.AddUint64(trade.Size)
+			bucket.Trades++
+		}
+	}
+}
+
+// prune drops buckets past their interval's retention. Callers hold c.mu.
+func (c *Candles) prune(now time.Time) {
+	for key := range c.buckets {
+		if time.Unix(key.start, 0).Before(now.Add(-candleIntervals[key.interval].retention)) {
+			delete(c.buckets, key)
+		}
+	}
+}
+
+// Query returns a market's candles for the interval, oldest first,
+// narrowed to [from, to] when non-zero.
+func (c *Candles) Query(market, interval string, from, to time.Time, limit int) []Candle {
+	if limit <= 0 {
+		limit = 500
+	}
+	c.mu.Lock()
+	out := make([]Candle, 0)
+	for key, bucket := range c.buckets {
+		if key.market != market || key.interval != interval {
+			continue
+		}
+		if !from.IsZero() && bucket.Start.Before(from) {
+			continue
+		}
+		if !to.IsZero() && bucket.Start.After(to) {
+			continue
+		}
+		out = append(out, *bucket)
+	}
+	c.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	if len(out) > limit {
+		out = out[len(out)-limit:] // keep the most recent buckets
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// governanceKeep bounds the proposals retained per realm.
+const governanceKeep = 1_000
+
+// GovernanceProposal is one SPL Governance proposal's observed
+// lifecycle. Status follows the latest action.
+type GovernanceProposal struct {
+	Proposal  string    `json:"proposal"`
+	Status    string    `json:"status"`
+	Creator   string    `json:"creator,omitempty"`
+	Votes     uint64    `json:"votes"`
+	Slot      uint64    `json:"slot"` // slot of the latest action
+	BlockTime time.Time `json:"block_time"`
+	Signature string    `json:"signature"` // of the latest action
+}
+
+// RealmStats aggregates one realm's governance activity since startup.
+type RealmStats struct {
+	Realm                string `json:"realm"`
+	Proposals            uint64 `json:"proposals"`
+	VotesCast            uint64 `json:"votes_cast"`
+	TransactionsAdded    uint64 `json:"transactions_added"`
+	TransactionsExecuted uint64 `json:"transactions_executed"`
+}
+
+// realmState is one realm's counters plus proposal records.
+type realmState struct {
+	stats     RealmStats
+	proposals map[string]*GovernanceProposal
+}
+
+// Governance tracks SPL Governance activity per realm. Transaction
+// instructions do not reference the realm, so proposals are mapped to
+// their realm when created and resolved through that mapping. It is
+// wired as a transaction inspector and is safe for concurrent use.
+type Governance struct {
+	mu            sync.Mutex
+	realms        map[string]*realmState
+	proposalRealm map[string]string
+}
+
+// NewGovernance creates an empty tracker.
+func NewGovernance() *Governance {
+	return &Governance{
+		realms:        make(map[string]*realmState),
+		proposalRealm: make(map[string]string),
+	}
+}
+
+// Observe folds one transaction's governance actions into the realms.
+func (g *Governance) Observe(tc *processor.TxContext) {
+	actions := processor.DecodeGovernanceActions(tc)
+	if len(actions) == 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, action := range actions {
+		realm := action.Realm
+		if realm == "" {
+			realm = g.proposalRealm[action.Proposal]
+		}
+		if realm == "" {
+			continue // proposal created before indexing started
+		}
+		g.proposalRealm[action.Proposal] = realm
+		state := g.realms[realm]
+		if state == nil {
+			state = &realmState{
+				stats:     RealmStats{Realm: realm},
+				proposals: make(map[string]*GovernanceProposal),
+			}
+			g.realms[realm] = state
+		}
+		p := state.proposals[action.Proposal]
+		if p == nil {
+			if len(state.proposals) >= governanceKeep {
+				g.evictOldest(state.proposals)
+			}
+			p = &GovernanceProposal{Proposal: action.Proposal}
+			state.proposals[action.Proposal] = p
+		}
+		p.Slot = tc.Slot
+		p.BlockTime = tc.BlockTime
+		p.Signature = tc.Signature
+		switch action.Action {
+		case processor.GovProposalCreated:
+			p.Status = action.Action
+			p.Creator = action.Actor
+			state.stats.Proposals++
+		case processor.GovVoteCast:
+			p.Votes++
+			state.stats.VotesCast++
+		case processor.GovVoteRelinquished:
+			if p.Votes > 0 {
+				p.Votes--
+			}
+			if state.stats.VotesCast > 0 {
+				state.stats.VotesCast--
+			}
+		case processor.GovTransactionAdded:
+			state.stats.TransactionsAdded++
+		case processor.GovTransactionExecuted:
+			p.Status = action.Action
+			state.stats.TransactionsExecuted++
+		default:
+			p.Status = action.Action
+		}
+	}
+}
+
+// evictOldest drops the proposal with the lowest slot. Callers hold
+// g.mu.
+func (g *Governance) evictOldest(proposals map[string]*GovernanceProposal) {
+	var oldest string
+	var slot uint64
+	for key, p := range proposals {
+		if oldest == "" || p.Slot < slot {
+			oldest, slot = key, p.Slot
+		}
+	}
+	delete(proposals, oldest)
+}
+
+// Proposals returns a realm's proposals, newest action first. The
+// second return reports whether the realm has been seen at all.
+func (g *Governance) Proposals(realm string, limit int) ([]GovernanceProposal, bool) {
+	if limit <= 0 {
+		limit = 100
+	}
+	g.mu.Lock()
+	state, ok := g.realms[realm]
+	if !ok {
+		g.mu.Unlock()
+		return nil, false
+	}
+	out := make([]GovernanceProposal, 0, len(state.proposals))
+	for _, p := range state.proposals {
+		out = append(out, *p)
+	}
+	g.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot > out[j].Slot })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, true
+}
+
+// Stats returns a realm's aggregate counters. The second return
+// reports whether the realm has been seen at all.
+func (g *Governance) Stats(realm string) (RealmStats, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state, ok := g.realms[realm]
+	if !ok {
+		return RealmStats{}, false
+	}
+	return state.stats, true
+}
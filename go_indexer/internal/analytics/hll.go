@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"encoding/base64"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog precision: 2^hllPrecision registers, giving a standard
+// error of about 0.8% at 16 KiB per sketch.
+const hllPrecision = 14
+
+const hllRegisters = 1 << hllPrecision
+
+// Sketch is a HyperLogLog cardinality estimator. Adding an element is a
+// hash and one register update, so sketches can sit directly on the
+// indexing hot path; estimates over long windows never scan raw rows.
+type Sketch struct {
+	registers []uint8
+}
+
+// NewSketch creates an empty sketch.
+func NewSketch() *Sketch {
+	return &Sketch{registers: make([]uint8, hllRegisters)}
+}
+
+// Add folds one element into the sketch.
+func (s *Sketch) Add(value string) {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	sum := mix64(h.Sum64())
+	idx := sum >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(sum<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// mix64 is the MurmurHash3 finalizer. FNV alone leaves the high bits
+// poorly avalanched for short keys, which skews the register index and
+// ruins the estimate; the finalizer spreads every input bit across the
+// whole word.
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Merge folds other into s; the result estimates the union.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, rank := range other.registers {
+		if rank > s.registers[i] {
+			s.registers[i] = rank
+		}
+	}
+}
+
+// Estimate returns the approximate number of distinct elements added,
+// using the standard bias correction and linear counting for small
+// cardinalities.
+func (s *Sketch) Estimate() uint64 {
+	alpha := 0.7213 / (1 + 1.079/float64(hllRegisters))
+	sum := 0.0
+	zeros := 0
+	for _, rank := range s.registers {
+		sum += 1 / float64(uint64(1)<<rank)
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha * hllRegisters * hllRegisters / sum
+	if estimate <= 2.5*hllRegisters && zeros > 0 {
+		estimate = hllRegisters * math.Log(float64(hllRegisters)/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Encode serializes the registers for persistence.
+func (s *Sketch) Encode() string {
+	return base64.StdEncoding.EncodeToString(s.registers)
+}
+
+// DecodeSketch rebuilds a sketch from Encode output.
+func DecodeSketch(encoded string) (*Sketch, bool) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != hllRegisters {
+		return nil, false
+	}
+	return &Sketch{registers: raw}, true
+}
@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// addN folds n distinct elements from a labelled stream into s.
+func addN(s *Sketch, label string, n int) {
+	for i := 0; i < n; i++ {
+		s.Add(fmt.Sprintf("%s-%d", label, i))
+	}
+}
+
+// within checks an estimate against a known cardinality with tolerance
+// expressed as a fraction; the standard error at this precision is
+// about 0.8%, so 3% leaves roughly three sigmas of headroom.
+func within(t *testing.T, got uint64, want int, tolerance float64) {
+	t.Helper()
+	if diff := math.Abs(float64(got) - float64(want)); diff > tolerance*float64(want) {
+		t.Errorf("estimate = %d, want %d ±%.0f%%", got, want, tolerance*100)
+	}
+}
+
+func TestEstimateAccuracy(t *testing.T) {
+	for _, n := range []int{100, 1_000, 10_000, 100_000} {
+		s := NewSketch()
+		addN(s, "wallet", n)
+		within(t, s.Estimate(), n, 0.03)
+	}
+}
+
+func TestEstimateEmpty(t *testing.T) {
+	if got := NewSketch().Estimate(); got != 0 {
+		t.Errorf("empty sketch estimate = %d, want 0", got)
+	}
+}
+
+func TestAddIdempotent(t *testing.T) {
+	s := NewSketch()
+	for i := 0; i < 1000; i++ {
+		s.Add("same-wallet")
+	}
+	if got := s.Estimate(); got != 1 {
+		t.Errorf("estimate after repeated adds = %d, want 1", got)
+	}
+}
+
+func TestMergeUnion(t *testing.T) {
+	a, b := NewSketch(), NewSketch()
+	addN(a, "wallet", 10_000)      // wallet-0 .. wallet-9999
+	addN(b, "wallet", 5_000)       // overlaps the first half of a
+	addN(b, "other-wallet", 5_000) // distinct
+	a.Merge(b)
+	within(t, a.Estimate(), 15_000, 0.03)
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	s := NewSketch()
+	addN(s, "wallet", 25_000)
+	decoded, ok := DecodeSketch(s.Encode())
+	if !ok {
+		t.Fatal("DecodeSketch rejected Encode output")
+	}
+	if got, want := decoded.Estimate(), s.Estimate(); got != want {
+		t.Errorf("decoded estimate = %d, want %d", got, want)
+	}
+	// A decoded sketch must stay mergeable and addable.
+	decoded.Add("one-more-wallet")
+	if decoded.Estimate() < s.Estimate() {
+		t.Error("decoded sketch lost registers")
+	}
+}
+
+func TestDecodeSketchRejects(t *testing.T) {
+	if _, ok := DecodeSketch("not base64!"); ok {
+		t.Error("DecodeSketch accepted invalid base64")
+	}
+	if _, ok := DecodeSketch("AAAA"); ok {
+		t.Error("DecodeSketch accepted a truncated register array")
+	}
+}
@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// LendingMarketStats aggregates normalized lending activity for one
+// market (reserve or bank) since startup.
+type LendingMarketStats struct {
+	Market   string `json:"market"`
+	Protocol string `json:"protocol"`
+
+	Deposits     uint64 `json:"deposits"`
+	Borrows      uint64 `json:"borrows"`
+	Repays       uint64 `json:"repays"`
+	Withdrawals  uint64 `json:"withdrawals"`
+	Liquidations uint64 `json:"liquidations"`
+
+	// Volumes are summed instruction amounts in the market's base
+	// units, decimal strings in JSON so busy markets cannot wrap
+	// uint64.
+	DepositVolume     solana.Amount `json:"deposit_volume"`
+	BorrowVolume      solana.Amount `json:"borrow_volume"`
+	RepayVolume       solana.Amount `json:"repay_volume"`
+	WithdrawVolume    solana.Amount `json:"withdraw_volume"`
+	LiquidationVolume solana.Amount `json:"liquidation_volume"`
+}
+
+// Lending maintains per-market counters over the decoded lending
+// stream. It is wired as a transaction inspector and is safe for
+// concurrent use.
+type Lending struct {
+	mu      sync.Mutex
+	markets map[string]*LendingMarketStats
+}
+
+// NewLending creates an empty aggregator.
+func NewLending() *Lending {
+	return &Lending{markets: make(map[string]*LendingMarketStats)}
+}
+
+// Observe folds one transaction's lending actions into the aggregates.
+func (l *Lending) Observe(tc *processor.TxContext) {
+	events := processor.DecodeLendingEvents(tc)
+	if len(events) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, event := range events {
+		stats := l.markets[event.Market]
+		if stats == nil {
+			stats = &LendingMarketStats{Market: event.Market, Protocol: event.Protocol}
+			l.markets[event.Market] = stats
+		}
+		switch event.Action {
+		case processor.LendingDeposit:
+			stats.Deposits++
+			stats.DepositVolume.AddUint64(event.Amount)
+		case processor.LendingBorrow:
+			stats.Borrows++
+			stats.BorrowVolume.AddUint64(event.Amount)
+		case processor.LendingRepay:
+			stats.Repays++
+			stats.RepayVolume.AddUint64(event.Amount)
+		case processor.LendingWithdraw:
+			stats.Withdrawals++
+			stats.WithdrawVolume.AddUint64(event.Amount)
+		case processor.LendingLiquidation:
+			stats.Liquidations++
+			stats.LiquidationVolume.AddUint64(event.Amount)
+		}
+	}
+}
+
+// Query returns per-market rows, most active markets first. An empty
+// protocol matches all.
+func (l *Lending) Query(protocol string, limit int) []LendingMarketStats {
+	if limit <= 0 {
+		limit = 100
+	}
+	l.mu.Lock()
+	out := make([]LendingMarketStats, 0, len(l.markets))
+	for _, stats := range l.markets {
+		if protocol != "" && stats.Protocol != protocol {
+			continue
+		}
+		out = append(out, *stats)
+	}
+	l.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		ti := out[i].Deposits + out[i].Borrows + out[i].Repays + out[i].Withdrawals + out[i].Liquidations
+		tj := out[j].Deposits + out[j].Borrows + out[j].Repays + out[j].Withdrawals + out[j].Liquidations
+		if ti != tj {
+			return ti > tj
+		}
+		return out[i].Market < out[j].Market
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
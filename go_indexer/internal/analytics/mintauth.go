@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// mintAuthorityKeep bounds per-mint history; authority changes are rare,
+// so the cap only matters for adversarial churn.
+const mintAuthorityKeep = 100
+
+// MintAuthorityEvent is one recorded mint or freeze authority change.
+type MintAuthorityEvent struct {
+	Slot      uint64    `json:"slot"`
+	Signature string    `json:"signature"`
+	BlockTime time.Time `json:"block_time"`
+	// Type is "mint" or "freeze".
+	Type      string `json:"type"`
+	Authority string `json:"authority"`
+	// NewAuthority empty means the authority was revoked.
+	NewAuthority string `json:"new_authority,omitempty"`
+}
+
+// MintAuthorities records SetAuthority history per mint. Authority
+// changes — especially a fresh mint authority on a traded token — are a
+// primary rug-pull indicator, so the history is kept queryable rather
+// than only alerting on. It is wired as a transaction inspector and is
+// safe for concurrent use.
+type MintAuthorities struct {
+	mu      sync.Mutex
+	history map[string][]MintAuthorityEvent
+}
+
+// NewMintAuthorities creates an empty tracker.
+func NewMintAuthorities() *MintAuthorities {
+	return &MintAuthorities{history: make(map[string][]MintAuthorityEvent)}
+}
+
+// Observe records any mint authority changes in one transaction.
+func (m *MintAuthorities) Observe(tc *processor.TxContext) {
+	changes := processor.DecodeMintAuthorityChanges(tc)
+	if len(changes) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range changes {
+		events := append(m.history[change.Mint], MintAuthorityEvent{
+			Slot:         tc.Slot,
+			Signature:    tc.Signature,
+			BlockTime:    tc.BlockTime,
+			Type:         change.Type,
+			Authority:    change.Authority,
+			NewAuthority: change.NewAuthority,
+		})
+		if len(events) > mintAuthorityKeep {
+			events = events[len(events)-mintAuthorityKeep:]
+		}
+		m.history[change.Mint] = events
+	}
+}
+
+// History returns the mint's recorded authority changes, slot-ascending.
+func (m *MintAuthorities) History(mint string) []MintAuthorityEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := append([]MintAuthorityEvent(nil), m.history[mint]...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot < out[j].Slot })
+	return out
+}
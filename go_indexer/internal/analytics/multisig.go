@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// multisigKeep bounds the proposals retained per multisig.
+const multisigKeep = 1_000
+
+// Proposal is one Squads transaction's lifecycle as observed on-chain.
+// Status follows the latest action: created, approved (at least one
+// vote), rejected, cancelled, or executed.
+type Proposal struct {
+	Proposal  string    `json:"proposal"`
+	Version   string    `json:"version"`
+	Status    string    `json:"status"`
+	Creator   string    `json:"creator,omitempty"`
+	Approvals []string  `json:"approvals,omitempty"` // voting members, in order seen
+	Slot      uint64    `json:"slot"`                // slot of the latest action
+	BlockTime time.Time `json:"block_time"`
+	Signature string    `json:"signature"` // of the latest action
+}
+
+// Multisigs tracks Squads proposal lifecycles per multisig. It is wired
+// as a transaction inspector and is safe for concurrent use.
+type Multisigs struct {
+	mu        sync.Mutex
+	proposals map[string]map[string]*Proposal // multisig -> proposal account
+}
+
+// NewMultisigs creates an empty tracker.
+func NewMultisigs() *Multisigs {
+	return &Multisigs{proposals: make(map[string]map[string]*Proposal)}
+}
+
+// Observe folds one transaction's Squads actions into the tracked
+// proposals.
+func (m *Multisigs) Observe(tc *processor.TxContext) {
+	actions := processor.DecodeMultisigActions(tc)
+	if len(actions) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, action := range actions {
+		byProposal := m.proposals[action.Multisig]
+		if byProposal == nil {
+			byProposal = make(map[string]*Proposal)
+			m.proposals[action.Multisig] = byProposal
+		}
+		p := byProposal[action.Proposal]
+		if p == nil {
+			if len(byProposal) >= multisigKeep {
+				m.evictOldest(byProposal)
+			}
+			p = &Proposal{Proposal: action.Proposal, Version: action.Version}
+			byProposal[action.Proposal] = p
+		}
+		p.Slot = tc.Slot
+		p.BlockTime = tc.BlockTime
+		p.Signature = tc.Signature
+		switch action.Action {
+		case processor.MultisigCreated:
+			p.Status = processor.MultisigCreated
+			p.Creator = action.Member
+		case processor.MultisigApproved:
+			p.Status = processor.MultisigApproved
+			if action.Member != "" {
+				p.Approvals = append(p.Approvals, action.Member)
+			}
+		default:
+			p.Status = action.Action
+		}
+	}
+}
+
+// evictOldest drops the proposal with the lowest slot. Callers hold
+// m.mu.
+func (m *Multisigs) evictOldest(byProposal map[string]*Proposal) {
+	var oldest string
+	var slot uint64
+	for key, p := range byProposal {
+		if oldest == "" || p.Slot < slot {
+			oldest, slot = key, p.Slot
+		}
+	}
+	delete(byProposal, oldest)
+}
+
+// Proposals returns a multisig's proposals, newest action first. The
+// second return reports whether the multisig has been seen at all.
+func (m *Multisigs) Proposals(multisig string, limit int) ([]Proposal, bool) {
+	if limit <= 0 {
+		limit = 100
+	}
+	m.mu.Lock()
+	byProposal, ok := m.proposals[multisig]
+	if !ok {
+		m.mu.Unlock()
+		return nil, false
+	}
+	out := make([]Proposal, 0, len(byProposal))
+	for _, p := range byProposal {
+		out = append(out, *p)
+	}
+	m.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot > out[j].Slot })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, true
+}
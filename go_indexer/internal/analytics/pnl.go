@@ -0,0 +1,201 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// PriceFunc resolves a mint to its current price in quote units per UI
+// token, typically Pools.LatestPrice. Unpriced mints report false and
+// are carried at zero cost basis.
+type PriceFunc func(mint string) (float64, bool)
+
+// lot is one FIFO cost-basis entry: tokens acquired together at one
+// price.
+type lot struct {
+	amount      uint64 // base units remaining in the lot
+	costPerUnit float64
+	acquired    time.Time
+}
+
+// pnlPosition is one wallet's holding of one mint.
+type pnlPosition struct {
+	decimals uint8
+	lots     []lot
+	realized float64
+}
+
+// PositionPnL is one mint's line in a wallet's PnL report. Balance is
+// a decimal string in JSON: open lots can sum past uint64.
+type PositionPnL struct {
+	Mint     string        `json:"mint"`
+	Balance  solana.Amount `json:"balance"`
+	Decimals uint8         `json:"decimals"`
+	// CostBasis is the quote value of the open lots at their purchase
+	// prices; MarkPrice is the current price used for Unrealized, zero
+	// when no configured pool prices the mint.
+	CostBasis  float64 `json:"cost_basis"`
+	MarkPrice  float64 `json:"mark_price"`
+	Realized   float64 `json:"realized"`
+	Unrealized float64 `json:"unrealized"`
+}
+
+// WalletPnL is the full report for one tracked wallet.
+type WalletPnL struct {
+	Wallet    string        `json:"wallet"`
+	Positions []PositionPnL `json:"positions"`
+}
+
+// PnL maintains per-wallet FIFO cost basis and realized PnL for the
+// wallets listed in PNL_WALLETS. Balance changes are taken from token
+// balance meta, so swaps and transfers through any program are covered.
+// The computation is incremental: each transaction folds into the open
+// lots as it is indexed, nothing is recomputed from storage. It is
+// wired as a transaction inspector and is safe for concurrent use.
+type PnL struct {
+	mu        sync.Mutex
+	price     PriceFunc
+	positions map[string]map[string]*pnlPosition // wallet -> mint
+}
+
+// NewPnL creates a tracker for the given wallets. price may be nil,
+// leaving every lot at zero cost basis.
+func NewPnL(wallets []string, price PriceFunc) *PnL {
+	p := &PnL{price: price, positions: make(map[string]map[string]*pnlPosition)}
+	for _, wallet := range wallets {
+		p.positions[wallet] = make(map[string]*pnlPosition)
+	}
+	return p
+}
+
+// Observe folds one transaction's balance changes into the tracked
+// wallets' lots.
+func (p *PnL) Observe(tc *processor.TxContext) {
+	meta := tc.Tx.Meta
+	if meta == nil || !meta.Succeeded() {
+		return
+	}
+	type balance struct {
+		pre, post uint64
+		decimals  uint8
+	}
+	deltas := map[[2]string]*balance{} // (owner, mint)
+	record := func(owner, mint, raw string, decimals uint8, post bool) {
+		if owner == "" || mint == "" {
+			return
+		}
+		if _, tracked := p.positions[owner]; !tracked {
+			return
+		}
+		amount, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return
+		}
+		key := [2]string{owner, mint}
+		b := deltas[key]
+		if b == nil {
+			b = &balance{decimals: decimals}
+			deltas[key] = b
+		}
+		if post {
+			b.post = amount
+		} else {
+			b.pre = amount
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, tb := range meta.PreTokenBalances {
+		record(tb.Owner, tb.Mint, tb.UITokenAmount.Amount, tb.UITokenAmount.Decimals, false)
+	}
+	for _, tb := range meta.PostTokenBalances {
+		record(tb.Owner, tb.Mint, tb.UITokenAmount.Amount, tb.UITokenAmount.Decimals, true)
+	}
+	for key, b := range deltas {
+		wallet, mint := key[0], key[1]
+		pos := p.positions[wallet][mint]
+		if pos == nil {
+			pos = &pnlPosition{decimals: b.decimals}
+			p.positions[wallet][mint] = pos
+		}
+		switch {
+		case b.post > b.pre:
+			pos.acquire(b.post-b.pre, p.markPrice(mint), tc.BlockTime)
+		case b.pre > b.post:
+			pos.dispose(b.pre-b.post, p.markPrice(mint))
+		}
+	}
+}
+
+// markPrice resolves the current price for a mint, zero when unknown.
+func (p *PnL) markPrice(mint string) float64 {
+	if p.price == nil {
+		return 0
+	}
+	price, _ := p.price(mint)
+	return price
+}
+
+// acquire opens a new FIFO lot.
+func (pos *pnlPosition) acquire(amount uint64, price float64, when time.Time) {
+	pos.lots = append(pos.lots, lot{amount: amount, costPerUnit: price, acquired: when})
+}
+
+// dispose consumes lots oldest-first, realizing the difference between
+// the proceeds price and each lot's cost.
+func (pos *pnlPosition) dispose(amount uint64, price float64) {
+	ui := math.Pow10(int(pos.decimals))
+	for amount > 0 && len(pos.lots) > 0 {
+		l := &pos.lots[0]
+		take := amount
+		if l.amount < take {
+			take = l.amount
+		}
+		pos.realized += (price - l.costPerUnit) * float64(take) / ui
+		l.amount -= take
+		amount -= take
+		if l.amount == 0 {
+			pos.lots = pos.lots[1:]
+		}
+	}
+	// A disposal exceeding the open lots means acquisition history
+	// predates indexing; the excess is carried at zero basis.
+	if amount > 0 {
+		pos.realized += price * float64(amount) / ui
+	}
+}
+
+// Query returns a wallet's PnL report. It reports false for wallets not
+// listed in PNL_WALLETS.
+func (p *PnL) Query(wallet string) (WalletPnL, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	positions, ok := p.positions[wallet]
+	if !ok {
+		return WalletPnL{}, false
+	}
+	out := WalletPnL{Wallet: wallet, Positions: []PositionPnL{}}
+	for mint, pos := range positions {
+		ui := math.Pow10(int(pos.decimals))
+		line := PositionPnL{
+			Mint:     mint,
+			Decimals: pos.decimals,
+			Realized: pos.realized,
+		}
+		line.MarkPrice = p.markPrice(mint)
+		for _, l := range pos.lots {
+			line.Balance.AddUint64(l.amount)
+			line.CostBasis += l.costPerUnit * float64(l.amount) / ui
+		}
+		line.Unrealized = line.MarkPrice*line.Balance.Float64()/ui - line.CostBasis
+		out.Positions = append(out.Positions, line)
+	}
+	sort.Slice(out.Positions, func(i, j int) bool { return out.Positions[i].Mint < out.Positions[j].Mint })
+	return out, true
+}
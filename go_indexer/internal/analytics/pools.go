@@ -0,0 +1,218 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// poolSnapshotKeep bounds each pool's in-memory history.
+const poolSnapshotKeep = 10_000
+
+// PoolConfig declares one watched AMM pool by its vault token accounts.
+// The vault layout is what Raydium and Orca pools have in common, so one
+// declaration shape covers both: reserves are read from the vaults'
+// post-transaction token balances, no program-specific state decoding.
+type PoolConfig struct {
+	Address    string `json:"address"`
+	Name       string `json:"name,omitempty"` // e.g. "SOL/USDC"
+	BaseVault  string `json:"base_vault"`
+	QuoteVault string `json:"quote_vault"`
+	// Optional mint declarations; when set, the pool's mid-price is
+	// offered as a price source for the base mint (see LatestPrice).
+	BaseMint  string `json:"base_mint,omitempty"`
+	QuoteMint string `json:"quote_mint,omitempty"`
+}
+
+// LoadPoolConfigs reads a JSON array of pool declarations.
+func LoadPoolConfigs(path string) ([]PoolConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pools file: %w", err)
+	}
+	var pools []PoolConfig
+	if err := json.Unmarshal(raw, &pools); err != nil {
+		return nil, fmt.Errorf("pools file: %w", err)
+	}
+	for i, p := range pools {
+		if p.Address == "" || p.BaseVault == "" || p.QuoteVault == "" {
+			return nil, fmt.Errorf("pools file: entry %d needs address, base_vault, and quote_vault", i)
+		}
+	}
+	return pools, nil
+}
+
+// PoolSnapshot is one pool's reserves at one slot, taken from the vault
+// balances after a transaction touched them.
+type PoolSnapshot struct {
+	Slot      uint64    `json:"slot"`
+	BlockTime time.Time `json:"block_time"`
+	// Raw vault amounts, in base units.
+	BaseReserve  uint64 `json:"base_reserve"`
+	QuoteReserve uint64 `json:"quote_reserve"`
+	// MidPrice is quote per base, decimal-adjusted from the vault
+	// balances — the constant-product mid, not an execution price.
+	MidPrice float64 `json:"mid_price"`
+	// TVL is the pool's value in quote units: twice the quote reserve,
+	// assuming a balanced pool.
+	TVL float64 `json:"tvl"`
+}
+
+// poolState is one watched pool's config plus rolling history.
+type poolState struct {
+	cfg       PoolConfig
+	snapshots []PoolSnapshot
+}
+
+// Pools maintains per-slot reserve snapshots for the configured AMM
+// pools. It is wired as a transaction inspector and is safe for
+// concurrent use.
+type Pools struct {
+	mu         sync.Mutex
+	byVault    map[string]*poolState // both vaults point at the state
+	byPool     map[string]*poolState
+	byBaseMint map[string][]*poolState
+}
+
+// NewPools creates a tracker for the configured pools.
+func NewPools(configs []PoolConfig) *Pools {
+	p := &Pools{
+		byVault:    make(map[string]*poolState),
+		byPool:     make(map[string]*poolState),
+		byBaseMint: make(map[string][]*poolState),
+	}
+	for _, cfg := range configs {
+		state := &poolState{cfg: cfg}
+		p.byPool[cfg.Address] = state
+		p.byVault[cfg.BaseVault] = state
+		p.byVault[cfg.QuoteVault] = state
+		if cfg.BaseMint != "" {
+			p.byBaseMint[cfg.BaseMint] = append(p.byBaseMint[cfg.BaseMint], state)
+		}
+	}
+	return p
+}
+
+// Observe snapshots any watched pool whose vaults appear in the
+// transaction's post token balances.
+func (p *Pools) Observe(tc *processor.TxContext) {
+	meta := tc.Tx.Meta
+	if meta == nil || !meta.Succeeded() || len(meta.PostTokenBalances) == 0 {
+		return
+	}
+	keys := tc.Tx.Transaction.Message.AccountKeys
+	keys = append(append(append([]string(nil), keys...),
+		meta.LoadedAddresses.Writable...), meta.LoadedAddresses.Readonly...)
+
+	// Collect post balances per touched pool; both vaults must be
+	// present for a consistent snapshot.
+	type reserves struct {
+		base, quote       uint64
+		baseOK, quoteOK   bool
+		baseDec, quoteDec uint8
+	}
+	touched := map[*poolState]*reserves{}
+	for _, tb := range meta.PostTokenBalances {
+		if tb.AccountIndex < 0 || tb.AccountIndex >= len(keys) {
+			continue
+		}
+		account := keys[tb.AccountIndex]
+		state, ok := p.byVault[account]
+		if !ok {
+			continue
+		}
+		amount, err := strconv.ParseUint(tb.UITokenAmount.Amount, 10, 64)
+		if err != nil {
+			continue
+		}
+		r := touched[state]
+		if r == nil {
+			r = &reserves{}
+			touched[state] = r
+		}
+		if account == state.cfg.BaseVault {
+			r.base, r.baseDec, r.baseOK = amount, tb.UITokenAmount.Decimals, true
+		} else {
+			r.quote, r.quoteDec, r.quoteOK = amount, tb.UITokenAmount.Decimals, true
+		}
+	}
+	if len(touched) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for state, r := range touched {
+		if !r.baseOK || !r.quoteOK || r.base == 0 {
+			continue
+		}
+		baseUI := float64(r.base) / math.Pow10(int(r.baseDec))
+		quoteUI := float64(r.quote) / math.Pow10(int(r.quoteDec))
+		state.snapshots = append(state.snapshots, PoolSnapshot{
+			Slot:         tc.Slot,
+			BlockTime:    tc.BlockTime,
+			BaseReserve:  r.base,
+			QuoteReserve: r.quote,
+			MidPrice:     quoteUI / baseUI,
+			TVL:          2 * quoteUI,
+		})
+		if len(state.snapshots) > poolSnapshotKeep {
+			state.snapshots = state.snapshots[len(state.snapshots)-poolSnapshotKeep:]
+		}
+	}
+}
+
+// LatestPrice returns the freshest mid-price for a mint among pools
+// declaring it as their base mint, in the pool's quote units. It
+// reports false for mints no configured pool prices.
+func (p *Pools) LatestPrice(mint string) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var price float64
+	var slot uint64
+	found := false
+	for _, state := range p.byBaseMint[mint] {
+		for _, snap := range state.snapshots {
+			if !found || snap.Slot > slot {
+				price, slot, found = snap.MidPrice, snap.Slot, true
+			}
+		}
+	}
+	return price, found
+}
+
+// History returns a pool's snapshots, slot-ascending, narrowed to the
+// slot range. limit 0 means no cap; a negative limit is treated as 0.
+// The second return reports whether the pool is configured at all.
+func (p *Pools) History(address string, fromSlot, toSlot uint64, limit int) ([]PoolSnapshot, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, ok := p.byPool[address]
+	if !ok {
+		return nil, false
+	}
+	// Concurrent block workers may append slightly out of slot order.
+	snapshots := append([]PoolSnapshot(nil), state.snapshots...)
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Slot < snapshots[j].Slot })
+	var out []PoolSnapshot
+	for _, snap := range snapshots {
+		if fromSlot > 0 && snap.Slot < fromSlot {
+			continue
+		}
+		if toSlot > 0 && snap.Slot > toSlot {
+			continue
+		}
+		out = append(out, snap)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,184 @@
+// Package analytics maintains incremental rollup statistics over the
+// indexed stream, turning the indexer into a small program-usage
+// analytics backend. Rollups are updated per transaction as blocks are
+// processed; nothing is recomputed from storage.
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// Rollup periods.
+const (
+	PeriodHour = "hour"
+	PeriodDay  = "day"
+)
+
+// Retention per period; older buckets are pruned as new ones appear.
+const (
+	hourRetention = 7 * 24 * time.Hour
+	dayRetention  = 90 * 24 * time.Hour
+)
+
+// ProgramStats is one rollup row: activity of a program in one period.
+type ProgramStats struct {
+	Program      string    `json:"program"`
+	Period       string    `json:"period"`
+	PeriodStart  time.Time `json:"period_start"`
+	Transactions uint64    `json:"transactions"`
+	Instructions uint64    `json:"instructions"`
+	Failures     uint64    `json:"failures"`
+	// ComputeUnits is the total consumed by transactions invoking the
+	// program; a transaction touching several programs counts fully
+	// toward each.
+	ComputeUnits uint64 `json:"compute_units"`
+	// UniqueUsers counts distinct fee payers seen in the period.
+	UniqueUsers int `json:"unique_users"`
+}
+
+type bucketKey struct {
+	program string
+	period  string
+	start   int64 // unix seconds of the period start
+}
+
+type bucket struct {
+	transactions uint64
+	instructions uint64
+	failures     uint64
+	computeUnits uint64
+	users        map[string]struct{}
+}
+
+// ProgramAggregator maintains hourly and daily per-program rollups. It
+// is safe for concurrent use by the indexer workers.
+type ProgramAggregator struct {
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+// NewProgramAggregator creates an empty aggregator.
+func NewProgramAggregator() *ProgramAggregator {
+	return &ProgramAggregator{buckets: make(map[bucketKey]*bucket)}
+}
+
+// Observe folds one transaction into the rollups. It is wired as a
+// transaction inspector on the indexer.
+func (a *ProgramAggregator) Observe(tc *processor.TxContext) {
+	when := tc.BlockTime
+	if when.IsZero() {
+		when = time.Now().UTC()
+	}
+	msg := &tc.Tx.Transaction.Message
+	instructions := make(map[string]uint64)
+	for _, ix := range msg.Instructions {
+		if program := msg.ProgramID(ix); program != "" {
+			instructions[program]++
+		}
+	}
+	if len(instructions) == 0 {
+		return
+	}
+	var feePayer string
+	if len(msg.AccountKeys) > 0 {
+		feePayer = msg.AccountKeys[0]
+	}
+	failed := tc.Tx.Meta != nil && !tc.Tx.Meta.Succeeded()
+	var computeUnits uint64
+	if tc.Tx.Meta != nil {
+		computeUnits = tc.Tx.Meta.ComputeUnitsConsumed
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for program, count := range instructions {
+		for _, period := range []string{PeriodHour, PeriodDay} {
+			b := a.bucket(program, period, when)
+			b.transactions++
+			b.instructions += count
+			b.computeUnits += computeUnits
+			if failed {
+				b.failures++
+			}
+			if feePayer != "" {
+				b.users[feePayer] = struct{}{}
+			}
+		}
+	}
+}
+
+// bucket returns the rollup bucket for (program, period, when), creating
+// it and pruning expired buckets on first use. Callers hold a.mu.
+func (a *ProgramAggregator) bucket(program, period string, when time.Time) *bucket {
+	start := when.Truncate(time.Hour)
+	if period == PeriodDay {
+		start = when.Truncate(24 * time.Hour)
+	}
+	key := bucketKey{program: program, period: period, start: start.Unix()}
+	b, ok := a.buckets[key]
+	if !ok {
+		b = &bucket{users: make(map[string]struct{})}
+		a.buckets[key] = b
+		a.prune(when)
+	}
+	return b
+}
+
+// prune drops buckets past their period's retention. Callers hold a.mu.
+func (a *ProgramAggregator) prune(now time.Time) {
+	for key := range a.buckets {
+		retention := hourRetention
+		if key.period == PeriodDay {
+			retention = dayRetention
+		}
+		if time.Unix(key.start, 0).Before(now.Add(-retention)) {
+			delete(a.buckets, key)
+		}
+	}
+}
+
+// Query returns rollup rows for the period, newest first, programs
+// sorted by transactions within a period. An empty program matches all.
+func (a *ProgramAggregator) Query(program, period string, limit int) []ProgramStats {
+	if period == "" {
+		period = PeriodHour
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	a.mu.Lock()
+	out := make([]ProgramStats, 0)
+	for key, b := range a.buckets {
+		if key.period != period || (program != "" && key.program != program) {
+			continue
+		}
+		out = append(out, ProgramStats{
+			Program:      key.program,
+			Period:       key.period,
+			PeriodStart:  time.Unix(key.start, 0).UTC(),
+			Transactions: b.transactions,
+			Instructions: b.instructions,
+			Failures:     b.failures,
+			ComputeUnits: b.computeUnits,
+			UniqueUsers:  len(b.users),
+		})
+	}
+	a.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].PeriodStart.Equal(out[j].PeriodStart) {
+			return out[i].PeriodStart.After(out[j].PeriodStart)
+		}
+		if out[i].Transactions != out[j].Transactions {
+			return out[i].Transactions > out[j].Transactions
+		}
+		return out[i].Program < out[j].Program
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// backfillPageSize is how many stored transactions one backfill page
+// folds into the rollups.
+const backfillPageSize = 1000
+
+// ChainStats is one chain-wide rollup row: activity across the whole
+// indexed stream in one period.
+type ChainStats struct {
+	Period       string    `json:"period"`
+	PeriodStart  time.Time `json:"period_start"`
+	Transactions uint64    `json:"transactions"`
+	Failures     uint64    `json:"failures"`
+	// Fees is the total lamports paid in the period, a decimal string
+	// in JSON so aggregates past uint64 survive intact.
+	Fees solana.Amount `json:"fees"`
+	// ActiveAddresses counts distinct account keys that appeared in a
+	// transaction during the period.
+	ActiveAddresses int `json:"active_addresses"`
+	// NewTokenAccounts counts token account initializations.
+	NewTokenAccounts uint64 `json:"new_token_accounts"`
+}
+
+type chainKey struct {
+	period string
+	start  int64 // unix seconds of the period start
+}
+
+type chainBucket struct {
+	transactions     uint64
+	failures         uint64
+	fees             solana.Amount
+	addresses        map[string]struct{}
+	newTokenAccounts uint64
+}
+
+// Rollups maintains hourly and daily chain-wide aggregates (transaction
+// counts, fees, active addresses, new token accounts) incrementally as
+// blocks are indexed, so dashboards never run heavy GROUP BYs at query
+// time. It is safe for concurrent use by the indexer workers.
+type Rollups struct {
+	mu      sync.Mutex
+	buckets map[chainKey]*chainBucket
+}
+
+// NewRollups creates an empty rollup engine.
+func NewRollups() *Rollups {
+	return &Rollups{buckets: make(map[chainKey]*chainBucket)}
+}
+
+// Observe folds one transaction into the rollups. It is wired as a
+// transaction inspector on the indexer.
+func (r *Rollups) Observe(tc *processor.TxContext) {
+	failed := tc.Tx.Meta != nil && !tc.Tx.Meta.Succeeded()
+	var fee uint64
+	if tc.Tx.Meta != nil {
+		fee = tc.Tx.Meta.Fee
+	}
+	r.fold(tc.BlockTime, failed, fee, tc.Tx.Transaction.Message.AccountKeys)
+}
+
+// ObserveOutput folds processor output into the rollups; token account
+// initializations are only visible here, not on the raw transaction.
+func (r *Rollups) ObserveOutput(tc *processor.TxContext, out *processor.Output) {
+	created := uint64(0)
+	for _, update := range out.TokenAccountUpdates {
+		if update.Kind == "initialized" {
+			created++
+		}
+	}
+	if created == 0 {
+		return
+	}
+	when := bucketTime(tc.BlockTime)
+	r.mu.Lock()
+	for _, period := range []string{PeriodHour, PeriodDay} {
+		r.bucket(period, when).newTokenAccounts += created
+	}
+	r.mu.Unlock()
+}
+
+// fold records one transaction's metrics into both periods.
+func (r *Rollups) fold(when time.Time, failed bool, fee uint64, addresses []string) {
+	when = bucketTime(when)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, period := range []string{PeriodHour, PeriodDay} {
+		b := r.bucket(period, when)
+		b.transactions++
+		if failed {
+			b.failures++
+		}
+		b.fees.AddUint64(fee)
+		for _, address := range addresses {
+			if address != "" {
+				b.addresses[address] = struct{}{}
+			}
+		}
+	}
+}
+
+func bucketTime(when time.Time) time.Time {
+	if when.IsZero() {
+		return time.Now().UTC()
+	}
+	return when
+}
+
+// bucket returns the rollup bucket for (period, when), creating it and
+// pruning expired buckets on first use. Callers hold r.mu.
+func (r *Rollups) bucket(period string, when time.Time) *chainBucket {
+	start := when.Truncate(time.Hour)
+	if period == PeriodDay {
+		start = when.Truncate(24 * time.Hour)
+	}
+	key := chainKey{period: period, start: start.Unix()}
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &chainBucket{addresses: make(map[string]struct{})}
+		r.buckets[key] = b
+		r.prune(when)
+	}
+	return b
+}
+
+// prune drops buckets past their period's retention. Callers hold r.mu.
+func (r *Rollups) prune(now time.Time) {
+	for key := range r.buckets {
+		retention := hourRetention
+		if key.period == PeriodDay {
+			retention = dayRetention
+		}
+		if time.Unix(key.start, 0).Before(now.Add(-retention)) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// Backfill folds the transactions already in the store into the
+// rollups, so a restarted indexer serves history instead of starting
+// cold. Stored rows only carry the fee payer, so backfilled
+// active-address counts undercount relative to live observation.
+func (r *Rollups) Backfill(ctx context.Context, store storage.Store, log *slog.Logger) {
+	filter := storage.TransactionFilter{Limit: backfillPageSize}
+	total := 0
+	for {
+		page, err := store.SearchTransactions(ctx, filter)
+		if err != nil {
+			log.Warn("rollup backfill aborted", "error", err)
+			return
+		}
+		for _, tx := range page {
+			var addresses []string
+			if tx.Signer != "" {
+				addresses = []string{tx.Signer}
+			}
+			r.fold(tx.BlockTime, !tx.Success, tx.Fee, addresses)
+		}
+		total += len(page)
+		if len(page) < backfillPageSize {
+			break
+		}
+		filter.Offset += backfillPageSize
+	}
+	if total > 0 {
+		log.Info("rollups backfilled", "transactions", total)
+	}
+}
+
+// Query returns chain rollup rows for the period, newest first.
+func (r *Rollups) Query(period string, limit int) []ChainStats {
+	if period == "" {
+		period = PeriodHour
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	r.mu.Lock()
+	out := make([]ChainStats, 0)
+	for key, b := range r.buckets {
+		if key.period != period {
+			continue
+		}
+		out = append(out, ChainStats{
+			Period:           key.period,
+			PeriodStart:      time.Unix(key.start, 0).UTC(),
+			Transactions:     b.transactions,
+			Failures:         b.failures,
+			Fees:             b.fees,
+			ActiveAddresses:  len(b.addresses),
+			NewTokenAccounts: b.newTokenAccounts,
+		})
+	}
+	r.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].PeriodStart.After(out[j].PeriodStart)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
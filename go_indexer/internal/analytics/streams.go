@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// streamsKeep bounds the streams retained in memory.
+const streamsKeep = 10_000
+
+// Stream is one streaming payment's observed state. Remaining is the
+// deposited amount minus withdrawals, in the stream mint's base units;
+// it does not account for deposits made before indexing started.
+type Stream struct {
+	Address   string `json:"address"`
+	Protocol  string `json:"protocol"`
+	Status    string `json:"status"` // active, cancelled
+	Sender    string `json:"sender,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
+	Deposited uint64 `json:"deposited"`
+	Withdrawn uint64 `json:"withdrawn"`
+	Remaining uint64 `json:"remaining"`
+
+	// Vesting schedule, when the create instruction carried one.
+	Start           uint64 `json:"start,omitempty"`
+	Period          uint64 `json:"period,omitempty"`
+	AmountPerPeriod uint64 `json:"amount_per_period,omitempty"`
+	Cliff           uint64 `json:"cliff,omitempty"`
+
+	Slot      uint64    `json:"slot"` // slot of the latest action
+	BlockTime time.Time `json:"block_time"`
+}
+
+// Streams tracks streaming payments per stream and recipient. It is
+// wired as a transaction inspector and is safe for concurrent use.
+type Streams struct {
+	mu          sync.Mutex
+	byStream    map[string]*Stream
+	byRecipient map[string][]*Stream
+}
+
+// NewStreams creates an empty tracker.
+func NewStreams() *Streams {
+	return &Streams{
+		byStream:    make(map[string]*Stream),
+		byRecipient: make(map[string][]*Stream),
+	}
+}
+
+// Observe folds one transaction's stream actions into the tracker.
+func (s *Streams) Observe(tc *processor.TxContext) {
+	actions := processor.DecodeStreamActions(tc)
+	if len(actions) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, action := range actions {
+		stream := s.byStream[action.Stream]
+		if stream == nil {
+			if len(s.byStream) >= streamsKeep {
+				s.evictOldest()
+			}
+			stream = &Stream{
+				Address:  action.Stream,
+				Protocol: action.Protocol,
+				Status:   "active",
+			}
+			s.byStream[action.Stream] = stream
+		}
+		if action.Sender != "" {
+			stream.Sender = action.Sender
+		}
+		if action.Recipient != "" && stream.Recipient == "" {
+			stream.Recipient = action.Recipient
+			s.byRecipient[action.Recipient] = append(s.byRecipient[action.Recipient], stream)
+		}
+		stream.Slot = tc.Slot
+		stream.BlockTime = tc.BlockTime
+		switch action.Action {
+		case processor.StreamCreated:
+			stream.Deposited += action.Amount
+			stream.Start = action.Start
+			stream.Period = action.Period
+			stream.AmountPerPeriod = action.AmountPerPeriod
+			stream.Cliff = action.Cliff
+		case processor.StreamToppedUp:
+			stream.Deposited += action.Amount
+		case processor.StreamWithdrawn:
+			stream.Withdrawn += action.Amount
+		case processor.StreamCancelled:
+			stream.Status = "cancelled"
+		}
+		if stream.Deposited > stream.Withdrawn {
+			stream.Remaining = stream.Deposited - stream.Withdrawn
+		} else {
+			stream.Remaining = 0
+		}
+	}
+}
+
+// evictOldest drops the stream with the lowest slot. Callers hold s.mu.
+func (s *Streams) evictOldest() {
+	var oldest string
+	var slot uint64
+	for key, stream := range s.byStream {
+		if oldest == "" || stream.Slot < slot {
+			oldest, slot = key, stream.Slot
+		}
+	}
+	stream := s.byStream[oldest]
+	delete(s.byStream, oldest)
+	if stream == nil || stream.Recipient == "" {
+		return
+	}
+	refs := s.byRecipient[stream.Recipient]
+	for i, ref := range refs {
+		if ref == stream {
+			s.byRecipient[stream.Recipient] = append(refs[:i], refs[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns one stream by its metadata account.
+func (s *Streams) Get(address string) (Stream, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream, ok := s.byStream[address]
+	if !ok {
+		return Stream{}, false
+	}
+	return *stream, true
+}
+
+// List returns streams, newest action first, optionally narrowed to a
+// recipient or to active streams only.
+func (s *Streams) List(recipient string, activeOnly bool, limit int) []Stream {
+	if limit <= 0 {
+		limit = 100
+	}
+	s.mu.Lock()
+	var candidates []*Stream
+	if recipient != "" {
+		candidates = s.byRecipient[recipient]
+	} else {
+		candidates = make([]*Stream, 0, len(s.byStream))
+		for _, stream := range s.byStream {
+			candidates = append(candidates, stream)
+		}
+	}
+	out := make([]Stream, 0, len(candidates))
+	for _, stream := range candidates {
+		if activeOnly && stream.Status != "active" {
+			continue
+		}
+		out = append(out, *stream)
+	}
+	s.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot > out[j].Slot })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// epochSlots is the number of slots per epoch on mainnet and testnet.
+const epochSlots = 432000
+
+// validatorEpochKeep bounds memory: the current epoch plus three past
+// epochs are retained.
+const validatorEpochKeep = 4
+
+// ValidatorStats is one per-validator, per-epoch consensus summary. All
+// figures are relative to the slots this indexer actually observed in
+// the epoch, so they are participation estimates, not ledger truth.
+type ValidatorStats struct {
+	VoteAccount string `json:"vote_account"`
+	Epoch       uint64 `json:"epoch"`
+	// Votes counts landed vote transactions.
+	Votes uint64 `json:"votes"`
+	// MeanLatencySlots is the average distance between the slot a vote
+	// landed in and the newest slot it voted on.
+	MeanLatencySlots float64 `json:"mean_latency_slots"`
+	LastVotedSlot    uint64  `json:"last_voted_slot"`
+	// MissedVotes is the observed slot window minus landed votes.
+	MissedVotes uint64 `json:"missed_votes"`
+	// Participation is votes divided by the observed slot window.
+	Participation float64 `json:"participation"`
+}
+
+type validatorKey struct {
+	account string
+	epoch   uint64
+}
+
+type validatorBucket struct {
+	votes      uint64
+	latencySum uint64
+	lastVoted  uint64
+}
+
+type epochWindow struct {
+	minSlot uint64
+	maxSlot uint64
+}
+
+// Validators folds vote transactions into per-validator, per-epoch
+// latency and participation statistics. It is wired as a transaction
+// inspector when INDEX_VOTES is enabled and is safe for concurrent use.
+type Validators struct {
+	mu      sync.Mutex
+	buckets map[validatorKey]*validatorBucket
+	windows map[uint64]*epochWindow
+}
+
+// NewValidators creates an empty aggregator.
+func NewValidators() *Validators {
+	return &Validators{
+		buckets: make(map[validatorKey]*validatorBucket),
+		windows: make(map[uint64]*epochWindow),
+	}
+}
+
+// Observe folds one transaction into the statistics. Non-vote and failed
+// transactions are ignored.
+func (v *Validators) Observe(tc *processor.TxContext) {
+	if tc.Tx.Meta != nil && !tc.Tx.Meta.Succeeded() {
+		return
+	}
+	info, ok := tc.Tx.Transaction.Message.VoteInfo()
+	if !ok {
+		return
+	}
+	epoch := tc.Slot / epochSlots
+	var latency uint64
+	if tc.Slot > info.VotedSlot {
+		latency = tc.Slot - info.VotedSlot
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w := v.windows[epoch]
+	if w == nil {
+		w = &epochWindow{minSlot: tc.Slot, maxSlot: tc.Slot}
+		v.windows[epoch] = w
+		v.prune(epoch)
+	}
+	if tc.Slot < w.minSlot {
+		w.minSlot = tc.Slot
+	}
+	if tc.Slot > w.maxSlot {
+		w.maxSlot = tc.Slot
+	}
+	key := validatorKey{account: info.VoteAccount, epoch: epoch}
+	b := v.buckets[key]
+	if b == nil {
+		b = &validatorBucket{}
+		v.buckets[key] = b
+	}
+	b.votes++
+	b.latencySum += latency
+	if info.VotedSlot > b.lastVoted {
+		b.lastVoted = info.VotedSlot
+	}
+}
+
+// prune drops epochs past the retention window. Callers hold v.mu.
+func (v *Validators) prune(latest uint64) {
+	if latest < validatorEpochKeep {
+		return
+	}
+	cutoff := latest - validatorEpochKeep + 1
+	for epoch := range v.windows {
+		if epoch < cutoff {
+			delete(v.windows, epoch)
+		}
+	}
+	for key := range v.buckets {
+		if key.epoch < cutoff {
+			delete(v.buckets, key)
+		}
+	}
+}
+
+// Query returns statistics for the epoch sorted by votes, most active
+// first. Epoch 0 selects the newest epoch observed.
+func (v *Validators) Query(epoch uint64, limit int) []ValidatorStats {
+	if limit <= 0 {
+		limit = 100
+	}
+	v.mu.Lock()
+	if epoch == 0 {
+		for e := range v.windows {
+			if e > epoch {
+				epoch = e
+			}
+		}
+	}
+	var window uint64
+	if w := v.windows[epoch]; w != nil {
+		window = w.maxSlot - w.minSlot + 1
+	}
+	out := make([]ValidatorStats, 0)
+	for key, b := range v.buckets {
+		if key.epoch != epoch {
+			continue
+		}
+		stats := ValidatorStats{
+			VoteAccount:   key.account,
+			Epoch:         key.epoch,
+			Votes:         b.votes,
+			LastVotedSlot: b.lastVoted,
+		}
+		if b.votes > 0 {
+			stats.MeanLatencySlots = float64(b.latencySum) / float64(b.votes)
+		}
+		if window > 0 {
+			if b.votes < window {
+				stats.MissedVotes = window - b.votes
+			}
+			stats.Participation = float64(b.votes) / float64(window)
+			if stats.Participation > 1 {
+				stats.Participation = 1
+			}
+		}
+		out = append(out, stats)
+	}
+	v.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Votes != out[j].Votes {
+			return out[i].Votes > out[j].Votes
+		}
+		return out[i].VoteAccount < out[j].VoteAccount
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountindex"
+)
+
+// SetAccountIndex exposes the inverted account index under
+// /api/v1/addresses/{address}/transactions.
+func (s *Server) SetAccountIndex(ix *accountindex.Index) {
+	s.acctIndex = ix
+}
+
+// handleAddressTransactions serves
+// GET /api/v1/addresses/{address}/transactions.
+func (s *Server) handleAddressTransactions(w http.ResponseWriter, r *http.Request) {
+	if s.acctIndex == nil {
+		writeError(w, http.StatusNotFound, errors.New("account index not enabled"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/addresses/")
+	address, ok := strings.CutSuffix(rest, "/transactions")
+	if !ok || address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	q := r.URL.Query()
+	switch role := q.Get("role"); role {
+	case "", accountindex.RoleSigner, accountindex.RoleProgram,
+		accountindex.RoleLoaded, accountindex.RoleAccount:
+		entries := s.acctIndex.Lookup(address, role,
+			queryUint(q.Get("from_slot")), queryUint(q.Get("to_slot")),
+			queryInt(q.Get("limit"), 100))
+		if entries == nil {
+			entries = []accountindex.Entry{}
+		}
+		writeJSON(w, http.StatusOK, entries)
+	default:
+		writeError(w, http.StatusBadRequest,
+			errors.New("role must be signer, program, loaded, or account"))
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/alerts"
+)
+
+// SetAlertEngine enables the /api/v1/alerts/rules endpoints.
+func (s *Server) SetAlertEngine(e *alerts.Engine) { s.alerts = e }
+
+func (s *Server) handleListAlertRules(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeError(w, http.StatusNotFound, errors.New("alerting not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alerts.Rules())
+}
+
+func (s *Server) handleSetAlertRule(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeError(w, http.StatusNotFound, errors.New("alerting not enabled"))
+		return
+	}
+	var rule alerts.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	if err := s.alerts.SetRule(rule); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+// handleAlertRuleByID serves DELETE /api/v1/alerts/rules/{id}.
+func (s *Server) handleAlertRuleByID(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeError(w, http.StatusNotFound, errors.New("alerting not enabled"))
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/alerts/rules/")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, errors.New("rule id is required"))
+		return
+	}
+	s.alerts.DeleteRule(id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Amount formatting for list endpoints. ?amounts=ui adds decimal-string
+// fields computed from mint decimals next to the raw base-unit figures,
+// so clients do not each reimplement the decimal-point logic; the
+// default (and ?amounts=raw) keeps responses as the raw models. CSV and
+// parquet exports always carry raw amounts.
+
+// amountsUI parses the amounts query option. A false ok means the value
+// was invalid and the error response has been written.
+func amountsUI(w http.ResponseWriter, r *http.Request) (ui, ok bool) {
+	switch r.URL.Query().Get("amounts") {
+	case "", "raw":
+		return false, true
+	case "ui":
+		return true, true
+	}
+	writeError(w, http.StatusBadRequest, errors.New("amounts must be ui or raw"))
+	return false, false
+}
+
+// transferUI is a transfer with its amount also formatted as a decimal
+// string.
+type transferUI struct {
+	models.Transfer
+	AmountUI string `json:"amount_ui"`
+}
+
+// uiTransfers wraps transfers with their formatted amounts.
+func uiTransfers(transfers []models.Transfer) []transferUI {
+	out := make([]transferUI, len(transfers))
+	for i, t := range transfers {
+		out[i] = transferUI{Transfer: t, AmountUI: solana.UIAmount(t.Amount, t.Decimals)}
+	}
+	return out
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// SetProgramAnalytics exposes per-program rollup statistics at
+// /api/v1/analytics/programs.
+func (s *Server) SetProgramAnalytics(agg *analytics.ProgramAggregator) { s.programStats = agg }
+
+// handleProgramAnalytics serves GET /api/v1/analytics/programs.
+func (s *Server) handleProgramAnalytics(w http.ResponseWriter, r *http.Request) {
+	if s.programStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("program analytics not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	period := q.Get("period")
+	if period != "" && period != analytics.PeriodHour && period != analytics.PeriodDay {
+		writeError(w, http.StatusBadRequest, errors.New("period must be hour or day"))
+		return
+	}
+	stats := s.programStats.Query(q.Get("program"), period, queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetChainAnalytics exposes chain-wide rollup statistics at
+// /api/v1/analytics/chain.
+func (s *Server) SetChainAnalytics(r *analytics.Rollups) { s.chainStats = r }
+
+// handleChainAnalytics serves GET /api/v1/analytics/chain.
+func (s *Server) handleChainAnalytics(w http.ResponseWriter, r *http.Request) {
+	if s.chainStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("chain analytics not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	period := q.Get("period")
+	if period != "" && period != analytics.PeriodHour && period != analytics.PeriodDay {
+		writeError(w, http.StatusBadRequest, errors.New("period must be hour or day"))
+		return
+	}
+	stats := s.chainStats.Query(period, queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetActiveAddressAnalytics exposes HyperLogLog active address
+// estimates at /api/v1/analytics/active-addresses.
+func (s *Server) SetActiveAddressAnalytics(a *analytics.ActiveAddresses) { s.activeStats = a }
+
+// handleActiveAddresses serves GET /api/v1/analytics/active-addresses.
+func (s *Server) handleActiveAddresses(w http.ResponseWriter, r *http.Request) {
+	if s.activeStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("active address analytics not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	period := q.Get("period")
+	if period != "" && period != analytics.PeriodDay && period != analytics.PeriodWeek {
+		writeError(w, http.StatusBadRequest, errors.New("period must be day or week"))
+		return
+	}
+	stats := s.activeStats.Query(period, queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetValidatorAnalytics exposes per-validator vote statistics at
+// /api/v1/analytics/validators.
+func (s *Server) SetValidatorAnalytics(v *analytics.Validators) { s.validatorStats = v }
+
+// handleValidators serves GET /api/v1/analytics/validators.
+func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request) {
+	if s.validatorStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("validator analytics not enabled; set INDEX_VOTES"))
+		return
+	}
+	q := r.URL.Query()
+	stats := s.validatorStats.Query(queryUint(q.Get("epoch")), queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetLendingAnalytics exposes per-market lending aggregates at
+// /api/v1/analytics/lending.
+func (s *Server) SetLendingAnalytics(l *analytics.Lending) { s.lendingStats = l }
+
+// handleLendingAnalytics serves GET /api/v1/analytics/lending.
+func (s *Server) handleLendingAnalytics(w http.ResponseWriter, r *http.Request) {
+	if s.lendingStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("lending analytics not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	stats := s.lendingStats.Query(q.Get("protocol"), queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetMintAuthorities exposes mint authority change history at
+// /api/v1/mints/{mint}/authority-history.
+func (s *Server) SetMintAuthorities(m *analytics.MintAuthorities) { s.mintAuth = m }
+
+// handleMintAuthorityHistory serves
+// GET /api/v1/mints/{mint}/authority-history.
+func (s *Server) handleMintAuthorityHistory(w http.ResponseWriter, r *http.Request) {
+	if s.mintAuth == nil {
+		writeError(w, http.StatusNotFound, errors.New("mint authority tracking not enabled"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/mints/")
+	mint, ok := strings.CutSuffix(rest, "/authority-history")
+	if !ok || mint == "" || strings.Contains(mint, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	history := s.mintAuth.History(mint)
+	if history == nil {
+		history = []analytics.MintAuthorityEvent{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// SetPools exposes AMM pool reserve history at
+// /api/v1/pools/{address}/history.
+func (s *Server) SetPools(p *analytics.Pools) { s.pools = p }
+
+// handlePoolHistory serves GET /api/v1/pools/{address}/history.
+func (s *Server) handlePoolHistory(w http.ResponseWriter, r *http.Request) {
+	if s.pools == nil {
+		writeError(w, http.StatusNotFound, errors.New("pool tracking not enabled; set POOLS_FILE"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/pools/")
+	address, ok := strings.CutSuffix(rest, "/history")
+	if !ok || address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	q := r.URL.Query()
+	history, ok := s.pools.History(address,
+		queryUint(q.Get("from_slot")), queryUint(q.Get("to_slot")),
+		queryInt(q.Get("limit"), 1000))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("pool not configured"))
+		return
+	}
+	if history == nil {
+		history = []analytics.PoolSnapshot{}
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// SetCandles exposes OHLCV candles at
+// /api/v1/markets/{market}/candles.
+func (s *Server) SetCandles(c *analytics.Candles) { s.candles = c }
+
+// handleCandles serves GET /api/v1/markets/{market}/candles.
+func (s *Server) handleCandles(w http.ResponseWriter, r *http.Request) {
+	if s.candles == nil {
+		writeError(w, http.StatusNotFound, errors.New("candles not enabled"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/markets/")
+	market, ok := strings.CutSuffix(rest, "/candles")
+	if !ok || market == "" || strings.Contains(market, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	q := r.URL.Query()
+	interval := q.Get("interval")
+	if interval == "" {
+		interval = analytics.Interval1m
+	}
+	if !analytics.ValidCandleInterval(interval) {
+		writeError(w, http.StatusBadRequest, errors.New("interval must be 1m, 5m, 1h, or 1d"))
+		return
+	}
+	var from, to time.Time
+	for _, key := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from_time", &from}, {"to_time", &to}} {
+		if raw := q.Get(key.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be RFC 3339", key.name))
+				return
+			}
+			*key.dst = t
+		}
+	}
+	candles := s.candles.Query(market, interval, from, to, queryInt(q.Get("limit"), 500))
+	writeJSON(w, http.StatusOK, candles)
+}
+
+// SetMultisigs exposes Squads proposal tracking at
+// /api/v1/multisigs/{address}/proposals.
+func (s *Server) SetMultisigs(m *analytics.Multisigs) { s.multisigs = m }
+
+// handleMultisigProposals serves
+// GET /api/v1/multisigs/{address}/proposals.
+func (s *Server) handleMultisigProposals(w http.ResponseWriter, r *http.Request) {
+	if s.multisigs == nil {
+		writeError(w, http.StatusNotFound, errors.New("multisig tracking not enabled"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/multisigs/")
+	address, ok := strings.CutSuffix(rest, "/proposals")
+	if !ok || address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	proposals, ok := s.multisigs.Proposals(address, queryInt(r.URL.Query().Get("limit"), 100))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("multisig not seen"))
+		return
+	}
+	writeJSON(w, http.StatusOK, proposals)
+}
+
+// SetGovernance exposes SPL Governance tracking under
+// /api/v1/realms/{realm}/.
+func (s *Server) SetGovernance(g *analytics.Governance) { s.governance = g }
+
+// realmParam extracts the realm from /api/v1/realms/{realm}/<suffix>.
+func (s *Server) realmParam(w http.ResponseWriter, r *http.Request, suffix string) (string, bool) {
+	if s.governance == nil {
+		writeError(w, http.StatusNotFound, errors.New("governance tracking not enabled"))
+		return "", false
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/realms/")
+	realm, ok := strings.CutSuffix(rest, suffix)
+	if !ok || realm == "" || strings.Contains(realm, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return "", false
+	}
+	return realm, true
+}
+
+// handleRealmProposals serves GET /api/v1/realms/{realm}/proposals.
+func (s *Server) handleRealmProposals(w http.ResponseWriter, r *http.Request) {
+	realm, ok := s.realmParam(w, r, "/proposals")
+	if !ok {
+		return
+	}
+	proposals, ok := s.governance.Proposals(realm, queryInt(r.URL.Query().Get("limit"), 100))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("realm not seen"))
+		return
+	}
+	writeJSON(w, http.StatusOK, proposals)
+}
+
+// handleRealmStats serves GET /api/v1/realms/{realm}/stats.
+func (s *Server) handleRealmStats(w http.ResponseWriter, r *http.Request) {
+	realm, ok := s.realmParam(w, r, "/stats")
+	if !ok {
+		return
+	}
+	stats, ok := s.governance.Stats(realm)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("realm not seen"))
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// SetStreams exposes streaming-payment tracking at /api/v1/streams.
+func (s *Server) SetStreams(st *analytics.Streams) { s.streams = st }
+
+// handleListStreams serves GET /api/v1/streams.
+func (s *Server) handleListStreams(w http.ResponseWriter, r *http.Request) {
+	if s.streams == nil {
+		writeError(w, http.StatusNotFound, errors.New("stream tracking not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	streams := s.streams.List(q.Get("recipient"), q.Get("status") == "active",
+		queryInt(q.Get("limit"), 100))
+	writeJSON(w, http.StatusOK, streams)
+}
+
+// handleGetStream serves GET /api/v1/streams/{address}.
+func (s *Server) handleGetStream(w http.ResponseWriter, r *http.Request) {
+	if s.streams == nil {
+		writeError(w, http.StatusNotFound, errors.New("stream tracking not enabled"))
+		return
+	}
+	address := strings.TrimPrefix(r.URL.Path, "/api/v1/streams/")
+	if address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	stream, ok := s.streams.Get(address)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("stream not seen"))
+		return
+	}
+	writeJSON(w, http.StatusOK, stream)
+}
+
+// SetPnL exposes wallet PnL reports at /api/v1/accounts/{pubkey}/pnl.
+func (s *Server) SetPnL(p *analytics.PnL) { s.pnl = p }
+
+// handleAccountPnL serves GET /api/v1/accounts/{pubkey}/pnl.
+func (s *Server) handleAccountPnL(w http.ResponseWriter, r *http.Request) {
+	if s.pnl == nil {
+		writeError(w, http.StatusNotFound, errors.New("pnl tracking not enabled; set PNL_WALLETS"))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/accounts/")
+	wallet, ok := strings.CutSuffix(rest, "/pnl")
+	if !ok || wallet == "" || strings.Contains(wallet, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	report, ok := s.pnl.Query(wallet)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("wallet not tracked"))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// mevSlotStats aggregates Jito tip activity for one slot.
+type mevSlotStats struct {
+	Slot        uint64 `json:"slot"`
+	TipCount    int    `json:"tip_count"`
+	TipLamports uint64 `json:"tip_lamports"`
+}
+
+func (s *Server) handleMEVAnalytics(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	events, err := s.store.ListEvents(r.Context(), storage.EventFilter{
+		Type:     "jito_tip",
+		FromSlot: queryUint(q.Get("from_slot")),
+		ToSlot:   queryUint(q.Get("to_slot")),
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	bySlot := map[uint64]*mevSlotStats{}
+	for _, event := range events {
+		stats := bySlot[event.Slot]
+		if stats == nil {
+			stats = &mevSlotStats{Slot: event.Slot}
+			bySlot[event.Slot] = stats
+		}
+		stats.TipCount++
+		switch lamports := event.Data["lamports"].(type) {
+		case uint64:
+			stats.TipLamports += lamports
+		case float64:
+			// Events reloaded through JSON carry numbers as float64.
+			stats.TipLamports += uint64(lamports)
+		}
+	}
+	out := make([]mevSlotStats, 0, len(bySlot))
+	for _, stats := range bySlot {
+		out = append(out, *stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot < out[j].Slot })
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/attest"
+)
+
+// SetAttestor exposes epoch dataset digests at /api/v1/attestation.
+func (s *Server) SetAttestor(a *attest.Attestor) { s.attestor = a }
+
+// handleAttestation serves GET /api/v1/attestation.
+func (s *Server) handleAttestation(w http.ResponseWriter, r *http.Request) {
+	if s.attestor == nil {
+		writeError(w, http.StatusNotFound, errors.New("attestation not enabled"))
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("epoch") == "" {
+		writeError(w, http.StatusBadRequest, errors.New("epoch is required"))
+		return
+	}
+	att, err := s.attestor.Attest(r.Context(), queryUint(q.Get("epoch")))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, att)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Role is an authorization level attached to an API credential. Roles
+// are hierarchical: admin includes analytics, analytics includes read.
+type Role string
+
+const (
+	// RoleRead grants the public read endpoints (blocks, transfers,
+	// events, token accounts).
+	RoleRead Role = "read"
+	// RoleAnalytics additionally grants the aggregation, search, export,
+	// and debug endpoints, which are heavier to serve.
+	RoleAnalytics Role = "analytics"
+	// RoleAdmin additionally grants write-style operations: alert rules,
+	// jobs, signature watches, and feed cursor commits.
+	RoleAdmin Role = "admin"
+)
+
+func roleRank(r Role) int {
+	switch r {
+	case RoleAdmin:
+		return 2
+	case RoleAnalytics:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Admits reports whether a credential holding r may use an endpoint
+// requiring need.
+func (r Role) Admits(need Role) bool { return roleRank(r) >= roleRank(need) }
+
+// Authorizer authenticates requests against static API keys and
+// enforces per-route-group roles. A nil Authorizer (no keys configured)
+// leaves the API open, the pre-auth behavior.
+type Authorizer struct {
+	keys map[string]Role
+	oidc *OIDCVerifier
+	log  *slog.Logger
+}
+
+// NewAuthorizer builds an Authorizer from a key-to-role map, as parsed
+// from the API_KEYS setting.
+func NewAuthorizer(keys map[string]string, log *slog.Logger) *Authorizer {
+	a := &Authorizer{keys: make(map[string]Role, len(keys)), log: log}
+	for key, role := range keys {
+		a.keys[key] = Role(role)
+	}
+	return a
+}
+
+// SetAuth enables API key authentication and role enforcement.
+func (s *Server) SetAuth(a *Authorizer) { s.auth = a }
+
+// requiredRole maps a request to the role its route group demands.
+// Unauthenticated paths return the empty role.
+func requiredRole(r *http.Request) Role {
+	path := r.URL.Path
+	switch {
+	// Liveness and API metadata stay open for probes and tooling.
+	case path == "/health" || path == "/openapi.json" || path == "/docs" ||
+		path == "/schema" || path == "/api/versions":
+		return ""
+	// Write-style operations and operational state.
+	case r.Method != http.MethodGet,
+		strings.HasPrefix(path, "/api/v1/alerts/"),
+		strings.HasPrefix(path, "/api/v1/jobs"),
+		strings.HasPrefix(path, "/api/v1/watch/"):
+		return RoleAdmin
+	// Aggregations, search, and operator debugging.
+	case strings.HasPrefix(path, "/api/v1/analytics/"),
+		strings.HasPrefix(path, "/api/v1/search/"),
+		strings.HasPrefix(path, "/debug/"):
+		return RoleAnalytics
+	default:
+		return RoleRead
+	}
+}
+
+// credential extracts the presented API key from the Authorization
+// bearer header or X-API-Key.
+func credential(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return token
+		}
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// resolveRole maps a request's credential to its role: static API keys
+// first, then OIDC bearer tokens when a verifier is configured.
+func (a *Authorizer) resolveRole(r *http.Request) (Role, bool) {
+	cred := credential(r)
+	if role, ok := a.keys[cred]; ok {
+		return role, true
+	}
+	if a.oidc != nil && strings.Count(cred, ".") == 2 {
+		role, err := a.oidc.Verify(cred)
+		if err != nil {
+			a.log.Debug("jwt rejected", "error", err)
+			return "", false
+		}
+		return role, true
+	}
+	return "", false
+}
+
+// authMiddleware rejects requests whose credential is missing, unknown,
+// or under-privileged for the route group, logging every denial.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.auth == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		need := requiredRole(r)
+		if need == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		role, ok := s.auth.resolveRole(r)
+		if !ok {
+			s.auth.log.Warn("authentication failed",
+				"method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or unknown API key"))
+			return
+		}
+		if !role.Admits(need) {
+			s.auth.log.Warn("authorization denied",
+				"method", r.Method, "path", r.URL.Path, "role", role, "required", need,
+				"remote", r.RemoteAddr)
+			writeError(w, http.StatusForbidden, errors.New("insufficient role"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/coverage"
+)
+
+// SetCoverage exposes the indexed-slot coverage map at
+// /api/v1/coverage. commitment is the level every range was fetched at.
+func (s *Server) SetCoverage(commitment string, t *coverage.Tracker) {
+	s.covCommitment = commitment
+	s.coverage = t
+}
+
+// coverageResponse is the covered slot ranges, per source lane and
+// merged. An empty query result inside a merged range means no data; a
+// slot outside every range has simply not been indexed yet.
+type coverageResponse struct {
+	Commitment string                      `json:"commitment"`
+	Sources    map[string][]coverage.Range `json:"sources"`
+	Merged     []coverage.Range            `json:"merged"`
+}
+
+// handleCoverage serves GET /api/v1/coverage.
+func (s *Server) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	if s.coverage == nil {
+		writeError(w, http.StatusNotFound, errors.New("coverage tracking not enabled"))
+		return
+	}
+	merged := s.coverage.Merged()
+	if merged == nil {
+		merged = []coverage.Range{}
+	}
+	writeJSON(w, http.StatusOK, coverageResponse{
+		Commitment: s.covCommitment,
+		Sources:    s.coverage.Snapshot(),
+		Merged:     merged,
+	})
+}
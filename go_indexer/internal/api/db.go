@@ -0,0 +1,20 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage/postgres"
+)
+
+// SetPoolStats exposes database connection pool metrics at
+// /api/v1/db/pool. Stores without a pool leave it unset.
+func (s *Server) SetPoolStats(fn func() postgres.PoolStats) { s.poolStats = fn }
+
+func (s *Server) handlePoolStats(w http.ResponseWriter, r *http.Request) {
+	if s.poolStats == nil {
+		writeError(w, http.StatusNotFound, errors.New("connection pooling not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.poolStats())
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// SetRPCHealth exposes per-endpoint RPC health scores at /debug/rpc.
+func (s *Server) SetRPCHealth(scorer *solana.Scorer) { s.rpcHealth = scorer }
+
+// handleRPCHealth reports each configured RPC endpoint's score, slot
+// skew, and latency so operators can see which provider is lagging.
+func (s *Server) handleRPCHealth(w http.ResponseWriter, r *http.Request) {
+	if s.rpcHealth == nil {
+		writeError(w, http.StatusNotFound, errors.New("multi-endpoint RPC scoring not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.rpcHealth.Snapshot())
+}
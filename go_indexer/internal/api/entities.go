@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/entity"
+)
+
+// SetEntityResolver exposes wallet clusters at
+// /api/v1/entities/{address}.
+func (s *Server) SetEntityResolver(r *entity.Resolver) { s.entities = r }
+
+// handleEntityLookup serves GET /api/v1/entities/{address}.
+func (s *Server) handleEntityLookup(w http.ResponseWriter, r *http.Request) {
+	if s.entities == nil {
+		writeError(w, http.StatusNotFound, errors.New("entity resolution not enabled"))
+		return
+	}
+	address := strings.TrimPrefix(r.URL.Path, "/api/v1/entities/")
+	if address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	cluster, ok := s.entities.Resolve(address)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("address not linked to any cluster"))
+		return
+	}
+	writeJSON(w, http.StatusOK, cluster)
+}
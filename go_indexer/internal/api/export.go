@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Bulk export of list endpoints. Clients ask for an export encoding via
+// Accept: text/csv or ?format=csv|parquet; JSON stays the default. One
+// synchronous export is bounded by exportMaxRows — larger ranges belong
+// in an export job that pages through the data.
+
+// exportMaxRows bounds a single synchronous export response.
+const exportMaxRows = 100_000
+
+// exportFormat returns the requested export encoding ("csv", "parquet")
+// or "" for JSON.
+func exportFormat(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		return "csv"
+	case "parquet":
+		return "parquet"
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		return "csv"
+	}
+	return ""
+}
+
+// exportLimit returns the row limit to use for an export request,
+// rejecting requests past the synchronous bound.
+func exportLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	limit := queryInt(r.URL.Query().Get("limit"), exportMaxRows)
+	if limit > exportMaxRows {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf(
+			"export limited to %d rows per request; page with offset or submit an export job", exportMaxRows))
+		return 0, false
+	}
+	return limit, true
+}
+
+// writeExport streams rows in the negotiated export encoding and
+// reports whether it handled the response. rows must be a slice of
+// structs; columns are derived from json tags.
+func writeExport(w http.ResponseWriter, r *http.Request, rows any) bool {
+	switch exportFormat(r) {
+	case "csv":
+		if err := writeCSV(w, rows); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return true
+	case "parquet":
+		if err := writeParquet(w, rows); err != nil {
+			writeError(w, http.StatusNotImplemented, err)
+		}
+		return true
+	}
+	return false
+}
+
+// writeCSV streams a slice of structs as CSV, one column per json tag.
+func writeCSV(w http.ResponseWriter, rows any) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return errors.New("export: rows must be a slice")
+	}
+	t := v.Type().Elem()
+	var header []string
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		header = append(header, tag)
+		fields = append(fields, i)
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for i := 0; i < v.Len(); i++ {
+		row := v.Index(i)
+		for j, f := range fields {
+			record[j] = csvValue(row.Field(f).Interface())
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue renders one cell: times as RFC 3339, maps and slices as
+// embedded JSON, everything else via fmt.
+func csvValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	case string:
+		return val
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Struct:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
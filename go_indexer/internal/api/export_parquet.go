@@ -0,0 +1,24 @@
+//go:build parquet
+
+package api
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// writeParquet streams a slice of structs as a parquet file. It is only
+// compiled in with -tags parquet, which adds the parquet-go dependency.
+func writeParquet(w http.ResponseWriter, rows any) error {
+	v := reflect.ValueOf(rows)
+	w.Header().Set("Content-Type", "application/vnd.apache.parquet")
+	pw := parquet.NewGenericWriter[any](w, parquet.SchemaOf(reflect.New(v.Type().Elem()).Interface()))
+	for i := 0; i < v.Len(); i++ {
+		if _, err := pw.Write([]any{v.Index(i).Interface()}); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
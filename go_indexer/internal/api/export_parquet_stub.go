@@ -0,0 +1,14 @@
+//go:build !parquet
+
+package api
+
+import (
+	"errors"
+	"net/http"
+)
+
+// writeParquet is the stub used when the binary is built without the
+// parquet encoder; see export_parquet.go for the real implementation.
+func writeParquet(w http.ResponseWriter, rows any) error {
+	return errors.New("binary built without parquet support; rebuild with -tags parquet")
+}
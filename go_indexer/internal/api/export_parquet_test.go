@@ -0,0 +1,46 @@
+//go:build parquet
+
+package api
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// TestWriteParquetRoundTrip writes transfers through the export encoder
+// and reads them back with the parquet reader, checking the schema
+// derived via reflection survives a full encode/decode cycle.
+func TestWriteParquetRoundTrip(t *testing.T) {
+	in := []models.Transfer{
+		{Signature: "sig1", Slot: 10, Source: "alice", Destination: "bob", Amount: 250, Decimals: 9},
+		{Signature: "sig2", Slot: 11, Source: "bob", Destination: "carol", Mint: "mintA", Amount: 7, Decimals: 6},
+	}
+	rec := httptest.NewRecorder()
+	if err := writeParquet(rec, in); err != nil {
+		t.Fatalf("writeParquet: %v", err)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/vnd.apache.parquet" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.Bytes()
+	out, err := parquet.Read[models.Transfer](bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("read parquet back: %v", err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("rows = %d, want %d", len(out), len(in))
+	}
+	for i := range in {
+		if out[i].Signature != in[i].Signature || out[i].Slot != in[i].Slot ||
+			out[i].Source != in[i].Source || out[i].Destination != in[i].Destination ||
+			out[i].Mint != in[i].Mint || out[i].Amount != in[i].Amount ||
+			out[i].Decimals != in[i].Decimals {
+			t.Errorf("row %d = %+v, want %+v", i, out[i], in[i])
+		}
+	}
+}
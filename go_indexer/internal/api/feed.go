@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/grpcapi"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// feedPollInterval is how often a long-polling feed request re-checks the
+// outbox for new entries.
+const feedPollInterval = 250 * time.Millisecond
+
+const (
+	feedDefaultLimit = 100
+	feedMaxWait      = 30 * time.Second
+)
+
+type feedResponse struct {
+	Entries    []models.OutboxEntry `json:"entries"`
+	NextCursor uint64               `json:"next_cursor"`
+}
+
+type feedCommitRequest struct {
+	Consumer string `json:"consumer"`
+	Cursor   uint64 `json:"cursor"`
+}
+
+// handleEventFeed serves the pull-based change feed over the outbox.
+// Consumers page forward with after_cursor, or pass consumer= to resume
+// from their durably committed offset; wait_ms enables long polling.
+// encoding=proto returns the page as an indexer.v1.FeedResponse (see
+// proto/indexer.proto) for Kafka/NATS bridge relays that want the same
+// compact payloads as the gRPC stream.
+func (s *Server) handleEventFeed(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	encoding := q.Get("encoding")
+	switch encoding {
+	case "", "json", "proto":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown encoding %q (known: json, proto)", encoding))
+		return
+	}
+	after := queryUint(q.Get("after_cursor"))
+	if q.Get("after_cursor") == "" && q.Get("consumer") != "" {
+		offset, err := s.store.GetConsumerOffset(r.Context(), q.Get("consumer"))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		after = offset
+	}
+	limit := queryInt(q.Get("limit"), feedDefaultLimit)
+	wait := time.Duration(queryInt(q.Get("wait_ms"), 0)) * time.Millisecond
+	if wait > feedMaxWait {
+		wait = feedMaxWait
+	}
+	deadline := time.Now().Add(wait)
+
+	for {
+		entries, err := s.store.ListOutbox(r.Context(), after, limit)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if len(entries) > 0 || time.Now().After(deadline) {
+			next := after
+			if len(entries) > 0 {
+				next = entries[len(entries)-1].Cursor
+			}
+			if encoding == "proto" {
+				payload, err := grpcapi.MarshalFeedResponse(entries, next)
+				if err != nil {
+					writeError(w, http.StatusInternalServerError, err)
+					return
+				}
+				w.Header().Set("Content-Type", "application/x-protobuf")
+				_, _ = w.Write(payload)
+				return
+			}
+			writeJSON(w, http.StatusOK, feedResponse{Entries: entries, NextCursor: next})
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(feedPollInterval):
+		}
+	}
+}
+
+// handleEventFeedCommit durably records a consumer's delivered cursor.
+func (s *Server) handleEventFeedCommit(w http.ResponseWriter, r *http.Request) {
+	var req feedCommitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	if req.Consumer == "" {
+		writeError(w, http.StatusBadRequest, errors.New("consumer is required"))
+		return
+	}
+	if err := s.store.SaveConsumerOffset(r.Context(), req.Consumer, req.Cursor); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
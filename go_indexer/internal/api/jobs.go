@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/jobs"
+)
+
+// SetJobManager exposes the jobs framework under /api/v1/jobs. ctx
+// bounds submitted jobs to the process lifetime, not to the submitting
+// request.
+func (s *Server) SetJobManager(ctx context.Context, m *jobs.Manager) {
+	s.jobs = m
+	s.jobCtx = ctx
+}
+
+type submitJobRequest struct {
+	Kind   string         `json:"kind"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// handleListJobs serves GET /api/v1/jobs.
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	if s.jobs == nil {
+		writeError(w, http.StatusNotFound, errors.New("jobs not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.jobs.List(queryInt(r.URL.Query().Get("limit"), 100)))
+}
+
+// handleSubmitJob serves POST /api/v1/jobs. The job runs under the
+// process context, not the request context: submission returns
+// immediately with the queued record.
+func (s *Server) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	if s.jobs == nil {
+		writeError(w, http.StatusNotFound, errors.New("jobs not enabled"))
+		return
+	}
+	var req submitJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	if req.Kind == "" {
+		writeError(w, http.StatusBadRequest, errors.New("kind is required"))
+		return
+	}
+	job, err := s.jobs.Submit(s.jobCtx, req.Kind, req.Params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleJobByID serves GET and DELETE on /api/v1/jobs/{id}.
+func (s *Server) handleJobByID(w http.ResponseWriter, r *http.Request) {
+	if s.jobs == nil {
+		writeError(w, http.StatusNotFound, errors.New("jobs not enabled"))
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusBadRequest, errors.New("job id is required"))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		job, err := s.jobs.Get(id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+	case http.MethodDelete:
+		if err := s.jobs.Cancel(id); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
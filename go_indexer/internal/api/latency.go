@@ -0,0 +1,20 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/latency"
+)
+
+// SetLatency exposes end-to-end indexing latency percentiles and the
+// SLO burn rate at /api/v1/latency and on /metrics.
+func (s *Server) SetLatency(fn func() latency.Snapshot) { s.latency = fn }
+
+func (s *Server) handleLatency(w http.ResponseWriter, r *http.Request) {
+	if s.latency == nil {
+		writeError(w, http.StatusNotFound, errors.New("latency tracking not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.latency())
+}
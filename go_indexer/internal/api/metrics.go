@@ -0,0 +1,123 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// handleMetrics serves GET /metrics in the Prometheus text exposition
+// format, built from the same sources the JSON endpoints read. The
+// metric names here are what "indexer gen-dashboards" generates
+// dashboards and alert rules against; rename in both places or not at
+// all. SetNamespace prepends a deployment prefix to every name.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	p := "indexer"
+	if s.metricNamespace != "" {
+		p = s.metricNamespace + "_indexer"
+	}
+	var b strings.Builder
+
+	if slot, err := s.store.LatestSlot(r.Context()); err == nil {
+		fmt.Fprintf(&b, "# HELP %s_latest_indexed_slot Highest slot stored.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_latest_indexed_slot gauge\n", p)
+		fmt.Fprintf(&b, "%s_latest_indexed_slot %d\n", p, slot)
+	}
+
+	if s.progress != nil {
+		live, backfill := s.progress()
+		fmt.Fprintf(&b, "# HELP %s_current_slot Slot each lane is processing.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_current_slot gauge\n", p)
+		fmt.Fprintf(&b, "%s_current_slot{lane=\"live\"} %d\n", p, live.CurrentSlot)
+		fmt.Fprintf(&b, "%s_current_slot{lane=\"backfill\"} %d\n", p, backfill.CurrentSlot)
+		fmt.Fprintf(&b, "# HELP %s_slots_processed_total Slots processed per lane.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_slots_processed_total counter\n", p)
+		fmt.Fprintf(&b, "%s_slots_processed_total{lane=\"live\"} %d\n", p, live.SlotsProcessed)
+		fmt.Fprintf(&b, "%s_slots_processed_total{lane=\"backfill\"} %d\n", p, backfill.SlotsProcessed)
+
+		if s.rpcHealth != nil {
+			var leader uint64
+			for _, ep := range s.rpcHealth.Snapshot() {
+				if ep.Slot > leader {
+					leader = ep.Slot
+				}
+			}
+			if leader >= live.CurrentSlot && live.CurrentSlot > 0 {
+				fmt.Fprintf(&b, "# HELP %s_slot_lag Slots between the cluster tip and the live lane.\n", p)
+				fmt.Fprintf(&b, "# TYPE %s_slot_lag gauge\n", p)
+				fmt.Fprintf(&b, "%s_slot_lag %d\n", p, leader-live.CurrentSlot)
+			}
+		}
+	}
+
+	if s.rpcHealth != nil {
+		fmt.Fprintf(&b, "# HELP %s_rpc_requests_total RPC calls per endpoint.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_rpc_requests_total counter\n", p)
+		for _, ep := range s.rpcHealth.Snapshot() {
+			fmt.Fprintf(&b, "%s_rpc_requests_total{url=%q} %d\n", p, ep.URL, ep.Requests)
+		}
+		fmt.Fprintf(&b, "# HELP %s_rpc_errors_total Failed RPC calls per endpoint.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_rpc_errors_total counter\n", p)
+		for _, ep := range s.rpcHealth.Snapshot() {
+			fmt.Fprintf(&b, "%s_rpc_errors_total{url=%q} %d\n", p, ep.URL, ep.Errors)
+		}
+		fmt.Fprintf(&b, "# HELP %s_rpc_latency_ms Smoothed RPC latency per endpoint.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_rpc_latency_ms gauge\n", p)
+		for _, ep := range s.rpcHealth.Snapshot() {
+			fmt.Fprintf(&b, "%s_rpc_latency_ms{url=%q} %g\n", p, ep.URL, ep.LatencyMS)
+		}
+	}
+
+	if s.latency != nil {
+		snap := s.latency()
+		if snap.Transactions > 0 {
+			fmt.Fprintf(&b, "# HELP %s_e2e_latency_seconds Commit latency percentiles per basis: block_time is commit minus blockTime, receipt is commit minus RPC receipt.\n", p)
+			fmt.Fprintf(&b, "# TYPE %s_e2e_latency_seconds gauge\n", p)
+			for _, q := range []struct {
+				label string
+				value float64
+			}{
+				{"0.5", snap.BlockTime.P50},
+				{"0.9", snap.BlockTime.P90},
+				{"0.99", snap.BlockTime.P99},
+			} {
+				fmt.Fprintf(&b, "%s_e2e_latency_seconds{basis=\"block_time\",quantile=%q} %g\n", p, q.label, q.value)
+			}
+			if snap.HasReceipt {
+				for _, q := range []struct {
+					label string
+					value float64
+				}{
+					{"0.5", snap.Receipt.P50},
+					{"0.9", snap.Receipt.P90},
+					{"0.99", snap.Receipt.P99},
+				} {
+					fmt.Fprintf(&b, "%s_e2e_latency_seconds{basis=\"receipt\",quantile=%q} %g\n", p, q.label, q.value)
+				}
+			}
+			fmt.Fprintf(&b, "# HELP %s_e2e_latency_slo_burn_rate How fast the unfresh fraction consumes the SLO error budget; above 1 the objective is being missed.\n", p)
+			fmt.Fprintf(&b, "# TYPE %s_e2e_latency_slo_burn_rate gauge\n", p)
+			fmt.Fprintf(&b, "%s_e2e_latency_slo_burn_rate %g\n", p, snap.BurnRate)
+			fmt.Fprintf(&b, "# HELP %s_e2e_latency_slo_target_seconds The SLO freshness target.\n", p)
+			fmt.Fprintf(&b, "# TYPE %s_e2e_latency_slo_target_seconds gauge\n", p)
+			fmt.Fprintf(&b, "%s_e2e_latency_slo_target_seconds %g\n", p, snap.Target)
+		}
+	}
+
+	if s.poolStats != nil {
+		pool := s.poolStats()
+		fmt.Fprintf(&b, "# HELP %s_db_pool_conns Database connections by state.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_db_pool_conns gauge\n", p)
+		fmt.Fprintf(&b, "%s_db_pool_conns{state=\"acquired\"} %d\n", p, pool.AcquiredConns)
+		fmt.Fprintf(&b, "%s_db_pool_conns{state=\"idle\"} %d\n", p, pool.IdleConns)
+		fmt.Fprintf(&b, "# HELP %s_db_pool_max_conns Database connection pool size.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_db_pool_max_conns gauge\n", p)
+		fmt.Fprintf(&b, "%s_db_pool_max_conns %d\n", p, pool.MaxConns)
+		fmt.Fprintf(&b, "# HELP %s_db_pool_acquire_wait_seconds_total Time spent waiting for a connection.\n", p)
+		fmt.Fprintf(&b, "# TYPE %s_db_pool_acquire_wait_seconds_total counter\n", p)
+		fmt.Fprintf(&b, "%s_db_pool_acquire_wait_seconds_total %g\n", p, float64(pool.AcquireWaitNS)/1e9)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
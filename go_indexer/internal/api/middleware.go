@@ -0,0 +1,117 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// SetAccessLog enables the structured access log middleware, writing
+// one JSON record per request to w (stdout or a file).
+func (s *Server) SetAccessLog(w io.Writer) {
+	s.accessLog = slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// statusWriter records the response status and size for the access log
+// while passing flushes through, which the SSE stream depends on.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// logMiddleware is the outermost middleware: it recovers panics into
+// 500 responses, propagates the W3C traceparent, and emits one access
+// log record per request with latency, status, route, and the caller's
+// key ID.
+func (s *Server) logMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := traceIDFrom(r)
+		w.Header().Set("Traceparent", "00-"+traceID+"-"+randomHex(8)+"-01")
+
+		sw := &statusWriter{ResponseWriter: w}
+		start := time.Now()
+		defer func() {
+			if rec := recover(); rec != nil {
+				if s.accessLog != nil {
+					s.accessLog.Error("panic serving request",
+						"trace_id", traceID, "method", r.Method, "path", r.URL.Path,
+						"panic", rec, "stack", string(debug.Stack()))
+				}
+				if sw.status == 0 {
+					writeError(sw, http.StatusInternalServerError, errors.New("internal server error"))
+				}
+			}
+			if s.accessLog != nil {
+				s.accessLog.Info("request",
+					"trace_id", traceID,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"status", sw.status,
+					"bytes", sw.bytes,
+					"latency_ms", time.Since(start).Milliseconds(),
+					"key_id", keyID(r),
+					"remote", r.RemoteAddr)
+			}
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// traceIDFrom reuses the trace ID of an incoming traceparent header so
+// the indexer's spans join the caller's trace, minting a fresh ID
+// otherwise.
+func traceIDFrom(r *http.Request) string {
+	// traceparent: version-traceid-spanid-flags
+	parts := strings.Split(r.Header.Get("Traceparent"), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && parts[1] != strings.Repeat("0", 32) {
+		return parts[1]
+	}
+	return randomHex(16)
+}
+
+// keyID is a short digest of the presented credential, so access logs
+// can attribute traffic to a key without recording the secret itself.
+func keyID(r *http.Request) string {
+	cred := credential(r)
+	if cred == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(cred))
+	return hex.EncodeToString(sum[:4])
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.Repeat("0", n*2)
+	}
+	return hex.EncodeToString(buf)
+}
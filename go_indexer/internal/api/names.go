@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/sns"
+)
+
+// SetNameRegistry enables .sol name resolution: names are accepted
+// anywhere a pubkey is through the resolving middleware, and
+// /api/v1/names/{query} serves forward and reverse lookups.
+func (s *Server) SetNameRegistry(r *sns.Registry) { s.names = r }
+
+// nameMiddleware rewrites .sol names in path segments and query values
+// to the registered owner address, so every address-taking endpoint
+// accepts domains without per-handler changes. Unknown names pass
+// through untouched and fail address validation where they land.
+func (s *Server) nameMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.names == nil || strings.HasPrefix(r.URL.Path, "/api/v1/names/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rewritten := false
+		segments := strings.Split(r.URL.Path, "/")
+		for i, segment := range segments {
+			if !strings.HasSuffix(segment, ".sol") {
+				continue
+			}
+			if address, ok := s.names.Resolve(segment); ok {
+				segments[i] = address
+				rewritten = true
+			}
+		}
+		query := r.URL.Query()
+		for _, values := range query {
+			for i, value := range values {
+				if !strings.HasSuffix(value, ".sol") {
+					continue
+				}
+				if address, ok := s.names.Resolve(value); ok {
+					values[i] = address
+					rewritten = true
+				}
+			}
+		}
+		if rewritten {
+			r = r.Clone(r.Context())
+			r.URL.Path = strings.Join(segments, "/")
+			r.URL.RawQuery = query.Encode()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// nameResponse pairs a .sol name with its owner address.
+type nameResponse struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// handleNameLookup serves GET /api/v1/names/{query}: a .sol name
+// resolves to its owner, anything else is reverse-looked-up to a name.
+func (s *Server) handleNameLookup(w http.ResponseWriter, r *http.Request) {
+	if s.names == nil {
+		writeError(w, http.StatusNotFound, errors.New("name resolution not enabled"))
+		return
+	}
+	query := strings.TrimPrefix(r.URL.Path, "/api/v1/names/")
+	if query == "" || strings.Contains(query, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if strings.HasSuffix(query, ".sol") {
+		address, ok := s.names.Resolve(query)
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.New("name not registered"))
+			return
+		}
+		writeJSON(w, http.StatusOK, nameResponse{Name: query, Address: address})
+		return
+	}
+	name, ok := s.names.ReverseLookup(query)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("no name registered for address"))
+		return
+	}
+	writeJSON(w, http.StatusOK, nameResponse{Name: name, Address: query})
+}
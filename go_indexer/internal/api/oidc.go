@@ -0,0 +1,248 @@
+package api
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksRefreshMin is the shortest interval between JWKS refetches, so a
+// flood of tokens with unknown key IDs cannot hammer the issuer.
+const jwksRefreshMin = time.Minute
+
+// OIDCVerifier validates bearer JWTs against an OIDC issuer as an
+// alternative to static API keys: it discovers the issuer's JWKS
+// endpoint, caches the signing keys, and maps a token claim to a Role.
+type OIDCVerifier struct {
+	issuer   string
+	audience string
+	// roleClaim is the claim read for the role, e.g. "roles" or "scope";
+	// string, list-of-string, and space-separated values are accepted.
+	roleClaim string
+	// defaultRole is granted to valid tokens without a recognized role
+	// claim value.
+	defaultRole Role
+	httpc       *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey // by kid
+	lastRefresh time.Time
+}
+
+// NewOIDCVerifier creates a verifier for tokens issued by issuer.
+// audience is optional; when set, tokens must carry it in aud.
+func NewOIDCVerifier(issuer, audience, roleClaim string, defaultRole Role) *OIDCVerifier {
+	if roleClaim == "" {
+		roleClaim = "roles"
+	}
+	return &OIDCVerifier{
+		issuer:      strings.TrimSuffix(issuer, "/"),
+		audience:    audience,
+		roleClaim:   roleClaim,
+		defaultRole: defaultRole,
+		httpc:       &http.Client{Timeout: 10 * time.Second},
+		keys:        make(map[string]*rsa.PublicKey),
+	}
+}
+
+// SetOIDC accepts bearer JWTs from the verifier's issuer in addition to
+// the static API keys.
+func (a *Authorizer) SetOIDC(v *OIDCVerifier) { a.oidc = v }
+
+// claims is the subset of JWT claims the verifier checks.
+type claims struct {
+	Issuer   string          `json:"iss"`
+	Audience json.RawMessage `json:"aud"` // string or []string
+	Expiry   int64           `json:"exp"`
+	NotBefor int64           `json:"nbf"`
+}
+
+// Verify validates a compact JWT and returns the role its claims map
+// to. It returns an error for malformed, expired, or unsigned tokens.
+func (v *OIDCVerifier) Verify(token string) (Role, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("token is not a compact JWT")
+	}
+	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("decode header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerRaw, &header); err != nil {
+		return "", fmt.Errorf("parse header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return "", fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	key, err := v.key(header.Kid)
+	if err != nil {
+		return "", err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("decode signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return "", errors.New("signature verification failed")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("decode payload: %w", err)
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return "", fmt.Errorf("parse claims: %w", err)
+	}
+	now := time.Now().Unix()
+	if c.Expiry != 0 && now >= c.Expiry {
+		return "", errors.New("token expired")
+	}
+	if c.NotBefor != 0 && now < c.NotBefor {
+		return "", errors.New("token not yet valid")
+	}
+	if strings.TrimSuffix(c.Issuer, "/") != v.issuer {
+		return "", fmt.Errorf("issuer %q not trusted", c.Issuer)
+	}
+	if v.audience != "" && !audienceContains(c.Audience, v.audience) {
+		return "", errors.New("audience mismatch")
+	}
+	return v.roleFromClaims(payload), nil
+}
+
+// roleFromClaims picks the strongest recognized role out of the
+// configured claim, falling back to the default role.
+func (v *OIDCVerifier) roleFromClaims(payload []byte) Role {
+	var all map[string]any
+	if err := json.Unmarshal(payload, &all); err != nil {
+		return v.defaultRole
+	}
+	var values []string
+	switch raw := all[v.roleClaim].(type) {
+	case string:
+		values = strings.Fields(raw)
+	case []any:
+		for _, item := range raw {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	role := v.defaultRole
+	for _, value := range values {
+		if r := Role(value); r == RoleRead || r == RoleAnalytics || r == RoleAdmin {
+			if roleRank(r) > roleRank(role) {
+				role = r
+			}
+		}
+	}
+	return role
+}
+
+func audienceContains(raw json.RawMessage, want string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == want
+	}
+	var many []string
+	if json.Unmarshal(raw, &many) == nil {
+		for _, aud := range many {
+			if aud == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the issuer's signing key for kid, refreshing the cached
+// JWKS when the kid is unknown (rate limited by jwksRefreshMin).
+func (v *OIDCVerifier) key(kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(v.lastRefresh) < jwksRefreshMin {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := v.refreshLocked(); err != nil {
+		return nil, err
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// refreshLocked fetches the issuer's discovery document and JWKS.
+// Callers hold v.mu.
+func (v *OIDCVerifier) refreshLocked() error {
+	v.lastRefresh = time.Now()
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.getJSON(v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return fmt.Errorf("oidc discovery: %w", err)
+	}
+	if discovery.JWKSURI == "" {
+		return errors.New("oidc discovery: issuer has no jwks_uri")
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	v.keys = keys
+	return nil
+}
+
+func (v *OIDCVerifier) getJSON(url string, out any) error {
+	resp, err := v.httpc.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// oidcFixture runs a fake issuer serving the discovery document and a
+// JWKS with one RSA key, and signs tokens with the matching private key.
+type oidcFixture struct {
+	issuer string
+	key    *rsa.PrivateKey
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	f := &oidcFixture{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": f.issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}),
+			}},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	f.issuer = srv.URL
+	return f
+}
+
+// sign produces a compact RS256 JWT over claims with the fixture's key.
+func (f *oidcFixture) sign(t *testing.T, header map[string]any, body map[string]any) string {
+	t.Helper()
+	headerJSON, _ := json.Marshal(header)
+	bodyJSON, _ := json.Marshal(body)
+	signing := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(bodyJSON)
+	digest := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// claims returns a claim set that passes verification against the
+// fixture, for tests to break one field at a time.
+func (f *oidcFixture) claims() map[string]any {
+	return map[string]any{
+		"iss": f.issuer,
+		"aud": "indexer",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func rs256Header() map[string]any {
+	return map[string]any{"alg": "RS256", "kid": "test-key"}
+}
+
+func TestOIDCVerify(t *testing.T) {
+	f := newOIDCFixture(t)
+	v := NewOIDCVerifier(f.issuer, "indexer", "roles", RoleRead)
+
+	token := f.sign(t, rs256Header(), f.claims())
+	role, err := v.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if role != RoleRead {
+		t.Errorf("role = %q, want %q", role, RoleRead)
+	}
+}
+
+func TestOIDCVerifyRejects(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	expired := f.claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	notYet := f.claims()
+	notYet["nbf"] = time.Now().Add(time.Hour).Unix()
+	wrongIssuer := f.claims()
+	wrongIssuer["iss"] = "https://evil.example"
+	wrongAud := f.claims()
+	wrongAud["aud"] = "other-service"
+	listAudMiss := f.claims()
+	listAudMiss["aud"] = []string{"other", "another"}
+
+	valid := f.sign(t, rs256Header(), f.claims())
+	tampered := valid[:len(valid)-4] + "AAAA"
+	// Swap the payload of a validly signed token: the signature no
+	// longer covers it.
+	parts := strings.Split(valid, ".")
+	elevated, _ := json.Marshal(map[string]any{
+		"iss": f.issuer, "aud": "indexer",
+		"exp": time.Now().Add(time.Hour).Unix(), "roles": "admin",
+	})
+	spliced := parts[0] + "." + base64.RawURLEncoding.EncodeToString(elevated) + "." + parts[2]
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"not a JWT", "not-a-token", "not a compact JWT"},
+		{"tampered signature", tampered, "signature verification failed"},
+		{"spliced payload", spliced, "signature verification failed"},
+		{"alg none", unsignedToken(map[string]any{"alg": "none"}, f.claims()), `unsupported algorithm "none"`},
+		{"alg confusion HS256", unsignedToken(map[string]any{"alg": "HS256", "kid": "test-key"}, f.claims()), `unsupported algorithm "HS256"`},
+		{"unknown kid", f.sign(t, map[string]any{"alg": "RS256", "kid": "other-key"}, f.claims()), `unknown signing key "other-key"`},
+		{"expired", f.sign(t, rs256Header(), expired), "token expired"},
+		{"not yet valid", f.sign(t, rs256Header(), notYet), "token not yet valid"},
+		{"wrong issuer", f.sign(t, rs256Header(), wrongIssuer), "not trusted"},
+		{"wrong audience", f.sign(t, rs256Header(), wrongAud), "audience mismatch"},
+		{"audience list without ours", f.sign(t, rs256Header(), listAudMiss), "audience mismatch"},
+	}
+	for _, tt := range tests {
+		v := NewOIDCVerifier(f.issuer, "indexer", "roles", RoleRead)
+		_, err := v.Verify(tt.token)
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// unsignedToken builds a token with an empty signature, the shape an
+// alg=none forgery arrives in.
+func unsignedToken(header map[string]any, body map[string]any) string {
+	headerJSON, _ := json.Marshal(header)
+	bodyJSON, _ := json.Marshal(body)
+	return base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(bodyJSON) + "."
+}
+
+func TestOIDCAudienceList(t *testing.T) {
+	f := newOIDCFixture(t)
+	v := NewOIDCVerifier(f.issuer, "indexer", "roles", RoleRead)
+
+	c := f.claims()
+	c["aud"] = []string{"other-service", "indexer"}
+	if _, err := v.Verify(f.sign(t, rs256Header(), c)); err != nil {
+		t.Errorf("aud list containing ours: %v", err)
+	}
+
+	// With no audience configured, any aud is accepted.
+	open := NewOIDCVerifier(f.issuer, "", "roles", RoleRead)
+	c = f.claims()
+	c["aud"] = "whatever"
+	if _, err := open.Verify(f.sign(t, rs256Header(), c)); err != nil {
+		t.Errorf("no configured audience: %v", err)
+	}
+}
+
+func TestOIDCRoleMapping(t *testing.T) {
+	f := newOIDCFixture(t)
+	tests := []struct {
+		name  string
+		claim any
+		want  Role
+	}{
+		{"absent claim falls back", nil, RoleRead},
+		{"string", "analytics", RoleAnalytics},
+		{"space separated picks strongest", "read admin", RoleAdmin},
+		{"list", []string{"read", "analytics"}, RoleAnalytics},
+		{"unrecognized values fall back", "superuser root", RoleRead},
+	}
+	for _, tt := range tests {
+		v := NewOIDCVerifier(f.issuer, "indexer", "roles", RoleRead)
+		c := f.claims()
+		if tt.claim != nil {
+			c["roles"] = tt.claim
+		}
+		role, err := v.Verify(f.sign(t, rs256Header(), c))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if role != tt.want {
+			t.Errorf("%s: role = %q, want %q", tt.name, role, tt.want)
+		}
+	}
+}
+
+func TestOIDCRefreshRateLimit(t *testing.T) {
+	f := newOIDCFixture(t)
+	v := NewOIDCVerifier(f.issuer, "indexer", "roles", RoleRead)
+
+	// First unknown kid triggers a JWKS fetch; a second one inside
+	// jwksRefreshMin must fail without refetching.
+	if _, err := v.Verify(f.sign(t, map[string]any{"alg": "RS256", "kid": "missing"}, f.claims())); err == nil {
+		t.Fatal("unknown kid: expected error")
+	}
+	before := v.lastRefresh
+	if _, err := v.Verify(f.sign(t, map[string]any{"alg": "RS256", "kid": "missing"}, f.claims())); err == nil {
+		t.Fatal("unknown kid: expected error")
+	}
+	if !v.lastRefresh.Equal(before) {
+		t.Error("second unknown kid refetched the JWKS inside the refresh window")
+	}
+}
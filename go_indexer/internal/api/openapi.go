@@ -0,0 +1,199 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// route carries the metadata needed both to mount a handler and to describe
+// it in the generated OpenAPI document.
+type route struct {
+	method      string
+	path        string // OpenAPI-style path, e.g. /api/v1/blocks/{slot}
+	summary     string
+	pathParams  []param
+	queryParams []param
+	request     any // example value of the JSON request body, if any
+	response    any // example value of the 200 response body
+	// deprecated routes keep working but announce their retirement via
+	// Deprecation and Sunset response headers; sunset is an HTTP-date.
+	deprecated bool
+	sunset     string
+}
+
+type param struct {
+	name string
+	typ  string // OpenAPI type: string, integer, boolean
+}
+
+// describe records a route in the OpenAPI document without mounting a
+// handler, for paths that dispatch several methods from one handler.
+func (s *Server) describe(rt route) {
+	s.routes = append(s.routes, rt)
+}
+
+// handle records the route for OpenAPI generation and mounts the handler
+// with a method guard. Paths containing a {param} segment are mounted as a
+// prefix match; the handler parses the trailing segment itself.
+func (s *Server) handle(rt route, h http.HandlerFunc) {
+	s.routes = append(s.routes, rt)
+	pattern := rt.path
+	if i := strings.Index(pattern, "{"); i >= 0 {
+		pattern = pattern[:i]
+	}
+	method := rt.method
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if rt.deprecated {
+			w.Header().Set("Deprecation", "true")
+			if rt.sunset != "" {
+				w.Header().Set("Sunset", rt.sunset)
+			}
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.openAPIDocument())
+}
+
+// openAPIDocument builds an OpenAPI 3 document from the registered routes.
+func (s *Server) openAPIDocument() map[string]any {
+	paths := map[string]any{}
+	for _, rt := range s.routes {
+		var params []map[string]any
+		for _, p := range rt.pathParams {
+			params = append(params, map[string]any{
+				"name":     p.name,
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": p.typ},
+			})
+		}
+		for _, p := range rt.queryParams {
+			params = append(params, map[string]any{
+				"name":     p.name,
+				"in":       "query",
+				"required": false,
+				"schema":   map[string]any{"type": p.typ},
+			})
+		}
+		op := map[string]any{
+			"summary": rt.summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": schemaFor(reflect.TypeOf(rt.response)),
+						},
+					},
+				},
+			},
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if rt.deprecated {
+			op["deprecated"] = true
+		}
+		if rt.request != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{
+						"schema": schemaFor(reflect.TypeOf(rt.request)),
+					},
+				},
+			}
+		}
+		methods, _ := paths[rt.path].(map[string]any)
+		if methods == nil {
+			methods = map[string]any{}
+			paths[rt.path] = methods
+		}
+		methods[strings.ToLower(rt.method)] = op
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Solana Starter Indexer API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+	}
+}
+
+// schemaFor derives a JSON schema from a Go type via reflection, following
+// the same encoding/json rules the handlers use to marshal responses.
+func schemaFor(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": true}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Struct:
+		if t.PkgPath() == "time" && t.Name() == "Time" {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			_ = opts
+			props[name] = schemaFor(f.Type)
+		}
+		return map[string]any{"type": "object", "properties": props}
+	default:
+		return map[string]any{}
+	}
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Solana Starter Indexer API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(docsPage))
+}
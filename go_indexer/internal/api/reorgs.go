@@ -0,0 +1,15 @@
+package api
+
+import "net/http"
+
+// handleListReorgs serves GET /api/v1/reorgs, the historical reorg audit
+// log, newest first.
+func (s *Server) handleListReorgs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	reorgs, err := s.store.ListReorgs(r.Context(), queryInt(q.Get("limit"), 100), queryInt(q.Get("offset"), 0))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reorgs)
+}
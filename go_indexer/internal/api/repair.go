@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/repair"
+)
+
+// SetRepairWorker enables the /api/v1/repair endpoints.
+func (s *Server) SetRepairWorker(w *repair.Worker) { s.repair = w }
+
+// repairRequest asks for an immediate repair of one slot.
+type repairRequest struct {
+	Slot uint64 `json:"slot"`
+}
+
+// repairResponse reports the outcome of an immediate repair.
+type repairResponse struct {
+	Slot     uint64 `json:"slot"`
+	Repaired bool   `json:"repaired"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleListRepairs serves GET /api/v1/repair.
+func (s *Server) handleListRepairs(w http.ResponseWriter, r *http.Request) {
+	if s.repair == nil {
+		writeError(w, http.StatusNotFound, errors.New("repair worker not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.repair.Snapshot())
+}
+
+// handleRepairSlot serves POST /api/v1/repair, repairing the slot
+// synchronously.
+func (s *Server) handleRepairSlot(w http.ResponseWriter, r *http.Request) {
+	if s.repair == nil {
+		writeError(w, http.StatusNotFound, errors.New("repair worker not enabled"))
+		return
+	}
+	var req repairRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Slot == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("slot is required"))
+		return
+	}
+	repaired, err := s.repair.RepairNow(r.Context(), req.Slot)
+	resp := repairResponse{Slot: req.Slot, Repaired: repaired}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/sched"
+)
+
+// SetScheduler exposes the recurring-task scheduler under
+// /api/v1/schedules. ctx bounds manually triggered runs to the process
+// lifetime, not to the triggering request.
+func (s *Server) SetScheduler(ctx context.Context, sc *sched.Scheduler) {
+	s.scheduler = sc
+	s.schedCtx = ctx
+}
+
+// handleListSchedules serves GET /api/v1/schedules.
+func (s *Server) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeError(w, http.StatusNotFound, errors.New("scheduler not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.scheduler.Snapshot())
+}
+
+// handleTriggerSchedule serves POST /api/v1/schedules/{name}/run.
+func (s *Server) handleTriggerSchedule(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeError(w, http.StatusNotFound, errors.New("scheduler not enabled"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/schedules/"), "/run")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusBadRequest, errors.New("task name is required"))
+		return
+	}
+	switch err := s.scheduler.Trigger(s.schedCtx, name); {
+	case errors.Is(err, sched.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, sched.ErrRunning):
+		writeError(w, http.StatusConflict, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
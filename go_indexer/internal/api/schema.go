@@ -0,0 +1,168 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// TableDoc is one entry in the /schema data dictionary: a table the
+// indexer maintains, the processor that populates it, and its columns.
+type TableDoc struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Processor   string      `json:"processor,omitempty"` // empty for core pipeline tables
+	Columns     []ColumnDoc `json:"columns"`
+}
+
+// ColumnDoc describes one column of a table.
+type ColumnDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// schemaTables drives the dictionary. Column names and types are derived
+// from the model structs by reflection so the dictionary cannot drift
+// from what the API actually serves; descriptions are maintained here.
+var schemaTables = []struct {
+	name        string
+	description string
+	processor   string
+	model       any
+	columns     map[string]string
+}{
+	{
+		name:        "blocks",
+		description: "One row per indexed block.",
+		model:       models.Block{},
+		columns: map[string]string{
+			"block_time":     "Derived timestamp used by time-range queries.",
+			"raw_block_time": "Validator-reported timestamp; zero when the RPC returned null.",
+			"time_source":    "How block_time was obtained: validator, interpolated, or corrected.",
+			"orphaned":       "Set when a later fork abandoned this block; see /api/v1/reorgs.",
+		},
+	},
+	{
+		name:        "transactions",
+		description: "One row per indexed transaction.",
+		model:       models.Transaction{},
+		columns: map[string]string{
+			"durable_nonce": "True for durable-nonce transactions, whose ordering and replay differ.",
+		},
+	},
+	{
+		name:        "transfers",
+		description: "SOL and SPL token transfers extracted from transactions.",
+		processor:   "transfers",
+		model:       models.Transfer{},
+		columns: map[string]string{
+			"mint":   "Token mint; empty for native SOL transfers.",
+			"amount": "Raw amount in base units; divide by 10^decimals for the display value.",
+		},
+	},
+	{
+		name:        "events",
+		description: "Decoded program events from Anchor logs, WASM plugins, or provider APIs.",
+		processor:   "events, plugins",
+		model:       models.Event{},
+		columns: map[string]string{
+			"data": "Decoder-specific payload; shape depends on program and type.",
+		},
+	},
+	{
+		name:        "program_updates",
+		description: "BPF Loader Upgradeable actions affecting deployed programs.",
+		processor:   "programs",
+		model:       models.ProgramUpdate{},
+		columns: map[string]string{
+			"kind": "One of deployed, upgraded, set_authority, or closed.",
+		},
+	},
+	{
+		name:        "token_accounts",
+		description: "Dimension table resolving token accounts to mint, owner, and state.",
+		processor:   "token_accounts",
+		model:       models.TokenAccount{},
+		columns: map[string]string{
+			"state": "One of initialized, frozen, or closed.",
+		},
+	},
+	{
+		name:        "nonce_accounts",
+		description: "Observed durable nonce account usage.",
+		model:       models.NonceAccount{},
+	},
+	{
+		name:        "reorgs",
+		description: "Audit log of detected chain reorganizations.",
+		model:       models.Reorg{},
+		columns: map[string]string{
+			"fork_slot": "Last slot both forks agree on.",
+		},
+	},
+}
+
+// handleSchema serves GET /schema, a machine-readable data dictionary of
+// every table the indexer maintains.
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	docs := make([]TableDoc, 0, len(schemaTables))
+	for _, t := range schemaTables {
+		docs = append(docs, TableDoc{
+			Name:        t.name,
+			Description: t.description,
+			Processor:   t.processor,
+			Columns:     columnsFor(t.model, t.columns),
+		})
+	}
+	writeJSON(w, http.StatusOK, docs)
+}
+
+// columnsFor derives the column list from a model struct's json tags.
+func columnsFor(model any, descriptions map[string]string) []ColumnDoc {
+	t := reflect.TypeOf(model)
+	cols := make([]ColumnDoc, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		cols = append(cols, ColumnDoc{
+			Name:        tag,
+			Type:        columnType(field.Type),
+			Description: descriptions[tag],
+		})
+	}
+	return cols
+}
+
+// columnType maps a Go field type to the dictionary's type vocabulary.
+func columnType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "timestamp"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
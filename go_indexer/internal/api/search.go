@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// searchCandidateCap bounds the candidate sets built from the transfer
+// and event pre-filters so one unbounded query cannot scan everything.
+const searchCandidateCap = 10_000
+
+// handleSearchTransactions serves GET /api/v1/search/transactions with
+// composable filters: signer, any-account, program, status, slot/time
+// range, and decoded-field predicates. The handler plans the query
+// around the most selective filter first: account and program/event
+// predicates are resolved to candidate signature sets through the
+// transfer and event tables, then the transaction filter is applied to
+// the (intersected) candidates.
+func (s *Server) handleSearchTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := storage.TransactionFilter{
+		Signer:   q.Get("signer"),
+		FromSlot: queryUint(q.Get("from_slot")),
+		ToSlot:   queryUint(q.Get("to_slot")),
+		Limit:    queryInt(q.Get("limit"), 100),
+		Offset:   queryInt(q.Get("offset"), 0),
+	}
+	switch role := q.Get("role"); role {
+	case "", "fee_payer", "signer":
+		filter.SignerRole = role
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("role must be fee_payer or signer"))
+		return
+	}
+	switch q.Get("status") {
+	case "":
+	case "success":
+		v := true
+		filter.Success = &v
+	case "failed":
+		v := false
+		filter.Success = &v
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("status must be success or failed"))
+		return
+	}
+	for _, key := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from_time", &filter.FromTime}, {"to_time", &filter.ToTime}} {
+		if raw := q.Get(key.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be RFC 3339", key.name))
+				return
+			}
+			*key.dst = t
+		}
+	}
+
+	account := q.Get("account")
+	program := q.Get("program")
+	eventType := q.Get("event_type")
+	field, value := q.Get("field"), q.Get("value")
+	if (field == "") != (value == "") {
+		writeError(w, http.StatusBadRequest, errors.New("field and value must be used together"))
+		return
+	}
+	if filter.Signer == "" && account == "" && program == "" && eventType == "" && field == "" &&
+		filter.FromSlot == 0 && filter.ToSlot == 0 && filter.FromTime.IsZero() && filter.ToTime.IsZero() {
+		writeError(w, http.StatusBadRequest, errors.New("at least one search filter is required"))
+		return
+	}
+
+	var candidates map[string]struct{}
+	if program != "" || eventType != "" || field != "" {
+		events, err := s.store.ListEvents(r.Context(), storage.EventFilter{
+			Program:  program,
+			Type:     eventType,
+			FromSlot: filter.FromSlot,
+			ToSlot:   filter.ToSlot,
+			Limit:    searchCandidateCap,
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		set := map[string]struct{}{}
+		for _, event := range events {
+			if field != "" && !eventFieldEquals(event, field, value) {
+				continue
+			}
+			set[event.Signature] = struct{}{}
+		}
+		candidates = set
+	}
+	if account != "" && s.acctIndex != nil {
+		// The inverted account index resolves the candidates directly,
+		// covering lookup-table accounts the transfer table never sees.
+		set := map[string]struct{}{}
+		for _, e := range s.acctIndex.Lookup(account, "", filter.FromSlot, filter.ToSlot, searchCandidateCap) {
+			if candidates != nil {
+				if _, ok := candidates[e.Signature]; !ok {
+					continue
+				}
+			}
+			set[e.Signature] = struct{}{}
+		}
+		candidates = set
+	} else if account != "" {
+		transfers, err := s.store.ListTransfers(r.Context(), storage.TransferFilter{
+			Address:  account,
+			FromSlot: filter.FromSlot,
+			ToSlot:   filter.ToSlot,
+			Limit:    searchCandidateCap,
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		set := map[string]struct{}{}
+		for _, t := range transfers {
+			if candidates != nil {
+				if _, ok := candidates[t.Signature]; !ok {
+					continue
+				}
+			}
+			set[t.Signature] = struct{}{}
+		}
+		candidates = set
+	}
+	if candidates != nil {
+		filter.Signatures = make([]string, 0, len(candidates))
+		for sig := range candidates {
+			filter.Signatures = append(filter.Signatures, sig)
+		}
+		if len(filter.Signatures) == 0 {
+			writeJSON(w, http.StatusOK, []models.Transaction{})
+			return
+		}
+	}
+
+	if exportFormat(r) != "" {
+		limit, ok := exportLimit(w, r)
+		if !ok {
+			return
+		}
+		filter.Limit = limit
+	}
+	txs, err := s.store.SearchTransactions(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if txs == nil {
+		txs = []models.Transaction{}
+	}
+	if writeExport(w, r, txs) {
+		return
+	}
+	writeJSON(w, http.StatusOK, txs)
+}
+
+// eventFieldEquals reports whether the dot-separated path (rooted at the
+// event's decoded data) stringifies to value.
+func eventFieldEquals(event models.Event, path, value string) bool {
+	var current any = event.Data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		current, ok = m[part]
+		if !ok {
+			return false
+		}
+	}
+	return fmt.Sprint(current) == value
+}
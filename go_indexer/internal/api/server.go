@@ -0,0 +1,936 @@
+// Package api implements the indexer's REST API server.
+//
+// Routes are registered through Server.handle with metadata (summary,
+// parameters, response model) so the OpenAPI document served at
+// /openapi.json is generated from the same code that mounts the handlers.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountindex"
+	"github.com/lugondev/go-indexer-solana-starter/internal/alerts"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/attest"
+	"github.com/lugondev/go-indexer-solana-starter/internal/coverage"
+	"github.com/lugondev/go-indexer-solana-starter/internal/entity"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/jobs"
+	"github.com/lugondev/go-indexer-solana-starter/internal/latency"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repair"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sched"
+	"github.com/lugondev/go-indexer-solana-starter/internal/schema"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sns"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage/postgres"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watcher"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Server serves the indexer REST API.
+type Server struct {
+	store          storage.Store
+	mux            *http.ServeMux
+	routes         []route
+	events         *eventHub
+	budget         *solana.Budget
+	progress       func() (live, backfill indexer.LaneSnapshot)
+	replayProgress func() indexer.LaneSnapshot
+	watcher        *watcher.SignatureWatcher
+	alerts         *alerts.Engine
+	auth           *Authorizer
+	// accessLog, when set, receives one structured record per request.
+	accessLog *slog.Logger
+
+	poolStats      func() postgres.PoolStats
+	rpcHealth      *solana.Scorer
+	latency        func() latency.Snapshot
+	programStats   *analytics.ProgramAggregator
+	chainStats     *analytics.Rollups
+	activeStats    *analytics.ActiveAddresses
+	validatorStats *analytics.Validators
+	lendingStats   *analytics.Lending
+	candles        *analytics.Candles
+	pnl            *analytics.PnL
+	names          *sns.Registry
+	multisigs      *analytics.Multisigs
+	governance     *analytics.Governance
+	streams        *analytics.Streams
+	entities       *entity.Resolver
+	mintAuth       *analytics.MintAuthorities
+	pools          *analytics.Pools
+	repair         *repair.Worker
+	attestor       *attest.Attestor
+	jobs           *jobs.Manager
+	// jobCtx bounds submitted jobs to the process lifetime.
+	jobCtx    context.Context
+	scheduler *sched.Scheduler
+	// schedCtx bounds manually triggered scheduler runs to the process
+	// lifetime.
+	schedCtx  context.Context
+	acctIndex *accountindex.Index
+	coverage  *coverage.Tracker
+	// metricNamespace prefixes /metrics names; empty keeps the plain
+	// "indexer_" family.
+	metricNamespace string
+	// covCommitment is the commitment level the coverage ranges were
+	// indexed at.
+	covCommitment string
+}
+
+// NewServer creates a Server backed by store and mounts all routes.
+func NewServer(store storage.Store) *Server {
+	s := &Server{
+		store:  store,
+		mux:    http.NewServeMux(),
+		events: newEventHub(),
+	}
+	s.registerRoutes()
+	return s
+}
+
+// Handler returns the root http.Handler for the API.
+func (s *Server) Handler() http.Handler {
+	return s.logMiddleware(s.versionMiddleware(s.authMiddleware(s.nameMiddleware(s.mux))))
+}
+
+// PublishEvent pushes a decoded event to all /api/v1/events/stream
+// subscribers. The indexer calls this after storing each event.
+func (s *Server) PublishEvent(event models.Event) { s.events.publish(event) }
+
+// SetNamespace prefixes every /metrics name with ns + "_", so multiple
+// deployments scraped into one Prometheus stay distinguishable. Pass
+// the same namespace to gen-dashboards when set.
+func (s *Server) SetNamespace(ns string) { s.metricNamespace = ns }
+
+// SetBudget exposes the RPC budget tracker at /api/v1/budget.
+func (s *Server) SetBudget(b *solana.Budget) { s.budget = b }
+
+// SetProgress exposes scheduler lane progress at /api/v1/progress.
+func (s *Server) SetProgress(fn func() (live, backfill indexer.LaneSnapshot)) { s.progress = fn }
+
+// SetReplayProgress adds the backward replay lane to /api/v1/progress.
+func (s *Server) SetReplayProgress(fn func() indexer.LaneSnapshot) { s.replayProgress = fn }
+
+// SetSignatureWatcher enables POST /api/v1/watch/signature.
+func (s *Server) SetSignatureWatcher(w *watcher.SignatureWatcher) { s.watcher = w }
+
+func (s *Server) registerRoutes() {
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/health",
+		summary:  "Liveness check",
+		response: healthResponse{},
+	}, s.handleHealth)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/slot/latest",
+		summary:  "Most recently indexed slot",
+		response: slotResponse{},
+	}, s.handleLatestSlot)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/blocks/{slot}",
+		summary:    "Fetch one indexed block by slot",
+		pathParams: []param{{name: "slot", typ: "integer"}},
+		response:   models.Block{},
+	}, s.handleGetBlock)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/transfers",
+		summary: "List indexed SOL and SPL transfers",
+		queryParams: []param{
+			{name: "address", typ: "string"},
+			{name: "mint", typ: "string"},
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []models.Transfer{},
+	}, s.handleListTransfers)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/events",
+		summary: "List decoded program events",
+		queryParams: []param{
+			{name: "program", typ: "string"},
+			{name: "type", typ: "string"},
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []models.Event{},
+	}, s.handleListEvents)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/events/stream",
+		summary: "Stream decoded events as server-sent events",
+		queryParams: []param{
+			{name: "program", typ: "string"},
+			{name: "type", typ: "string"},
+		},
+		response: models.Event{},
+	}, s.handleStreamEvents)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/events/feed",
+		summary: "Pull outbox entries as a change feed with long-poll support",
+		queryParams: []param{
+			{name: "after_cursor", typ: "integer"},
+			{name: "consumer", typ: "string"},
+			{name: "limit", typ: "integer"},
+			{name: "wait_ms", typ: "integer"},
+		},
+		response: feedResponse{},
+	}, s.handleEventFeed)
+	s.handle(route{
+		method:  http.MethodPost,
+		path:    "/api/v1/events/feed/commit",
+		summary: "Commit a change feed consumer's durable cursor",
+		request: feedCommitRequest{},
+	}, s.handleEventFeedCommit)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/events/schemas",
+		summary:  "Canonical versioned JSON schemas of the decoded event shapes",
+		response: []schema.Schema{},
+	}, s.handleEventSchemas)
+
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/token-accounts",
+		summary: "List tracked SPL token accounts",
+		queryParams: []param{
+			{name: "owner", typ: "string"},
+			{name: "mint", typ: "string"},
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []models.TokenAccount{},
+	}, s.handleListTokenAccounts)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/tables/{name}",
+		summary:    "List rows of a custom mapped table",
+		pathParams: []param{{name: "name", typ: "string"}},
+		queryParams: []param{
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []map[string]any{},
+	}, s.handleListCustomTable)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/programs/{id}",
+		summary:    "Program registry entry with upgrade history",
+		pathParams: []param{{name: "id", typ: "string"}},
+		response:   models.Program{},
+	}, s.handleGetProgram)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/mev",
+		summary: "Per-slot Jito tip and bundle statistics",
+		queryParams: []param{
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+		},
+		response: []mevSlotStats{},
+	}, s.handleMEVAnalytics)
+	s.handle(route{
+		method:   http.MethodPost,
+		path:     "/api/v1/watch/signature",
+		summary:  "Register a signature for confirmation notification",
+		request:  watchSignatureRequest{},
+		response: watchSignatureResponse{},
+	}, s.handleWatchSignature)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/progress",
+		summary:  "Live, backfill, and replay lane progress",
+		response: progressResponse{},
+	}, s.handleProgress)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/coverage",
+		summary:  "Slot ranges the index covers, per source lane",
+		response: coverageResponse{},
+	}, s.handleCoverage)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/search/transactions",
+		summary: "Search transactions with composable filters",
+		queryParams: []param{
+			{name: "signer", typ: "string"},
+			{name: "role", typ: "string"},
+			{name: "account", typ: "string"},
+			{name: "program", typ: "string"},
+			{name: "status", typ: "string"},
+			{name: "event_type", typ: "string"},
+			{name: "field", typ: "string"},
+			{name: "value", typ: "string"},
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+			{name: "from_time", typ: "string"},
+			{name: "to_time", typ: "string"},
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []models.Transaction{},
+	}, s.handleSearchTransactions)
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/addresses/{address}/balance",
+		summary:    "Net balance of an address folded from transfer deltas, anchored at as_of_slot",
+		pathParams: []param{{name: "address", typ: "string"}},
+		queryParams: []param{
+			{name: "mint", typ: "string"},
+			{name: "as_of_slot", typ: "integer"},
+		},
+		response: balanceResponse{},
+	})
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/addresses/{address}/trace",
+		summary:    "Follow fund flows forward from an address across bounded hops",
+		pathParams: []param{{name: "address", typ: "string"}},
+		queryParams: []param{
+			{name: "signature", typ: "string"},
+			{name: "from_slot", typ: "integer"},
+			{name: "max_hops", typ: "integer"},
+			{name: "min_amount", typ: "integer"},
+			{name: "mint", typ: "string"},
+		},
+		response: traceResponse{},
+	})
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/addresses/{address}/transactions",
+		summary:    "Transactions referencing an address, from the inverted account index",
+		pathParams: []param{{name: "address", typ: "string"}},
+		queryParams: []param{
+			{name: "role", typ: "string"},
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []accountindex.Entry{},
+	})
+	s.mux.HandleFunc("/api/v1/addresses/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/balance"):
+			s.handleAddressBalance(w, r)
+		case strings.HasSuffix(r.URL.Path, "/trace"):
+			s.handleTraceFlows(w, r)
+		case strings.HasSuffix(r.URL.Path, "/transactions"):
+			s.handleAddressTransactions(w, r)
+		default:
+			writeError(w, http.StatusNotFound, errors.New("not found"))
+		}
+	})
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/mints/{mint}/holders",
+		summary:    "Holder distribution of a mint, anchored at as_of_slot",
+		pathParams: []param{{name: "mint", typ: "string"}},
+		queryParams: []param{
+			{name: "as_of_slot", typ: "integer"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []holderEntry{},
+	})
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/pools/{address}/history",
+		summary:    "Reserve, mid-price, and TVL history of a watched AMM pool",
+		pathParams: []param{{name: "address", typ: "string"}},
+		queryParams: []param{
+			{name: "from_slot", typ: "integer"},
+			{name: "to_slot", typ: "integer"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.PoolSnapshot{},
+	}, s.handlePoolHistory)
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/mints/{mint}/authority-history",
+		summary:    "Recorded mint and freeze authority changes of a mint",
+		pathParams: []param{{name: "mint", typ: "string"}},
+		response:   []analytics.MintAuthorityEvent{},
+	})
+	s.mux.HandleFunc("/api/v1/mints/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/holders"):
+			s.handleMintHolders(w, r)
+		case strings.HasSuffix(r.URL.Path, "/authority-history"):
+			s.handleMintAuthorityHistory(w, r)
+		default:
+			writeError(w, http.StatusNotFound, errors.New("not found"))
+		}
+	})
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/programs",
+		summary: "Per-program hourly and daily rollup statistics",
+		queryParams: []param{
+			{name: "program", typ: "string"},
+			{name: "period", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.ProgramStats{},
+	}, s.handleProgramAnalytics)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/chain",
+		summary: "Chain-wide hourly and daily rollup statistics",
+		queryParams: []param{
+			{name: "period", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.ChainStats{},
+	}, s.handleChainAnalytics)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/active-addresses",
+		summary: "Distinct active address estimates from HyperLogLog sketches",
+		queryParams: []param{
+			{name: "period", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.ActiveStat{},
+	}, s.handleActiveAddresses)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/validators",
+		summary: "Per-validator vote latency and participation statistics",
+		queryParams: []param{
+			{name: "epoch", typ: "integer"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.ValidatorStats{},
+	}, s.handleValidators)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/analytics/lending",
+		summary: "Per-market lending activity aggregates",
+		queryParams: []param{
+			{name: "protocol", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.LendingMarketStats{},
+	}, s.handleLendingAnalytics)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/markets/{market}/candles",
+		summary: "OHLCV candles built from indexed DEX fills",
+		queryParams: []param{
+			{name: "interval", typ: "string"},
+			{name: "from_time", typ: "string"},
+			{name: "to_time", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.Candle{},
+	}, s.handleCandles)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/accounts/{pubkey}/pnl",
+		summary:    "Realized and unrealized PnL with FIFO cost basis for a tracked wallet",
+		pathParams: []param{{name: "pubkey", typ: "string"}},
+		response:   analytics.WalletPnL{},
+	}, s.handleAccountPnL)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/names/{query}",
+		summary:    "Resolve a .sol name to its owner, or an address back to its name",
+		pathParams: []param{{name: "query", typ: "string"}},
+		response:   nameResponse{},
+	}, s.handleNameLookup)
+	s.handle(route{
+		method:      http.MethodGet,
+		path:        "/api/v1/multisigs/{address}/proposals",
+		summary:     "Squads proposal lifecycle for a multisig, newest action first",
+		pathParams:  []param{{name: "address", typ: "string"}},
+		queryParams: []param{{name: "limit", typ: "integer"}},
+		response:    []analytics.Proposal{},
+	}, s.handleMultisigProposals)
+	s.describe(route{
+		method:      http.MethodGet,
+		path:        "/api/v1/realms/{realm}/proposals",
+		summary:     "SPL Governance proposal lifecycle for a realm, newest action first",
+		pathParams:  []param{{name: "realm", typ: "string"}},
+		queryParams: []param{{name: "limit", typ: "integer"}},
+		response:    []analytics.GovernanceProposal{},
+	})
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/realms/{realm}/stats",
+		summary:    "Aggregate governance activity counters for a realm",
+		pathParams: []param{{name: "realm", typ: "string"}},
+		response:   analytics.RealmStats{},
+	})
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/streams",
+		summary: "Streaming payments, newest action first",
+		queryParams: []param{
+			{name: "recipient", typ: "string"},
+			{name: "status", typ: "string"},
+			{name: "limit", typ: "integer"},
+		},
+		response: []analytics.Stream{},
+	}, s.handleListStreams)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/streams/{address}",
+		summary:    "One streaming payment's schedule and remaining balance",
+		pathParams: []param{{name: "address", typ: "string"}},
+		response:   analytics.Stream{},
+	}, s.handleGetStream)
+	s.handle(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/entities/{address}",
+		summary:    "Heuristic wallet cluster an address belongs to",
+		pathParams: []param{{name: "address", typ: "string"}},
+		response:   entity.Cluster{},
+	}, s.handleEntityLookup)
+	s.mux.HandleFunc("/api/v1/realms/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/proposals"):
+			s.handleRealmProposals(w, r)
+		case strings.HasSuffix(r.URL.Path, "/stats"):
+			s.handleRealmStats(w, r)
+		default:
+			writeError(w, http.StatusNotFound, errors.New("not found"))
+		}
+	})
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/attestation",
+		summary: "Merkle digest of one epoch's indexed transactions",
+		queryParams: []param{
+			{name: "epoch", typ: "integer"},
+		},
+		response: attest.Attestation{},
+	}, s.handleAttestation)
+	s.handle(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/reorgs",
+		summary: "Historical reorg audit log, newest first",
+		queryParams: []param{
+			{name: "limit", typ: "integer"},
+			{name: "offset", typ: "integer"},
+		},
+		response: []models.Reorg{},
+	}, s.handleListReorgs)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/budget",
+		summary:  "RPC call counts and projected credit spend",
+		response: solana.Snapshot{},
+	}, s.handleBudget)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/db/pool",
+		summary:  "Database connection pool metrics",
+		response: postgres.PoolStats{},
+	}, s.handlePoolStats)
+	s.handle(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/latency",
+		summary:  "End-to-end indexing latency percentiles and SLO burn rate",
+		response: latency.Snapshot{},
+	}, s.handleLatency)
+
+	s.describe(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/alerts/rules",
+		summary:  "List alert rules",
+		response: []alerts.Rule{},
+	})
+	s.describe(route{
+		method:   http.MethodPost,
+		path:     "/api/v1/alerts/rules",
+		summary:  "Create or replace an alert rule",
+		request:  alerts.Rule{},
+		response: alerts.Rule{},
+	})
+	s.describe(route{
+		method:     http.MethodDelete,
+		path:       "/api/v1/alerts/rules/{id}",
+		summary:    "Delete an alert rule",
+		pathParams: []param{{name: "id", typ: "string"}},
+	})
+	s.mux.HandleFunc("/api/v1/alerts/rules", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleListAlertRules(w, r)
+		case http.MethodPost:
+			s.handleSetAlertRule(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	s.describe(route{
+		method:  http.MethodGet,
+		path:    "/api/v1/jobs",
+		summary: "List submitted jobs, newest first",
+		queryParams: []param{
+			{name: "limit", typ: "integer"},
+		},
+		response: []jobs.Job{},
+	})
+	s.describe(route{
+		method:   http.MethodPost,
+		path:     "/api/v1/jobs",
+		summary:  "Submit a long-running job",
+		request:  submitJobRequest{},
+		response: jobs.Job{},
+	})
+	s.describe(route{
+		method:     http.MethodGet,
+		path:       "/api/v1/jobs/{id}",
+		summary:    "Job record with state and progress",
+		pathParams: []param{{name: "id", typ: "string"}},
+		response:   jobs.Job{},
+	})
+	s.describe(route{
+		method:     http.MethodDelete,
+		path:       "/api/v1/jobs/{id}",
+		summary:    "Cancel a queued or running job",
+		pathParams: []param{{name: "id", typ: "string"}},
+	})
+	s.mux.HandleFunc("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleListJobs(w, r)
+		case http.MethodPost:
+			s.handleSubmitJob(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	s.mux.HandleFunc("/api/v1/jobs/", s.handleJobByID)
+
+	s.describe(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/schedules",
+		summary:  "Recurring task schedules with recent runs",
+		response: []sched.TaskStatus{},
+	})
+	s.describe(route{
+		method:     http.MethodPost,
+		path:       "/api/v1/schedules/{name}/run",
+		summary:    "Trigger a recurring task immediately",
+		pathParams: []param{{name: "name", typ: "string"}},
+	})
+	s.mux.HandleFunc("/api/v1/schedules", s.handleListSchedules)
+	s.mux.HandleFunc("/api/v1/schedules/", s.handleTriggerSchedule)
+
+	s.describe(route{
+		method:   http.MethodGet,
+		path:     "/api/v1/repair",
+		summary:  "List the partial-block repair queue",
+		response: []repair.Entry{},
+	})
+	s.describe(route{
+		method:   http.MethodPost,
+		path:     "/api/v1/repair",
+		summary:  "Repair one slot immediately",
+		request:  repairRequest{},
+		response: repairResponse{},
+	})
+	s.mux.HandleFunc("/api/v1/repair", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleListRepairs(w, r)
+		case http.MethodPost:
+			s.handleRepairSlot(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	s.mux.HandleFunc("/api/v1/alerts/rules/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleAlertRuleByID(w, r)
+	})
+
+	s.mux.HandleFunc("/debug/rpc", s.handleRPCHealth)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("/docs", s.handleDocs)
+	s.mux.HandleFunc("/schema", s.handleSchema)
+	s.mux.HandleFunc("/api/versions", s.handleVersions)
+}
+
+type healthResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type slotResponse struct {
+	Slot uint64 `json:"slot"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "healthy", Timestamp: time.Now().UTC()})
+}
+
+func (s *Server) handleLatestSlot(w http.ResponseWriter, r *http.Request) {
+	slot, err := s.store.LatestSlot(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slotResponse{Slot: slot})
+}
+
+func (s *Server) handleGetBlock(w http.ResponseWriter, r *http.Request) {
+	raw := strings.TrimPrefix(r.URL.Path, "/api/v1/blocks/")
+	slot, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid slot"))
+		return
+	}
+	block, err := s.store.GetBlock(r.Context(), slot)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, errors.New("block not indexed"))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, block)
+}
+
+func (s *Server) handleListTokenAccounts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	accounts, err := s.store.ListTokenAccounts(r.Context(), storage.TokenAccountFilter{
+		Owner:  q.Get("owner"),
+		Mint:   q.Get("mint"),
+		Limit:  queryInt(q.Get("limit"), 100),
+		Offset: queryInt(q.Get("offset"), 0),
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if accounts == nil {
+		accounts = []models.TokenAccount{}
+	}
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (s *Server) handleGetProgram(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/programs/")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, errors.New("program id is required"))
+		return
+	}
+	program, err := s.store.GetProgram(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, errors.New("program not tracked"))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, program)
+}
+
+func (s *Server) handleListTransfers(w http.ResponseWriter, r *http.Request) {
+	ui, ok := amountsUI(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	filter := storage.TransferFilter{
+		Address:  q.Get("address"),
+		Mint:     q.Get("mint"),
+		FromSlot: queryUint(q.Get("from_slot")),
+		ToSlot:   queryUint(q.Get("to_slot")),
+		Limit:    queryInt(q.Get("limit"), 100),
+		Offset:   queryInt(q.Get("offset"), 0),
+	}
+	if exportFormat(r) != "" {
+		limit, ok := exportLimit(w, r)
+		if !ok {
+			return
+		}
+		filter.Limit = limit
+	}
+	transfers, err := s.store.ListTransfers(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if transfers == nil {
+		transfers = []models.Transfer{}
+	}
+	if writeExport(w, r, transfers) {
+		return
+	}
+	if ui {
+		writeJSON(w, http.StatusOK, uiTransfers(transfers))
+		return
+	}
+	writeJSON(w, http.StatusOK, transfers)
+}
+
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := storage.EventFilter{
+		Program:  q.Get("program"),
+		Type:     q.Get("type"),
+		FromSlot: queryUint(q.Get("from_slot")),
+		ToSlot:   queryUint(q.Get("to_slot")),
+		Limit:    queryInt(q.Get("limit"), 100),
+		Offset:   queryInt(q.Get("offset"), 0),
+	}
+	if exportFormat(r) != "" {
+		limit, ok := exportLimit(w, r)
+		if !ok {
+			return
+		}
+		filter.Limit = limit
+	}
+	events, err := s.store.ListEvents(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if events == nil {
+		events = []models.Event{}
+	}
+	if writeExport(w, r, events) {
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// handleEventSchemas serves GET /api/v1/events/schemas.
+func (s *Server) handleEventSchemas(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, schema.All())
+}
+
+type watchSignatureRequest struct {
+	Signature  string `json:"signature"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+type watchSignatureResponse struct {
+	Signature string `json:"signature"`
+	Pending   int    `json:"pending"`
+}
+
+func (s *Server) handleWatchSignature(w http.ResponseWriter, r *http.Request) {
+	if s.watcher == nil {
+		writeError(w, http.StatusNotFound, errors.New("signature watching not enabled"))
+		return
+	}
+	var req watchSignatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	if req.Signature == "" {
+		writeError(w, http.StatusBadRequest, errors.New("signature is required"))
+		return
+	}
+	s.watcher.Watch(req.Signature, req.WebhookURL)
+	writeJSON(w, http.StatusAccepted, watchSignatureResponse{
+		Signature: req.Signature,
+		Pending:   s.watcher.Pending(),
+	})
+}
+
+type progressResponse struct {
+	Live     indexer.LaneSnapshot  `json:"live"`
+	Backfill indexer.LaneSnapshot  `json:"backfill"`
+	Replay   *indexer.LaneSnapshot `json:"replay,omitempty"`
+}
+
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	if s.progress == nil {
+		writeError(w, http.StatusNotFound, errors.New("progress reporting not enabled"))
+		return
+	}
+	live, backfill := s.progress()
+	out := progressResponse{Live: live, Backfill: backfill}
+	if s.replayProgress != nil {
+		replay := s.replayProgress()
+		out.Replay = &replay
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleBudget(w http.ResponseWriter, r *http.Request) {
+	if s.budget == nil {
+		writeError(w, http.StatusNotFound, errors.New("budget tracking not enabled"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.budget.Snapshot())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func queryUint(raw string) uint64 {
+	v, _ := strconv.ParseUint(raw, 10, 64)
+	return v
+}
+
+func queryInt(raw string, def int) int {
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// handleListCustomTable serves GET /api/v1/tables/{name}, returning rows
+// from a table declared in the mapping schema.
+func (s *Server) handleListCustomTable(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/tables/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusBadRequest, errors.New("table name is required"))
+		return
+	}
+	q := r.URL.Query()
+	rows, err := s.store.ListCustomRows(r.Context(), name, queryInt(q.Get("limit"), 100), queryInt(q.Get("offset"), 0))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("unknown table"))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
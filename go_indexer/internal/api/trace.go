@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// Fund-flow tracing follows SOL and token transfers forward from a
+// source address across a bounded number of hops, returning the flow
+// tree — where value went, hop by hop — from indexed data only.
+
+const (
+	// traceDefaultHops and traceMaxHops bound the trace depth.
+	traceDefaultHops = 3
+	traceMaxHops     = 6
+	// traceNodeBudget caps the total edges in one trace, so a hub
+	// address cannot explode the response.
+	traceNodeBudget = 500
+	// traceFanout caps the outgoing edges followed per address per hop.
+	traceFanout = 50
+)
+
+// flowEdge is one traced transfer plus the onward flows from its
+// destination.
+type flowEdge struct {
+	Signature   string `json:"signature"`
+	Slot        uint64 `json:"slot"`
+	Destination string `json:"destination"`
+	Mint        string `json:"mint,omitempty"`
+	Amount      uint64 `json:"amount"`
+	Decimals    uint8  `json:"decimals"`
+	// Hops are the transfers out of Destination at or after Slot.
+	Hops []flowEdge `json:"hops,omitempty"`
+}
+
+// traceResponse is the flow tree rooted at the source address.
+type traceResponse struct {
+	Address   string     `json:"address"`
+	FromSlot  uint64     `json:"from_slot"`
+	MaxHops   int        `json:"max_hops"`
+	MinAmount uint64     `json:"min_amount,omitempty"`
+	Mint      string     `json:"mint,omitempty"`
+	Edges     []flowEdge `json:"edges"`
+	// Truncated is set when the node budget cut the tree short.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// tracer carries one request's bounds and cycle/budget state.
+type tracer struct {
+	store     storage.Store
+	minAmount uint64
+	mint      string
+	maxHops   int
+	budget    int
+	truncated bool
+	// visited holds addresses already expanded, so circular flows
+	// (peeling chains, wash loops) terminate.
+	visited map[string]struct{}
+}
+
+// handleTraceFlows serves GET /api/v1/addresses/{address}/trace.
+func (s *Server) handleTraceFlows(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/addresses/")
+	address, ok := strings.CutSuffix(rest, "/trace")
+	if !ok || address == "" || strings.Contains(address, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	q := r.URL.Query()
+	fromSlot := queryUint(q.Get("from_slot"))
+	if sig := q.Get("signature"); sig != "" {
+		tx, err := s.store.GetTransaction(r.Context(), sig)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("starting transaction not indexed"))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		fromSlot = tx.Slot
+	}
+	maxHops := queryInt(q.Get("max_hops"), traceDefaultHops)
+	if maxHops < 1 || maxHops > traceMaxHops {
+		writeError(w, http.StatusBadRequest,
+			errors.New("max_hops must be between 1 and 6"))
+		return
+	}
+	t := &tracer{
+		store:     s.store,
+		minAmount: queryUint(q.Get("min_amount")),
+		mint:      q.Get("mint"),
+		maxHops:   maxHops,
+		budget:    traceNodeBudget,
+		visited:   map[string]struct{}{address: {}},
+	}
+	edges, err := t.expand(r.Context(), address, fromSlot, 1)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if edges == nil {
+		edges = []flowEdge{}
+	}
+	writeJSON(w, http.StatusOK, traceResponse{
+		Address:   address,
+		FromSlot:  fromSlot,
+		MaxHops:   maxHops,
+		MinAmount: t.minAmount,
+		Mint:      t.mint,
+		Edges:     edges,
+		Truncated: t.truncated,
+	})
+}
+
+// expand follows the outgoing transfers of address at or after
+// fromSlot, recursing one hop deeper per destination.
+func (t *tracer) expand(ctx context.Context, address string, fromSlot uint64, hop int) ([]flowEdge, error) {
+	transfers, err := t.store.ListTransfers(ctx, storage.TransferFilter{
+		Address:  address,
+		Mint:     t.mint,
+		FromSlot: fromSlot,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var edges []flowEdge
+	for _, tr := range transfers {
+		// The store filter matches both directions; only outgoing
+		// transfers continue the flow.
+		if tr.Source != address || tr.Destination == "" || tr.Destination == address {
+			continue
+		}
+		if t.minAmount > 0 && tr.Amount < t.minAmount {
+			continue
+		}
+		if len(edges) >= traceFanout {
+			t.truncated = true
+			break
+		}
+		if t.budget <= 0 {
+			t.truncated = true
+			break
+		}
+		t.budget--
+		edge := flowEdge{
+			Signature:   tr.Signature,
+			Slot:        tr.Slot,
+			Destination: tr.Destination,
+			Mint:        tr.Mint,
+			Amount:      tr.Amount,
+			Decimals:    tr.Decimals,
+		}
+		if _, seen := t.visited[tr.Destination]; !seen && hop < t.maxHops {
+			t.visited[tr.Destination] = struct{}{}
+			edge.Hops, err = t.expand(ctx, tr.Destination, tr.Slot, hop+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		edges = append(edges, edge)
+	}
+	return edges, nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// API versioning and compatibility.
+//
+// Every resource route lives under /api/v1. Within a major version the
+// API only evolves compatibly: new endpoints and new response fields
+// may appear, but existing fields keep their name, type, and meaning,
+// and existing routes keep their paths. Breaking changes get a new
+// /api/v2 prefix served alongside v1; routes scheduled for removal
+// first announce it with Deprecation and Sunset headers (see the
+// deprecated route flag).
+
+// CurrentVersion is the newest API version this binary serves.
+const CurrentVersion = "v1"
+
+// supportedVersions lists every version still served, oldest first.
+var supportedVersions = []string{"v1"}
+
+// versionInfo is one entry of the /api/versions response.
+type versionInfo struct {
+	Version string `json:"version"`
+	Prefix  string `json:"prefix"`
+	Status  string `json:"status"` // current or deprecated
+}
+
+// handleVersions serves GET /api/versions, the negotiation entry point:
+// clients pick a listed prefix and may pin it via the X-API-Version
+// request header.
+func (s *Server) handleVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	out := make([]versionInfo, 0, len(supportedVersions))
+	for _, v := range supportedVersions {
+		status := "deprecated"
+		if v == CurrentVersion {
+			status = "current"
+		}
+		out = append(out, versionInfo{Version: v, Prefix: "/api/" + v, Status: status})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// versionMiddleware rejects requests pinned to a version this binary no
+// longer serves and stamps every response with the version that handled
+// it.
+func (s *Server) versionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requested := r.Header.Get("X-API-Version"); requested != "" {
+			supported := false
+			for _, v := range supportedVersions {
+				if requested == v {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				writeError(w, http.StatusNotAcceptable, fmt.Errorf(
+					"unsupported API version %q; supported: %s",
+					requested, strings.Join(supportedVersions, ", ")))
+				return
+			}
+		}
+		w.Header().Set("X-API-Version", CurrentVersion)
+		next.ServeHTTP(w, r)
+	})
+}
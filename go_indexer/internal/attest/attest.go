@@ -0,0 +1,146 @@
+// Package attest derives comparable dataset digests from the indexed
+// transactions. For one epoch it builds a Merkle root over the
+// canonicalized stored transactions, so two independent deployments can
+// establish that they indexed identical data by exchanging one hash per
+// epoch instead of the data itself.
+package attest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// epochSlots is the number of slots per epoch on mainnet and testnet.
+const epochSlots = 432000
+
+// pageSize is how many transactions one attestation page reads.
+const pageSize = 1000
+
+// Attestation is one epoch's dataset digest.
+type Attestation struct {
+	Epoch    uint64 `json:"epoch"`
+	FromSlot uint64 `json:"from_slot"`
+	ToSlot   uint64 `json:"to_slot"`
+	// Transactions is the number of leaves under the root.
+	Transactions int `json:"transactions"`
+	// Root is the hex SHA-256 Merkle root over the sorted leaves.
+	Root string `json:"root"`
+}
+
+// Attestor computes and caches epoch digests over the shared store.
+type Attestor struct {
+	store storage.Store
+
+	mu    sync.Mutex
+	cache map[uint64]Attestation
+}
+
+// New creates an Attestor over the store.
+func New(store storage.Store) *Attestor {
+	return &Attestor{store: store, cache: make(map[uint64]Attestation)}
+}
+
+// Attest returns the epoch's digest, computing it from storage on first
+// request. Digests of epochs fully behind the indexed tip are cached;
+// the current epoch is recomputed per request because it is still
+// growing.
+func (a *Attestor) Attest(ctx context.Context, epoch uint64) (Attestation, error) {
+	a.mu.Lock()
+	if cached, ok := a.cache[epoch]; ok {
+		a.mu.Unlock()
+		return cached, nil
+	}
+	a.mu.Unlock()
+
+	att := Attestation{
+		Epoch:    epoch,
+		FromSlot: epoch * epochSlots,
+		ToSlot:   (epoch+1)*epochSlots - 1,
+	}
+	var leaves [][]byte
+	filter := storage.TransactionFilter{
+		FromSlot: att.FromSlot,
+		ToSlot:   att.ToSlot,
+		Limit:    pageSize,
+	}
+	for {
+		page, err := a.store.SearchTransactions(ctx, filter)
+		if err != nil {
+			return Attestation{}, fmt.Errorf("attest epoch %d: %w", epoch, err)
+		}
+		for i := range page {
+			leaves = append(leaves, leaf(&page[i]))
+		}
+		if len(page) < pageSize {
+			break
+		}
+		filter.Offset += pageSize
+	}
+	// Sorting makes the root independent of storage iteration order, so
+	// deployments with different stores still agree.
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i], leaves[j]) < 0 })
+	att.Transactions = len(leaves)
+	att.Root = hex.EncodeToString(merkleRoot(leaves))
+
+	if latest, err := a.store.LatestSlot(ctx); err == nil && latest > att.ToSlot {
+		a.mu.Lock()
+		a.cache[epoch] = att
+		a.mu.Unlock()
+	}
+	return att, nil
+}
+
+// Warm precomputes and caches the digest of the newest fully indexed
+// epoch, so the first API request for it does not pay the full scan. It
+// is run on a schedule by the scheduler.
+func (a *Attestor) Warm(ctx context.Context) error {
+	latest, err := a.store.LatestSlot(ctx)
+	if err != nil || latest < epochSlots {
+		return err
+	}
+	_, err = a.Attest(ctx, latest/epochSlots-1)
+	return err
+}
+
+// leaf canonicalizes one transaction. Block times are excluded: derived
+// timestamps legitimately differ between deployments (see
+// internal/blocktime), while these fields are ledger facts.
+func leaf(tx *models.Transaction) []byte {
+	sum := sha256.Sum256([]byte(tx.Signature + "|" +
+		strconv.FormatUint(tx.Slot, 10) + "|" +
+		strconv.FormatUint(tx.Fee, 10) + "|" +
+		strconv.FormatBool(tx.Success)))
+	return sum[:]
+}
+
+// merkleRoot folds the leaves pairwise; an odd node is promoted
+// unchanged. Zero leaves hash to SHA-256 of the empty input.
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+	level := leaves
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			sum := sha256.Sum256(append(append(make([]byte, 0, 64), level[i]...), level[i+1]...))
+			next = append(next, sum[:])
+		}
+		level = next
+	}
+	return level[0]
+}
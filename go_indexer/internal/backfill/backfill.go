@@ -0,0 +1,249 @@
+// Package backfill runs large historical backfills as leased chunks.
+// The requested slot range is split into fixed-width chunks persisted in
+// the backfill_ranges custom table; workers lease chunks and record
+// their progress as they go, so a crashed or resubmitted backfill
+// resumes from the persisted chunk state instead of starting over.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// rangeTable is the custom table chunk state is persisted in. Progress
+// updates are plain inserts; the newest row per chunk wins on reload.
+const rangeTable = "backfill_ranges"
+
+// defaultChunkSlots is the chunk width when the caller passes 0.
+const defaultChunkSlots = 10_000
+
+// progressEvery is how many slots a worker processes between persisted
+// progress rows; smaller values resume tighter at more write cost.
+const progressEvery = 200
+
+// reportInterval is how often the progress callback is invoked.
+const reportInterval = 5 * time.Second
+
+// Chunk states persisted in backfill_ranges.
+const (
+	stateLeased = "leased"
+	stateDone   = "done"
+)
+
+// chunk is one leased slice of the requested range. next is the first
+// slot not yet processed.
+type chunk struct {
+	from, to uint64
+	next     uint64
+	done     bool
+}
+
+// Coordinator splits a range, leases chunks to workers, and persists
+// their progress. process handles one slot and is normally the
+// indexer's ProcessSlot.
+type Coordinator struct {
+	store   storage.Store
+	process func(ctx context.Context, slot uint64) error
+	log     *slog.Logger
+}
+
+// New creates a Coordinator over the shared store.
+func New(store storage.Store, process func(ctx context.Context, slot uint64) error, log *slog.Logger) *Coordinator {
+	return &Coordinator{store: store, process: process, log: log}
+}
+
+// Run backfills [from, to] with the given worker count and chunk width,
+// resuming any persisted chunk progress for the same boundaries. report,
+// when non-nil, periodically receives the overall percentage and an ETA
+// extrapolated from the measured slot rate.
+func (c *Coordinator) Run(ctx context.Context, from, to, chunkSlots uint64, workers int, report func(pct float64, eta time.Duration)) error {
+	if to < from {
+		return fmt.Errorf("backfill: to_slot %d below from_slot %d", to, from)
+	}
+	if chunkSlots == 0 {
+		chunkSlots = defaultChunkSlots
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if err := c.store.EnsureCustomTable(ctx, rangeTable); err != nil {
+		return fmt.Errorf("ensure range table: %w", err)
+	}
+	persisted, err := c.loadChunks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var chunks []*chunk
+	var total, resumed uint64
+	for start := from; start <= to && start >= from; start += chunkSlots {
+		end := start + chunkSlots - 1
+		if end > to {
+			end = to
+		}
+		ck := &chunk{from: start, to: end, next: start}
+		if p, ok := persisted[start]; ok && p.to == end {
+			ck.next, ck.done = p.next, p.done
+			if ck.done {
+				ck.next = ck.to + 1
+			}
+		}
+		total += end - start + 1
+		resumed += ck.next - ck.from
+		chunks = append(chunks, ck)
+	}
+	c.log.Info("parallel backfill starting", "from", from, "to", to,
+		"chunks", len(chunks), "workers", workers, "resumed_slots", resumed)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := make(chan *chunk, len(chunks))
+	for _, ck := range chunks {
+		if !ck.done {
+			queue <- ck
+		}
+	}
+	close(queue)
+
+	var processed atomic.Uint64
+	start := time.Now()
+	if report != nil {
+		go func() {
+			ticker := time.NewTicker(reportInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-runCtx.Done():
+					return
+				case <-ticker.C:
+					done := resumed + processed.Load()
+					pct := float64(done) / float64(total) * 100
+					var eta time.Duration
+					if rate := float64(processed.Load()) / time.Since(start).Seconds(); rate > 0 {
+						eta = time.Duration(float64(total-done)/rate) * time.Second
+					}
+					report(pct, eta)
+				}
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ck := range queue {
+				if err := c.runChunk(runCtx, ck, &processed); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// runChunk leases one chunk and processes its remaining slots, persisting
+// progress every progressEvery slots and a done row at the end.
+func (c *Coordinator) runChunk(ctx context.Context, ck *chunk, processed *atomic.Uint64) error {
+	if err := c.saveChunk(ctx, ck, stateLeased); err != nil {
+		return err
+	}
+	sinceSave := uint64(0)
+	for ck.next <= ck.to {
+		if ctx.Err() != nil {
+			// Persist where we stopped so the next run resumes here.
+			c.saveChunk(context.WithoutCancel(ctx), ck, stateLeased)
+			return ctx.Err()
+		}
+		if err := c.process(ctx, ck.next); err != nil {
+			c.saveChunk(context.WithoutCancel(ctx), ck, stateLeased)
+			return fmt.Errorf("chunk %d-%d: slot %d: %w", ck.from, ck.to, ck.next, err)
+		}
+		ck.next++
+		processed.Add(1)
+		if sinceSave++; sinceSave >= progressEvery {
+			sinceSave = 0
+			if err := c.saveChunk(ctx, ck, stateLeased); err != nil {
+				return err
+			}
+		}
+	}
+	ck.done = true
+	return c.saveChunk(ctx, ck, stateDone)
+}
+
+// saveChunk appends the chunk's current state to the range table.
+func (c *Coordinator) saveChunk(ctx context.Context, ck *chunk, state string) error {
+	if err := c.store.InsertCustomRow(ctx, rangeTable, map[string]any{
+		"from_slot":  ck.from,
+		"to_slot":    ck.to,
+		"state":      state,
+		"next_slot":  ck.next,
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return fmt.Errorf("save chunk %d-%d: %w", ck.from, ck.to, err)
+	}
+	return nil
+}
+
+// loadChunks reduces the persisted rows to the newest state per chunk,
+// keyed by the chunk's first slot.
+func (c *Coordinator) loadChunks(ctx context.Context) (map[uint64]*chunk, error) {
+	rows, err := c.store.ListCustomRows(ctx, rangeTable, 0, 0)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list chunks: %w", err)
+	}
+	out := make(map[uint64]*chunk)
+	for _, row := range rows {
+		from := numeric(row["from_slot"])
+		ck := out[from]
+		if ck == nil {
+			ck = &chunk{from: from, to: numeric(row["to_slot"])}
+			out[from] = ck
+		}
+		if next := numeric(row["next_slot"]); next > ck.next {
+			ck.next = next
+		}
+		if row["state"] == stateDone {
+			ck.done = true
+		}
+	}
+	return out, nil
+}
+
+// numeric decodes a custom-table number; rows reloaded through JSON
+// carry numbers as float64.
+func numeric(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		return uint64(n)
+	case float64:
+		return uint64(n)
+	}
+	return 0
+}
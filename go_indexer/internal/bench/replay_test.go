@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// benchBlocks loads recorded blocks from BENCH_BLOCKS_DIR when set and
+// falls back to synthetic blocks otherwise.
+func benchBlocks(b *testing.B) []RecordedBlock {
+	b.Helper()
+	if dir := os.Getenv("BENCH_BLOCKS_DIR"); dir != "" {
+		blocks, err := LoadBlocksDir(dir)
+		if err != nil {
+			b.Fatalf("load %s: %v", dir, err)
+		}
+		return blocks
+	}
+	return GenerateBlocks(100, 20)
+}
+
+func BenchmarkReplay(b *testing.B) {
+	blocks := benchBlocks(b)
+	txs := 0
+	for _, rb := range blocks {
+		txs += len(rb.Block.Transactions)
+	}
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				stats, err := Replay(context.Background(), blocks, Options{Concurrency: concurrency})
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.ReportMetric(stats.BlocksPerSec(), "blocks/s")
+			}
+		})
+	}
+}
+
+func TestReplaySynthetic(t *testing.T) {
+	blocks := GenerateBlocks(10, 5)
+	stats, err := Replay(context.Background(), blocks, Options{Concurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Blocks != 10 || stats.Transactions != 50 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
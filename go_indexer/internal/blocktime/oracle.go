@@ -0,0 +1,72 @@
+// Package blocktime derives reliable block timestamps. Validator-reported
+// blockTime can be null or skew backwards between neighboring slots; the
+// Oracle interpolates missing values from the last trusted observation
+// and corrects non-monotonic ones, so time-range queries over stored data
+// behave. Both the raw and the derived timestamp are kept on the block
+// row.
+package blocktime
+
+import (
+	"sync"
+	"time"
+)
+
+// slotDuration is the nominal Solana slot time used for interpolation.
+const slotDuration = 400 * time.Millisecond
+
+// Time sources recorded on the block row.
+const (
+	SourceValidator    = "validator"    // raw timestamp used as-is
+	SourceInterpolated = "interpolated" // raw was null; derived from neighbors
+	SourceCorrected    = "corrected"    // raw skewed backwards; clamped monotonic
+)
+
+// Oracle tracks the last trusted (slot, time) observation and derives
+// timestamps for blocks around it. It is safe for concurrent use.
+type Oracle struct {
+	mu       sync.Mutex
+	lastSlot uint64
+	lastTime time.Time
+}
+
+// NewOracle creates an Oracle with no observations yet.
+func NewOracle() *Oracle { return &Oracle{} }
+
+// Derive returns the timestamp to store for slot given the raw
+// validator-reported value (nil when the RPC returned null), along with
+// the source label. Blocks may arrive out of slot order under concurrent
+// fetching; only observations from newer slots move the reference.
+func (o *Oracle) Derive(slot uint64, raw *int64) (derived time.Time, source string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if raw == nil {
+		if o.lastTime.IsZero() {
+			// Nothing to interpolate from yet.
+			return time.Time{}, SourceInterpolated
+		}
+		return o.extrapolate(slot), SourceInterpolated
+	}
+
+	t := time.Unix(*raw, 0).UTC()
+	if !o.lastTime.IsZero() && slot > o.lastSlot && t.Before(o.lastTime) {
+		// A later slot must not be earlier in time; clamp onto the
+		// monotonic expectation instead of trusting the skewed report.
+		derived = o.extrapolate(slot)
+		o.lastSlot, o.lastTime = slot, derived
+		return derived, SourceCorrected
+	}
+	if slot >= o.lastSlot {
+		o.lastSlot, o.lastTime = slot, t
+	}
+	return t, SourceValidator
+}
+
+// extrapolate projects the reference observation to slot at the nominal
+// slot duration. Callers hold o.mu.
+func (o *Oracle) extrapolate(slot uint64) time.Time {
+	if slot >= o.lastSlot {
+		return o.lastTime.Add(time.Duration(slot-o.lastSlot) * slotDuration)
+	}
+	return o.lastTime.Add(-time.Duration(o.lastSlot-slot) * slotDuration)
+}
@@ -0,0 +1,626 @@
+// Package config loads the indexer configuration from environment
+// variables, matching the variables documented in the repository README.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the full indexer configuration.
+type Config struct {
+	RPCURL string
+	// RPCURLs lists additional RPC endpoints; with more than one total
+	// the client health-scores them by slot skew and latency and routes
+	// each call to the best, exposed at /debug/rpc.
+	RPCURLs []string
+	// ArchivalRPCURL is an archival node; getBlock/getTransaction calls
+	// for slots the standard endpoints have purged route there
+	// automatically. Empty surfaces purged slots as errors.
+	ArchivalRPCURL string
+	// Namespace prefixes everything this deployment publishes to shared
+	// infrastructure — the Postgres schema its tables live in, schema
+	// registry subjects, and metric names — so indexers for different
+	// networks or teams can share a database or cluster without
+	// collisions. Empty (the default) keeps the unprefixed names.
+	Namespace string
+	// Chain names the SVM network behind the RPC endpoints: "solana"
+	// (default), "eclipse", or "svm" for generic rollups and localnet
+	// forks. It selects the quirk adapter blocks are read through.
+	Chain         string
+	StartSlot     uint64
+	StartAtLatest bool // START_SLOT=latest
+	// StartTime starts indexing at the first slot whose block time is at
+	// or after it; the slot is resolved against the RPC node at startup.
+	// START_SLOT wins when both are set.
+	StartTime      time.Time
+	EndSlot        uint64 // 0 keeps indexing forever; otherwise stop after this slot
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxConcurrency int
+	// MinConcurrency enables worker auto-scaling: when set below
+	// MaxConcurrency the indexer adjusts the worker count between the
+	// two bounds from queue depth and RPC latency. Zero keeps the fixed
+	// MaxConcurrency behavior.
+	MinConcurrency int
+	ServerPort     int
+	// GRPCPort enables the gRPC streaming service (requires a -tags grpc
+	// build). 0 disables it.
+	GRPCPort int
+	// GRPCCodec is the stream's wire encoding: "json" (default) or
+	// "proto" for protobuf per proto/indexer.proto.
+	GRPCCodec string
+	// APIKeys maps static API keys to their role (read, analytics,
+	// admin), parsed from API_KEYS as "key=role" pairs. Empty leaves the
+	// API unauthenticated.
+	APIKeys map[string]string
+	// OIDCIssuer enables bearer JWT authentication against the issuer's
+	// JWKS as an alternative to static keys. OIDCRoleClaim names the
+	// claim mapped to a role; OIDCDefaultRole applies when the claim has
+	// no recognized value.
+	OIDCIssuer      string
+	OIDCAudience    string
+	OIDCRoleClaim   string
+	OIDCDefaultRole string
+	LogLevel        string
+	// AccessLog enables the API access log: "stdout" or a file path.
+	// Empty disables it.
+	AccessLog   string
+	DatabaseURL string
+	// CacheSlots keeps the last N slots of blocks and transfers in an
+	// in-process columnar cache, bounded by CacheMaxMB. 0 disables it.
+	CacheSlots uint64
+	CacheMaxMB int
+	// DatabaseURLGroups routes table groups to their own databases,
+	// parsed from DATABASE_URL_GROUPS as "group=url" pairs with the
+	// groups named in storage (raw, decoded, analytics). Groups without
+	// an entry stay on DATABASE_URL.
+	DatabaseURLGroups map[string]string
+	// DatabaseURLRead, when set, is a read replica; API reads route to it
+	// unless its indexed slot lags the primary by more than
+	// ReplicaMaxLagSlots.
+	DatabaseURLRead    string
+	ReplicaMaxLagSlots uint64
+
+	// AggregateUpstreams switches the process into aggregator mode: it
+	// consumes the event feeds of the listed upstream indexer base URLs
+	// into a merged canonical store instead of indexing RPC itself.
+	AggregateUpstreams []string
+	AggregateConsumer  string // consumer name committed to the upstreams
+	Profiles           []ProfileConfig
+	AlertRulesFile     string // JSON array of alert rules loaded at startup
+	// WatchMints seeds a built-in authority-change alert rule per listed
+	// mint, on top of any rules from AlertRulesFile.
+	WatchMints []string
+	TablesFile string // YAML schema for custom mapped tables
+	// PoolsFile is a JSON array of watched AMM pool declarations; see
+	// analytics.PoolConfig.
+	PoolsFile string
+	// PnLWallets lists wallets to track FIFO cost basis and PnL for;
+	// empty disables the /accounts/{pubkey}/pnl endpoint.
+	PnLWallets []string
+	PluginsDir string // directory of WASM decoder modules
+	TxFilter   string // global transaction selection expression
+	// FetchMetadata enables async off-chain metadata enrichment: events
+	// carrying a uri get their metadata fetched through the hardened
+	// fetcher and delivered via record_updated events. The domain lists
+	// constrain which hosts the fetcher will talk to; see internal/fetch.
+	FetchMetadata     bool
+	FetchAllowDomains []string
+	FetchDenyDomains  []string
+	// IPFSGateways and ArweaveGateways override the default public
+	// gateways ipfs:// and ar:// URIs resolve through; see
+	// internal/gateway.
+	IPFSGateways    []string
+	ArweaveGateways []string
+	// ScreeningDenylist enables compliance screening of transaction
+	// parties: a file path or HTTP URL serving one "address[,reason]"
+	// entry per line.
+	ScreeningDenylist string
+	// BootstrapProgram captures the program's full account state via
+	// getProgramAccounts on first start, records the snapshot slot as the
+	// state baseline, and starts indexing right after it.
+	BootstrapProgram string
+	// Firehose mode streams raw block payloads to a sink instead of
+	// indexing: FirehoseDir appends JSON-line segment files to a
+	// directory, FirehoseURL POSTs each block to a collector endpoint.
+	// Setting either replaces the whole indexing pipeline; see
+	// internal/firehose.
+	FirehoseDir string
+	FirehoseURL string
+	// SignaturesOnly stores only (signature, slot, fee payer, program
+	// ids, status, block time) per transaction — no instruction payloads,
+	// transfers, or events — cutting storage roughly 20x for deployments
+	// that only need existence and lookup queries.
+	SignaturesOnly bool
+	// IndexVotes enables per-validator vote latency and participation
+	// analytics decoded from vote program transactions.
+	IndexVotes bool
+	// RedactRules enables field-level redaction before storage, e.g.
+	// "event.data.memo=drop,transfer.source=hash"; RedactSalt salts the
+	// hashes.
+	RedactRules string
+	RedactSalt  string
+
+	// Notifier channels. Each channel is enabled by setting its env vars;
+	// NotifyTemplate optionally overrides the message layout and
+	// NotifyMinInterval rate limits each channel independently.
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+	TelegramBotToken  string
+	TelegramChatID    string
+	NotifyTemplate    string
+	NotifyMinInterval time.Duration
+
+	// Sinks lists the enabled push sinks: "stream", "notify", "grpc".
+	// Empty enables every sink the rest of the configuration sets up.
+	Sinks []string
+	// SchemaRegistryURL, when set, has the canonical event schemas
+	// registered with a Confluent-compatible schema registry at startup,
+	// for deployments bridging the push sinks into Kafka.
+	SchemaRegistryURL string
+	// Per-sink emission policy: the minimum commitment at which each
+	// push sink emits and whether it re-emits commitment upgrade and
+	// rollback copies. See outbox.SinkPolicy.
+	StreamMinCommitment  string
+	StreamReemitUpgrades bool
+	NotifyMinCommitment  string
+	NotifyReemitUpgrades bool
+
+	// Health monitor thresholds and alert destinations. SMTP is enabled
+	// by SMTPAddr and PagerDuty by PagerDutyRoutingKey.
+	HealthCheckInterval   time.Duration
+	HealthStallAfter      time.Duration
+	HealthMaxErrorsPerMin int
+	// HealthHaltAfter is how long the cluster tip may stay frozen across
+	// all scored RPC endpoints before the halt conditions fire.
+	HealthHaltAfter     time.Duration
+	SMTPAddr            string // host:port
+	SMTPFrom            string
+	SMTPTo              []string
+	SMTPUsername        string
+	SMTPPassword        string
+	PagerDutyRoutingKey string
+
+	// End-to-end latency SLO: a fraction LatencySLOObjective of
+	// transactions should be committed within LatencySLOTarget of
+	// their block's blockTime. LatencySLOBurnAlert is the burn rate
+	// above which the health monitor's freshness condition fires.
+	LatencySLOTarget    time.Duration
+	LatencySLOObjective float64
+	LatencySLOBurnAlert float64
+
+	// Postgres partition maintenance, consumed by the pgx-backed store.
+	PartitionSlots     uint64 // slot width of one partition
+	PartitionPreCreate int    // partitions created ahead of the indexer
+	PartitionRetain    int    // partitions kept before dropping; 0 keeps all
+
+	// Cold storage tiering. ColdDir enables it: rows older than
+	// ColdRetainSlots behind the tip migrate to JSON-line archives there,
+	// and fully-cold range queries are served from the archive. Size
+	// ColdRetainSlots below the PartitionRetain window so rows are
+	// archived before their partition drops.
+	ColdDir         string
+	ColdRetainSlots uint64
+
+	// AccountIndexSlots sizes the in-memory inverted account index
+	// serving /accounts/{address}/transactions; 0 disables it.
+	AccountIndexSlots uint64
+
+	// Schedules overrides the built-in cron specs of recurring tasks,
+	// e.g. "cold_sweep=*/5 * * * *;attest_warm=0 3 * * *". Pairs are
+	// ';'-separated because cron lists use commas.
+	Schedules map[string]string
+
+	// Postgres connection pool sizing, consumed by the pgx-backed store.
+	DBMaxConns          int
+	DBMinConns          int
+	DBConnIdleTime      time.Duration
+	DBConnLifetime      time.Duration
+	DBHealthCheckPeriod time.Duration
+	DBStmtCacheSize     int
+
+	// RPC budget tracking.
+	RPCProvider       string  // helius, quicknode, triton, or generic
+	RPCMonthlyCredits float64 // 0 disables the budget limit
+
+	// Backfill lane. When BackfillEndSlot > 0 a second lane indexes the
+	// historical range [BackfillStartSlot, BackfillEndSlot] using capacity
+	// left over by the live lane.
+	BackfillStartSlot uint64
+	BackfillEndSlot   uint64
+	LiveCapacityPct   int // share of MaxConcurrency reserved for the live lane
+
+	// Backward replay lane. When ReplayBackward is set a lane indexes
+	// from the chain tip toward ReplayEndSlot (newest first), resuming
+	// from its checkpointed frontier across restarts.
+	ReplayBackward bool
+	ReplayEndSlot  uint64
+}
+
+// SinkEnabled reports whether the named push sink should be wired up.
+func (c *Config) SinkEnabled(name string) bool {
+	if len(c.Sinks) == 0 {
+		return true
+	}
+	for _, sink := range c.Sinks {
+		if sink == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileConfig is one named indexing profile. Each profile has its own
+// program/account filters, processor list, and storage namespace, so one
+// deployment can serve several applications over the same block stream.
+type ProfileConfig struct {
+	Name       string   `json:"name"`
+	Programs   []string `json:"programs"`
+	Accounts   []string `json:"accounts"`
+	Processors []string `json:"processors"`
+	// Filter is an optional expression (see internal/filter) evaluated
+	// per transaction on top of the program/account matching.
+	Filter string `json:"filter"`
+	// Options carries per-processor settings keyed by processor name,
+	// e.g. {"transfers": {"include_mints": ["..."]}}. Each processor
+	// validates its own options when the profile is built. The reserved
+	// "isolate" key runs the processor in its own bounded worker pool
+	// decoupled from block ingestion; see internal/indexer/isolate.go.
+	Options map[string]map[string]any `json:"options"`
+}
+
+// Load reads configuration from the environment. When PROFILES_FILE is not
+// set, a single "default" profile is built from PROGRAM_ID_STARTER and
+// PROGRAM_ID_COUNTER.
+func Load() (*Config, error) {
+	cfg := &Config{
+		RPCURL:         getEnv("SOLANA_RPC_URL", "http://localhost:8899"),
+		RPCURLs:        splitList(getEnv("SOLANA_RPC_URLS", "")),
+		ArchivalRPCURL: getEnv("ARCHIVAL_RPC_URL", ""),
+		Namespace:      getEnv("NAMESPACE", ""),
+		Chain:          getEnv("CHAIN", "solana"),
+		PollInterval:   time.Duration(getEnvInt("POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		BatchSize:      getEnvInt("BATCH_SIZE", 10),
+		MaxConcurrency: getEnvInt("MAX_CONCURRENCY", 5),
+		MinConcurrency: getEnvInt("MIN_CONCURRENCY", 0),
+		ServerPort:     getEnvInt("SERVER_PORT", 8080),
+		GRPCPort:       getEnvInt("GRPC_PORT", 0),
+		GRPCCodec:      getEnv("GRPC_CODEC", "json"),
+		APIKeys:        splitPairs(getEnv("API_KEYS", "")),
+
+		OIDCIssuer:      getEnv("OIDC_ISSUER", ""),
+		OIDCAudience:    getEnv("OIDC_AUDIENCE", ""),
+		OIDCRoleClaim:   getEnv("OIDC_ROLE_CLAIM", "roles"),
+		OIDCDefaultRole: getEnv("OIDC_DEFAULT_ROLE", "read"),
+		LogLevel:        getEnv("LOG_LEVEL", "info"),
+		AccessLog:       getEnv("ACCESS_LOG", ""),
+		DatabaseURL:     getEnv("DATABASE_URL", ""),
+
+		CacheSlots: uint64(getEnvInt("CACHE_SLOTS", 0)),
+		CacheMaxMB: getEnvInt("CACHE_MAX_MB", 256),
+
+		DatabaseURLGroups:  splitPairs(getEnv("DATABASE_URL_GROUPS", "")),
+		DatabaseURLRead:    getEnv("DATABASE_URL_READ", ""),
+		ReplicaMaxLagSlots: uint64(getEnvInt("REPLICA_MAX_LAG_SLOTS", 100)),
+		AggregateUpstreams: splitList(getEnv("AGGREGATE_UPSTREAMS", "")),
+		AggregateConsumer:  getEnv("AGGREGATE_CONSUMER", "aggregator"),
+
+		AlertRulesFile: getEnv("ALERT_RULES_FILE", ""),
+		WatchMints:     splitList(getEnv("WATCH_MINTS", "")),
+		PoolsFile:      getEnv("POOLS_FILE", ""),
+		PnLWallets:     splitList(getEnv("PNL_WALLETS", "")),
+		TablesFile:     getEnv("TABLES_FILE", ""),
+		PluginsDir:     getEnv("PLUGINS_DIR", ""),
+		TxFilter:       getEnv("TX_FILTER", ""),
+
+		FetchMetadata:     getEnvBool("FETCH_METADATA", false),
+		FetchAllowDomains: splitList(getEnv("FETCH_ALLOW_DOMAINS", "")),
+		FetchDenyDomains:  splitList(getEnv("FETCH_DENY_DOMAINS", "")),
+		IPFSGateways:      splitList(getEnv("IPFS_GATEWAYS", "")),
+		ArweaveGateways:   splitList(getEnv("ARWEAVE_GATEWAYS", "")),
+		ScreeningDenylist: getEnv("SCREENING_DENYLIST", ""),
+		BootstrapProgram:  getEnv("BOOTSTRAP_PROGRAM", ""),
+		FirehoseDir:       getEnv("FIREHOSE_DIR", ""),
+		FirehoseURL:       getEnv("FIREHOSE_URL", ""),
+		SignaturesOnly:    getEnvBool("SIGNATURES_ONLY", false),
+		IndexVotes:        getEnvBool("INDEX_VOTES", false),
+		RedactRules:       getEnv("REDACT_RULES", ""),
+		RedactSalt:        getEnv("REDACT_SALT", ""),
+
+		SlackWebhookURL:   getEnv("SLACK_WEBHOOK_URL", ""),
+		DiscordWebhookURL: getEnv("DISCORD_WEBHOOK_URL", ""),
+		TelegramBotToken:  getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatID:    getEnv("TELEGRAM_CHAT_ID", ""),
+		NotifyTemplate:    getEnv("NOTIFY_TEMPLATE", ""),
+		NotifyMinInterval: time.Duration(getEnvInt("NOTIFY_MIN_INTERVAL_MS", 1000)) * time.Millisecond,
+
+		StreamMinCommitment:  getEnv("STREAM_MIN_COMMITMENT", "processed"),
+		StreamReemitUpgrades: getEnvBool("STREAM_REEMIT_UPGRADES", true),
+		NotifyMinCommitment:  getEnv("NOTIFY_MIN_COMMITMENT", "confirmed"),
+		NotifyReemitUpgrades: getEnvBool("NOTIFY_REEMIT_UPGRADES", false),
+
+		HealthCheckInterval:   time.Duration(getEnvInt("HEALTH_CHECK_INTERVAL_MS", 10000)) * time.Millisecond,
+		HealthStallAfter:      time.Duration(getEnvInt("HEALTH_STALL_AFTER_MS", 60000)) * time.Millisecond,
+		HealthHaltAfter:       time.Duration(getEnvInt("HEALTH_HALT_AFTER_MS", 120000)) * time.Millisecond,
+		HealthMaxErrorsPerMin: getEnvInt("HEALTH_MAX_ERRORS_PER_MIN", 10),
+		SMTPAddr:              getEnv("SMTP_ADDR", ""),
+		SMTPFrom:              getEnv("SMTP_FROM", ""),
+		SMTPTo:                splitList(getEnv("SMTP_TO", "")),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		PagerDutyRoutingKey:   getEnv("PAGERDUTY_ROUTING_KEY", ""),
+
+		LatencySLOTarget:    time.Duration(getEnvInt("LATENCY_SLO_TARGET_MS", 30000)) * time.Millisecond,
+		LatencySLOObjective: getEnvFloat("LATENCY_SLO_OBJECTIVE", 0.99),
+		LatencySLOBurnAlert: getEnvFloat("LATENCY_SLO_BURN_ALERT", 2),
+
+		PartitionSlots:     uint64(getEnvInt("PARTITION_SLOTS", 432000)),
+		PartitionPreCreate: getEnvInt("PARTITION_PRECREATE", 2),
+		PartitionRetain:    getEnvInt("PARTITION_RETAIN", 0),
+
+		ColdDir:         getEnv("COLD_DIR", ""),
+		ColdRetainSlots: uint64(getEnvInt("COLD_RETAIN_SLOTS", 864000)),
+
+		AccountIndexSlots: uint64(getEnvInt("ACCOUNT_INDEX_SLOTS", 0)),
+
+		Schedules: splitSchedulePairs(getEnv("SCHEDULES", "")),
+
+		DBMaxConns:          getEnvInt("DB_MAX_CONNS", 16),
+		DBMinConns:          getEnvInt("DB_MIN_CONNS", 2),
+		DBConnIdleTime:      time.Duration(getEnvInt("DB_CONN_IDLE_MS", 300000)) * time.Millisecond,
+		DBConnLifetime:      time.Duration(getEnvInt("DB_CONN_LIFETIME_MS", 3600000)) * time.Millisecond,
+		DBHealthCheckPeriod: time.Duration(getEnvInt("DB_HEALTHCHECK_MS", 60000)) * time.Millisecond,
+		DBStmtCacheSize:     getEnvInt("DB_STMT_CACHE_SIZE", 128),
+
+		RPCProvider:       getEnv("RPC_PROVIDER", "generic"),
+		RPCMonthlyCredits: getEnvFloat("RPC_MONTHLY_CREDITS", 0),
+
+		BackfillStartSlot: uint64(getEnvInt("BACKFILL_START_SLOT", 0)),
+		BackfillEndSlot:   uint64(getEnvInt("BACKFILL_END_SLOT", 0)),
+		LiveCapacityPct:   getEnvInt("LIVE_CAPACITY_PCT", 70),
+
+		ReplayBackward: getEnvBool("REPLAY_BACKWARD", false),
+		ReplayEndSlot:  uint64(getEnvInt("REPLAY_END_SLOT", 0)),
+	}
+	if err := resolveSecrets(cfg); err != nil {
+		return nil, err
+	}
+	cfg.Sinks = splitList(getEnv("SINKS", ""))
+	cfg.SchemaRegistryURL = getEnv("SCHEMA_REGISTRY_URL", "")
+	if cfg.GRPCCodec != "json" && cfg.GRPCCodec != "proto" {
+		return nil, fmt.Errorf("GRPC_CODEC: unknown codec %q (known: json, proto)", cfg.GRPCCodec)
+	}
+	for _, sink := range cfg.Sinks {
+		switch sink {
+		case "stream", "notify", "grpc":
+		default:
+			return nil, fmt.Errorf("SINKS: unknown sink %q (known: stream, notify, grpc)", sink)
+		}
+	}
+	if cfg.FirehoseDir != "" && cfg.FirehoseURL != "" {
+		return nil, fmt.Errorf("FIREHOSE_DIR and FIREHOSE_URL are mutually exclusive")
+	}
+	for group := range cfg.DatabaseURLGroups {
+		switch group {
+		case "raw", "decoded", "analytics":
+		default:
+			return nil, fmt.Errorf("DATABASE_URL_GROUPS: unknown table group %q (known: raw, decoded, analytics)", group)
+		}
+	}
+	if len(cfg.DatabaseURLGroups) > 0 && cfg.DatabaseURL == "" {
+		return nil, fmt.Errorf("DATABASE_URL_GROUPS requires DATABASE_URL for the default store (outbox and consumer offsets live there)")
+	}
+	if cfg.LatencySLOObjective <= 0 || cfg.LatencySLOObjective >= 1 {
+		return nil, fmt.Errorf("LATENCY_SLO_OBJECTIVE must be between 0 and 1 exclusive, got %g", cfg.LatencySLOObjective)
+	}
+	if cfg.Namespace != "" && !validNamespace(cfg.Namespace) {
+		return nil, fmt.Errorf("NAMESPACE %q must match [a-z][a-z0-9_]* (it names a Postgres schema and prefixes metrics)", cfg.Namespace)
+	}
+	if cfg.BackfillEndSlot > 0 && cfg.BackfillEndSlot < cfg.BackfillStartSlot {
+		return nil, fmt.Errorf("BACKFILL_END_SLOT %d is before BACKFILL_START_SLOT %d",
+			cfg.BackfillEndSlot, cfg.BackfillStartSlot)
+	}
+	if cfg.LiveCapacityPct < 1 || cfg.LiveCapacityPct > 100 {
+		return nil, fmt.Errorf("LIVE_CAPACITY_PCT must be between 1 and 100, got %d", cfg.LiveCapacityPct)
+	}
+
+	startSlot := getEnv("START_SLOT", "0")
+	if startSlot == "latest" {
+		cfg.StartAtLatest = true
+	} else {
+		v, err := strconv.ParseUint(startSlot, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid START_SLOT %q: %w", startSlot, err)
+		}
+		cfg.StartSlot = v
+	}
+
+	if startTime := getEnv("START_TIME", ""); startTime != "" {
+		t, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid START_TIME %q (want RFC3339): %w", startTime, err)
+		}
+		cfg.StartTime = t
+	}
+	if endSlot := getEnv("END_SLOT", ""); endSlot != "" {
+		v, err := strconv.ParseUint(endSlot, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid END_SLOT %q: %w", endSlot, err)
+		}
+		if !cfg.StartAtLatest && v < cfg.StartSlot {
+			return nil, fmt.Errorf("END_SLOT %d is before START_SLOT %d", v, cfg.StartSlot)
+		}
+		cfg.EndSlot = v
+	}
+
+	if path := getEnv("PROFILES_FILE", ""); path != "" {
+		profiles, err := loadProfiles(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Profiles = profiles
+	} else {
+		var programs []string
+		if id := getEnv("PROGRAM_ID_STARTER", ""); id != "" {
+			programs = append(programs, id)
+		}
+		if id := getEnv("PROGRAM_ID_COUNTER", ""); id != "" {
+			programs = append(programs, id)
+		}
+		cfg.Profiles = []ProfileConfig{{
+			Name:       "default",
+			Programs:   programs,
+			Processors: []string{"transfers", "events"},
+		}}
+	}
+	return cfg, nil
+}
+
+func loadProfiles(path string) ([]ProfileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read profiles file: %w", err)
+	}
+	var profiles []ProfileConfig
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parse profiles file %s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		if p.Name == "" {
+			return nil, fmt.Errorf("profiles file %s: profile without name", path)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("profiles file %s: duplicate profile %q", path, p.Name)
+		}
+		seen[p.Name] = true
+		for name := range p.Options {
+			enabled := false
+			for _, proc := range p.Processors {
+				if proc == name {
+					enabled = true
+					break
+				}
+			}
+			if !enabled {
+				return nil, fmt.Errorf("profiles file %s: profile %q has options for %q, which is not in its processors list",
+					path, p.Name, name)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// validNamespace reports whether v is a safe lowercase identifier. The
+// namespace is interpolated into DDL and metric names, so anything
+// outside [a-z][a-z0-9_]* is rejected.
+func validNamespace(v string) bool {
+	for i, r := range v {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case i > 0 && (r == '_' || (r >= '0' && r <= '9')):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// splitPairs splits a comma-separated list of "key=value" pairs into a
+// map, skipping malformed entries.
+func splitPairs(v string) map[string]string {
+	parts := splitList(v)
+	if len(parts) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(parts))
+	for _, part := range parts {
+		key, value, ok := strings.Cut(part, "=")
+		if ok && key != "" && value != "" {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// splitSchedulePairs parses ';'-separated name=spec pairs. It exists
+// alongside splitPairs because cron specs use commas, the splitPairs
+// list separator.
+func splitSchedulePairs(v string) map[string]string {
+	if v == "" {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, part := range strings.Split(v, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && key != "" && value != "" {
+			out[key] = value
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// splitList splits a comma-separated env value, trimming blanks.
+func splitList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func getEnvBool(key string, def bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+func getEnv(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+func getEnvInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
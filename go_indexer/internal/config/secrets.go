@@ -0,0 +1,188 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Secret-bearing values (DATABASE_URL, webhook URLs, API tokens) may be
+// supplied as references instead of plaintext, resolved once at load
+// time so secrets never sit in plain env files:
+//
+//	vault://secret/indexer#db_url       HashiCorp Vault KV v2, addressed
+//	                                    by VAULT_ADDR and VAULT_TOKEN
+//	sops://deploy/secrets.json#db_url   SOPS-encrypted JSON file,
+//	                                    decrypted with the sops binary
+//	aws-kms://<base64 ciphertext>       decrypted with the aws CLI
+//	gcp-kms://<key resource>#<base64>   decrypted with the gcloud CLI
+//
+// Values without a recognized scheme pass through unchanged.
+
+// resolveSecrets dereferences every secret-capable field in place.
+func resolveSecrets(cfg *Config) error {
+	fields := map[string]*string{
+		"DATABASE_URL":          &cfg.DatabaseURL,
+		"DATABASE_URL_READ":     &cfg.DatabaseURLRead,
+		"SLACK_WEBHOOK_URL":     &cfg.SlackWebhookURL,
+		"DISCORD_WEBHOOK_URL":   &cfg.DiscordWebhookURL,
+		"TELEGRAM_BOT_TOKEN":    &cfg.TelegramBotToken,
+		"SMTP_PASSWORD":         &cfg.SMTPPassword,
+		"PAGERDUTY_ROUTING_KEY": &cfg.PagerDutyRoutingKey,
+	}
+	for name, field := range fields {
+		resolved, err := resolveSecret(*field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*field = resolved
+	}
+	for group, url := range cfg.DatabaseURLGroups {
+		resolved, err := resolveSecret(url)
+		if err != nil {
+			return fmt.Errorf("DATABASE_URL_GROUPS[%s]: %w", group, err)
+		}
+		cfg.DatabaseURLGroups[group] = resolved
+	}
+	return nil
+}
+
+// resolveSecret dereferences one value if it carries a secret scheme.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "vault://"):
+		return resolveVault(strings.TrimPrefix(value, "vault://"))
+	case strings.HasPrefix(value, "sops://"):
+		return resolveSOPS(strings.TrimPrefix(value, "sops://"))
+	case strings.HasPrefix(value, "aws-kms://"):
+		return resolveAWSKMS(strings.TrimPrefix(value, "aws-kms://"))
+	case strings.HasPrefix(value, "gcp-kms://"):
+		return resolveGCPKMS(strings.TrimPrefix(value, "gcp-kms://"))
+	}
+	return value, nil
+}
+
+// resolveVault reads "mount/path#field" from Vault's KV v2 HTTP API.
+func resolveVault(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("vault reference %q missing #field", ref)
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("vault reference requires VAULT_ADDR and VAULT_TOKEN")
+	}
+	mount, rest, ok := strings.Cut(path, "/")
+	if !ok {
+		return "", fmt.Errorf("vault reference %q missing secret path", ref)
+	}
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(addr, "/"), mount, rest)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	httpc := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: %s returned status %d", path, resp.StatusCode)
+	}
+	var wire struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wire); err != nil {
+		return "", fmt.Errorf("vault: decode response: %w", err)
+	}
+	secret, ok := wire.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("vault: secret %s has no field %q", path, field)
+	}
+	return secret, nil
+}
+
+// resolveSOPS decrypts "file#field" with the sops binary and picks the
+// field out of the decrypted JSON document.
+func resolveSOPS(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("sops reference %q missing #field", ref)
+	}
+	out, err := exec.Command("sops", "--output-type", "json", "-d", path).Output()
+	if err != nil {
+		return "", fmt.Errorf("sops -d %s: %w", path, execErr(err))
+	}
+	var doc map[string]string
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return "", fmt.Errorf("sops: parse decrypted %s: %w", path, err)
+	}
+	secret, ok := doc[field]
+	if !ok {
+		return "", fmt.Errorf("sops: %s has no field %q", path, field)
+	}
+	return secret, nil
+}
+
+// resolveAWSKMS decrypts a base64 ciphertext with the aws CLI, which
+// carries the credentials and region configuration.
+func resolveAWSKMS(ciphertext string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("aws-kms: ciphertext is not base64: %w", err)
+	}
+	cmd := exec.Command("aws", "kms", "decrypt",
+		"--ciphertext-blob", "fileb:///dev/stdin",
+		"--query", "Plaintext", "--output", "text")
+	cmd.Stdin = strings.NewReader(string(blob))
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("aws kms decrypt: %w", execErr(err))
+	}
+	plain, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("aws-kms: decode plaintext: %w", err)
+	}
+	return string(plain), nil
+}
+
+// resolveGCPKMS decrypts "<key resource>#<base64 ciphertext>" with the
+// gcloud CLI; the key resource is the full
+// projects/.../cryptoKeys/... path.
+func resolveGCPKMS(ref string) (string, error) {
+	key, ciphertext, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("gcp-kms reference missing #ciphertext")
+	}
+	blob, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("gcp-kms: ciphertext is not base64: %w", err)
+	}
+	cmd := exec.Command("gcloud", "kms", "decrypt",
+		"--key", key, "--ciphertext-file", "-", "--plaintext-file", "-")
+	cmd.Stdin = strings.NewReader(string(blob))
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("gcloud kms decrypt: %w", execErr(err))
+	}
+	return string(out), nil
+}
+
+// execErr surfaces a command's stderr, which is where the CLIs explain
+// auth and key failures.
+func execErr(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
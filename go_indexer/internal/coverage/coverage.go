@@ -0,0 +1,153 @@
+// Package coverage tracks the exact slot ranges the index covers, per
+// source lane (live, backfill, replay, repair). The API serves the map
+// so consumers can tell an empty query result ("no data in a covered
+// range") from a gap the indexer has not reached yet. Ranges are
+// persisted in a custom table and reloaded at startup, so coverage
+// accumulated by earlier runs is not forgotten.
+package coverage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// rangeTable is the custom table coverage is persisted in. Flushes are
+// plain inserts; the newest row per source wins on reload.
+const rangeTable = "coverage_ranges"
+
+// Source lanes coverage is recorded under.
+const (
+	SourceLive     = "live"
+	SourceBackfill = "backfill"
+	SourceReplay   = "replay"
+	SourceRepair   = "repair"
+)
+
+// Range is one contiguous covered slot range, inclusive on both ends.
+// Skipped slots inside a processed range count as covered: the chain
+// produced no block there, which is an answer, not a gap.
+type Range struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// Tracker accumulates covered ranges per source.
+type Tracker struct {
+	mu      sync.Mutex
+	sources map[string][]Range
+}
+
+// New creates an empty Tracker.
+func New() *Tracker {
+	return &Tracker{sources: make(map[string][]Range)}
+}
+
+// Add records [from, to] as covered by source, coalescing with any
+// adjacent or overlapping ranges already recorded.
+func (t *Tracker) Add(source string, from, to uint64) {
+	if to < from {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sources[source] = merge(append(t.sources[source], Range{From: from, To: to}))
+}
+
+// Snapshot returns a copy of the per-source coverage, ranges ascending.
+func (t *Tracker) Snapshot() map[string][]Range {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string][]Range, len(t.sources))
+	for source, ranges := range t.sources {
+		out[source] = append([]Range(nil), ranges...)
+	}
+	return out
+}
+
+// Merged returns the union of every source's coverage.
+func (t *Tracker) Merged() []Range {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var all []Range
+	for _, ranges := range t.sources {
+		all = append(all, ranges...)
+	}
+	return merge(all)
+}
+
+// merge sorts and coalesces ranges; inputs touching or overlapping
+// collapse into one.
+func merge(ranges []Range) []Range {
+	if len(ranges) == 0 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From < ranges[j].From })
+	out := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &out[len(out)-1]
+		if r.From <= last.To+1 {
+			if r.To > last.To {
+				last.To = r.To
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Load restores persisted coverage from the store. Missing state is not
+// an error: a fresh deployment starts empty.
+func (t *Tracker) Load(ctx context.Context, store storage.Store) error {
+	if err := store.EnsureCustomTable(ctx, rangeTable); err != nil {
+		return fmt.Errorf("ensure coverage table: %w", err)
+	}
+	rows, err := store.ListCustomRows(ctx, rangeTable, 0, 0)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("load coverage: %w", err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, row := range rows {
+		source, _ := row["source"].(string)
+		raw, _ := row["ranges"].(string)
+		if source == "" || raw == "" {
+			continue
+		}
+		var ranges []Range
+		if err := json.Unmarshal([]byte(raw), &ranges); err != nil {
+			continue
+		}
+		t.sources[source] = merge(ranges)
+	}
+	return nil
+}
+
+// Flush persists the current coverage, one row per source. It is run on
+// a schedule by the scheduler.
+func (t *Tracker) Flush(ctx context.Context, store storage.Store) error {
+	for source, ranges := range t.Snapshot() {
+		raw, err := json.Marshal(ranges)
+		if err != nil {
+			return err
+		}
+		if err := store.InsertCustomRow(ctx, rangeTable, map[string]any{
+			"source":     source,
+			"ranges":     string(raw),
+			"updated_at": time.Now().UTC().Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("flush coverage %s: %w", source, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package decoder turns raw transaction logs into typed events. Anchor
+// programs emit binary events through "Program data:" log lines; non-Anchor
+// programs (like the counter program) are handled by log parsers.
+package decoder
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// AnchorDecoder decodes Anchor events from transaction log messages.
+type AnchorDecoder struct {
+	// eventNames maps an 8-byte discriminator (hex) to an event name.
+	// Unknown discriminators are still emitted, typed by the hex value.
+	eventNames map[string]string
+}
+
+// NewAnchorDecoder creates a decoder. eventNames maps hex-encoded 8-byte
+// event discriminators to human-readable event names and may be nil.
+func NewAnchorDecoder(eventNames map[string]string) *AnchorDecoder {
+	return &AnchorDecoder{eventNames: eventNames}
+}
+
+// DecodeLogs extracts Anchor events from a transaction's log messages,
+// attributing each event to the program whose invocation emitted it.
+func (d *AnchorDecoder) DecodeLogs(logs []string) []models.Event {
+	var events []models.Event
+	var stack []string
+	for _, line := range logs {
+		switch {
+		case strings.Contains(line, " invoke ["):
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				stack = append(stack, fields[1])
+			}
+		case strings.HasSuffix(line, " success") || strings.Contains(line, " failed:"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasPrefix(line, "Program data: "):
+			payload := strings.TrimPrefix(line, "Program data: ")
+			raw, err := base64.StdEncoding.DecodeString(payload)
+			if err != nil || len(raw) < 8 {
+				continue
+			}
+			disc := hex.EncodeToString(raw[:8])
+			name, ok := d.eventNames[disc]
+			if !ok {
+				name = "anchor:" + disc
+			}
+			program := ""
+			if len(stack) > 0 {
+				program = stack[len(stack)-1]
+			}
+			events = append(events, models.Event{
+				Program: program,
+				Type:    name,
+				Data: map[string]any{
+					"discriminator": disc,
+					"payload":       base64.StdEncoding.EncodeToString(raw[8:]),
+				},
+			})
+		}
+	}
+	return events
+}
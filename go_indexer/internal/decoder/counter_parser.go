@@ -0,0 +1,56 @@
+package decoder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// counterLogPattern matches the counter program's plain-text log lines,
+// e.g. "Program log: Counter incremented to 5".
+var counterLogPattern = regexp.MustCompile(`^Program log: Counter (incremented|decremented|reset) to (\d+)$`)
+
+// CounterParser extracts counter program events from raw log lines. The
+// counter program does not use Anchor events, so its state changes are
+// recovered from its msg! output.
+type CounterParser struct {
+	programID string
+}
+
+// NewCounterParser creates a parser for the counter program with the given
+// program ID.
+func NewCounterParser(programID string) *CounterParser {
+	return &CounterParser{programID: programID}
+}
+
+// ParseLogs extracts counter events from a transaction's log messages.
+func (p *CounterParser) ParseLogs(logs []string) []models.Event {
+	var events []models.Event
+	inProgram := false
+	for _, line := range logs {
+		if strings.HasPrefix(line, "Program "+p.programID+" invoke") {
+			inProgram = true
+			continue
+		}
+		if strings.HasPrefix(line, "Program "+p.programID+" success") {
+			inProgram = false
+			continue
+		}
+		if !inProgram {
+			continue
+		}
+		m := counterLogPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value, _ := strconv.ParseUint(m[2], 10, 64)
+		events = append(events, models.Event{
+			Program: p.programID,
+			Type:    "counter_" + m[1],
+			Data:    map[string]any{"value": value},
+		})
+	}
+	return events
+}
@@ -0,0 +1,172 @@
+// Package enrich completes records after their initial write. Some
+// fields — off-chain metadata, USD prices — are not available at
+// decode time, so events subject to enrichment are stored immediately
+// with an explicit "pending" marker and the late fields land through a
+// follow-up record_updated event once an enricher produces them. API
+// consumers see which rows are still incomplete from the marker; event
+// subscribers join the update to the original by signature and record
+// type.
+package enrich
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// Enrichment status markers carried in models.Event.Enrichment. The
+// zero value means the record is not subject to enrichment at all.
+const (
+	StatusPending  = "pending"
+	StatusComplete = "complete"
+)
+
+// enrichWorkers and enrichQueue bound the background pool. Enrichment
+// is best effort: when the queue is full new work is dropped (and the
+// record stays pending) rather than backpressuring block ingestion.
+const (
+	enrichWorkers = 2
+	enrichQueue   = 4096
+)
+
+// UpdateEventType is the type of the follow-up events carrying late
+// fields. Data holds "record_type" (the original event's type) and
+// "enrichment" (the added fields).
+const UpdateEventType = "record_updated"
+
+// Enricher produces late fields for events it recognizes. Enrich
+// returns the fields to add; an empty map means nothing was learned and
+// the record stays pending.
+type Enricher interface {
+	Name() string
+	Wants(e *models.Event) bool
+	Enrich(ctx context.Context, e *models.Event) (map[string]any, error)
+}
+
+// Manager runs registered enrichers over submitted events in a small
+// background pool and emits the follow-up events.
+type Manager struct {
+	store   storage.Store
+	publish func(models.Event)
+	log     *slog.Logger
+
+	mu        sync.RWMutex
+	enrichers []Enricher
+	queue     chan models.Event
+}
+
+// NewManager creates a manager and starts its workers. publish, when
+// non-nil, receives every follow-up event after it is stored.
+func NewManager(store storage.Store, publish func(models.Event), log *slog.Logger) *Manager {
+	m := &Manager{
+		store:   store,
+		publish: publish,
+		log:     log,
+		queue:   make(chan models.Event, enrichQueue),
+	}
+	for i := 0; i < enrichWorkers; i++ {
+		go m.run()
+	}
+	return m
+}
+
+// Register adds an enricher; call during wiring, before indexing
+// starts.
+func (m *Manager) Register(e Enricher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enrichers = append(m.enrichers, e)
+}
+
+// Mark flags an event as pending when any enricher wants it. Call
+// before the initial save so the stored row carries the marker.
+func (m *Manager) Mark(e *models.Event) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, enricher := range m.enrichers {
+		if enricher.Wants(e) {
+			e.Enrichment = StatusPending
+			return
+		}
+	}
+}
+
+// Submit queues a stored pending event for enrichment. A full queue
+// drops the work: the record simply stays pending.
+func (m *Manager) Submit(e models.Event) {
+	if e.Enrichment != StatusPending {
+		return
+	}
+	select {
+	case m.queue <- e:
+	default:
+		m.log.Warn("enrichment queue full, record stays pending",
+			"signature", e.Signature, "type", e.Type)
+	}
+}
+
+func (m *Manager) run() {
+	for e := range m.queue {
+		m.enrichOne(e)
+	}
+}
+
+// enrichOne runs every matching enricher and emits the record_updated
+// event carrying whatever fields were produced. Enrichers never mutate
+// the original event: its stored copy shares the Data map.
+func (m *Manager) enrichOne(e models.Event) {
+	m.mu.RLock()
+	enrichers := m.enrichers
+	m.mu.RUnlock()
+
+	fields := make(map[string]any)
+	complete := true
+	for _, enricher := range enrichers {
+		if !enricher.Wants(&e) {
+			continue
+		}
+		added, err := enricher.Enrich(context.Background(), &e)
+		if err != nil {
+			m.log.Warn("enrichment failed", "enricher", enricher.Name(),
+				"signature", e.Signature, "error", err)
+			complete = false
+			continue
+		}
+		if len(added) == 0 {
+			complete = false
+			continue
+		}
+		for key, value := range added {
+			fields[key] = value
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	status := StatusComplete
+	if !complete {
+		status = StatusPending
+	}
+	update := models.Event{
+		Signature:  e.Signature,
+		Slot:       e.Slot,
+		BlockTime:  e.BlockTime,
+		Program:    e.Program,
+		Type:       UpdateEventType,
+		Enrichment: status,
+		Data: map[string]any{
+			"record_type": e.Type,
+			"enrichment":  fields,
+		},
+	}
+	if err := m.store.SaveEvent(context.Background(), &update); err != nil {
+		m.log.Warn("enrichment update not stored", "signature", e.Signature, "error", err)
+		return
+	}
+	if m.publish != nil {
+		m.publish(update)
+	}
+}
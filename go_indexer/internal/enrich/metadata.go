@@ -0,0 +1,48 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/fetch"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// MetadataEnricher resolves off-chain metadata for events whose payload
+// carries a uri, through the hardened fetcher. JSON documents land
+// parsed under "metadata"; other accepted content types land as their
+// content type only, so consumers know the URI resolved without the
+// event growing a binary payload.
+type MetadataEnricher struct {
+	fetcher *fetch.Fetcher
+}
+
+// NewMetadataEnricher creates the enricher over fetcher.
+func NewMetadataEnricher(fetcher *fetch.Fetcher) *MetadataEnricher {
+	return &MetadataEnricher{fetcher: fetcher}
+}
+
+func (m *MetadataEnricher) Name() string { return "metadata" }
+
+// Wants claims events with a fetchable uri in their payload.
+func (m *MetadataEnricher) Wants(e *models.Event) bool {
+	uri, _ := e.Data["uri"].(string)
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") ||
+		strings.HasPrefix(uri, "ipfs://") || strings.HasPrefix(uri, "ar://")
+}
+
+func (m *MetadataEnricher) Enrich(ctx context.Context, e *models.Event) (map[string]any, error) {
+	uri, _ := e.Data["uri"].(string)
+	body, contentType, err := m.fetcher.Fetch(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(contentType, "application/json") {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err == nil {
+			return map[string]any{"metadata": doc}, nil
+		}
+	}
+	return map[string]any{"metadata_content_type": contentType}, nil
+}
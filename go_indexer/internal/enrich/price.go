@@ -0,0 +1,38 @@
+package enrich
+
+import (
+	"context"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// PriceEnricher adds the USD price of an event's mint from the pool
+// analytics' latest observed mid price. Events whose mint has no
+// watched pool stay pending until a pool observation prices them.
+type PriceEnricher struct {
+	price analytics.PriceFunc
+}
+
+// NewPriceEnricher creates the enricher over price, typically
+// Pools.LatestPrice.
+func NewPriceEnricher(price analytics.PriceFunc) *PriceEnricher {
+	return &PriceEnricher{price: price}
+}
+
+func (p *PriceEnricher) Name() string { return "price" }
+
+// Wants claims events carrying a mint in their payload.
+func (p *PriceEnricher) Wants(e *models.Event) bool {
+	mint, _ := e.Data["mint"].(string)
+	return mint != ""
+}
+
+func (p *PriceEnricher) Enrich(ctx context.Context, e *models.Event) (map[string]any, error) {
+	mint, _ := e.Data["mint"].(string)
+	price, ok := p.price(mint)
+	if !ok {
+		return nil, nil
+	}
+	return map[string]any{"price_usd": price}, nil
+}
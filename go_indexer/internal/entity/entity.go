@@ -0,0 +1,315 @@
+// Package entity clusters addresses into wallet entities using
+// heuristics over the indexed stream: wallets funding each other,
+// token accounts resolving to their owners, addresses signing the same
+// transactions. Each heuristic yields links with a confidence score;
+// clusters are the connected components of the link graph. Heuristics
+// are pluggable the same way processors are: implement Heuristic and
+// register it on the resolver at wiring time.
+package entity
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// entityTable is the custom table links are persisted in, so clusters
+// survive restarts.
+const entityTable = "entity_links"
+
+// entityKeep bounds the links retained; once reached, new links are
+// dropped rather than evicting established ones.
+const entityKeep = 100_000
+
+// Link connects two addresses believed to belong to the same entity.
+type Link struct {
+	A          string  `json:"a"`
+	B          string  `json:"b"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Heuristic produces entity links from one transaction.
+type Heuristic interface {
+	Name() string
+	Links(tc *processor.TxContext) []Link
+}
+
+// Cluster is one resolved entity: the connected addresses and the
+// links that bind them. Confidence is the mean link confidence.
+type Cluster struct {
+	Members    []string `json:"members"`
+	Links      []Link   `json:"links"`
+	Confidence float64  `json:"confidence"`
+}
+
+// Resolver maintains the link graph. It is wired as a transaction
+// inspector and is safe for concurrent use.
+type Resolver struct {
+	store storage.Store
+	log   *slog.Logger
+
+	mu         sync.Mutex
+	heuristics []Heuristic
+	parent     map[string]string // union-find forest
+	links      map[string][]Link // by cluster root, rebuilt on union
+	seen       map[string]struct{}
+}
+
+// NewResolver creates a resolver with the built-in heuristics
+// registered. store persists links and may be nil for ephemeral use.
+func NewResolver(store storage.Store, log *slog.Logger) *Resolver {
+	r := &Resolver{
+		store:  store,
+		log:    log,
+		parent: make(map[string]string),
+		links:  make(map[string][]Link),
+		seen:   make(map[string]struct{}),
+	}
+	r.Register(coSignHeuristic{})
+	r.Register(fundingHeuristic{})
+	r.Register(tokenOwnerHeuristic{})
+	return r
+}
+
+// Register adds a heuristic; call during wiring, before indexing
+// starts.
+func (r *Resolver) Register(h Heuristic) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.heuristics = append(r.heuristics, h)
+}
+
+// Load restores persisted links.
+func (r *Resolver) Load(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.EnsureCustomTable(ctx, entityTable); err != nil {
+		return fmt.Errorf("entity: ensure table: %w", err)
+	}
+	rows, err := r.store.ListCustomRows(ctx, entityTable, 0, 0)
+	if err != nil {
+		return fmt.Errorf("entity: load: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range rows {
+		link := Link{Reason: "unknown"}
+		link.A, _ = row["a"].(string)
+		link.B, _ = row["b"].(string)
+		if reason, ok := row["reason"].(string); ok {
+			link.Reason = reason
+		}
+		switch v := row["confidence"].(type) {
+		case float64:
+			link.Confidence = v
+		case string:
+			fmt.Sscanf(v, "%f", &link.Confidence)
+		}
+		if link.A != "" && link.B != "" {
+			r.add(link, false)
+		}
+	}
+	return nil
+}
+
+// Observe runs every heuristic over one transaction and folds the
+// yielded links into the graph.
+func (r *Resolver) Observe(tc *processor.TxContext) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, h := range r.heuristics {
+		for _, link := range h.Links(tc) {
+			if link.A == "" || link.B == "" || link.A == link.B {
+				continue
+			}
+			r.add(link, true)
+		}
+	}
+}
+
+// add records one link, unioning the two clusters. Callers hold r.mu.
+func (r *Resolver) add(link Link, persist bool) {
+	key := link.A + "|" + link.B + "|" + link.Reason
+	if _, ok := r.seen[key]; ok {
+		return
+	}
+	if len(r.seen) >= entityKeep {
+		return
+	}
+	r.seen[key] = struct{}{}
+
+	rootA, rootB := r.find(link.A), r.find(link.B)
+	if rootA != rootB {
+		r.parent[rootB] = rootA
+		r.links[rootA] = append(r.links[rootA], r.links[rootB]...)
+		delete(r.links, rootB)
+	}
+	r.links[rootA] = append(r.links[rootA], link)
+
+	if persist && r.store != nil {
+		if err := r.store.InsertCustomRow(context.Background(), entityTable, map[string]any{
+			"a":          link.A,
+			"b":          link.B,
+			"reason":     link.Reason,
+			"confidence": link.Confidence,
+		}); err != nil {
+			r.log.Warn("entity link not persisted", "error", err)
+		}
+	}
+}
+
+// find resolves an address's cluster root with path compression.
+// Callers hold r.mu.
+func (r *Resolver) find(address string) string {
+	root := address
+	for {
+		parent, ok := r.parent[root]
+		if !ok || parent == root {
+			break
+		}
+		root = parent
+	}
+	for address != root {
+		next := r.parent[address]
+		r.parent[address] = root
+		address = next
+	}
+	if _, ok := r.parent[root]; !ok {
+		r.parent[root] = root
+	}
+	return root
+}
+
+// Resolve returns the cluster an address belongs to. Addresses never
+// linked report false.
+func (r *Resolver) Resolve(address string) (Cluster, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.parent[address]; !ok {
+		return Cluster{}, false
+	}
+	root := r.find(address)
+	links := r.links[root]
+	members := make(map[string]struct{})
+	cluster := Cluster{Links: append([]Link(nil), links...)}
+	for _, link := range links {
+		members[link.A] = struct{}{}
+		members[link.B] = struct{}{}
+		cluster.Confidence += link.Confidence
+	}
+	if len(links) > 0 {
+		cluster.Confidence /= float64(len(links))
+	}
+	for member := range members {
+		cluster.Members = append(cluster.Members, member)
+	}
+	sort.Strings(cluster.Members)
+	return cluster, true
+}
+
+// coSignHeuristic links addresses signing the same transaction:
+// multiple signers usually means one operator holding both keys (or a
+// multisig, hence the moderate confidence).
+type coSignHeuristic struct{}
+
+func (coSignHeuristic) Name() string { return "co_sign" }
+
+func (coSignHeuristic) Links(tc *processor.TxContext) []Link {
+	msg := &tc.Tx.Transaction.Message
+	// The first len(signatures) static keys are the signers.
+	signers := len(tc.Tx.Transaction.Signatures)
+	if signers < 2 || signers > len(msg.AccountKeys) {
+		return nil
+	}
+	var out []Link
+	for i := 1; i < signers; i++ {
+		out = append(out, Link{
+			A:          msg.AccountKeys[0],
+			B:          msg.AccountKeys[i],
+			Reason:     "co_signed",
+			Confidence: 0.6,
+		})
+	}
+	return out
+}
+
+// fundingHeuristic links a fee payer to fresh accounts it funds with
+// system transfers: wallets are typically topped up by their owner.
+type fundingHeuristic struct{}
+
+func (fundingHeuristic) Name() string { return "funding" }
+
+func (fundingHeuristic) Links(tc *processor.TxContext) []Link {
+	if tc.Tx.Meta != nil && !tc.Tx.Meta.Succeeded() {
+		return nil
+	}
+	msg := &tc.Tx.Transaction.Message
+	if len(msg.AccountKeys) == 0 {
+		return nil
+	}
+	feePayer := msg.AccountKeys[0]
+	var out []Link
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != processor.SystemProgramID || len(ix.Accounts) < 2 {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 4 || binary.LittleEndian.Uint32(data[:4]) != 2 {
+			continue
+		}
+		source := msg.AccountKeys[0]
+		if idx := ix.Accounts[0]; idx < len(msg.AccountKeys) {
+			source = msg.AccountKeys[idx]
+		}
+		if source != feePayer {
+			continue
+		}
+		if idx := ix.Accounts[1]; idx < len(msg.AccountKeys) {
+			out = append(out, Link{
+				A:          feePayer,
+				B:          msg.AccountKeys[idx],
+				Reason:     "funded_by",
+				Confidence: 0.4,
+			})
+		}
+	}
+	return out
+}
+
+// tokenOwnerHeuristic links token accounts to their owning wallet from
+// balance meta — a certain relationship, not a guess.
+type tokenOwnerHeuristic struct{}
+
+func (tokenOwnerHeuristic) Name() string { return "token_owner" }
+
+func (tokenOwnerHeuristic) Links(tc *processor.TxContext) []Link {
+	meta := tc.Tx.Meta
+	if meta == nil {
+		return nil
+	}
+	keys := tc.Tx.Transaction.Message.AccountKeys
+	keys = append(append(append([]string(nil), keys...),
+		meta.LoadedAddresses.Writable...), meta.LoadedAddresses.Readonly...)
+	var out []Link
+	for _, tb := range meta.PostTokenBalances {
+		if tb.Owner == "" || tb.AccountIndex < 0 || tb.AccountIndex >= len(keys) {
+			continue
+		}
+		out = append(out, Link{
+			A:          tb.Owner,
+			B:          keys[tb.AccountIndex],
+			Reason:     "token_account_owner",
+			Confidence: 1,
+		})
+	}
+	return out
+}
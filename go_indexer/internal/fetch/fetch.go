@@ -0,0 +1,262 @@
+// Package fetch is a hardened HTTP client for off-chain metadata (NFT
+// and token URIs). Untrusted on-chain data chooses the URL, so every
+// request is validated before and after: scheme and domain checks with
+// an allow/deny list, private-address blocking against SSRF, response
+// size and content-type limits, and ipfs:// and ar:// URIs resolved
+// through the gateway resolver with fallback. Successful responses are
+// cached in storage so re-indexing does not re-fetch.
+package fetch
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/gateway"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+const (
+	// fetchTimeout bounds one fetch including redirects.
+	fetchTimeout = 10 * time.Second
+	// maxBody is the largest response accepted.
+	maxBody = 1 << 20
+	// maxRedirects bounds gateway redirect chains; every hop is
+	// re-validated.
+	maxRedirects = 3
+	// cacheTable persists fetched responses across restarts.
+	cacheTable = "fetch_cache"
+	// cacheTTL is how long a cached response stays fresh.
+	cacheTTL = 24 * time.Hour
+)
+
+// allowedContentTypes are the response content-type prefixes accepted;
+// anything else is rejected as not-metadata.
+var allowedContentTypes = []string{"application/json", "text/", "image/"}
+
+// Fetcher fetches and caches off-chain resources. It is safe for
+// concurrent use. store may be nil for an uncached fetcher.
+type Fetcher struct {
+	store    storage.Store
+	log      *slog.Logger
+	httpc    *http.Client
+	resolver *gateway.Resolver
+	allow    []string
+	deny     []string
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+type cached struct {
+	body        []byte
+	contentType string
+	fetchedAt   time.Time
+}
+
+// New creates a fetcher persisting its cache through store.
+func New(store storage.Store, log *slog.Logger) *Fetcher {
+	f := &Fetcher{
+		store:    store,
+		log:      log,
+		resolver: gateway.NewResolver(),
+		cache:    make(map[string]cached),
+	}
+	f.httpc = &http.Client{
+		Timeout: fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("fetch: more than %d redirects", maxRedirects)
+			}
+			// A safe host may redirect to an unsafe one.
+			return f.checkHost(req.URL)
+		},
+	}
+	return f
+}
+
+// SetResolver replaces the default gateway resolver, so deployments
+// with their own pinning service or shared resolver instance route
+// content-addressed URIs through it.
+func (f *Fetcher) SetResolver(r *gateway.Resolver) { f.resolver = r }
+
+// SetAllowList restricts fetches to the listed domains (and their
+// subdomains). Empty keeps every public domain allowed.
+func (f *Fetcher) SetAllowList(domains []string) { f.allow = domains }
+
+// SetDenyList blocks the listed domains and their subdomains, applied
+// on top of the allow list.
+func (f *Fetcher) SetDenyList(domains []string) { f.deny = domains }
+
+// Load restores the persisted response cache.
+func (f *Fetcher) Load(ctx context.Context) error {
+	if f.store == nil {
+		return nil
+	}
+	if err := f.store.EnsureCustomTable(ctx, cacheTable); err != nil {
+		return fmt.Errorf("fetch: ensure table: %w", err)
+	}
+	rows, err := f.store.ListCustomRows(ctx, cacheTable, 0, 0)
+	if err != nil {
+		return fmt.Errorf("fetch: load cache: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, row := range rows {
+		uri, _ := row["uri"].(string)
+		encoded, _ := row["body"].(string)
+		body, err := base64.StdEncoding.DecodeString(encoded)
+		if uri == "" || err != nil {
+			continue
+		}
+		entry := cached{body: body}
+		entry.contentType, _ = row["content_type"].(string)
+		if at, ok := row["fetched_at"].(string); ok {
+			entry.fetchedAt, _ = time.Parse(time.RFC3339, at)
+		}
+		f.cache[uri] = entry
+	}
+	return nil
+}
+
+// Fetch retrieves one URI, serving fresh cache hits without a request.
+// It returns the body and the response content type.
+func (f *Fetcher) Fetch(ctx context.Context, uri string) ([]byte, string, error) {
+	f.mu.Lock()
+	entry, ok := f.cache[uri]
+	f.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < cacheTTL {
+		return entry.body, entry.contentType, nil
+	}
+
+	var lastErr error
+	for _, target := range f.resolver.Resolve(uri) {
+		body, contentType, err := f.fetchOne(ctx, target)
+		f.resolver.Report(uri, target, err)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		f.remember(uri, body, contentType)
+		return body, contentType, nil
+	}
+	return nil, "", lastErr
+}
+
+// fetchOne performs one validated request.
+func (f *Fetcher) fetchOne(ctx context.Context, target string) ([]byte, string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch: parse %q: %w", target, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, "", fmt.Errorf("fetch: unsupported scheme %q", u.Scheme)
+	}
+	if err := f.checkHost(u); err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json, */*")
+	resp, err := f.httpc.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch: %s returned status %d", u.Host, resp.StatusCode)
+	}
+	if resp.ContentLength > maxBody {
+		return nil, "", fmt.Errorf("fetch: response is %d bytes (limit %d)", resp.ContentLength, maxBody)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !contentTypeAllowed(contentType) {
+		return nil, "", fmt.Errorf("fetch: content type %q not accepted", contentType)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > maxBody {
+		return nil, "", fmt.Errorf("fetch: response exceeds %d bytes", maxBody)
+	}
+	return body, contentType, nil
+}
+
+// checkHost enforces the domain lists and blocks addresses that would
+// reach internal infrastructure.
+func (f *Fetcher) checkHost(u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("fetch: url %q has no host", u.Redacted())
+	}
+	if domainListed(host, f.deny) {
+		return fmt.Errorf("fetch: domain %s is denied", host)
+	}
+	if len(f.allow) > 0 && !domainListed(host, f.allow) {
+		return fmt.Errorf("fetch: domain %s is not on the allow list", host)
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("fetch: resolve %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+			ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+			return fmt.Errorf("fetch: domain %s resolves to non-public address %s", host, ip)
+		}
+	}
+	return nil
+}
+
+// contentTypeAllowed reports whether a response content type matches
+// one of the accepted prefixes.
+func contentTypeAllowed(contentType string) bool {
+	for _, prefix := range allowedContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// domainListed reports whether host is one of the domains or a
+// subdomain of one.
+func domainListed(host string, domains []string) bool {
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// remember caches a response in memory and in storage.
+func (f *Fetcher) remember(uri string, body []byte, contentType string) {
+	now := time.Now().UTC()
+	f.mu.Lock()
+	f.cache[uri] = cached{body: body, contentType: contentType, fetchedAt: now}
+	f.mu.Unlock()
+	if f.store == nil {
+		return
+	}
+	if err := f.store.InsertCustomRow(context.Background(), cacheTable, map[string]any{
+		"uri":          uri,
+		"body":         base64.StdEncoding.EncodeToString(body),
+		"content_type": contentType,
+		"fetched_at":   now.Format(time.RFC3339),
+	}); err != nil {
+		f.log.Warn("fetch cache entry not persisted", "uri", uri, "error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// TxEnv builds the evaluation environment for one transaction. Exposed
+// fields: signature, slot, fee, success, num_instructions, accounts (all
+// account keys), and programs (programs invoked by top-level
+// instructions).
+func TxEnv(slot uint64, signature string, tx *solana.TransactionWithMeta) Env {
+	msg := &tx.Transaction.Message
+	seen := make(map[string]struct{}, len(msg.Instructions))
+	programs := make([]string, 0, len(msg.Instructions))
+	for _, ix := range msg.Instructions {
+		id := msg.ProgramID(ix)
+		if _, dup := seen[id]; dup || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		programs = append(programs, id)
+	}
+	env := Env{
+		"signature":        signature,
+		"slot":             slot,
+		"success":          true,
+		"fee":              uint64(0),
+		"num_instructions": len(msg.Instructions),
+		"accounts":         msg.AccountKeys,
+		"programs":         programs,
+	}
+	if tx.Meta != nil {
+		env["success"] = tx.Meta.Succeeded()
+		env["fee"] = tx.Meta.Fee
+	}
+	return env
+}
@@ -0,0 +1,419 @@
+// Package filter implements the small expression language used to select
+// transactions from config, e.g.
+//
+//	success && fee > 5000 && "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA" in programs
+//
+// Expressions are compiled once and evaluated per transaction against an
+// environment of decoded fields. Supported syntax: && || !, comparisons
+// (== != < <= > >=), arithmetic (+ - * /), string and number literals,
+// identifiers, membership with "x in list", and parentheses.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Env is the per-transaction evaluation environment. Values are bool,
+// float64, string, or []string.
+type Env map[string]any
+
+// Expr is a compiled filter expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses src into an evaluable expression.
+func Compile(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("filter: unexpected %q", p.tokens[p.pos].text)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source expression.
+func (e *Expr) String() string { return e.src }
+
+// Eval evaluates the expression; the result must be boolean.
+func (e *Expr) Eval(env Env) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, fmt.Errorf("filter: %w", err)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter: expression %q is not boolean", e.src)
+	}
+	return b, nil
+}
+
+type token struct {
+	kind string // ident, number, string, op
+	text string
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], byte(c))
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{kind: "string", text: src[i+1 : i+1+end]})
+			i += end + 2
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.' || src[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: "number", text: strings.ReplaceAll(src[i:j], "_", "")})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '.') {
+				j++
+			}
+			word := src[i:j]
+			if word == "in" {
+				tokens = append(tokens, token{kind: "op", text: "in"})
+			} else {
+				tokens = append(tokens, token{kind: "ident", text: word})
+			}
+			i = j
+		default:
+			matched := false
+			for _, op := range [...]string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "(", ")"} {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: "op", text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type node interface {
+	eval(env Env) (any, error)
+}
+
+type literal struct{ value any }
+
+func (n literal) eval(Env) (any, error) { return n.value, nil }
+
+type ident struct{ name string }
+
+func (n ident) eval(env Env) (any, error) {
+	v, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", n.name)
+	}
+	return v, nil
+}
+
+type unary struct{ operand node }
+
+func (n unary) eval(env Env) (any, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of ! is not boolean")
+	}
+	return !b, nil
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (n binary) eval(env Env) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit the logical operators.
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operand of %s is not boolean", n.op)
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operand of %s is not boolean", n.op)
+		}
+		return rb, nil
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "in":
+		needle, ok := left.(string)
+		if !ok {
+			return nil, fmt.Errorf("left operand of in is not a string")
+		}
+		list, ok := right.([]string)
+		if !ok {
+			return nil, fmt.Errorf("right operand of in is not a list")
+		}
+		for _, item := range list {
+			if item == needle {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "==", "!=":
+		if ls, ok := left.(string); ok {
+			rs, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("comparing string with %T", right)
+			}
+			return (ls == rs) == (n.op == "=="), nil
+		}
+		if lb, ok := left.(bool); ok {
+			rb, ok := right.(bool)
+			if !ok {
+				return nil, fmt.Errorf("comparing bool with %T", right)
+			}
+			return (lb == rb) == (n.op == "=="), nil
+		}
+	}
+	lf, err := toNumber(left)
+	if err != nil {
+		return nil, fmt.Errorf("operand of %s: %w", n.op, err)
+	}
+	rf, err := toNumber(right)
+	if err != nil {
+		return nil, fmt.Errorf("operand of %s: %w", n.op, err)
+	}
+	switch n.op {
+	case "==":
+		return lf == rf, nil
+	case "!=":
+		return lf != rf, nil
+	case "<":
+		return lf < rf, nil
+	case "<=":
+		return lf <= rf, nil
+	case ">":
+		return lf > rf, nil
+	case ">=":
+		return lf >= rf, nil
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func toNumber(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case uint64:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf("%T is not a number", v)
+	}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unary{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return binary{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseFactor() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	switch tok.kind {
+	case "number":
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		p.pos++
+		return literal{value: f}, nil
+	case "string":
+		p.pos++
+		return literal{value: tok.text}, nil
+	case "ident":
+		p.pos++
+		switch tok.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		}
+		return ident{name: tok.text}, nil
+	case "op":
+		if tok.text == "(" {
+			p.pos++
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("missing closing parenthesis")
+			}
+			return inner, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
@@ -0,0 +1,178 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// testEnv is the environment the indexer builds per transaction; the
+// value types here match the doc on Env (bool, float64, string,
+// []string) plus the uint64/int cases toNumber accepts.
+func testEnv() Env {
+	return Env{
+		"success":  true,
+		"fee":      float64(5000),
+		"slot":     uint64(100),
+		"count":    int(3),
+		"signer":   "alice",
+		"programs": []string{"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", "11111111111111111111111111111111"},
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		// Literals and identifiers.
+		{"true", true},
+		{"false", false},
+		{"success", true},
+		{"!success", false},
+
+		// Comparisons across the supported value types.
+		{"fee == 5000", true},
+		{"fee != 5000", false},
+		{"fee > 4999", true},
+		{"fee >= 5000", true},
+		{"fee < 5000", false},
+		{"fee <= 5000", true},
+		{"slot == 100", true},
+		{"count == 3", true},
+		{`signer == "alice"`, true},
+		{`signer != "bob"`, true},
+		{"success == true", true},
+		{"success != false", true},
+
+		// Arithmetic, including number literals with underscores.
+		{"fee + 1000 == 6000", true},
+		{"fee - 1000 == 4000", true},
+		{"fee * 2 == 10_000", true},
+		{"fee / 2 == 2500", true},
+		{"1.5 * 2 == 3", true},
+
+		// Precedence: * / bind tighter than + -, which bind tighter
+		// than comparisons; && binds tighter than ||.
+		{"1 + 2 * 3 == 7", true},
+		{"(1 + 2) * 3 == 9", true},
+		{"10 - 4 / 2 == 8", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!false && false", false},
+		{"!(false && false)", true},
+
+		// Membership, with both quote styles.
+		{`"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA" in programs`, true},
+		{"'missing' in programs", false},
+		{"signer in programs", false},
+
+		// The doc-comment example shape.
+		{`success && fee > 4000 && "11111111111111111111111111111111" in programs`, true},
+		{`success && fee > 6000 && "11111111111111111111111111111111" in programs`, false},
+	}
+	for _, tt := range tests {
+		expr, err := Compile(tt.src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.src, err)
+			continue
+		}
+		got, err := expr.Eval(testEnv())
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.src, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"", "unexpected end of expression"},
+		{"fee >", "unexpected end of expression"},
+		{"(fee > 100", "missing closing parenthesis"},
+		{"fee > 100)", `unexpected ")"`},
+		{`"unterminated`, "unterminated string"},
+		{"fee @ 5", `unexpected character '@'`},
+		{"1.2.3 > 0", `invalid number "1.2.3"`},
+		{"fee 5000", `unexpected "5000"`},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src)
+		if err == nil {
+			t.Errorf("Compile(%q): expected error", tt.src)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Compile(%q) = %v, want error containing %q", tt.src, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"missing > 5", `unknown field "missing"`},
+		{"fee / 0 > 1", "division by zero"},
+		{"!fee", "operand of ! is not boolean"},
+		{"fee && success", "operand of && is not boolean"},
+		{"false || fee", "operand of || is not boolean"},
+		{`signer == 5`, "comparing string with float64"},
+		{"success == 1", "comparing bool with float64"},
+		{"signer + 1 > 0", "is not a number"},
+		{"5 in programs", "left operand of in is not a string"},
+		{`signer in "alice"`, "right operand of in is not a list"},
+		{"fee", "is not boolean"},
+		{"fee + 1", "is not boolean"},
+	}
+	for _, tt := range tests {
+		expr, err := Compile(tt.src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.src, err)
+			continue
+		}
+		_, err = expr.Eval(testEnv())
+		if err == nil {
+			t.Errorf("Eval(%q): expected error", tt.src)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Eval(%q) = %v, want error containing %q", tt.src, err, tt.wantErr)
+		}
+	}
+}
+
+// TestShortCircuit checks && and || skip the right operand, so filters
+// can guard fields that are absent for some transactions.
+func TestShortCircuit(t *testing.T) {
+	for _, src := range []string{"false && missing > 5", "!(true || missing > 5)"} {
+		expr, err := Compile(src)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", src, err)
+		}
+		got, err := expr.Eval(testEnv())
+		if err != nil {
+			t.Fatalf("Eval(%q): %v", src, err)
+		}
+		if got {
+			t.Errorf("Eval(%q) = true, want false", src)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	const src = "fee > 5000"
+	expr, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if expr.String() != src {
+		t.Errorf("String() = %q, want %q", expr.String(), src)
+	}
+}
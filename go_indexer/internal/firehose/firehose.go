@@ -0,0 +1,176 @@
+// Package firehose streams raw block payloads to a sink without
+// decoding them, for teams that do their processing downstream in
+// Spark or Flink and only want this binary as a reliable fetcher. The
+// getBlock result bytes pass straight from the RPC response to the
+// sink — no transaction parsing, no processors, no storage — so
+// throughput is bounded by the RPC provider and the sink, not CPU.
+// Firehose mode replaces the indexing pipeline entirely; see
+// FIREHOSE_DIR and FIREHOSE_URL in the configuration.
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Sink receives raw block payloads in slot order.
+type Sink interface {
+	// Write delivers one block's raw getBlock result. Payloads arrive in
+	// strictly increasing slot order; an error aborts the run.
+	Write(ctx context.Context, slot uint64, payload []byte) error
+	Close() error
+}
+
+// Firehose fetches raw blocks concurrently and writes them to the sink
+// in slot order.
+type Firehose struct {
+	client *solana.Client
+	sink   Sink
+	cfg    *config.Config
+	log    *slog.Logger
+}
+
+// New creates a firehose over the client and sink.
+func New(cfg *config.Config, client *solana.Client, sink Sink, log *slog.Logger) *Firehose {
+	return &Firehose{client: client, sink: sink, cfg: cfg, log: log}
+}
+
+// Run streams blocks until ctx is canceled or EndSlot is passed. Sinks
+// that checkpoint (DirSink) resume past their cursor regardless of the
+// configured start slot.
+func (f *Firehose) Run(ctx context.Context) error {
+	defer f.sink.Close()
+	next := f.cfg.StartSlot
+	if f.cfg.StartAtLatest {
+		tip, err := f.client.GetSlot(ctx)
+		if err != nil {
+			return fmt.Errorf("firehose: resolve latest slot: %w", err)
+		}
+		next = tip
+	}
+	if r, ok := f.sink.(interface{ LastSlot() (uint64, bool) }); ok {
+		if last, resumed := r.LastSlot(); resumed && last+1 > next {
+			next = last + 1
+			f.log.Info("firehose resuming from sink cursor", "slot", next)
+		}
+	}
+	f.log.Info("firehose started", "start_slot", next)
+
+	for {
+		if f.cfg.EndSlot > 0 && next > f.cfg.EndSlot {
+			f.log.Info("firehose reached end slot", "end_slot", f.cfg.EndSlot)
+			return nil
+		}
+		tip, err := f.client.GetSlot(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			f.log.Warn("firehose slot poll failed", "error", err)
+			tip = 0
+		}
+		if f.cfg.EndSlot > 0 && tip > f.cfg.EndSlot {
+			tip = f.cfg.EndSlot
+		}
+		if tip < next {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(f.cfg.PollInterval):
+			}
+			continue
+		}
+		end := next + uint64(f.cfg.BatchSize) - 1
+		if end > tip {
+			end = tip
+		}
+		if err := f.processBatch(ctx, next, end); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		next = end + 1
+	}
+}
+
+// processBatch fetches [from, to] concurrently and writes the payloads
+// in slot order, retrying slots the node does not serve yet.
+func (f *Firehose) processBatch(ctx context.Context, from, to uint64) error {
+	payloads := make([][]byte, to-from+1)
+	for {
+		if err := f.fetchMissing(ctx, from, payloads); err == nil {
+			break
+		} else if !errors.Is(err, solana.ErrBlockNotAvailable) && !errors.Is(err, solana.ErrRateLimited) {
+			return err
+		}
+		// The node is behind or throttling; re-poll the missing slots.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.cfg.PollInterval):
+		}
+	}
+	for i, payload := range payloads {
+		if payload == nil {
+			continue // skipped slot
+		}
+		if err := f.sink.Write(ctx, from+uint64(i), payload); err != nil {
+			return fmt.Errorf("firehose: write slot %d: %w", from+uint64(i), err)
+		}
+	}
+	return nil
+}
+
+// fetchMissing fills the unfetched entries of payloads with workers
+// bounded by MaxConcurrency. Skipped slots stay nil.
+func (f *Firehose) fetchMissing(ctx context.Context, from uint64, payloads [][]byte) error {
+	sem := make(chan struct{}, f.cfg.MaxConcurrency)
+	errs := make(chan error, len(payloads))
+	pending := 0
+	for i := range payloads {
+		if payloads[i] != nil {
+			continue
+		}
+		pending++
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem }()
+			payload, err := f.fetchRaw(ctx, from+uint64(i))
+			if err == nil {
+				payloads[i] = payload
+			}
+			errs <- err
+		}(i)
+	}
+	var firstErr error
+	for ; pending > 0; pending-- {
+		if err := <-errs; err != nil && !errors.Is(err, solana.ErrSlotSkipped) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// fetchRaw fetches one block and returns the undecoded getBlock result.
+func (f *Firehose) fetchRaw(ctx context.Context, slot uint64) ([]byte, error) {
+	params := []any{slot, map[string]any{
+		"encoding":                       "json",
+		"transactionDetails":             "full",
+		"maxSupportedTransactionVersion": 0,
+		"rewards":                        false,
+		"commitment":                     "confirmed",
+	}}
+	var raw json.RawMessage
+	if err := f.client.Call(ctx, "getBlock", params, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
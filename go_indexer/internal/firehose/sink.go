@@ -0,0 +1,132 @@
+package firehose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// dirSegmentSlots is the slot width of one archive segment file.
+const dirSegmentSlots = 10_000
+
+// DirSink writes blocks to JSON-line segment files in a directory, one
+// segment per dirSegmentSlots slots, named blocks-<firstslot>.jsonl.
+// Each line is {"slot":N,"block":<raw getBlock result>}. A cursor file
+// tracks the last written slot so restarts resume without gaps; the
+// directory is typically synced to object storage out of band.
+type DirSink struct {
+	dir     string
+	segment uint64 // first slot of the open segment
+	file    *os.File
+	w       *bufio.Writer
+	last    uint64
+	haveAny bool
+}
+
+// NewDirSink opens (or creates) the archive directory.
+func NewDirSink(dir string) (*DirSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("firehose: create sink dir: %w", err)
+	}
+	s := &DirSink{dir: dir}
+	if data, err := os.ReadFile(s.cursorPath()); err == nil {
+		if last, err := strconv.ParseUint(string(bytes.TrimSpace(data)), 10, 64); err == nil {
+			s.last, s.haveAny = last, true
+		}
+	}
+	return s, nil
+}
+
+// LastSlot returns the slot of the last payload written across runs.
+func (s *DirSink) LastSlot() (uint64, bool) { return s.last, s.haveAny }
+
+func (s *DirSink) cursorPath() string { return filepath.Join(s.dir, "cursor") }
+
+func (s *DirSink) Write(ctx context.Context, slot uint64, payload []byte) error {
+	segment := slot - slot%dirSegmentSlots
+	if s.file == nil || segment != s.segment {
+		if err := s.roll(segment); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(s.w, `{"slot":%d,"block":`, slot)
+	s.w.Write(payload)
+	if _, err := s.w.WriteString("}\n"); err != nil {
+		return err
+	}
+	s.last, s.haveAny = slot, true
+	return nil
+}
+
+// roll flushes the open segment and opens the one starting at segment.
+func (s *DirSink) roll(segment uint64) error {
+	if err := s.closeSegment(); err != nil {
+		return err
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("blocks-%012d.jsonl", segment))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("firehose: open segment: %w", err)
+	}
+	s.file, s.w, s.segment = file, bufio.NewWriterSize(file, 1<<20), segment
+	return nil
+}
+
+// closeSegment flushes and closes the open segment and checkpoints the
+// cursor, so a restart re-fetches at most one unflushed batch.
+func (s *DirSink) closeSegment() error {
+	if s.file == nil {
+		return nil
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file, s.w = nil, nil
+	return os.WriteFile(s.cursorPath(), []byte(strconv.FormatUint(s.last, 10)), 0o644)
+}
+
+func (s *DirSink) Close() error { return s.closeSegment() }
+
+// HTTPSink POSTs each block payload to a collector endpoint (a Kafka
+// REST proxy, a bucket gateway, or any ingest service), with the slot
+// in the X-Block-Slot header. Delivery is at-least-once: a restart
+// re-sends from the configured start slot unless the collector tracks
+// its own cursor.
+type HTTPSink struct {
+	url   string
+	httpc *http.Client
+}
+
+// NewHTTPSink creates a sink posting to url.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url: url, httpc: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (s *HTTPSink) Write(ctx context.Context, slot uint64, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Block-Slot", strconv.FormatUint(slot, 10))
+	resp, err := s.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *HTTPSink) Close() error { return nil }
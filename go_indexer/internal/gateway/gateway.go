@@ -0,0 +1,137 @@
+// Package gateway resolves content-addressed URIs (ipfs://, ar://)
+// into fetchable HTTP URLs across multiple gateway endpoints. The
+// resolver tracks each gateway's recent failures and rotates unhealthy
+// ones to the back with a cooldown, and remembers which gateway last
+// served each URI so repeat fetches go straight to a known-good host.
+// The metadata fetcher resolves through it; custom processors doing
+// their own off-chain reads can share the same instance so health
+// observations accumulate in one place.
+package gateway
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// failureCooldown is how long a gateway rests after each consecutive
+// failure before it is tried again.
+const failureCooldown = 30 * time.Second
+
+// resolvedKeep bounds the remembered uri→gateway assignments.
+const resolvedKeep = 10_000
+
+// Resolver maps URI schemes to gateway endpoints. It is safe for
+// concurrent use.
+type Resolver struct {
+	mu       sync.Mutex
+	schemes  map[string][]*endpoint
+	resolved map[string]string // uri -> last successful candidate URL
+}
+
+type endpoint struct {
+	base     string
+	failures int
+	lastFail time.Time
+}
+
+// NewResolver creates a resolver with the default public gateways
+// registered for ipfs:// and ar://.
+func NewResolver() *Resolver {
+	r := &Resolver{
+		schemes:  make(map[string][]*endpoint),
+		resolved: make(map[string]string),
+	}
+	r.SetGateways("ipfs", []string{"https://ipfs.io/ipfs/", "https://cloudflare-ipfs.com/ipfs/"})
+	r.SetGateways("ar", []string{"https://arweave.net/"})
+	return r
+}
+
+// SetGateways replaces the endpoints serving a scheme. Bases must end
+// where the content path begins, e.g. "https://ipfs.io/ipfs/".
+func (r *Resolver) SetGateways(scheme string, bases []string) {
+	endpoints := make([]*endpoint, 0, len(bases))
+	for _, base := range bases {
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		endpoints = append(endpoints, &endpoint{base: base})
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemes[scheme] = endpoints
+}
+
+// Resolve expands a URI into candidate URLs ordered by gateway health,
+// with the gateway that last served this URI first. URIs of schemes
+// the resolver does not manage pass through as a single candidate.
+func (r *Resolver) Resolve(uri string) []string {
+	scheme, path, ok := strings.Cut(uri, "://")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoints := r.schemes[scheme]
+	if !ok || len(endpoints) == 0 {
+		return []string{uri}
+	}
+	// ipfs://ipfs/CID and ipfs://CID are both seen in the wild.
+	path = strings.TrimPrefix(path, scheme+"/")
+
+	ordered := append([]*endpoint(nil), endpoints...)
+	now := time.Now()
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ci, cj := ordered[i].cooling(now), ordered[j].cooling(now)
+		if ci != cj {
+			return !ci
+		}
+		return ordered[i].failures < ordered[j].failures
+	})
+	out := make([]string, 0, len(ordered)+1)
+	if winner, ok := r.resolved[uri]; ok {
+		out = append(out, winner)
+	}
+	for _, ep := range ordered {
+		if candidate := ep.base + path; len(out) == 0 || candidate != out[0] {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// cooling reports whether the endpoint is resting after failures. The
+// rest grows with consecutive failures, so a dead gateway is probed
+// ever more rarely but never written off.
+func (ep *endpoint) cooling(now time.Time) bool {
+	if ep.failures == 0 {
+		return false
+	}
+	return now.Sub(ep.lastFail) < time.Duration(ep.failures)*failureCooldown
+}
+
+// Report feeds the outcome of fetching a candidate back into the
+// health tracking. uri is the original URI the candidate was resolved
+// from.
+func (r *Resolver) Report(uri, candidate string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, endpoints := range r.schemes {
+		for _, ep := range endpoints {
+			if !strings.HasPrefix(candidate, ep.base) {
+				continue
+			}
+			if err != nil {
+				ep.failures++
+				ep.lastFail = time.Now()
+				if r.resolved[uri] == candidate {
+					delete(r.resolved, uri)
+				}
+			} else {
+				ep.failures = 0
+				if len(r.resolved) < resolvedKeep {
+					r.resolved[uri] = candidate
+				}
+			}
+			return
+		}
+	}
+}
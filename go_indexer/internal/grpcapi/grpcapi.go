@@ -0,0 +1,191 @@
+// Package grpcapi streams decoded instruction events over gRPC with
+// server-side filtering, complementing the REST API's SSE stream for
+// consumers that want typed, flow-controlled streaming. The gRPC
+// transport itself is an opt-in build (go build -tags grpc); the default
+// build compiles only the filter and fan-out logic.
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// replayPageSize is how many stored events one catch-up page loads.
+const replayPageSize = 1000
+
+// subscriberBuffer is each live subscriber's channel depth; a consumer
+// that falls further behind is dropped rather than backpressuring the
+// dispatcher.
+const subscriberBuffer = 256
+
+// Filter is the client's server-side selection: empty slices match
+// everything, FromSlot > 0 replays stored events first so consumption
+// is at-least-once across reconnects.
+type Filter struct {
+	Programs   []string `json:"programs,omitempty"`
+	Accounts   []string `json:"accounts,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	FromSlot   uint64   `json:"from_slot,omitempty"`
+}
+
+// Matches reports whether the filter selects the event. Account
+// matching scans the decoded data values, where processors record the
+// participating accounts.
+func (f Filter) Matches(ev models.Event) bool {
+	if len(f.Programs) > 0 && !contains(f.Programs, ev.Program) {
+		return false
+	}
+	if len(f.EventTypes) > 0 && !contains(f.EventTypes, ev.Type) {
+		return false
+	}
+	if len(f.Accounts) > 0 {
+		found := false
+		for _, value := range ev.Data {
+			if s, ok := value.(string); ok && contains(f.Accounts, s) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Server fans decoded events out to gRPC stream subscribers. It is
+// attached to the outbox as a sink, so delivery shares the commitment
+// policy machinery of the other push sinks.
+type Server struct {
+	store storage.Store
+	log   *slog.Logger
+	// codec selects the wire encoding: "json" (default) or "proto"
+	// (hand-rolled protobuf per proto/indexer.proto).
+	codec string
+
+	mu   sync.Mutex
+	subs map[chan models.Event]Filter
+}
+
+// New creates a Server backed by store for catch-up replay.
+func New(store storage.Store, log *slog.Logger) *Server {
+	return &Server{
+		store: store,
+		log:   log,
+		codec: "json",
+		subs:  make(map[chan models.Event]Filter),
+	}
+}
+
+// SetCodec selects the stream's wire encoding, "json" or "proto".
+func (s *Server) SetCodec(name string) {
+	s.codec = name
+}
+
+// Publish delivers one event to every subscriber whose filter matches.
+// Slow subscribers are dropped, matching the SSE hub's behavior.
+func (s *Server) Publish(ev models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch, f := range s.subs {
+		if !f.Matches(ev) {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+			close(ch)
+			delete(s.subs, ch)
+			s.log.Warn("grpc subscriber dropped: stream too slow")
+		}
+	}
+}
+
+// subscribe attaches a live channel for the filter.
+func (s *Server) subscribe(f Filter) chan models.Event {
+	ch := make(chan models.Event, subscriberBuffer)
+	s.mu.Lock()
+	s.subs[ch] = f
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan models.Event) {
+	s.mu.Lock()
+	if _, ok := s.subs[ch]; ok {
+		delete(s.subs, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+}
+
+// replay sends stored events from the filter's FromSlot through send,
+// paging so one catch-up cannot hold a large result set in memory.
+func (s *Server) replay(ctx context.Context, f Filter, send func(models.Event) error) error {
+	// The store filters on a single program/type; broader filters match
+	// client-side through Filter.Matches.
+	query := storage.EventFilter{FromSlot: f.FromSlot, Limit: replayPageSize}
+	if len(f.Programs) == 1 {
+		query.Program = f.Programs[0]
+	}
+	if len(f.EventTypes) == 1 {
+		query.Type = f.EventTypes[0]
+	}
+	for {
+		page, err := s.store.ListEvents(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, ev := range page {
+			if !f.Matches(ev) {
+				continue
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+		if len(page) < replayPageSize {
+			return nil
+		}
+		query.Offset += replayPageSize
+	}
+}
+
+// stream replays history when FromSlot is set, then forwards live
+// events until ctx ends. Events indexed during the replay window are
+// delivered by both phases, preserving at-least-once semantics.
+func (s *Server) stream(ctx context.Context, f Filter, send func(models.Event) error) error {
+	ch := s.subscribe(f)
+	defer s.unsubscribe(ch)
+	if f.FromSlot > 0 {
+		if err := s.replay(ctx, f, send); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-ch:
+			if !ok {
+				return context.Canceled
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
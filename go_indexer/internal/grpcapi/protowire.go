@@ -0,0 +1,167 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Hand-rolled proto3 wire encoding of the messages in
+// proto/indexer.proto, for consumers that want protobuf payloads
+// instead of JSON. Like the service wiring, it is written out rather
+// than protoc-generated so the template stays free of the protobuf
+// toolchain; the field numbers below must match the .proto file.
+
+// Instruction field numbers (message Instruction).
+const (
+	fieldSignature = 1
+	fieldSlot      = 2
+	fieldBlockTime = 3
+	fieldProgram   = 4
+	fieldType      = 5
+	fieldDataJSON  = 6
+)
+
+// wire types used by the two messages.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// MarshalInstruction encodes one event as an indexer.v1.Instruction.
+func MarshalInstruction(ev *models.Event) ([]byte, error) {
+	var dataJSON []byte
+	if len(ev.Data) > 0 {
+		var err error
+		if dataJSON, err = json.Marshal(ev.Data); err != nil {
+			return nil, err
+		}
+	}
+	var b []byte
+	b = appendStringField(b, fieldSignature, ev.Signature)
+	b = appendVarintField(b, fieldSlot, ev.Slot)
+	b = appendStringField(b, fieldBlockTime, ev.BlockTime.Format(time.RFC3339))
+	b = appendStringField(b, fieldProgram, ev.Program)
+	b = appendStringField(b, fieldType, ev.Type)
+	b = appendStringField(b, fieldDataJSON, string(dataJSON))
+	return b, nil
+}
+
+// FeedEntry field numbers (message FeedEntry).
+const (
+	fieldFeedCursor = 1
+	fieldFeedEvent  = 2
+)
+
+// FeedResponse field numbers (message FeedResponse).
+const (
+	fieldFeedEntries    = 1
+	fieldFeedNextCursor = 2
+)
+
+// MarshalFeedResponse encodes one change-feed page as an
+// indexer.v1.FeedResponse — the protobuf shape of the pull feed that
+// Kafka/NATS bridge relays poll.
+func MarshalFeedResponse(entries []models.OutboxEntry, nextCursor uint64) ([]byte, error) {
+	var b []byte
+	for i := range entries {
+		event, err := MarshalInstruction(&entries[i].Event)
+		if err != nil {
+			return nil, err
+		}
+		var entry []byte
+		entry = appendVarintField(entry, fieldFeedCursor, entries[i].Cursor)
+		entry = appendBytesField(entry, fieldFeedEvent, event)
+		b = appendBytesField(b, fieldFeedEntries, entry)
+	}
+	b = appendVarintField(b, fieldFeedNextCursor, nextCursor)
+	return b, nil
+}
+
+// UnmarshalFilter decodes an indexer.v1.InstructionFilter. Unknown
+// fields are skipped, matching proto3 semantics.
+func UnmarshalFilter(b []byte, f *Filter) error {
+	for len(b) > 0 {
+		key, n := decodeVarint(b)
+		if n == 0 {
+			return fmt.Errorf("proto filter: truncated tag")
+		}
+		b = b[n:]
+		field, wire := key>>3, key&7
+		switch wire {
+		case wireVarint:
+			v, n := decodeVarint(b)
+			if n == 0 {
+				return fmt.Errorf("proto filter: truncated varint")
+			}
+			b = b[n:]
+			if field == 4 {
+				f.FromSlot = v
+			}
+		case wireBytes:
+			size, n := decodeVarint(b)
+			if n == 0 || uint64(len(b)-n) < size {
+				return fmt.Errorf("proto filter: truncated bytes field")
+			}
+			value := string(b[n : uint64(n)+size])
+			b = b[uint64(n)+size:]
+			switch field {
+			case 1:
+				f.Programs = append(f.Programs, value)
+			case 2:
+				f.Accounts = append(f.Accounts, value)
+			case 3:
+				f.EventTypes = append(f.EventTypes, value)
+			}
+		default:
+			return fmt.Errorf("proto filter: unsupported wire type %d", wire)
+		}
+	}
+	return nil
+}
+
+func appendStringField(b []byte, field int, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = appendVarint(b, uint64(field)<<3|wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = appendVarint(b, uint64(field)<<3|wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendVarint(b, uint64(field)<<3|wireVarint)
+	return appendVarint(b, v)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// decodeVarint returns the value and the bytes consumed; 0 consumed
+// means the input was truncated.
+func decodeVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,105 @@
+//go:build grpc
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// The service is hand-wired rather than protoc-generated so the wire
+// schema lives next to the implementation; messages travel as JSON by
+// default, or as protobuf when GRPC_CODEC=proto. proto/indexer.proto
+// documents the schema for clients that prefer generated stubs.
+
+const codecName = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)   { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(b []byte, v any) error { return json.Unmarshal(b, v) }
+func (jsonCodec) Name() string                    { return codecName }
+
+// protoCodec speaks the protobuf wire format of proto/indexer.proto
+// (see protowire.go), halving payload size for high-volume consumers.
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v any) ([]byte, error) {
+	ev, ok := v.(*models.Event)
+	if !ok {
+		return nil, fmt.Errorf("proto codec: unsupported message %T", v)
+	}
+	return MarshalInstruction(ev)
+}
+
+func (protoCodec) Unmarshal(b []byte, v any) error {
+	f, ok := v.(*Filter)
+	if !ok {
+		return fmt.Errorf("proto codec: unsupported message %T", v)
+	}
+	return UnmarshalFilter(b, f)
+}
+
+func (protoCodec) Name() string { return "proto" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+	encoding.RegisterCodec(protoCodec{})
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "indexer.v1.Indexer",
+	HandlerType: (*streamService)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "StreamInstructions",
+		Handler:       streamInstructionsHandler,
+		ServerStreams: true,
+	}},
+}
+
+type streamService interface {
+	StreamInstructions(Filter, grpc.ServerStream) error
+}
+
+func streamInstructionsHandler(srv any, stream grpc.ServerStream) error {
+	var f Filter
+	if err := stream.RecvMsg(&f); err != nil {
+		return err
+	}
+	return srv.(streamService).StreamInstructions(f, stream)
+}
+
+// StreamInstructions implements the server-streaming RPC: catch-up from
+// Filter.FromSlot, then live matching events until the client goes away.
+func (s *Server) StreamInstructions(f Filter, stream grpc.ServerStream) error {
+	return s.stream(stream.Context(), f, func(ev models.Event) error {
+		return stream.SendMsg(&ev)
+	})
+}
+
+// Serve listens for gRPC streaming clients on port until ctx ends.
+func (s *Server) Serve(ctx context.Context, port int) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("grpc listen: %w", err)
+	}
+	var codec encoding.Codec = jsonCodec{}
+	if s.codec == "proto" {
+		codec = protoCodec{}
+	}
+	srv := grpc.NewServer(grpc.ForceServerCodec(codec))
+	srv.RegisterService(&serviceDesc, s)
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+	s.log.Info("grpc server listening", "addr", lis.Addr())
+	return srv.Serve(lis)
+}
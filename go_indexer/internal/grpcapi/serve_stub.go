@@ -0,0 +1,15 @@
+//go:build !grpc
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+)
+
+// Serve is the default-build placeholder. The gRPC transport is an
+// external dependency the template does not vendor, so the streaming
+// service needs an opt-in build: go build -tags grpc ./...
+func (s *Server) Serve(ctx context.Context, port int) error {
+	return errors.New("binary built without gRPC support; rebuild with -tags grpc")
+}
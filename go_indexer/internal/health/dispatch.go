@@ -0,0 +1,85 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPDispatcher emails health alerts.
+type SMTPDispatcher struct {
+	Addr     string // host:port
+	From     string
+	To       []string
+	Username string // empty disables authentication
+	Password string
+}
+
+func (d *SMTPDispatcher) Name() string { return "smtp" }
+
+func (d *SMTPDispatcher) Dispatch(ctx context.Context, alert Alert) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", d.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(d.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [indexer %s] %s\r\n", alert.Severity, alert.Condition)
+	fmt.Fprintf(&msg, "\r\n%s\r\n", alert.Message)
+	var auth smtp.Auth
+	if d.Username != "" {
+		host, _, _ := strings.Cut(d.Addr, ":")
+		auth = smtp.PlainAuth("", d.Username, d.Password, host)
+	}
+	return smtp.SendMail(d.Addr, auth, d.From, d.To, msg.Bytes())
+}
+
+// PagerDutyDispatcher triggers incidents through the Events API v2.
+type PagerDutyDispatcher struct {
+	RoutingKey string
+	// Endpoint overrides the Events API URL; empty uses the public one.
+	Endpoint string
+
+	httpc *http.Client
+}
+
+func (d *PagerDutyDispatcher) Name() string { return "pagerduty" }
+
+func (d *PagerDutyDispatcher) Dispatch(ctx context.Context, alert Alert) error {
+	endpoint := d.Endpoint
+	if endpoint == "" {
+		endpoint = "https://events.pagerduty.com/v2/enqueue"
+	}
+	if d.httpc == nil {
+		d.httpc = &http.Client{Timeout: 10 * time.Second}
+	}
+	body, err := json.Marshal(map[string]any{
+		"routing_key":  d.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    "indexer-" + alert.Condition,
+		"payload": map[string]any{
+			"summary":  alert.Condition + ": " + alert.Message,
+			"severity": alert.Severity,
+			"source":   "go-indexer",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("health: pagerduty returned %d", resp.StatusCode)
+	}
+	return nil
+}
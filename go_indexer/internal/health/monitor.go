@@ -0,0 +1,248 @@
+// Package health monitors the indexer itself. A background loop evaluates
+// operational conditions — no new slot indexed for too long, an error rate
+// spike, the store unreachable — and dispatches alerts through SMTP and
+// the PagerDuty Events API when a condition starts failing.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Alert is one condition transition reported to the dispatchers.
+type Alert struct {
+	Condition string `json:"condition"`
+	Message   string `json:"message"`
+	Severity  string `json:"severity"` // warning or critical
+}
+
+// Dispatcher delivers health alerts to one destination.
+type Dispatcher interface {
+	Name() string
+	Dispatch(ctx context.Context, alert Alert) error
+}
+
+// Options are the monitor thresholds; zero values select the defaults.
+type Options struct {
+	CheckInterval    time.Duration // how often conditions are evaluated
+	StallAfter       time.Duration // alert when no slot is indexed for this long
+	MaxErrorsPerMin  int           // alert when more errors than this occur within a minute
+	StorePingTimeout time.Duration // deadline for the store reachability probe
+	// HaltAfter is how long the cluster tip may stay frozen before the
+	// halt conditions fire; it only applies when a tip status source is
+	// registered through SetTipStatus.
+	HaltAfter time.Duration
+	// LatencyBurnAlert is the SLO burn rate above which the freshness
+	// condition fires; it only applies when a burn-rate source is
+	// registered through SetLatencyStatus. A rate of 1 exhausts the
+	// error budget exactly on schedule, so the default of 2 alerts
+	// when the budget burns twice as fast as the objective allows.
+	LatencyBurnAlert float64
+}
+
+// Monitor evaluates the health conditions on a timer.
+type Monitor struct {
+	opts        Options
+	progress    func() (live, backfill indexer.LaneSnapshot)
+	ping        func(ctx context.Context) error
+	publish     func(models.Event)
+	dispatchers []Dispatcher
+	tipStatus   func() (tip uint64, sinceAdvance, sinceReachable time.Duration)
+	burnRate    func() (rate float64, ok bool)
+	log         *slog.Logger
+
+	mu          sync.Mutex
+	lastAdvance time.Time
+	lastSlots   uint64
+	errorTimes  []time.Time
+	activeConds map[string]bool
+}
+
+// NewMonitor creates a Monitor. progress and ping supply the observations;
+// publish, when non-nil, receives one event per condition transition so
+// the notifier channels see them too.
+func NewMonitor(opts Options, progress func() (live, backfill indexer.LaneSnapshot), ping func(ctx context.Context) error, publish func(models.Event), dispatchers []Dispatcher, log *slog.Logger) *Monitor {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = 10 * time.Second
+	}
+	if opts.StallAfter <= 0 {
+		opts.StallAfter = time.Minute
+	}
+	if opts.MaxErrorsPerMin <= 0 {
+		opts.MaxErrorsPerMin = 10
+	}
+	if opts.StorePingTimeout <= 0 {
+		opts.StorePingTimeout = 5 * time.Second
+	}
+	if opts.HaltAfter <= 0 {
+		opts.HaltAfter = 2 * time.Minute
+	}
+	if opts.LatencyBurnAlert <= 0 {
+		opts.LatencyBurnAlert = 2
+	}
+	return &Monitor{
+		opts:        opts,
+		progress:    progress,
+		ping:        ping,
+		publish:     publish,
+		dispatchers: dispatchers,
+		log:         log,
+		lastAdvance: time.Now(),
+		activeConds: make(map[string]bool),
+	}
+}
+
+// SetTipStatus registers the cluster tip observations the halt conditions
+// evaluate, normally the RPC endpoint scorer's TipStatus. Because the
+// scorer probes several endpoints, a frozen tip with endpoints still
+// answering is reported as chain_halted while a frozen tip with nothing
+// answering is reported as rpc_unreachable.
+func (m *Monitor) SetTipStatus(fn func() (tip uint64, sinceAdvance, sinceReachable time.Duration)) {
+	m.tipStatus = fn
+}
+
+// SetLatencyStatus registers the end-to-end latency SLO burn rate the
+// freshness condition evaluates, normally the latency tracker's
+// BurnStatus. A false ok means the rate is not yet meaningful (too few
+// transactions observed) and the condition stays quiet.
+func (m *Monitor) SetLatencyStatus(fn func() (rate float64, ok bool)) {
+	m.burnRate = fn
+}
+
+// Halted reports whether a halt condition is currently active, letting
+// the indexer pause lag-based autoscaling while slots cannot advance.
+func (m *Monitor) Halted() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.activeConds["chain_halted"] || m.activeConds["rpc_unreachable"]
+}
+
+// RecordError counts one indexing error toward the error rate condition.
+// The indexer calls this from its batch failure path.
+func (m *Monitor) RecordError() {
+	m.mu.Lock()
+	m.errorTimes = append(m.errorTimes, time.Now())
+	m.mu.Unlock()
+}
+
+// Run evaluates the conditions until ctx is canceled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evaluate(ctx)
+		}
+	}
+}
+
+func (m *Monitor) evaluate(ctx context.Context) {
+	live, backfill := m.progress()
+	total := live.SlotsProcessed + backfill.SlotsProcessed
+
+	m.mu.Lock()
+	if total > m.lastSlots {
+		m.lastSlots = total
+		m.lastAdvance = time.Now()
+	}
+	stalledFor := time.Since(m.lastAdvance)
+	cutoff := time.Now().Add(-time.Minute)
+	kept := m.errorTimes[:0]
+	for _, t := range m.errorTimes {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	m.errorTimes = kept
+	errorsLastMin := len(kept)
+	m.mu.Unlock()
+
+	m.transition("indexer_stalled", stalledFor > m.opts.StallAfter, Alert{
+		Condition: "indexer_stalled",
+		Message:   "no new slot indexed for " + stalledFor.Truncate(time.Second).String(),
+		Severity:  "critical",
+	})
+	m.transition("error_rate_spike", errorsLastMin > m.opts.MaxErrorsPerMin, Alert{
+		Condition: "error_rate_spike",
+		Message:   "indexing error rate above threshold",
+		Severity:  "warning",
+	})
+
+	if m.tipStatus != nil {
+		tip, sinceAdvance, sinceReachable := m.tipStatus()
+		frozen := sinceAdvance > m.opts.HaltAfter
+		unreachable := frozen && sinceReachable > m.opts.HaltAfter
+		m.transition("rpc_unreachable", unreachable, Alert{
+			Condition: "rpc_unreachable",
+			Message:   "no RPC endpoint has answered for " + sinceReachable.Truncate(time.Second).String(),
+			Severity:  "critical",
+		})
+		m.transition("chain_halted", frozen && !unreachable, Alert{
+			Condition: "chain_halted",
+			Message: "cluster tip frozen at slot " + strconv.FormatUint(tip, 10) +
+				" for " + sinceAdvance.Truncate(time.Second).String() + " with endpoints still reachable",
+			Severity: "critical",
+		})
+	}
+
+	if m.burnRate != nil {
+		rate, ok := m.burnRate()
+		m.transition("latency_slo_burn", ok && rate > m.opts.LatencyBurnAlert, Alert{
+			Condition: "latency_slo_burn",
+			Message: "indexing latency SLO burning at " +
+				strconv.FormatFloat(rate, 'f', 1, 64) + "x the error budget",
+			Severity: "warning",
+		})
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, m.opts.StorePingTimeout)
+	pingErr := m.ping(pingCtx)
+	cancel()
+	alert := Alert{
+		Condition: "store_unreachable",
+		Severity:  "critical",
+	}
+	if pingErr != nil {
+		alert.Message = "store probe failed: " + pingErr.Error()
+	}
+	m.transition("store_unreachable", pingErr != nil, alert)
+}
+
+// transition dispatches an alert on the healthy-to-failing edge and logs
+// the recovery on the way back, so each episode alerts exactly once.
+func (m *Monitor) transition(name string, failing bool, alert Alert) {
+	m.mu.Lock()
+	wasActive := m.activeConds[name]
+	m.activeConds[name] = failing
+	m.mu.Unlock()
+	if failing == wasActive {
+		return
+	}
+	if !failing {
+		m.log.Info("health condition recovered", "condition", name)
+		return
+	}
+	m.log.Warn("health condition failing", "condition", name, "message", alert.Message)
+	if m.publish != nil {
+		m.publish(models.Event{
+			Type: name,
+			Data: map[string]any{"message": alert.Message, "severity": alert.Severity},
+		})
+	}
+	for _, d := range m.dispatchers {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := d.Dispatch(ctx, alert); err != nil {
+			m.log.Warn("health alert dispatch failed", "dispatcher", d.Name(), "error", err)
+		}
+		cancel()
+	}
+}
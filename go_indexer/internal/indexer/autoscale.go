@@ -0,0 +1,87 @@
+package indexer
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// autoscaler converges the worker count between MIN_CONCURRENCY and
+// MAX_CONCURRENCY without manual tuning per provider. Queue depth (slots
+// behind the tip) pushes the count up; rising RPC fetch latency pushes
+// it back down, so capacity settles at what the endpoint sustains.
+type autoscaler struct {
+	min, max int
+	workers  atomic.Int64
+
+	mu sync.Mutex
+	// Fetch latency accumulated since the last adjustment.
+	sum time.Duration
+	n   int
+	// baseline is an EWMA of past windows; a window averaging well above
+	// it means the provider is struggling at the current worker count.
+	baseline time.Duration
+}
+
+func newAutoscaler(min, max int) *autoscaler {
+	a := &autoscaler{min: min, max: max}
+	a.workers.Store(int64(min))
+	return a
+}
+
+// current returns the worker count to use for the next batch.
+func (a *autoscaler) current() int { return int(a.workers.Load()) }
+
+// observe records one block fetch latency.
+func (a *autoscaler) observe(d time.Duration) {
+	a.mu.Lock()
+	a.sum += d
+	a.n++
+	a.mu.Unlock()
+}
+
+// adjust reconsiders the worker count; the indexer calls it once per
+// poll tick with the current queue depth.
+func (a *autoscaler) adjust(queueDepth uint64, batchSize int, log *slog.Logger) {
+	a.mu.Lock()
+	var avg time.Duration
+	if a.n > 0 {
+		avg = a.sum / time.Duration(a.n)
+	}
+	a.sum, a.n = 0, 0
+	baseline := a.baseline
+	if avg > 0 {
+		if baseline == 0 {
+			a.baseline = avg
+		} else {
+			a.baseline = (3*baseline + avg) / 4
+		}
+	}
+	a.mu.Unlock()
+
+	cur := int(a.workers.Load())
+	next := cur
+	switch {
+	case avg > 0 && baseline > 0 && avg > baseline*3/2:
+		// Latency degraded at this size; back off even if behind.
+		next = cur - 1
+	case queueDepth > uint64(batchSize):
+		// More than one batch behind and latency is stable; grow.
+		next = cur + 1
+	case queueDepth == 0:
+		// Caught up; release capacity gradually.
+		next = cur - 1
+	}
+	if next < a.min {
+		next = a.min
+	}
+	if next > a.max {
+		next = a.max
+	}
+	if next != cur {
+		a.workers.Store(int64(next))
+		log.Debug("worker count adjusted", "workers", next,
+			"queue_depth", queueDepth, "fetch_latency", avg)
+	}
+}
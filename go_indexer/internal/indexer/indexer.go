@@ -0,0 +1,520 @@
+// Package indexer implements the core indexing loop: polling for new
+// slots, fetching blocks, and dispatching transactions to the configured
+// indexing profiles.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/blocktime"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/filter"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/pool"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/redact"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Indexer drives the fetch-decode-store pipeline. A single block stream is
+// shared by all profiles; each profile filters and stores independently.
+type Indexer struct {
+	cfg            *config.Config
+	client         solana.ChainSource
+	store          storage.Store // block and transaction summaries
+	profiles       []*Profile
+	publish        func(models.Event)
+	observeTx      func(models.Transaction)
+	inspect        func(*processor.TxContext)
+	observeOut     func(*processor.TxContext, *processor.Output)
+	recordErr      func()
+	haltCheck      func() bool
+	observePartial func(slot uint64)
+	observeLatency func(blockTime, received time.Time, transactions int)
+	recordCovered  func(source string, from, to uint64)
+	txFilter       *filter.Expr
+	backfillDone   func()
+	clock          *blocktime.Oracle
+	scale          *autoscaler // nil when MIN_CONCURRENCY is unset
+	log            *slog.Logger
+
+	// Lane scheduling state: see scheduler.go and replay.go.
+	caughtUp atomic.Bool
+	live     laneProgress
+	backfill laneProgress
+	replay   laneProgress
+}
+
+// New creates an Indexer over the shared store and the given profiles.
+// client is usually a *solana.Client; other SVM networks pass the
+// adapter from solana.NewChainSource.
+func New(cfg *config.Config, client solana.ChainSource, store storage.Store, profiles []*Profile, log *slog.Logger) *Indexer {
+	idx := &Indexer{
+		cfg:      cfg,
+		client:   client,
+		store:    store,
+		profiles: profiles,
+		clock:    blocktime.NewOracle(),
+		log:      log,
+	}
+	if cfg.MinConcurrency > 0 && cfg.MinConcurrency < cfg.MaxConcurrency {
+		idx.scale = newAutoscaler(cfg.MinConcurrency, cfg.MaxConcurrency)
+	}
+	return idx
+}
+
+// SetEventPublisher registers a callback invoked for every stored event,
+// used by the API server to feed its SSE stream.
+func (idx *Indexer) SetEventPublisher(fn func(models.Event)) { idx.publish = fn }
+
+// SetTransactionObserver registers a callback invoked for every stored
+// transaction, used by the signature watcher.
+func (idx *Indexer) SetTransactionObserver(fn func(models.Transaction)) { idx.observeTx = fn }
+
+// SetTransactionInspector registers a callback that receives the full
+// transaction context for every stored transaction, used by the alert
+// rules engine.
+func (idx *Indexer) SetTransactionInspector(fn func(*processor.TxContext)) { idx.inspect = fn }
+
+// SetOutputObserver registers a callback that receives each processor's
+// stored output, used by the rollup engine's token account counters.
+func (idx *Indexer) SetOutputObserver(fn func(*processor.TxContext, *processor.Output)) {
+	idx.observeOut = fn
+}
+
+// SetErrorRecorder registers a callback invoked for every failed batch,
+// used by the health monitor's error rate condition.
+func (idx *Indexer) SetErrorRecorder(fn func()) { idx.recordErr = fn }
+
+// SetPartialObserver registers a callback invoked with the slot of every
+// block stored partial, used to feed the repair queue.
+func (idx *Indexer) SetPartialObserver(fn func(slot uint64)) { idx.observePartial = fn }
+
+// SetCoverageRecorder registers a callback invoked with every slot range
+// a lane completes, used to maintain the coverage map.
+func (idx *Indexer) SetCoverageRecorder(fn func(source string, from, to uint64)) {
+	idx.recordCovered = fn
+}
+
+// SetLatencyObserver registers a callback invoked once per committed
+// block with its blockTime, the time its payload arrived from RPC
+// (zero during replay), and its transaction count, used by the latency
+// tracker's freshness figures.
+func (idx *Indexer) SetLatencyObserver(fn func(blockTime, received time.Time, transactions int)) {
+	idx.observeLatency = fn
+}
+
+// SetHaltCheck registers a callback reporting whether the cluster is
+// currently halted. While it returns true, lag-based autoscaling is
+// paused: queue depth is meaningless when slots cannot advance.
+func (idx *Indexer) SetHaltCheck(fn func() bool) { idx.haltCheck = fn }
+
+// SetTransactionFilter installs the TX_FILTER expression; transactions it
+// rejects are neither stored nor published.
+func (idx *Indexer) SetTransactionFilter(expr *filter.Expr) { idx.txFilter = expr }
+
+// SetBackfillDone registers a callback invoked once when the backfill
+// lane finishes its range, used by bulk-loading stores to flush buffers
+// and build indexes.
+func (idx *Indexer) SetBackfillDone(fn func()) { idx.backfillDone = fn }
+
+// Run polls for new slots and processes them until ctx is canceled.
+func (idx *Indexer) Run(ctx context.Context) error {
+	current := idx.cfg.StartSlot
+	if idx.cfg.StartAtLatest {
+		slot, err := idx.client.GetSlot(ctx)
+		if err != nil {
+			return fmt.Errorf("resolve start slot: %w", err)
+		}
+		current = slot
+	}
+	startSlot := current
+	idx.log.Info("indexer starting", "start_slot", current, "end_slot", idx.cfg.EndSlot,
+		"profiles", len(idx.profiles), "live_workers", idx.liveConcurrency())
+	if err := idx.startBackfill(ctx); err != nil {
+		return err
+	}
+	if err := idx.startReplay(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(idx.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		tip, err := idx.client.GetSlot(ctx)
+		if err != nil {
+			idx.log.Warn("get slot failed", "error", err)
+		} else {
+			// With END_SLOT configured this is a bounded batch job:
+			// process up to the end slot and exit cleanly when done.
+			if idx.cfg.EndSlot > 0 && tip > idx.cfg.EndSlot {
+				tip = idx.cfg.EndSlot
+			}
+			if idx.scale != nil && (idx.haltCheck == nil || !idx.haltCheck()) {
+				var depth uint64
+				if tip >= current {
+					depth = tip - current + 1
+				}
+				idx.scale.adjust(depth, idx.cfg.BatchSize, idx.log)
+			}
+			idx.caughtUp.Store(current > tip)
+			for current <= tip {
+				end := current + uint64(idx.cfg.BatchSize) - 1
+				if end > tip {
+					end = tip
+				}
+				if err := idx.processRange(ctx, current, end, idx.liveConcurrency()); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					if idx.recordErr != nil {
+						idx.recordErr()
+					}
+					if errors.Is(err, solana.ErrRateLimited) {
+						idx.log.Warn("rpc rate limited, backing off", "from", current, "to", end)
+					} else {
+						idx.log.Error("batch failed", "from", current, "to", end, "error", err)
+					}
+					break
+				}
+				idx.live.current.Store(end)
+				idx.live.processed.Add(end - current + 1)
+				if idx.recordCovered != nil {
+					idx.recordCovered("live", current, end)
+				}
+				current = end + 1
+				idx.caughtUp.Store(current > tip)
+				if idx.cfg.EndSlot > 0 {
+					idx.log.Info("range progress",
+						"slot", end,
+						"percent", fmt.Sprintf("%.1f", rangeProgress(startSlot, idx.cfg.EndSlot, end)))
+				}
+			}
+			if idx.cfg.EndSlot > 0 && current > idx.cfg.EndSlot {
+				idx.log.Info("configured range complete", "start_slot", startSlot, "end_slot", idx.cfg.EndSlot)
+				return nil
+			}
+		}
+		// When projected RPC spend is near the configured budget, skip
+		// extra ticks so usage falls back under the limit.
+		waits := 1
+		if b := idx.client.Budget(); b != nil && b.NearLimit() {
+			idx.log.Warn("rpc budget nearly exhausted, slowing down")
+			waits = 5
+		}
+		for i := 0; i < waits; i++ {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// rangeProgress returns the percentage of [start, end] completed at slot.
+func rangeProgress(start, end, slot uint64) float64 {
+	if end <= start {
+		return 100
+	}
+	return float64(slot-start) / float64(end-start) * 100
+}
+
+// processRange processes [from, end] with up to concurrency workers.
+func (idx *Indexer) processRange(ctx context.Context, from, end uint64, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for slot := from; slot <= end; slot++ {
+		slot := slot
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := idx.ProcessSlot(ctx, slot); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("slot %d: %w", slot, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// ProcessSlot fetches one block and runs it through every profile.
+func (idx *Indexer) ProcessSlot(ctx context.Context, slot uint64) error {
+	fetchStart := time.Now()
+	block, err := idx.client.GetBlock(ctx, slot)
+	if err == nil && idx.scale != nil {
+		idx.scale.observe(time.Since(fetchStart))
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, solana.ErrSlotSkipped):
+			// The slot produced no block; skip it permanently.
+			idx.log.Debug("slot skipped", "slot", slot)
+			return nil
+		case errors.Is(err, solana.ErrBlockNotAvailable):
+			// The node is behind; the batch will be retried.
+			return err
+		case errors.Is(err, solana.ErrBlockPurged):
+			// The block aged out of every configured endpoint's retention;
+			// only an archival endpoint can recover it.
+			return fmt.Errorf("slot %d purged from RPC retention (set ARCHIVAL_RPC_URL to backfill it): %w", slot, err)
+		default:
+			return err
+		}
+	}
+	return idx.processBlock(ctx, slot, block, time.Now())
+}
+
+// ProcessBlock runs an already fetched block through the pipeline. It is
+// used by ProcessSlot and by the replay-based benchmarks.
+func (idx *Indexer) ProcessBlock(ctx context.Context, slot uint64, block *solana.Block) error {
+	// Replayed blocks have no receipt moment, so the latency tracker's
+	// receipt series skips them.
+	return idx.processBlock(ctx, slot, block, time.Time{})
+}
+
+func (idx *Indexer) processBlock(ctx context.Context, slot uint64, block *solana.Block, received time.Time) error {
+	rawTime := time.Time{}
+	if block.BlockTime != nil {
+		rawTime = time.Unix(*block.BlockTime, 0).UTC()
+	}
+	blockTime, timeSource := idx.clock.Derive(slot, block.BlockTime)
+	row := &models.Block{
+		Slot:              slot,
+		BlockHash:         block.Blockhash,
+		ParentSlot:        block.ParentSlot,
+		BlockTime:         blockTime,
+		RawBlockTime:      rawTime,
+		TimeSource:        timeSource,
+		TransactionsCount: len(block.Transactions),
+		IndexedAt:         time.Now().UTC(),
+	}
+	if len(block.FailedTransactions) > 0 {
+		row.Partial = true
+		row.FailedTransactions = append([]string(nil), block.FailedTransactions...)
+		idx.log.Warn("block stored partial", "slot", slot,
+			"failed_transactions", len(block.FailedTransactions))
+		if idx.observePartial != nil {
+			defer idx.observePartial(slot)
+		}
+	}
+	if err := idx.detectReorg(ctx, slot, block, row); err != nil {
+		return err
+	}
+	// A stored partial block is replaced rather than kept, so
+	// re-processing the slot (normally via the repair worker) converges
+	// it to complete once every transaction decodes.
+	if existing, err := idx.store.GetBlock(ctx, slot); err == nil && existing.Partial {
+		if err := idx.store.ReplaceBlock(ctx, row); err != nil {
+			return fmt.Errorf("replace partial block: %w", err)
+		}
+	} else if err := idx.store.SaveBlock(ctx, row); err != nil && !errors.Is(err, storage.ErrDuplicate) {
+		return fmt.Errorf("save block: %w", err)
+	}
+
+	for i := range block.Transactions {
+		tx := &block.Transactions[i]
+		if len(tx.Transaction.Signatures) == 0 {
+			continue
+		}
+		if err := idx.processTransaction(ctx, slot, blockTime, tx); err != nil {
+			return err
+		}
+	}
+	if idx.observeLatency != nil && len(block.Transactions) > 0 {
+		idx.observeLatency(blockTime, received, len(block.Transactions))
+	}
+	return nil
+}
+
+// detectReorg compares the fetched block against what the store already
+// holds for its slot and for its parent. On a mismatch the abandoned
+// stored blocks are flagged, an audit row is written, and a
+// reorg_detected event is published; see models.Reorg.
+func (idx *Indexer) detectReorg(ctx context.Context, slot uint64, block *solana.Block, row *models.Block) error {
+	existing, err := idx.store.GetBlock(ctx, slot)
+	if err == nil && existing.BlockHash != block.Blockhash {
+		// The slot itself was re-produced under a different hash.
+		abandoned, err := idx.store.MarkBlocksOrphaned(ctx, slot-1, slot)
+		if err != nil {
+			return fmt.Errorf("mark orphaned: %w", err)
+		}
+		if err := idx.store.ReplaceBlock(ctx, row); err != nil {
+			return fmt.Errorf("replace block: %w", err)
+		}
+		return idx.recordReorg(ctx, slot, block.ParentSlot, abandoned, []string{block.Blockhash})
+	}
+	if block.PreviousBlockhash == "" {
+		return nil
+	}
+	parent, err := idx.store.GetBlock(ctx, block.ParentSlot)
+	if err != nil || parent.Orphaned || parent.BlockHash == block.PreviousBlockhash {
+		return nil
+	}
+	// The stored parent is on an abandoned fork: everything from it up to
+	// (but excluding) this block is orphaned. The fork point is at or
+	// before the stored parent's own parent.
+	abandoned, markErr := idx.store.MarkBlocksOrphaned(ctx, parent.ParentSlot, slot-1)
+	if markErr != nil {
+		return fmt.Errorf("mark orphaned: %w", markErr)
+	}
+	return idx.recordReorg(ctx, slot, parent.ParentSlot, abandoned, []string{block.Blockhash})
+}
+
+// recordReorg persists the audit row and publishes the reorg_detected
+// ops event.
+func (idx *Indexer) recordReorg(ctx context.Context, slot, forkSlot uint64, abandoned, replacement []string) error {
+	if err := idx.store.SaveReorg(ctx, &models.Reorg{
+		ForkSlot:          forkSlot,
+		AbandonedBlocks:   abandoned,
+		ReplacementBlocks: replacement,
+		DetectedAt:        time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("save reorg: %w", err)
+	}
+	idx.log.Warn("reorg detected", "slot", slot, "fork_slot", forkSlot, "abandoned_blocks", len(abandoned))
+	if idx.publish != nil {
+		idx.publish(models.Event{
+			Slot: slot,
+			Type: "reorg_detected",
+			Data: map[string]any{
+				"fork_slot":          forkSlot,
+				"abandoned_blocks":   abandoned,
+				"replacement_blocks": replacement,
+			},
+		})
+	}
+	return nil
+}
+
+func (idx *Indexer) processTransaction(ctx context.Context, slot uint64, blockTime time.Time, tx *solana.TransactionWithMeta) error {
+	signature := tx.Transaction.Signatures[0]
+	if idx.txFilter != nil {
+		keep, err := idx.txFilter.Eval(filter.TxEnv(slot, signature, tx))
+		if err != nil {
+			return fmt.Errorf("transaction filter: %w", err)
+		}
+		if !keep {
+			return nil
+		}
+	}
+	if idx.cfg.SignaturesOnly {
+		return idx.processSignatureOnly(ctx, slot, blockTime, signature, tx)
+	}
+	// The row is pooled: it is released once the storage commit and the
+	// observers below have all returned.
+	row := pool.Transaction()
+	defer pool.ReleaseTransaction(row)
+	row.Signature = signature
+	if keys := tx.Transaction.Message.AccountKeys; len(keys) > 0 {
+		row.Signer = keys[0]
+		// The first len(Signatures) account keys are the signing accounts.
+		if n := len(tx.Transaction.Signatures); n <= len(keys) {
+			row.Signers = append(row.Signers, keys[:n]...)
+		}
+	}
+	row.Slot = slot
+	row.BlockTime = blockTime
+	row.Success = tx.Meta.Succeeded()
+	row.IndexedAt = time.Now().UTC()
+	if tx.Meta != nil {
+		row.Fee = tx.Meta.Fee
+		if !tx.Meta.Succeeded() {
+			row.Error = string(tx.Meta.Err)
+		}
+	}
+	if nonceAccount, authority, ok := tx.Transaction.Message.DurableNonce(); ok {
+		row.DurableNonce = true
+		row.NonceAccount = nonceAccount
+		if err := idx.store.SaveNonceUse(ctx, &models.NonceAccount{
+			Address:       nonceAccount,
+			Authority:     authority,
+			LastUsedSlot:  slot,
+			LastSignature: signature,
+		}); err != nil {
+			return fmt.Errorf("save nonce use %s: %w", nonceAccount, err)
+		}
+	}
+	if red := redact.Default(); red != nil {
+		red.Transaction(row)
+	}
+	if err := idx.store.SaveTransaction(ctx, row); err != nil && !errors.Is(err, storage.ErrDuplicate) {
+		return fmt.Errorf("save transaction %s: %w", signature, err)
+	}
+	if idx.observeTx != nil {
+		idx.observeTx(*row)
+	}
+	if idx.inspect != nil {
+		idx.inspect(&processor.TxContext{
+			Slot:      slot,
+			BlockTime: blockTime,
+			Signature: signature,
+			Tx:        tx,
+		})
+	}
+
+	for _, profile := range idx.profiles {
+		if !profile.Matches(tx) {
+			continue
+		}
+		if err := profile.Process(ctx, slot, blockTime, signature, tx, idx.publish, idx.observeOut); err != nil {
+			return fmt.Errorf("profile %s: %w", profile.Name, err)
+		}
+	}
+	return nil
+}
+
+// processSignatureOnly stores the minimal existence row of
+// signatures-only mode: signature, slot, block time, fee payer, invoked
+// programs, and status. Instruction payloads are never decoded, so
+// profiles, inspectors, and nonce tracking are all skipped.
+func (idx *Indexer) processSignatureOnly(ctx context.Context, slot uint64, blockTime time.Time, signature string, tx *solana.TransactionWithMeta) error {
+	row := pool.Transaction()
+	defer pool.ReleaseTransaction(row)
+	row.Signature = signature
+	row.Slot = slot
+	row.BlockTime = blockTime
+	row.Success = tx.Meta.Succeeded()
+	row.IndexedAt = time.Now().UTC()
+	msg := &tx.Transaction.Message
+	if len(msg.AccountKeys) > 0 {
+		row.Signer = msg.AccountKeys[0]
+	}
+	seen := make(map[string]struct{}, 4)
+	for _, ix := range msg.Instructions {
+		program := msg.ProgramID(ix)
+		if program == "" {
+			continue
+		}
+		if _, ok := seen[program]; ok {
+			continue
+		}
+		seen[program] = struct{}{}
+		row.ProgramIDs = append(row.ProgramIDs, program)
+	}
+	if err := idx.store.SaveTransaction(ctx, row); err != nil && !errors.Is(err, storage.ErrDuplicate) {
+		return fmt.Errorf("save transaction %s: %w", signature, err)
+	}
+	if idx.observeTx != nil {
+		idx.observeTx(*row)
+	}
+	return nil
+}
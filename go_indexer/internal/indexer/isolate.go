@@ -0,0 +1,109 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// Default sizing of an isolated processor's pool when the profile's
+// "isolate" option does not override it.
+const (
+	defaultIsolateWorkers = 2
+	defaultIsolateQueue   = 1024
+)
+
+// isolated runs one processor in its own bounded worker pool, decoupled
+// from the block pipeline: Profile.Process enqueues the transaction and
+// moves on, so a slow processor (typically one doing HTTP enrichment)
+// lags behind instead of stalling ingestion. The queue bounds the lag;
+// when it fills, enqueue blocks and backpressure reaches the pipeline
+// again — size it for the burst the processor is expected to absorb.
+// Errors cannot fail the batch anymore, so they are logged instead.
+//
+// A profile opts a processor in through its options:
+//
+//	{"options": {"metadata": {"isolate": {"workers": 4, "queue": 4096}}}}
+type isolated struct {
+	profile *Profile
+	proc    processor.Processor
+	queue   chan isolatedWork
+}
+
+type isolatedWork struct {
+	txCtx   *processor.TxContext
+	publish func(models.Event)
+	observe func(*processor.TxContext, *processor.Output)
+}
+
+// newIsolated creates the pool and starts its workers. Workers live for
+// the process: the queue drains even while ingestion is stopped.
+func newIsolated(p *Profile, proc processor.Processor, workers, queue int) *isolated {
+	iso := &isolated{profile: p, proc: proc, queue: make(chan isolatedWork, queue)}
+	for i := 0; i < workers; i++ {
+		go iso.run()
+	}
+	return iso
+}
+
+// enqueue hands one transaction to the pool, blocking while the queue
+// is full.
+func (iso *isolated) enqueue(txCtx *processor.TxContext, publish func(models.Event), observe func(*processor.TxContext, *processor.Output)) {
+	iso.queue <- isolatedWork{txCtx: txCtx, publish: publish, observe: observe}
+}
+
+func (iso *isolated) run() {
+	for work := range iso.queue {
+		out, err := iso.proc.Process(context.Background(), work.txCtx)
+		if err != nil {
+			slog.Warn("isolated processor failed",
+				"profile", iso.profile.Name, "processor", iso.proc.Name(),
+				"signature", work.txCtx.Signature, "error", err)
+			continue
+		}
+		if err := iso.profile.storeOutput(context.Background(), work.txCtx, &out, work.publish, work.observe); err != nil {
+			slog.Warn("isolated processor output not stored",
+				"profile", iso.profile.Name, "processor", iso.proc.Name(),
+				"signature", work.txCtx.Signature, "error", err)
+		}
+	}
+}
+
+// parseIsolate interprets the reserved "isolate" processor option. It
+// returns the pool sizing and the remaining options to hand to the
+// processor itself.
+func parseIsolate(opts map[string]any) (workers, queue int, rest map[string]any, err error) {
+	raw, ok := opts["isolate"]
+	if !ok {
+		return 0, 0, opts, nil
+	}
+	workers, queue = defaultIsolateWorkers, defaultIsolateQueue
+	settings, ok := raw.(map[string]any)
+	if !ok {
+		return 0, 0, nil, fmt.Errorf(`"isolate" must be an object like {"workers": 4, "queue": 1024}`)
+	}
+	for key, value := range settings {
+		n, ok := value.(float64)
+		if !ok || n < 1 {
+			return 0, 0, nil, fmt.Errorf("isolate.%s must be a positive number", key)
+		}
+		switch key {
+		case "workers":
+			workers = int(n)
+		case "queue":
+			queue = int(n)
+		default:
+			return 0, 0, nil, fmt.Errorf("unknown isolate option %q (known: workers, queue)", key)
+		}
+	}
+	rest = make(map[string]any, len(opts)-1)
+	for key, value := range opts {
+		if key != "isolate" {
+			rest[key] = value
+		}
+	}
+	return workers, queue, rest, nil
+}
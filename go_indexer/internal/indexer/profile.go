@@ -0,0 +1,184 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/enrich"
+	"github.com/lugondev/go-indexer-solana-starter/internal/filter"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/redact"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Profile is one named indexing profile: a transaction filter, a processor
+// chain, and a dedicated store. All profiles share the block stream, so one
+// deployment can index for several applications at once.
+type Profile struct {
+	Name       string
+	programs   map[string]struct{}
+	accounts   map[string]struct{}
+	filter     *filter.Expr
+	processors []processor.Processor
+	// isolated holds the worker pools of processors opted out of the
+	// synchronous chain via the "isolate" option; see isolate.go.
+	isolated map[processor.Processor]*isolated
+	enrich   *enrich.Manager
+	store    storage.Store
+}
+
+// SetEnricher attaches the async enrichment manager: stored events an
+// enricher recognizes are marked pending and completed in the
+// background; see internal/enrich.
+func (p *Profile) SetEnricher(m *enrich.Manager) { p.enrich = m }
+
+// NewProfile builds a Profile from config, resolving processor names
+// through the processor registry. store receives the profile's output and
+// should be namespaced per profile.
+func NewProfile(cfg config.ProfileConfig, store storage.Store) (*Profile, error) {
+	p := &Profile{
+		Name:     cfg.Name,
+		programs: make(map[string]struct{}, len(cfg.Programs)),
+		accounts: make(map[string]struct{}, len(cfg.Accounts)),
+		store:    store,
+	}
+	for _, id := range cfg.Programs {
+		p.programs[id] = struct{}{}
+	}
+	for _, id := range cfg.Accounts {
+		p.accounts[id] = struct{}{}
+	}
+	if cfg.Filter != "" {
+		expr, err := filter.Compile(cfg.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", cfg.Name, err)
+		}
+		p.filter = expr
+	}
+	p.isolated = make(map[processor.Processor]*isolated)
+	for _, name := range cfg.Processors {
+		workers, queue, opts, err := parseIsolate(cfg.Options[name])
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: processor %q: %w", cfg.Name, name, err)
+		}
+		proc, err := processor.NewWithOptions(name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", cfg.Name, err)
+		}
+		if workers > 0 {
+			p.isolated[proc] = newIsolated(p, proc, workers, queue)
+		}
+		p.processors = append(p.processors, proc)
+	}
+	return p, nil
+}
+
+// Store returns the profile's backing store.
+func (p *Profile) Store() storage.Store { return p.store }
+
+// Matches reports whether the profile wants this transaction. A profile
+// with no program and no account filters matches everything.
+func (p *Profile) Matches(tx *solana.TransactionWithMeta) bool {
+	if len(p.programs) == 0 && len(p.accounts) == 0 {
+		return true
+	}
+	msg := &tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if _, ok := p.programs[msg.ProgramID(ix)]; ok {
+			return true
+		}
+	}
+	for _, key := range msg.AccountKeys {
+		if _, ok := p.accounts[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Process runs the transaction through the profile's processor chain and
+// stores the output. publish, when non-nil, receives every stored event;
+// observe, when non-nil, receives each processor's full output after it
+// is stored.
+func (p *Profile) Process(ctx context.Context, slot uint64, blockTime time.Time, signature string, tx *solana.TransactionWithMeta, publish func(models.Event), observe func(*processor.TxContext, *processor.Output)) error {
+	if p.filter != nil {
+		keep, err := p.filter.Eval(filter.TxEnv(slot, signature, tx))
+		if err != nil {
+			return fmt.Errorf("profile %s filter: %w", p.Name, err)
+		}
+		if !keep {
+			return nil
+		}
+	}
+	txCtx := &processor.TxContext{
+		Slot:      slot,
+		BlockTime: blockTime,
+		Signature: signature,
+		Tx:        tx,
+	}
+	for _, proc := range p.processors {
+		if iso := p.isolated[proc]; iso != nil {
+			iso.enqueue(txCtx, publish, observe)
+			continue
+		}
+		out, err := proc.Process(ctx, txCtx)
+		if err != nil {
+			return fmt.Errorf("processor %s: %w", proc.Name(), err)
+		}
+		if err := p.storeOutput(ctx, txCtx, &out, publish, observe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeOutput persists one processor's output and notifies the
+// publish/observe hooks, shared by the synchronous chain and the
+// isolated worker pools.
+func (p *Profile) storeOutput(ctx context.Context, txCtx *processor.TxContext, out *processor.Output, publish func(models.Event), observe func(*processor.TxContext, *processor.Output)) error {
+	if red := redact.Default(); red != nil {
+		for i := range out.Transfers {
+			red.Transfer(&out.Transfers[i])
+		}
+		for i := range out.Events {
+			red.Event(&out.Events[i])
+		}
+	}
+	for i := range out.Transfers {
+		if err := p.store.SaveTransfer(ctx, &out.Transfers[i]); err != nil {
+			return fmt.Errorf("save transfer: %w", err)
+		}
+	}
+	for i := range out.ProgramUpdates {
+		if err := p.store.SaveProgramUpdate(ctx, &out.ProgramUpdates[i]); err != nil {
+			return fmt.Errorf("save program update: %w", err)
+		}
+	}
+	for i := range out.TokenAccountUpdates {
+		if err := p.store.SaveTokenAccountUpdate(ctx, &out.TokenAccountUpdates[i]); err != nil {
+			return fmt.Errorf("save token account update: %w", err)
+		}
+	}
+	for i := range out.Events {
+		if p.enrich != nil {
+			p.enrich.Mark(&out.Events[i])
+		}
+		if err := p.store.SaveEvent(ctx, &out.Events[i]); err != nil {
+			return fmt.Errorf("save event: %w", err)
+		}
+		if publish != nil {
+			publish(out.Events[i])
+		}
+		if p.enrich != nil {
+			p.enrich.Submit(out.Events[i])
+		}
+	}
+	if observe != nil {
+		observe(txCtx, out)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// Backward replay indexes from the chain tip toward older slots, for
+// deployments where recent history matters most: the newest data lands
+// first and coverage extends backward while the live lane follows the
+// head forward. The two frontiers are checkpointed in a custom table,
+// so a restart resumes the replay where it stopped instead of
+// re-anchoring at the new tip and leaving a gap.
+
+// replayTable is the custom table the replay frontiers are persisted
+// in. Progress updates are plain inserts; the newest row wins on reload.
+const replayTable = "replay_frontier"
+
+// ReplayProgress returns the replay lane's snapshot. CurrentSlot is the
+// oldest slot reached; coverage is [CurrentSlot, anchor] going backward.
+func (idx *Indexer) ReplayProgress() LaneSnapshot {
+	return LaneSnapshot{
+		CurrentSlot:    idx.replay.current.Load(),
+		SlotsProcessed: idx.replay.processed.Load(),
+	}
+}
+
+// startReplay launches the backward replay lane when configured.
+func (idx *Indexer) startReplay(ctx context.Context) error {
+	if !idx.cfg.ReplayBackward {
+		return nil
+	}
+	anchor, oldest, err := idx.loadReplayFrontier(ctx)
+	if err != nil {
+		return err
+	}
+	if anchor == 0 {
+		// First run: anchor the replay at the current tip. The live lane
+		// takes over from here forward.
+		if anchor, err = idx.client.GetSlot(ctx); err != nil {
+			return fmt.Errorf("resolve replay anchor: %w", err)
+		}
+		oldest = anchor + 1
+	}
+	go idx.runReplay(ctx, anchor, oldest)
+	return nil
+}
+
+// runReplay walks downward from oldest (the oldest slot already
+// replayed, exclusive) toward the configured end slot, sharing the
+// backfill lane's capacity rules: leftover workers only, pausing while
+// the live lane is behind the head.
+func (idx *Indexer) runReplay(ctx context.Context, anchor, oldest uint64) {
+	end := idx.cfg.ReplayEndSlot
+	concurrency := idx.backfillConcurrency()
+	idx.log.Info("replay lane starting", "anchor", anchor, "oldest", oldest,
+		"end_slot", end, "workers", concurrency)
+
+	for oldest > end {
+		if ctx.Err() != nil {
+			return
+		}
+		if !idx.caughtUp.Load() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idx.cfg.PollInterval):
+			}
+			continue
+		}
+		// Each batch is processed oldest-to-newest internally, but
+		// batches advance newest-to-oldest.
+		from := end
+		if oldest > uint64(idx.cfg.BatchSize) && oldest-uint64(idx.cfg.BatchSize) > end {
+			from = oldest - uint64(idx.cfg.BatchSize)
+		}
+		if err := idx.processRange(ctx, from, oldest-1, concurrency); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			idx.log.Error("replay batch failed", "from", from, "to", oldest-1, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idx.cfg.PollInterval):
+			}
+			continue
+		}
+		idx.replay.processed.Add(oldest - from)
+		if idx.recordCovered != nil {
+			idx.recordCovered("replay", from, oldest-1)
+		}
+		oldest = from
+		idx.replay.current.Store(oldest)
+		if err := idx.saveReplayFrontier(ctx, anchor, oldest); err != nil {
+			idx.log.Error("replay checkpoint failed", "oldest", oldest, "error", err)
+		}
+	}
+	idx.log.Info("replay lane complete", "anchor", anchor, "oldest", oldest)
+}
+
+// loadReplayFrontier returns the persisted anchor and oldest-reached
+// slot; a zero anchor means no replay has been checkpointed yet.
+func (idx *Indexer) loadReplayFrontier(ctx context.Context) (anchor, oldest uint64, err error) {
+	if err := idx.store.EnsureCustomTable(ctx, replayTable); err != nil {
+		return 0, 0, fmt.Errorf("ensure replay table: %w", err)
+	}
+	rows, err := idx.store.ListCustomRows(ctx, replayTable, 0, 0)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("load replay frontier: %w", err)
+	}
+	for _, row := range rows {
+		anchor = asUint64(row["anchor_slot"])
+		oldest = asUint64(row["oldest_slot"])
+	}
+	return anchor, oldest, nil
+}
+
+func (idx *Indexer) saveReplayFrontier(ctx context.Context, anchor, oldest uint64) error {
+	idx.replay.current.Store(oldest)
+	return idx.store.InsertCustomRow(ctx, replayTable, map[string]any{
+		"anchor_slot": anchor,
+		"oldest_slot": oldest,
+		"updated_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// asUint64 reads a numeric custom-row value regardless of how the store
+// round-tripped it.
+func asUint64(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		return uint64(n)
+	case float64:
+		return uint64(n)
+	}
+	return 0
+}
@@ -0,0 +1,142 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// The indexer schedules work in two lanes. The live lane follows the chain
+// head and always gets its configured share of the worker capacity; the
+// backfill lane consumes the leftover capacity and additionally pauses
+// whenever the live lane has fallen behind the head.
+
+// laneProgress tracks one lane's position and slot count.
+type laneProgress struct {
+	current   atomic.Uint64
+	processed atomic.Uint64
+}
+
+// LaneSnapshot is a point-in-time view of one lane's progress.
+type LaneSnapshot struct {
+	CurrentSlot    uint64 `json:"current_slot"`
+	SlotsProcessed uint64 `json:"slots_processed"`
+}
+
+// Progress returns per-lane progress for metrics and status reporting.
+func (idx *Indexer) Progress() (live, backfill LaneSnapshot) {
+	live = LaneSnapshot{
+		CurrentSlot:    idx.live.current.Load(),
+		SlotsProcessed: idx.live.processed.Load(),
+	}
+	backfill = LaneSnapshot{
+		CurrentSlot:    idx.backfill.current.Load(),
+		SlotsProcessed: idx.backfill.processed.Load(),
+	}
+	return live, backfill
+}
+
+// totalConcurrency returns the worker capacity to split across lanes:
+// the autoscaler's current count when enabled, the fixed configuration
+// otherwise.
+func (idx *Indexer) totalConcurrency() int {
+	if idx.scale != nil {
+		return idx.scale.current()
+	}
+	return idx.cfg.MaxConcurrency
+}
+
+// liveConcurrency returns the worker count reserved for the live lane.
+func (idx *Indexer) liveConcurrency() int {
+	total := idx.totalConcurrency()
+	pct := idx.cfg.LiveCapacityPct
+	if pct <= 0 || pct > 100 || idx.cfg.BackfillEndSlot == 0 {
+		return max(1, total)
+	}
+	return max(1, total*pct/100)
+}
+
+// backfillConcurrency returns the worker count left for the backfill lane.
+func (idx *Indexer) backfillConcurrency() int {
+	return max(1, idx.totalConcurrency()-idx.liveConcurrency())
+}
+
+// runBackfill processes the configured historical range in the backfill
+// lane until it completes or ctx is canceled.
+func (idx *Indexer) runBackfill(ctx context.Context) {
+	// Mark the lane's writes so stores can route them through bulk
+	// loading; see storage.WithBackfillLane.
+	ctx = storage.WithBackfillLane(ctx)
+	from, to := idx.cfg.BackfillStartSlot, idx.cfg.BackfillEndSlot
+	concurrency := idx.backfillConcurrency()
+	idx.log.Info("backfill lane starting", "from", from, "to", to, "workers", concurrency)
+
+	current := from
+	for current <= to {
+		if ctx.Err() != nil {
+			return
+		}
+		// Yield all capacity to the live lane while it is behind.
+		if !idx.caughtUp.Load() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idx.cfg.PollInterval):
+			}
+			continue
+		}
+		end := current + uint64(idx.cfg.BatchSize) - 1
+		if end > to {
+			end = to
+		}
+		if err := idx.processRange(ctx, current, end, concurrency); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			idx.log.Error("backfill batch failed", "from", current, "to", end, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idx.cfg.PollInterval):
+			}
+			continue
+		}
+		idx.backfill.current.Store(end)
+		idx.backfill.processed.Add(end - current + 1)
+		if idx.recordCovered != nil {
+			idx.recordCovered("backfill", current, end)
+		}
+		idx.log.Info("backfill progress", "slot", end,
+			"percent", fmt.Sprintf("%.1f", rangeProgress(from, to, end)))
+		current = end + 1
+	}
+	idx.log.Info("backfill lane complete", "from", from, "to", to)
+	if idx.backfillDone != nil {
+		idx.backfillDone()
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+var errBackfillMisconfigured = errors.New("indexer: backfill range misconfigured")
+
+// startBackfill launches the backfill lane when a range is configured.
+func (idx *Indexer) startBackfill(ctx context.Context) error {
+	if idx.cfg.BackfillEndSlot == 0 {
+		return nil
+	}
+	if idx.cfg.BackfillEndSlot < idx.cfg.BackfillStartSlot {
+		return errBackfillMisconfigured
+	}
+	go idx.runBackfill(ctx)
+	return nil
+}
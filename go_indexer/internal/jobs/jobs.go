@@ -0,0 +1,247 @@
+// Package jobs runs long-lived tasks — backfills, exports, verification
+// sweeps — as tracked jobs with states, progress percentage, and
+// cancellation, submitted and monitored through the API. Runners are
+// registered per job kind; the manager bounds how many run at once and
+// keeps the finished records for inspection.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job states.
+const (
+	StateQueued   = "queued"
+	StateRunning  = "running"
+	StateDone     = "done"
+	StateFailed   = "failed"
+	StateCanceled = "canceled"
+)
+
+// ErrNotFound is returned for unknown job IDs.
+var ErrNotFound = errors.New("jobs: not found")
+
+// Job is the record of one submitted task.
+type Job struct {
+	ID         string         `json:"id"`
+	Kind       string         `json:"kind"`
+	Params     map[string]any `json:"params,omitempty"`
+	State      string         `json:"state"`
+	Progress   float64        `json:"progress"` // 0-100
+	Error      string         `json:"error,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	StartedAt  time.Time      `json:"started_at,omitempty"`
+	FinishedAt time.Time      `json:"finished_at,omitempty"`
+}
+
+// Context is handed to a runner; it carries the parameters and lets the
+// runner report progress.
+type Context struct {
+	Params  map[string]any
+	manager *Manager
+	id      string
+}
+
+// SetProgress records the job's completion percentage (clamped 0-100).
+func (c *Context) SetProgress(pct float64) {
+	if pct < 0 {
+		pct = 0
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	c.manager.update(c.id, func(job *tracked) { job.record.Progress = pct })
+}
+
+// SetResult records an output value (e.g. an export file path) on the
+// job's parameter map under the manager lock.
+func (c *Context) SetResult(key string, value any) {
+	c.manager.update(c.id, func(job *tracked) {
+		if job.record.Params == nil {
+			job.record.Params = make(map[string]any)
+		}
+		job.record.Params[key] = value
+	})
+}
+
+// Runner executes one kind of job. A runner must return promptly when
+// ctx is canceled; the job is then recorded as canceled.
+type Runner func(ctx context.Context, job *Context) error
+
+type tracked struct {
+	record Job
+	cancel context.CancelFunc
+}
+
+// Manager tracks jobs and runs them through registered runners.
+type Manager struct {
+	log     *slog.Logger
+	sem     chan struct{}
+	mu      sync.Mutex
+	runners map[string]Runner
+	jobs    map[string]*tracked
+}
+
+// NewManager creates a manager running at most maxConcurrent jobs.
+func NewManager(maxConcurrent int, log *slog.Logger) *Manager {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Manager{
+		log:     log,
+		sem:     make(chan struct{}, maxConcurrent),
+		runners: make(map[string]Runner),
+		jobs:    make(map[string]*tracked),
+	}
+}
+
+// Register makes a job kind submittable. It panics on duplicate kinds
+// and is intended for wiring at startup.
+func (m *Manager) Register(kind string, r Runner) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.runners[kind]; ok {
+		panic(fmt.Sprintf("jobs: duplicate registration of %q", kind))
+	}
+	m.runners[kind] = r
+}
+
+// Kinds lists the registered job kinds.
+func (m *Manager) Kinds() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kinds := make([]string, 0, len(m.runners))
+	for kind := range m.runners {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Submit queues a job and returns its record. The job runs as soon as a
+// concurrency slot frees up; ctx bounds the job's whole lifetime and is
+// normally the process context.
+func (m *Manager) Submit(ctx context.Context, kind string, params map[string]any) (Job, error) {
+	m.mu.Lock()
+	runner, ok := m.runners[kind]
+	if !ok {
+		m.mu.Unlock()
+		return Job{}, fmt.Errorf("jobs: unknown kind %q", kind)
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	t := &tracked{
+		record: Job{
+			ID:        newID(),
+			Kind:      kind,
+			Params:    params,
+			State:     StateQueued,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	m.jobs[t.record.ID] = t
+	record := t.record
+	m.mu.Unlock()
+
+	go m.run(jobCtx, t.record.ID, runner, params)
+	return record, nil
+}
+
+func (m *Manager) run(ctx context.Context, id string, runner Runner, params map[string]any) {
+	select {
+	case m.sem <- struct{}{}:
+		defer func() { <-m.sem }()
+	case <-ctx.Done():
+		m.update(id, func(job *tracked) {
+			job.record.State = StateCanceled
+			job.record.FinishedAt = time.Now().UTC()
+		})
+		return
+	}
+	m.update(id, func(job *tracked) {
+		job.record.State = StateRunning
+		job.record.StartedAt = time.Now().UTC()
+	})
+
+	err := runner(ctx, &Context{Params: params, manager: m, id: id})
+	m.update(id, func(job *tracked) {
+		job.record.FinishedAt = time.Now().UTC()
+		switch {
+		case ctx.Err() != nil:
+			job.record.State = StateCanceled
+		case err != nil:
+			job.record.State = StateFailed
+			job.record.Error = err.Error()
+		default:
+			job.record.State = StateDone
+			job.record.Progress = 100
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		m.log.Error("job failed", "id", id, "error", err)
+	}
+}
+
+// Get returns one job record.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return t.record, nil
+}
+
+// List returns job records, newest first.
+func (m *Manager) List(limit int) []Job {
+	if limit <= 0 {
+		limit = 100
+	}
+	m.mu.Lock()
+	out := make([]Job, 0, len(m.jobs))
+	for _, t := range m.jobs {
+		out = append(out, t.record)
+	}
+	m.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// Cancel stops a queued or running job.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	t, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return ErrNotFound
+	}
+	t.cancel()
+	return nil
+}
+
+// update applies fn to a job record under the manager lock.
+func (m *Manager) update(id string, fn func(*tracked)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.jobs[id]; ok {
+		fn(t)
+	}
+}
+
+func newID() string {
+	var buf [8]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,192 @@
+// Package latency quantifies indexing freshness: the delay between a
+// block's on-chain blockTime (and the moment its payload arrived from
+// RPC) and the moment its transactions were committed and published.
+// The tracker keeps a rolling window of observations, serves p50, p90,
+// and p99 for /metrics, and derives an SLO burn rate — how fast the
+// unfresh fraction is consuming the error budget the objective leaves —
+// that the health monitor alerts on. Together they put a number on
+// "how far behind the chain are we", per transaction rather than per
+// slot, so stakeholder freshness questions have a measured answer.
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// sampleKeep bounds the retained block observations.
+	sampleKeep = 4096
+	// sampleWindow is how far back the percentile and burn-rate
+	// figures look.
+	sampleWindow = time.Hour
+	// minTransactions is how many transactions the window must hold
+	// before the burn rate is considered meaningful.
+	minTransactions = 100
+)
+
+// sample is one committed block, weighted by its transaction count so
+// the figures are per transaction.
+type sample struct {
+	at           time.Time
+	blockSec     float64 // commit minus blockTime
+	receiptSec   float64 // commit minus RPC receipt, negative when unknown
+	transactions int
+}
+
+// Quantiles are latency percentiles in seconds.
+type Quantiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// Snapshot is the tracker state one /metrics scrape renders.
+type Snapshot struct {
+	// BlockTime measures commit time minus blockTime; Receipt measures
+	// commit time minus RPC receipt, isolating indexing cost from chain
+	// and RPC propagation. HasReceipt is false during replay, which
+	// has no receipt moment.
+	BlockTime  Quantiles `json:"block_time"`
+	Receipt    Quantiles `json:"receipt"`
+	HasReceipt bool      `json:"has_receipt"`
+	// Transactions in the window, and how many of them were committed
+	// within the SLO target of their blockTime.
+	Transactions uint64 `json:"transactions"`
+	Fresh        uint64 `json:"fresh"`
+	// BurnRate is the unfresh fraction divided by the error budget:
+	// above 1 the objective is being missed faster than it allows.
+	BurnRate float64 `json:"burn_rate"`
+	Target   float64 `json:"target_seconds"`
+}
+
+// Tracker accumulates latency observations from the indexer. It is
+// safe for concurrent use.
+type Tracker struct {
+	target    time.Duration
+	objective float64
+
+	mu      sync.Mutex
+	samples []sample
+}
+
+// NewTracker creates a tracker for an SLO of the form "a fraction
+// objective of transactions commit within target of their blockTime".
+// Zero values select 30 seconds and 0.99.
+func NewTracker(target time.Duration, objective float64) *Tracker {
+	if target <= 0 {
+		target = 30 * time.Second
+	}
+	if objective <= 0 || objective >= 1 {
+		objective = 0.99
+	}
+	return &Tracker{target: target, objective: objective}
+}
+
+// Observe records one committed block. received is when the block
+// payload arrived from RPC, zero when unknown (replay). The indexer
+// calls this after the block's transactions are stored and published.
+// Blocks already older than the sample window when committed — the
+// backfill and repair lanes' work — are excluded: the SLO quantifies
+// live freshness, and a backfilled month of history would otherwise
+// read as an outage.
+func (t *Tracker) Observe(blockTime, received time.Time, transactions int) {
+	if blockTime.IsZero() || transactions <= 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(blockTime) > sampleWindow {
+		return
+	}
+	s := sample{
+		at:           now,
+		blockSec:     now.Sub(blockTime).Seconds(),
+		receiptSec:   -1,
+		transactions: transactions,
+	}
+	if !received.IsZero() {
+		s.receiptSec = now.Sub(received).Seconds()
+	}
+	t.mu.Lock()
+	t.samples = append(t.samples, s)
+	if len(t.samples) > sampleKeep {
+		t.samples = append(t.samples[:0], t.samples[len(t.samples)-sampleKeep:]...)
+	}
+	t.mu.Unlock()
+}
+
+// Snapshot computes the current window's figures.
+func (t *Tracker) Snapshot() Snapshot {
+	kept := t.window()
+	out := Snapshot{Target: t.target.Seconds()}
+	var block, receipt []sample
+	for _, s := range kept {
+		out.Transactions += uint64(s.transactions)
+		if s.blockSec <= t.target.Seconds() {
+			out.Fresh += uint64(s.transactions)
+		}
+		block = append(block, s)
+		if s.receiptSec >= 0 {
+			receipt = append(receipt, s)
+		}
+	}
+	out.BlockTime = quantiles(block, func(s sample) float64 { return s.blockSec })
+	if len(receipt) > 0 {
+		out.HasReceipt = true
+		out.Receipt = quantiles(receipt, func(s sample) float64 { return s.receiptSec })
+	}
+	if out.Transactions > 0 {
+		bad := float64(out.Transactions-out.Fresh) / float64(out.Transactions)
+		out.BurnRate = bad / (1 - t.objective)
+	}
+	return out
+}
+
+// BurnStatus reports the current burn rate for the health monitor,
+// false until the window holds enough transactions to mean anything.
+func (t *Tracker) BurnStatus() (float64, bool) {
+	snap := t.Snapshot()
+	return snap.BurnRate, snap.Transactions >= minTransactions
+}
+
+// window returns the samples still inside sampleWindow, pruning the
+// rest. Samples are appended in time order, so the cut is a prefix.
+func (t *Tracker) window() []sample {
+	cutoff := time.Now().Add(-sampleWindow)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	start := 0
+	for start < len(t.samples) && t.samples[start].at.Before(cutoff) {
+		start++
+	}
+	if start > 0 {
+		t.samples = append(t.samples[:0], t.samples[start:]...)
+	}
+	return append([]sample(nil), t.samples...)
+}
+
+// quantiles computes transaction-weighted percentiles of one latency
+// dimension.
+func quantiles(samples []sample, value func(sample) float64) Quantiles {
+	if len(samples) == 0 {
+		return Quantiles{}
+	}
+	sort.Slice(samples, func(i, j int) bool { return value(samples[i]) < value(samples[j]) })
+	total := 0
+	for _, s := range samples {
+		total += s.transactions
+	}
+	at := func(q float64) float64 {
+		rank := q * float64(total)
+		seen := 0
+		for _, s := range samples {
+			seen += s.transactions
+			if float64(seen) >= rank {
+				return value(s)
+			}
+		}
+		return value(samples[len(samples)-1])
+	}
+	return Quantiles{P50: at(0.50), P90: at(0.90), P99: at(0.99)}
+}
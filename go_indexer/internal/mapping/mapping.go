@@ -0,0 +1,234 @@
+// Package mapping populates user-defined tables from decoded events
+// without any Go code. A YAML schema file declares tables, a source
+// filter, and columns that pull fields out of the event (with type
+// conversion); the mapper inserts one row per matching event and the API
+// serves the rows back at /api/v1/tables/{name}.
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// TableSpec declares one custom table.
+type TableSpec struct {
+	Name    string       `json:"name"`
+	Source  string       `json:"source"` // only "event" is supported today
+	Filter  FilterSpec   `json:"filter"`
+	Columns []ColumnSpec `json:"columns"`
+}
+
+// FilterSpec narrows which events feed the table; empty fields match all.
+type FilterSpec struct {
+	Program string `json:"program"`
+	Type    string `json:"type"`
+}
+
+// ColumnSpec maps one event field to a column. From is a dotted path into
+// the event (signature, slot, program, type, block_time, or data.<key>);
+// Type converts the value (string, uint64, int64, float64, bool) and
+// Scale, when non-zero, divides numeric values — e.g. 1e9 for lamports
+// to SOL.
+type ColumnSpec struct {
+	Name  string  `json:"name"`
+	From  string  `json:"from"`
+	Type  string  `json:"type"`
+	Scale float64 `json:"scale"`
+}
+
+// LoadSchema reads and validates a YAML schema file.
+func LoadSchema(path string) ([]TableSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tables file: %w", err)
+	}
+	tree, err := parseYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse tables file %s: %w", path, err)
+	}
+	// The parsed tree is plain maps and slices, so encoding/json bridges
+	// it onto the spec structs.
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("tables file %s: %w", path, err)
+	}
+	var doc struct {
+		Tables []TableSpec `json:"tables"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("tables file %s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(doc.Tables))
+	for i := range doc.Tables {
+		t := &doc.Tables[i]
+		if t.Name == "" {
+			return nil, fmt.Errorf("tables file %s: table without name", path)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("tables file %s: duplicate table %q", path, t.Name)
+		}
+		seen[t.Name] = true
+		if t.Source == "" {
+			t.Source = "event"
+		}
+		if t.Source != "event" {
+			return nil, fmt.Errorf("tables file %s: table %q: unsupported source %q", path, t.Name, t.Source)
+		}
+		if len(t.Columns) == 0 {
+			return nil, fmt.Errorf("tables file %s: table %q has no columns", path, t.Name)
+		}
+		for _, col := range t.Columns {
+			if col.Name == "" || col.From == "" {
+				return nil, fmt.Errorf("tables file %s: table %q: column needs name and from", path, t.Name)
+			}
+		}
+	}
+	return doc.Tables, nil
+}
+
+// Mapper inserts rows into the declared tables as events are indexed.
+type Mapper struct {
+	store  storage.Store
+	tables []TableSpec
+	log    *slog.Logger
+}
+
+// New creates a Mapper over the given table specs, creating each table
+// in the store.
+func New(ctx context.Context, store storage.Store, tables []TableSpec, log *slog.Logger) (*Mapper, error) {
+	for _, t := range tables {
+		if err := store.EnsureCustomTable(ctx, t.Name); err != nil {
+			return nil, fmt.Errorf("create table %s: %w", t.Name, err)
+		}
+	}
+	return &Mapper{store: store, tables: tables, log: log}, nil
+}
+
+// Tables returns the declared table names.
+func (m *Mapper) Tables() []string {
+	names := make([]string, 0, len(m.tables))
+	for _, t := range m.tables {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// HandleEvent inserts one row per table whose filter matches the event.
+// It sits on the indexer's publish path, before outbox delivery.
+func (m *Mapper) HandleEvent(ev models.Event) {
+	for _, t := range m.tables {
+		if t.Filter.Program != "" && ev.Program != t.Filter.Program {
+			continue
+		}
+		if t.Filter.Type != "" && ev.Type != t.Filter.Type {
+			continue
+		}
+		row := make(map[string]any, len(t.Columns))
+		for _, col := range t.Columns {
+			value, err := convertColumn(col, lookupField(ev, col.From))
+			if err != nil {
+				m.log.Warn("table mapping conversion failed",
+					"table", t.Name, "column", col.Name, "error", err)
+				value = nil
+			}
+			row[col.Name] = value
+		}
+		if err := m.store.InsertCustomRow(context.Background(), t.Name, row); err != nil {
+			m.log.Error("table mapping insert failed", "table", t.Name, "error", err)
+		}
+	}
+}
+
+// lookupField resolves a dotted path against the event.
+func lookupField(ev models.Event, path string) any {
+	head, rest, _ := strings.Cut(path, ".")
+	switch head {
+	case "signature":
+		return ev.Signature
+	case "slot":
+		return ev.Slot
+	case "block_time":
+		return ev.BlockTime
+	case "program":
+		return ev.Program
+	case "type":
+		return ev.Type
+	case "data":
+		var value any = ev.Data
+		for rest != "" {
+			var key string
+			key, rest, _ = strings.Cut(rest, ".")
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			value = m[key]
+		}
+		return value
+	default:
+		return nil
+	}
+}
+
+// convertColumn applies the column's type conversion and scale.
+func convertColumn(col ColumnSpec, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch col.Type {
+	case "", "string":
+		if col.Type == "" {
+			return value, nil
+		}
+		return fmt.Sprintf("%v", value), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert %T to bool", value)
+		}
+		return b, nil
+	case "uint64", "int64", "float64":
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		if col.Scale != 0 {
+			f /= col.Scale
+		}
+		switch col.Type {
+		case "uint64":
+			return uint64(f), nil
+		case "int64":
+			return int64(f), nil
+		default:
+			return f, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported column type %q", col.Type)
+	}
+}
+
+func toFloat(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to number", value)
+	}
+}
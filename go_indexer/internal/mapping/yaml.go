@@ -0,0 +1,202 @@
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML decodes the YAML subset used by schema files: nested mappings
+// and lists with two-space indentation, scalar values (strings, numbers,
+// booleans), quoted strings, and # comments. Anchors, multi-line scalars,
+// and flow syntax are not supported; keeping the parser small beats
+// pulling in a YAML dependency for config files this simple.
+func parseYAML(data []byte) (any, error) {
+	var lines []yamlLine
+	for n, raw := range strings.Split(string(data), "\n") {
+		text := stripComment(raw)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if strings.Contains(text, "\t") {
+			return nil, fmt.Errorf("yaml line %d: tabs are not allowed for indentation", n+1)
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		lines = append(lines, yamlLine{num: n + 1, indent: indent, text: strings.TrimLeft(text, " ")})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	value, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next != len(lines) {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", lines[next].num)
+	}
+	return value, nil
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAMLBlock parses the mapping or list starting at lines[i], which
+// all share the given indent, and returns the next unconsumed index.
+func parseYAMLBlock(lines []yamlLine, i, indent int) (any, int, error) {
+	if strings.HasPrefix(lines[i].text, "- ") || lines[i].text == "-" {
+		return parseYAMLList(lines, i, indent)
+	}
+	return parseYAMLMapping(lines, i, indent)
+}
+
+func parseYAMLList(lines []yamlLine, i, indent int) (any, int, error) {
+	var out []any
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		if !strings.HasPrefix(line.text, "- ") && line.text != "-" {
+			return nil, 0, fmt.Errorf("yaml line %d: expected list item", line.num)
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if rest == "" {
+			// Item body is the nested block on the following lines.
+			i++
+			if i >= len(lines) || lines[i].indent <= indent {
+				out = append(out, nil)
+				continue
+			}
+			value, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			out = append(out, value)
+			i = next
+			continue
+		}
+		if key, val, isMapping := splitYAMLKey(rest); isMapping {
+			// Inline first entry of a mapping item: re-parse the rest of
+			// the line as if it were indented under the dash.
+			item := map[string]any{}
+			var err error
+			if i, err = parseYAMLEntry(item, lines, i, indent+2, key, val, line.num); err != nil {
+				return nil, 0, err
+			}
+			for i < len(lines) && lines[i].indent == indent+2 {
+				entry := lines[i]
+				key, val, ok := splitYAMLKey(entry.text)
+				if !ok {
+					return nil, 0, fmt.Errorf("yaml line %d: expected key: value", entry.num)
+				}
+				if i, err = parseYAMLEntry(item, lines, i, indent+2, key, val, entry.num); err != nil {
+					return nil, 0, err
+				}
+			}
+			out = append(out, item)
+			continue
+		}
+		out = append(out, yamlScalar(rest))
+		i++
+	}
+	return out, i, nil
+}
+
+func parseYAMLMapping(lines []yamlLine, i, indent int) (any, int, error) {
+	out := map[string]any{}
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		key, val, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, 0, fmt.Errorf("yaml line %d: expected key: value", line.num)
+		}
+		var err error
+		if i, err = parseYAMLEntry(out, lines, i, indent, key, val, line.num); err != nil {
+			return nil, 0, err
+		}
+	}
+	return out, i, nil
+}
+
+// parseYAMLEntry stores one key (with inline value or nested block) into
+// the mapping and returns the next unconsumed line index.
+func parseYAMLEntry(into map[string]any, lines []yamlLine, i, indent int, key, val string, num int) (int, error) {
+	if _, dup := into[key]; dup {
+		return 0, fmt.Errorf("yaml line %d: duplicate key %q", num, key)
+	}
+	if val != "" {
+		into[key] = yamlScalar(val)
+		return i + 1, nil
+	}
+	i++
+	if i >= len(lines) || lines[i].indent <= indent {
+		into[key] = nil
+		return i, nil
+	}
+	value, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+	if err != nil {
+		return 0, err
+	}
+	into[key] = value
+	return next, nil
+}
+
+// splitYAMLKey splits "key: value" (value may be empty), reporting false
+// for scalar lines without a key.
+func splitYAMLKey(text string) (key, value string, ok bool) {
+	if strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		return "", "", false
+	}
+	idx := strings.Index(text, ":")
+	if idx < 0 {
+		return "", "", false
+	}
+	rest := text[idx+1:]
+	if rest != "" && !strings.HasPrefix(rest, " ") {
+		return "", "", false
+	}
+	return strings.TrimSpace(text[:idx]), strings.TrimSpace(rest), true
+}
+
+// yamlScalar converts a scalar token to bool, number, or string.
+func yamlScalar(token string) any {
+	if len(token) >= 2 {
+		if (token[0] == '"' && token[len(token)-1] == '"') || (token[0] == '\'' && token[len(token)-1] == '\'') {
+			return token[1 : len(token)-1]
+		}
+	}
+	switch token {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null", "~":
+		return nil
+	}
+	if n, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f
+	}
+	return token
+}
+
+// stripComment removes a trailing # comment, respecting quoted strings.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
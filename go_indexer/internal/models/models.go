@@ -0,0 +1,155 @@
+// Package models defines the data types the indexer stores and serves.
+package models
+
+import "time"
+
+// Block is a summary of an indexed block.
+type Block struct {
+	Slot       uint64 `json:"slot"`
+	BlockHash  string `json:"block_hash"`
+	ParentSlot uint64 `json:"parent_slot"`
+	// BlockTime is the derived timestamp used by time-range queries;
+	// RawBlockTime is the validator-reported value (zero when the RPC
+	// returned null) and TimeSource says how BlockTime was obtained.
+	BlockTime         time.Time `json:"block_time"`
+	RawBlockTime      time.Time `json:"raw_block_time,omitempty"`
+	TimeSource        string    `json:"time_source,omitempty"`
+	TransactionsCount int       `json:"transactions_count"`
+	IndexedAt         time.Time `json:"indexed_at"`
+	// Orphaned is set when a later fork abandoned this block; the reorg
+	// audit log records when and by what it was replaced.
+	Orphaned bool `json:"orphaned,omitempty"`
+	// Partial is set when some transactions survived every encoding
+	// fallback undecoded; FailedTransactions lists their signatures for
+	// later repair.
+	Partial            bool     `json:"partial,omitempty"`
+	FailedTransactions []string `json:"failed_transactions,omitempty"`
+}
+
+// Transaction is an indexed transaction.
+type Transaction struct {
+	Signature string `json:"signature"`
+	// Signer is the fee payer (the first account key); Signers lists
+	// every signing account, fee payer first, so role-scoped address
+	// queries do not re-derive them from the account key ordering.
+	Signer    string    `json:"signer,omitempty"`
+	Signers   []string  `json:"signers,omitempty"`
+	Slot      uint64    `json:"slot"`
+	BlockTime time.Time `json:"block_time"`
+	Fee       uint64    `json:"fee"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	IndexedAt time.Time `json:"indexed_at"`
+	// ProgramIDs lists the programs the transaction invoked, populated
+	// in signatures-only mode where the instruction payloads themselves
+	// are not kept; see config.SignaturesOnly.
+	ProgramIDs []string `json:"program_ids,omitempty"`
+
+	// Durable-nonce transactions are flagged because their ordering and
+	// replay characteristics differ from blockhash-based transactions.
+	DurableNonce bool   `json:"durable_nonce,omitempty"`
+	NonceAccount string `json:"nonce_account,omitempty"`
+}
+
+// NonceAccount tracks observed durable nonce account usage.
+type NonceAccount struct {
+	Address       string `json:"address"`
+	Authority     string `json:"authority,omitempty"`
+	LastUsedSlot  uint64 `json:"last_used_slot"`
+	LastSignature string `json:"last_signature,omitempty"`
+	UseCount      uint64 `json:"use_count"`
+}
+
+// Transfer is a single SOL or SPL token transfer extracted from a transaction.
+// Mint is empty for native SOL transfers.
+type Transfer struct {
+	Signature   string    `json:"signature"`
+	Slot        uint64    `json:"slot"`
+	BlockTime   time.Time `json:"block_time"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Mint        string    `json:"mint,omitempty"`
+	Amount      uint64    `json:"amount"`
+	Decimals    uint8     `json:"decimals"`
+}
+
+// ProgramUpdate is one observed BPF Loader Upgradeable action affecting a
+// deployed program. Kind is one of deployed, upgraded, set_authority, or
+// closed.
+type ProgramUpdate struct {
+	ProgramID   string    `json:"program_id"`
+	ProgramData string    `json:"program_data,omitempty"`
+	Authority   string    `json:"authority,omitempty"`
+	Kind        string    `json:"kind"`
+	Slot        uint64    `json:"slot"`
+	Signature   string    `json:"signature"`
+	BlockTime   time.Time `json:"block_time"`
+}
+
+// Program is the registry entry for a deployed program, built by folding
+// ProgramUpdates in slot order.
+type Program struct {
+	ProgramID          string          `json:"program_id"`
+	ProgramDataAccount string          `json:"program_data_account,omitempty"`
+	UpgradeAuthority   string          `json:"upgrade_authority,omitempty"`
+	Closed             bool            `json:"closed"`
+	LastUpdatedSlot    uint64          `json:"last_updated_slot"`
+	History            []ProgramUpdate `json:"history"`
+}
+
+// TokenAccountUpdate is one observed SPL token account lifecycle action.
+// Kind is one of initialized, owner_changed, frozen, thawed, or closed.
+type TokenAccountUpdate struct {
+	Address   string    `json:"address"`
+	Mint      string    `json:"mint,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	Kind      string    `json:"kind"`
+	Slot      uint64    `json:"slot"`
+	Signature string    `json:"signature"`
+	BlockTime time.Time `json:"block_time"`
+}
+
+// TokenAccount is the dimension-table row resolving a token account to its
+// mint, wallet owner, and current state (initialized, frozen, or closed).
+type TokenAccount struct {
+	Address     string `json:"address"`
+	Mint        string `json:"mint,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	State       string `json:"state"`
+	CreatedSlot uint64 `json:"created_slot,omitempty"`
+	UpdatedSlot uint64 `json:"updated_slot"`
+}
+
+// Event is a decoded program event: either an Anchor event decoded from
+// transaction logs or a line parsed by a log-based decoder.
+type Event struct {
+	Signature string         `json:"signature"`
+	Slot      uint64         `json:"slot"`
+	BlockTime time.Time      `json:"block_time"`
+	Program   string         `json:"program"`
+	Type      string         `json:"type"`
+	Data      map[string]any `json:"data,omitempty"`
+	// Enrichment marks two-phase records: "pending" rows are awaiting
+	// late fields that land via a follow-up record_updated event, and
+	// "complete" marks the follow-up carrying them. Empty means the
+	// record is not subject to enrichment; see internal/enrich.
+	Enrichment string `json:"enrichment,omitempty"`
+}
+
+// Reorg is one detected chain reorganization, kept as an audit log so
+// consumers can check whether data they acted on was later orphaned.
+type Reorg struct {
+	ForkSlot          uint64    `json:"fork_slot"` // last slot both forks agree on
+	AbandonedBlocks   []string  `json:"abandoned_blocks"`
+	ReplacementBlocks []string  `json:"replacement_blocks"`
+	DetectedAt        time.Time `json:"detected_at"`
+}
+
+// OutboxEntry is one event recorded in the transactional outbox, waiting
+// for the dispatcher to deliver it to the push sinks. Cursor is assigned
+// by the store and increases monotonically.
+type OutboxEntry struct {
+	Cursor    uint64    `json:"cursor"`
+	Event     Event     `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,216 @@
+// Package notify delivers alert and operational notifications to chat
+// channels. Slack, Discord, and Telegram are supported; every channel
+// renders messages through an optional template and is rate limited
+// independently so a noisy rule cannot flood a channel.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ErrRateLimited is returned by Send when the channel's rate limit is
+// exceeded; the message is dropped, not queued.
+var ErrRateLimited = errors.New("notify: channel rate limited")
+
+// Message is one notification. Fields carry structured context appended
+// after the text (or available to a custom template as {{.Fields}}).
+type Message struct {
+	Title  string
+	Text   string
+	Fields map[string]any
+}
+
+// Notifier delivers messages to one channel.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// channel holds the state shared by all notifier implementations.
+type channel struct {
+	name  string
+	httpc *http.Client
+	tmpl  *template.Template
+	limit *limiter
+}
+
+// limiter enforces a minimum interval between messages on one channel.
+type limiter struct {
+	mu       sync.Mutex
+	lastSend time.Time
+	interval time.Duration
+}
+
+func (l *limiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastSend) < l.interval {
+		return false
+	}
+	l.lastSend = now
+	return true
+}
+
+func newChannel(name, tmplText string, interval time.Duration) (channel, error) {
+	ch := channel{
+		name:  name,
+		httpc: &http.Client{Timeout: 10 * time.Second},
+	}
+	if interval > 0 {
+		ch.limit = &limiter{interval: interval}
+	}
+	if tmplText != "" {
+		tmpl, err := template.New(name).Parse(tmplText)
+		if err != nil {
+			return channel{}, fmt.Errorf("notify: parse %s template: %w", name, err)
+		}
+		ch.tmpl = tmpl
+	}
+	return ch, nil
+}
+
+func (c *channel) Name() string { return c.name }
+
+// allow applies the per-channel rate limit.
+func (c *channel) allow() bool {
+	return c.limit == nil || c.limit.allow()
+}
+
+// render produces the message body, via the custom template when one is
+// configured and a plain title/text/fields layout otherwise.
+func (c *channel) render(msg Message) string {
+	if c.tmpl != nil {
+		var buf bytes.Buffer
+		if err := c.tmpl.Execute(&buf, msg); err == nil {
+			return buf.String()
+		}
+	}
+	var b strings.Builder
+	if msg.Title != "" {
+		b.WriteString(msg.Title)
+		if msg.Text != "" {
+			b.WriteString(": ")
+		}
+	}
+	b.WriteString(msg.Text)
+	keys := make([]string, 0, len(msg.Fields))
+	for k := range msg.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s=%v", k, msg.Fields[k])
+	}
+	return b.String()
+}
+
+func (c *channel) postJSON(ctx context.Context, endpoint string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: %s returned %d", c.name, resp.StatusCode)
+	}
+	return nil
+}
+
+// Slack posts to a Slack incoming webhook.
+type Slack struct {
+	channel
+	webhookURL string
+}
+
+// NewSlack creates a Slack notifier. tmplText may be empty for the
+// default layout; interval is the minimum time between messages.
+func NewSlack(webhookURL, tmplText string, interval time.Duration) (*Slack, error) {
+	ch, err := newChannel("slack", tmplText, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &Slack{channel: ch, webhookURL: webhookURL}, nil
+}
+
+func (s *Slack) Send(ctx context.Context, msg Message) error {
+	if !s.allow() {
+		return ErrRateLimited
+	}
+	return s.postJSON(ctx, s.webhookURL, map[string]any{"text": s.render(msg)})
+}
+
+// Discord posts to a Discord webhook.
+type Discord struct {
+	channel
+	webhookURL string
+}
+
+// NewDiscord creates a Discord notifier.
+func NewDiscord(webhookURL, tmplText string, interval time.Duration) (*Discord, error) {
+	ch, err := newChannel("discord", tmplText, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &Discord{channel: ch, webhookURL: webhookURL}, nil
+}
+
+func (d *Discord) Send(ctx context.Context, msg Message) error {
+	if !d.allow() {
+		return ErrRateLimited
+	}
+	return d.postJSON(ctx, d.webhookURL, map[string]any{"content": d.render(msg)})
+}
+
+// Telegram sends through the Bot API sendMessage method.
+type Telegram struct {
+	channel
+	botToken string
+	chatID   string
+	baseURL  string
+}
+
+// NewTelegram creates a Telegram notifier for the given bot and chat.
+func NewTelegram(botToken, chatID, tmplText string, interval time.Duration) (*Telegram, error) {
+	ch, err := newChannel("telegram", tmplText, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &Telegram{
+		channel:  ch,
+		botToken: botToken,
+		chatID:   chatID,
+		baseURL:  "https://api.telegram.org",
+	}, nil
+}
+
+func (t *Telegram) Send(ctx context.Context, msg Message) error {
+	if !t.allow() {
+		return ErrRateLimited
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", t.baseURL, url.PathEscape(t.botToken))
+	return t.postJSON(ctx, endpoint, map[string]any{
+		"chat_id": t.chatID,
+		"text":    t.render(msg),
+	})
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// opsEventTypes are the operational event types forwarded to every
+// configured channel, independent of any alert rule.
+var opsEventTypes = map[string]bool{
+	"indexer_stalled":     true,
+	"reorg_detected":      true,
+	"lag_above_threshold": true,
+	"error_rate_spike":    true,
+	"store_unreachable":   true,
+}
+
+// Router fans notifications out to named channels. Alert rules address
+// channels by name; operational events are broadcast to all of them.
+type Router struct {
+	log      *slog.Logger
+	channels map[string]Notifier
+}
+
+// NewRouter creates an empty Router.
+func NewRouter(log *slog.Logger) *Router {
+	return &Router{log: log, channels: make(map[string]Notifier)}
+}
+
+// Add registers a channel under its own name.
+func (r *Router) Add(n Notifier) { r.channels[n.Name()] = n }
+
+// Len returns the number of registered channels.
+func (r *Router) Len() int { return len(r.channels) }
+
+// Channels returns the registered channel names sorted alphabetically.
+func (r *Router) Channels() []string {
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Notify sends a message to one named channel. Unknown channels are
+// logged and skipped so a stale rule cannot break alert delivery.
+func (r *Router) Notify(name string, msg Message) {
+	ch, ok := r.channels[name]
+	if !ok {
+		r.log.Warn("unknown notification channel", "channel", name)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ch.Send(ctx, msg); err != nil && !errors.Is(err, ErrRateLimited) {
+		r.log.Warn("notification delivery failed", "channel", name, "error", err)
+	}
+}
+
+// HandleEvent forwards operational events to every channel. It is wired
+// into the indexer's event publish path.
+func (r *Router) HandleEvent(ev models.Event) {
+	if !opsEventTypes[ev.Type] {
+		return
+	}
+	msg := Message{
+		Title:  ev.Type,
+		Fields: map[string]any{"slot": ev.Slot},
+	}
+	for k, v := range ev.Data {
+		msg.Fields[k] = v
+	}
+	for _, name := range r.Channels() {
+		r.Notify(name, msg)
+	}
+}
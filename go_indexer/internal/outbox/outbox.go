@@ -0,0 +1,108 @@
+// Package outbox implements the transactional outbox pattern for event
+// delivery. Producers append events to an outbox table in the store,
+// atomically with the indexed data; the Dispatcher drains the table in
+// cursor order, hands each event to the push sinks, and advances the
+// delivered cursor. A crash between write and delivery replays the
+// undelivered tail instead of losing it, and an event is never delivered
+// unless its transaction committed.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+const drainBatchSize = 256
+
+// sink is one registered push destination with its emission policy.
+type sink struct {
+	name    string
+	policy  SinkPolicy
+	deliver func(models.Event)
+}
+
+// Dispatcher delivers outbox entries to the push sinks.
+type Dispatcher struct {
+	store storage.Store
+	sinks []sink
+	log   *slog.Logger
+	poll  time.Duration
+	wake  chan struct{}
+}
+
+// New creates a Dispatcher; sinks are attached with AddSink before Run.
+func New(store storage.Store, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store: store,
+		log:   log,
+		poll:  time.Second,
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// AddSink registers a push destination behind its emission policy.
+func (d *Dispatcher) AddSink(name string, policy SinkPolicy, deliver func(models.Event)) {
+	d.sinks = append(d.sinks, sink{name: name, policy: policy, deliver: deliver})
+}
+
+// Enqueue records one event in the outbox and nudges the dispatcher. It
+// is the publish function handed to the indexer and the other producers.
+func (d *Dispatcher) Enqueue(event models.Event) {
+	if _, err := d.store.AppendOutbox(context.Background(), &event); err != nil {
+		d.log.Error("outbox append failed", "type", event.Type, "error", err)
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run drains the outbox until ctx is canceled, resuming from the durable
+// delivered cursor so a restart replays anything not yet delivered.
+func (d *Dispatcher) Run(ctx context.Context) {
+	cursor, err := d.store.OutboxDeliveredCursor(ctx)
+	if err != nil {
+		d.log.Error("outbox cursor load failed", "error", err)
+	}
+	ticker := time.NewTicker(d.poll)
+	defer ticker.Stop()
+	for {
+		cursor = d.drain(ctx, cursor)
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) drain(ctx context.Context, cursor uint64) uint64 {
+	for {
+		entries, err := d.store.ListOutbox(ctx, cursor, drainBatchSize)
+		if err != nil {
+			d.log.Error("outbox list failed", "error", err)
+			return cursor
+		}
+		if len(entries) == 0 {
+			return cursor
+		}
+		for _, entry := range entries {
+			for _, s := range d.sinks {
+				if s.policy.Admits(entry.Event) {
+					s.deliver(entry.Event)
+				}
+			}
+			cursor = entry.Cursor
+		}
+		if err := d.store.MarkOutboxDelivered(ctx, cursor); err != nil {
+			d.log.Error("outbox mark delivered failed", "cursor", cursor, "error", err)
+			return cursor
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package outbox
+
+import "github.com/lugondev/go-indexer-solana-starter/internal/models"
+
+// Commitment levels an event may carry under data.commitment, in
+// ascending finalization order. Events without the field are treated as
+// confirmed, the commitment the indexer fetches blocks at.
+const (
+	CommitmentProcessed = "processed"
+	CommitmentConfirmed = "confirmed"
+	CommitmentFinalized = "finalized"
+)
+
+func commitmentRank(c string) int {
+	switch c {
+	case CommitmentProcessed:
+		return 0
+	case CommitmentFinalized:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SinkPolicy controls when one push sink sees an event, so low-latency
+// consumers (emit at processed) and correctness-critical consumers
+// (emit only at finalized) can share one outbox.
+type SinkPolicy struct {
+	// MinCommitment is the lowest commitment the sink accepts; events
+	// below it are skipped for this sink, not buffered.
+	MinCommitment string
+	// ReemitUpgrades also delivers copies that only upgrade an earlier
+	// event's commitment (data.commitment_upgrade=true), letting the
+	// consumer track finalization; rollback copies
+	// (data.commitment_rollback=true) follow the same switch.
+	ReemitUpgrades bool
+}
+
+// Admits reports whether the policy lets the sink see the event.
+func (p SinkPolicy) Admits(event models.Event) bool {
+	commitment := CommitmentConfirmed
+	if c, ok := event.Data["commitment"].(string); ok && c != "" {
+		commitment = c
+	}
+	if commitmentRank(commitment) < commitmentRank(p.MinCommitment) {
+		return false
+	}
+	if !p.ReemitUpgrades {
+		if up, ok := event.Data["commitment_upgrade"].(bool); ok && up {
+			return false
+		}
+		if rb, ok := event.Data["commitment_rollback"].(bool); ok && rb {
+			return false
+		}
+	}
+	return true
+}
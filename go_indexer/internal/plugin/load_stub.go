@@ -0,0 +1,15 @@
+//go:build !wazero
+
+package plugin
+
+import (
+	"context"
+	"errors"
+)
+
+// loadModule is the default-build placeholder. The wazero runtime is an
+// external dependency the template does not vendor, so WASM plugins need
+// an opt-in build: go build -tags wazero ./...
+func loadModule(ctx context.Context, programID, path string) (Module, error) {
+	return nil, errors.New("binary built without WASM support; rebuild with -tags wazero")
+}
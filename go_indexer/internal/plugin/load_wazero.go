@@ -0,0 +1,80 @@
+//go:build wazero
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// wasmModule runs one decoder module under the shared wazero runtime.
+type wasmModule struct {
+	programID string
+	runtime   wazero.Runtime
+	module    api.Module
+	alloc     api.Function
+	decode    api.Function
+}
+
+func loadModule(ctx context.Context, programID, path string) (Module, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	runtime := wazero.NewRuntime(ctx)
+	module, err := runtime.Instantiate(ctx, source)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	m := &wasmModule{
+		programID: programID,
+		runtime:   runtime,
+		module:    module,
+		alloc:     module.ExportedFunction("alloc"),
+		decode:    module.ExportedFunction("decode"),
+	}
+	if m.alloc == nil || m.decode == nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("module does not export alloc and decode")
+	}
+	return m, nil
+}
+
+func (m *wasmModule) ProgramID() string { return m.programID }
+
+func (m *wasmModule) Decode(ctx context.Context, instruction []byte) (map[string]any, error) {
+	results, err := m.alloc.Call(ctx, uint64(len(instruction)))
+	if err != nil {
+		return nil, fmt.Errorf("alloc: %w", err)
+	}
+	ptr := uint32(results[0])
+	if !m.module.Memory().Write(ptr, instruction) {
+		return nil, fmt.Errorf("write instruction at %d out of range", ptr)
+	}
+	results, err = m.decode.Call(ctx, uint64(ptr), uint64(len(instruction)))
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	packed := results[0]
+	outPtr, outLen := uint32(packed>>32), uint32(packed)
+	if outLen == 0 {
+		return nil, nil
+	}
+	raw, ok := m.module.Memory().Read(outPtr, outLen)
+	if !ok {
+		return nil, fmt.Errorf("read result at %d+%d out of range", outPtr, outLen)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("result is not a JSON object: %w", err)
+	}
+	return data, nil
+}
+
+func (m *wasmModule) Close(ctx context.Context) error { return m.runtime.Close(ctx) }
@@ -0,0 +1,109 @@
+// Package plugin loads user-provided WASM decoder modules so teams can
+// ship decoders for their own programs, written in any language, without
+// forking the indexer.
+//
+// # ABI
+//
+// A module is named <program-id>.wasm and must export:
+//
+//	alloc(size: u32) -> u32          // allocate size bytes, return pointer
+//	decode(ptr: u32, len: u32) -> u64 // decode instruction data
+//
+// The host writes the raw instruction bytes at the pointer returned by
+// alloc and calls decode. The return value packs the result location as
+// (ptr << 32) | len; the bytes at that location must be a JSON object,
+// which becomes the decoded event's data. An empty result means the
+// instruction was not recognized.
+//
+// The wazero-backed runtime is compiled in with -tags wazero (the
+// dependency is not vendored in the template); the default build reports
+// a clear error when PLUGINS_DIR is configured.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module is one loaded decoder, bound to a program ID.
+type Module interface {
+	ProgramID() string
+	// Decode decodes raw instruction data into the event data object.
+	// A nil map with nil error means the instruction was not recognized.
+	Decode(ctx context.Context, instruction []byte) (map[string]any, error)
+	Close(ctx context.Context) error
+}
+
+// Runtime holds the loaded decoder modules, keyed by program ID.
+type Runtime struct {
+	modules map[string]Module
+}
+
+// LoadDir loads every *.wasm module in dir; each file must be named after
+// the program ID it decodes.
+func LoadDir(ctx context.Context, dir string) (*Runtime, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: read dir: %w", err)
+	}
+	rt := &Runtime{modules: make(map[string]Module)}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".wasm") {
+			continue
+		}
+		programID := strings.TrimSuffix(entry.Name(), ".wasm")
+		module, err := loadModule(ctx, programID, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			rt.Close(ctx)
+			return nil, fmt.Errorf("plugin: load %s: %w", entry.Name(), err)
+		}
+		rt.modules[programID] = module
+	}
+	return rt, nil
+}
+
+// Programs returns the program IDs with a loaded decoder, sorted.
+func (r *Runtime) Programs() []string {
+	ids := make([]string, 0, len(r.modules))
+	for id := range r.modules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Decode runs the module bound to programID, reporting ok=false when no
+// module is loaded for it or the module did not recognize the data.
+func (r *Runtime) Decode(ctx context.Context, programID string, instruction []byte) (map[string]any, bool, error) {
+	module, loaded := r.modules[programID]
+	if !loaded {
+		return nil, false, nil
+	}
+	data, err := module.Decode(ctx, instruction)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, data != nil, nil
+}
+
+// Close releases every loaded module.
+func (r *Runtime) Close(ctx context.Context) {
+	for _, module := range r.modules {
+		_ = module.Close(ctx)
+	}
+}
+
+// defaultRuntime is consulted by the "plugins" processor; processors are
+// built by the registry from a bare name, so the runtime is installed
+// process-wide before profiles are constructed.
+var defaultRuntime *Runtime
+
+// SetDefault installs the runtime used by the plugins processor.
+func SetDefault(r *Runtime) { defaultRuntime = r }
+
+// Default returns the installed runtime, or nil.
+func Default() *Runtime { return defaultRuntime }
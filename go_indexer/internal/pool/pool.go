@@ -0,0 +1,64 @@
+// Package pool recycles the structs the pipeline allocates once per
+// transaction. At high block throughput the Transaction, Transfer, and
+// Event rows dominate allocation churn; handing them back after the
+// storage commit keeps GC pressure flat as throughput grows.
+//
+// Lifecycle: acquire, fill every field (values come back zeroed), pass
+// to storage, and release only after the storage call has returned.
+// Released structs must not be retained. The wire-format structs decoded
+// by encoding/json are not pooled; the decoder owns their lifecycle.
+package pool
+
+import (
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+var (
+	txPool       = sync.Pool{New: func() any { return new(models.Transaction) }}
+	transferPool = sync.Pool{New: func() any { return new(models.Transfer) }}
+	eventPool    = sync.Pool{New: func() any { return new(models.Event) }}
+)
+
+// Transaction returns a zeroed transaction row.
+func Transaction() *models.Transaction {
+	return txPool.Get().(*models.Transaction)
+}
+
+// ReleaseTransaction hands a row back after its storage commit.
+func ReleaseTransaction(tx *models.Transaction) {
+	*tx = models.Transaction{}
+	txPool.Put(tx)
+}
+
+// Transfer returns a zeroed transfer row.
+func Transfer() *models.Transfer {
+	return transferPool.Get().(*models.Transfer)
+}
+
+// ReleaseTransfer hands a row back after its storage commit.
+func ReleaseTransfer(t *models.Transfer) {
+	*t = models.Transfer{}
+	transferPool.Put(t)
+}
+
+// Event returns an event row with an empty, possibly reused, Data map.
+func Event() *models.Event {
+	e := eventPool.Get().(*models.Event)
+	if e.Data == nil {
+		e.Data = make(map[string]any)
+	}
+	return e
+}
+
+// ReleaseEvent hands a row back after its storage commit. The Data map
+// is cleared and kept for reuse.
+func ReleaseEvent(e *models.Event) {
+	data := e.Data
+	for k := range data {
+		delete(data, k)
+	}
+	*e = models.Event{Data: data}
+	eventPool.Put(e)
+}
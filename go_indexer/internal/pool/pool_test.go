@@ -0,0 +1,61 @@
+package pool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+var sinkTx *models.Transaction
+
+// fill mimics what the indexer does with a transaction row before the
+// storage commit.
+func fill(tx *models.Transaction) {
+	tx.Signature = "signature"
+	tx.Slot = 42
+	tx.BlockTime = time.Unix(0, 0)
+	tx.Fee = 5000
+	tx.Success = true
+	tx.IndexedAt = time.Unix(1, 0)
+}
+
+func BenchmarkTransactionPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tx := Transaction()
+		fill(tx)
+		sinkTx = tx
+		ReleaseTransaction(tx)
+	}
+}
+
+func BenchmarkTransactionUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tx := new(models.Transaction)
+		fill(tx)
+		sinkTx = tx
+	}
+}
+
+func TestReleaseZeroes(t *testing.T) {
+	tx := Transaction()
+	fill(tx)
+	ReleaseTransaction(tx)
+	got := Transaction()
+	if !reflect.DeepEqual(*got, models.Transaction{}) {
+		t.Fatalf("pooled transaction not zeroed: %+v", got)
+	}
+	ReleaseTransaction(got)
+
+	e := Event()
+	e.Data["k"] = "v"
+	ReleaseEvent(e)
+	got2 := Event()
+	if len(got2.Data) != 0 {
+		t.Fatalf("pooled event data not cleared: %v", got2.Data)
+	}
+	ReleaseEvent(got2)
+}
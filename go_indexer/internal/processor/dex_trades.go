@@ -0,0 +1,354 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Mainnet orderbook and perps venue program IDs.
+const (
+	PhoenixProgramID  = "PhoeNiXZ8ByJGLkxNfZRnkUfjvmuYqLR89jjFHGqdXY"
+	OpenBookProgramID = "opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb"
+	DriftProgramID    = "dRiftyHA39MWEi3m9aunc5MzRF1JYuBsbn6VPcn33UH"
+)
+
+// Trade sides, from the taker's perspective.
+const (
+	TradeBuy  = "buy"
+	TradeSell = "sell"
+)
+
+// Trade is one normalized fill: the venue-specific event mapped onto a
+// common shape. Price and Size stay in the venue's native units (ticks
+// and lots for Phoenix, lots for OpenBook, Drift precision integers);
+// decimal adjustment needs per-market metadata the indexer does not
+// fetch.
+type Trade struct {
+	Program string
+	Venue   string // "phoenix", "openbook", or "drift"
+	// Market is the market account; Drift markets are numeric, rendered
+	// as "drift:spot:3" / "drift:perp:0".
+	Market string
+	Side   string
+	Price  uint64
+	Size   uint64
+	Maker  string // "" when the venue does not identify the maker
+	Taker  string
+}
+
+// eventDiscriminator is the Anchor event discriminator: the first 8
+// bytes of sha256("event:<Name>"), here hex-encoded to match the anchor
+// decoder's output.
+func eventDiscriminator(name string) string {
+	sum := sha256.Sum256([]byte("event:" + name))
+	return hex.EncodeToString(sum[:8])
+}
+
+var (
+	openbookFillLogDisc  = eventDiscriminator("FillLog")
+	driftOrderActionDisc = eventDiscriminator("OrderActionRecord")
+)
+
+// Phoenix instruction and market event tags.
+const (
+	phoenixLogInstruction = 15
+	phoenixEventHeader    = 0
+	phoenixEventFill      = 1
+)
+
+// DecodeTrades extracts normalized fills from a transaction: Anchor
+// fill events from OpenBook and Drift logs, and Phoenix's packed event
+// buffer from its self-CPI log instruction. Exported so the candle
+// builder can share the decode with the dex_trades processor.
+func DecodeTrades(tx *TxContext) []Trade {
+	meta := tx.Tx.Meta
+	if meta == nil || !meta.Succeeded() {
+		return nil
+	}
+	var out []Trade
+	for _, event := range decoder.NewAnchorDecoder(nil).DecodeLogs(meta.LogMessages) {
+		disc, _ := event.Data["discriminator"].(string)
+		encoded, _ := event.Data["payload"].(string)
+		payload, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		switch {
+		case event.Program == OpenBookProgramID && disc == openbookFillLogDisc:
+			if trade, ok := decodeOpenBookFill(payload); ok {
+				out = append(out, trade)
+			}
+		case event.Program == DriftProgramID && disc == driftOrderActionDisc:
+			if trade, ok := decodeDriftFill(payload); ok {
+				out = append(out, trade)
+			}
+		}
+	}
+	out = append(out, decodePhoenixFills(tx)...)
+	return out
+}
+
+// decodeOpenBookFill reads an openbook-v2 FillLog event. The layout is
+// fixed: market, taker_side, maker_slot, maker_out, timestamp, seq_num,
+// maker, maker_client_order_id, maker_fee, maker_timestamp, taker,
+// taker_client_order_id, taker_fee, price, quantity.
+func decodeOpenBookFill(payload []byte) (Trade, bool) {
+	if len(payload) < 171 {
+		return Trade{}, false
+	}
+	side := TradeBuy
+	if payload[32] == 1 { // taker_side: 0 bid, 1 ask
+		side = TradeSell
+	}
+	return Trade{
+		Program: OpenBookProgramID,
+		Venue:   "openbook",
+		Market:  solana.Base58Encode(payload[0:32]),
+		Side:    side,
+		Maker:   solana.Base58Encode(payload[51:83]),
+		Taker:   solana.Base58Encode(payload[107:139]),
+		Price:   binary.LittleEndian.Uint64(payload[155:163]),
+		Size:    binary.LittleEndian.Uint64(payload[163:171]),
+	}, true
+}
+
+// Drift OrderAction variants; only fills become trades.
+const driftActionFill = 2
+
+// decodeDriftFill reads a drift OrderActionRecord event. The record is
+// option-heavy, so it is walked field by field rather than read at
+// fixed offsets.
+func decodeDriftFill(payload []byte) (Trade, bool) {
+	r := &borshReader{buf: payload}
+	r.skip(8) // ts
+	action := r.u8()
+	r.skip(1) // action_explanation
+	marketIndex := r.u16()
+	marketType := r.u8() // 0 spot, 1 perp
+	r.optionPubkey()     // filler
+	r.optionU64()        // filler_reward
+	r.optionU64()        // fill_record_id
+	base, baseOK := r.optionU64()
+	quote, quoteOK := r.optionU64()
+	r.optionU64() // taker_fee
+	r.optionU64() // maker_fee (i64)
+	r.optionU32() // referrer_reward
+	r.optionU64() // quote_asset_amount_surplus (i64)
+	r.optionU64() // spot_fulfillment_method_fee
+	taker, _ := r.optionPubkey()
+	r.optionU32() // taker_order_id
+	direction, directionOK := r.optionU8()
+	r.optionU64() // taker_order_base_asset_amount
+	r.optionU64() // taker_order_cumulative_base_asset_amount_filled
+	r.optionU64() // taker_order_cumulative_quote_asset_amount_filled
+	maker, _ := r.optionPubkey()
+	if r.err || action != driftActionFill || !baseOK || !quoteOK || base == 0 {
+		return Trade{}, false
+	}
+	kind := "spot"
+	if marketType == 1 {
+		kind = "perp"
+	}
+	side := TradeBuy
+	if directionOK && direction == 1 { // 0 long, 1 short
+		side = TradeSell
+	}
+	return Trade{
+		Program: DriftProgramID,
+		Venue:   "drift",
+		Market:  fmt.Sprintf("drift:%s:%d", kind, marketIndex),
+		Side:    side,
+		// Base is 1e9-scaled, quote 1e6-scaled; this yields the price in
+		// Drift's 1e6 PRICE_PRECISION.
+		Price: quote * 1_000_000_000 / base,
+		Size:  base,
+		Maker: maker,
+		Taker: taker,
+	}, true
+}
+
+// decodePhoenixFills reads Phoenix's packed market events from the
+// program's self-CPI log instruction: a header (instruction, sequence
+// number, timestamp, slot, market, signer, event count) followed by
+// tagged event entries.
+func decodePhoenixFills(tx *TxContext) []Trade {
+	var out []Trade
+	for _, inner := range tx.Tx.Meta.InnerInstructions {
+		for _, ix := range inner.Instructions {
+			if tx.Tx.Transaction.Message.ProgramID(ix) != PhoenixProgramID {
+				continue
+			}
+			data, err := solana.Base58Decode(ix.Data)
+			if err != nil || len(data) < 2 || data[0] != phoenixLogInstruction || data[1] != phoenixEventHeader {
+				continue
+			}
+			r := &borshReader{buf: data[2:]}
+			r.skip(1 + 8 + 8 + 8) // header instruction, sequence number, timestamp, slot
+			market := r.pubkey()
+			taker := r.pubkey()
+			count := r.u16()
+			for i := 0; i < int(count) && !r.err; i++ {
+				if r.u8() != phoenixEventFill {
+					break // other event kinds have different sizes
+				}
+				r.skip(2) // index
+				maker := r.pubkey()
+				seq := r.u64()
+				price := r.u64()
+				filled := r.u64()
+				r.skip(8) // base_lots_remaining
+				if r.err {
+					break
+				}
+				// The resting order's side lives in the sequence
+				// number's top bit; the taker traded against it.
+				side := TradeBuy
+				if seq>>63 == 1 { // maker was a bid, so the taker sold
+					side = TradeSell
+				}
+				out = append(out, Trade{
+					Program: PhoenixProgramID,
+					Venue:   "phoenix",
+					Market:  market,
+					Side:    side,
+					Price:   price,
+					Size:    filled,
+					Maker:   maker,
+					Taker:   taker,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// borshReader walks a Borsh-encoded payload, latching the first
+// out-of-bounds read as an error.
+type borshReader struct {
+	buf []byte
+	pos int
+	err bool
+}
+
+func (r *borshReader) take(n int) []byte {
+	if r.err || r.pos+n > len(r.buf) {
+		r.err = true
+		return nil
+	}
+	out := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return out
+}
+
+func (r *borshReader) skip(n int) { r.take(n) }
+
+func (r *borshReader) u8() byte {
+	b := r.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *borshReader) u16() uint16 {
+	b := r.take(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (r *borshReader) u64() uint64 {
+	b := r.take(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (r *borshReader) pubkey() string {
+	b := r.take(32)
+	if b == nil {
+		return ""
+	}
+	return solana.Base58Encode(b)
+}
+
+// option reads the 1-byte presence tag of a Borsh Option.
+func (r *borshReader) option() bool { return r.u8() == 1 && !r.err }
+
+func (r *borshReader) optionU8() (byte, bool) {
+	if !r.option() {
+		return 0, false
+	}
+	return r.u8(), !r.err
+}
+
+func (r *borshReader) optionU32() (uint32, bool) {
+	if !r.option() {
+		return 0, false
+	}
+	b := r.take(4)
+	if b == nil {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(b), true
+}
+
+func (r *borshReader) optionU64() (uint64, bool) {
+	if !r.option() {
+		return 0, false
+	}
+	return r.u64(), !r.err
+}
+
+func (r *borshReader) optionPubkey() (string, bool) {
+	if !r.option() {
+		return "", false
+	}
+	return r.pubkey(), !r.err
+}
+
+func init() {
+	Register("dex_trades", func() Processor { return &DEXTradesProcessor{} })
+}
+
+// DEXTradesProcessor emits normalized trade rows for Phoenix, OpenBook,
+// and Drift fills, the feed behind candle and volume endpoints.
+type DEXTradesProcessor struct{}
+
+// Name implements Processor.
+func (p *DEXTradesProcessor) Name() string { return "dex_trades" }
+
+// Process implements Processor. Each fill becomes one "trade" event
+// attributed to the venue's program.
+func (p *DEXTradesProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	for _, trade := range DecodeTrades(tx) {
+		out.Events = append(out.Events, models.Event{
+			Signature: tx.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Program:   trade.Program,
+			Type:      "trade",
+			Data: map[string]any{
+				"venue":  trade.Venue,
+				"market": trade.Market,
+				"side":   trade.Side,
+				"price":  trade.Price,
+				"size":   trade.Size,
+				"maker":  trade.Maker,
+				"taker":  trade.Taker,
+			},
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+func init() {
+	Register("events", func() Processor {
+		return &EventProcessor{
+			anchor:   decoder.NewAnchorDecoder(nil),
+			provider: solana.DefaultProvider(),
+		}
+	})
+}
+
+// EventProcessor decodes Anchor events from transaction logs. When an
+// enhanced RPC provider is configured, transactions the built-in decoder
+// cannot handle are parsed through the vendor's API instead.
+type EventProcessor struct {
+	anchor   *decoder.AnchorDecoder
+	provider solana.Provider
+}
+
+// Name implements Processor.
+func (p *EventProcessor) Name() string { return "events" }
+
+// Process implements Processor.
+func (p *EventProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if tx.Tx.Meta == nil {
+		return out, nil
+	}
+	for _, event := range p.anchor.DecodeLogs(tx.Tx.Meta.LogMessages) {
+		event.Signature = tx.Signature
+		event.Slot = tx.Slot
+		event.BlockTime = tx.BlockTime
+		out.Events = append(out.Events, event)
+	}
+	if len(out.Events) == 0 && p.provider != nil {
+		if event, ok := p.parseWithProvider(ctx, tx); ok {
+			out.Events = append(out.Events, event)
+		}
+	}
+	return out, nil
+}
+
+// parseWithProvider asks the vendor's enhanced API to decode a
+// transaction the Anchor decoder produced nothing for. Vendor failures
+// are swallowed: coverage degrades back to the built-in decoders.
+func (p *EventProcessor) parseWithProvider(ctx context.Context, tx *TxContext) (models.Event, bool) {
+	parsed, err := p.provider.ParseTransaction(ctx, tx.Signature)
+	if err != nil {
+		return models.Event{}, false
+	}
+	if parsed.Type == "" || parsed.Type == "UNKNOWN" {
+		return models.Event{}, false
+	}
+	data := map[string]any{"provider": p.provider.Name()}
+	if parsed.Description != "" {
+		data["description"] = parsed.Description
+	}
+	return models.Event{
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+		BlockTime: tx.BlockTime,
+		Program:   parsed.Source,
+		Type:      parsed.Type,
+		Data:      data,
+	}, true
+}
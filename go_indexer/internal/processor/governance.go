@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// GovernanceProgramID is the mainnet SPL Governance (Realms) program.
+const GovernanceProgramID = "GovER5Lthms3bLBqWub97yVrMmEogzX7xNjdXpPPCVZw"
+
+// Normalized governance actions.
+const (
+	GovProposalCreated     = "proposal_created"
+	GovProposalCancelled   = "proposal_cancelled"
+	GovProposalSignedOff   = "proposal_signed_off"
+	GovVoteCast            = "vote_cast"
+	GovVoteFinalized       = "vote_finalized"
+	GovVoteRelinquished    = "vote_relinquished"
+	GovTransactionAdded    = "transaction_added"
+	GovTransactionExecuted = "transaction_executed"
+)
+
+// GovernanceAction is one normalized SPL Governance lifecycle step.
+// Realm is empty for transaction instructions, which do not reference
+// the realm directly; the tracker resolves it through the proposal.
+type GovernanceAction struct {
+	Action     string
+	Realm      string
+	Proposal   string
+	Actor      string // signing authority or signatory, "" when absent
+	VoteRecord string // set for vote_cast only
+}
+
+// governanceMethod maps one instruction tag to its action and account
+// positions, -1 for accounts the instruction does not carry. Positions
+// follow the spl-governance v3 layouts.
+type governanceMethod struct {
+	action     string
+	realm      int
+	proposal   int
+	actor      int
+	voteRecord int
+}
+
+// SPL Governance is borsh-dispatched: the first instruction byte is the
+// enum variant index.
+var governanceMethods = map[byte]governanceMethod{
+	6:  {GovProposalCreated, 0, 1, 5, -1},       // CreateProposal
+	9:  {GovTransactionAdded, -1, 1, 3, -1},     // InsertTransaction
+	11: {GovProposalCancelled, 0, 2, 4, -1},     // CancelProposal
+	12: {GovProposalSignedOff, 0, 2, 3, -1},     // SignOffProposal
+	13: {GovVoteCast, 0, 2, 5, 6},               // CastVote
+	14: {GovVoteFinalized, 0, 2, -1, -1},        // FinalizeVote
+	15: {GovVoteRelinquished, 0, 2, -1, -1},     // RelinquishVote
+	16: {GovTransactionExecuted, -1, 1, -1, -1}, // ExecuteTransaction
+}
+
+// DecodeGovernanceActions extracts normalized governance steps from a
+// transaction's top-level instructions. Exported so the realm tracker
+// shares the decode.
+func DecodeGovernanceActions(tx *TxContext) []GovernanceAction {
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return nil
+	}
+	var out []GovernanceAction
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != GovernanceProgramID {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		method, ok := governanceMethods[data[0]]
+		if !ok || method.proposal >= len(ix.Accounts) {
+			continue
+		}
+		action := GovernanceAction{
+			Action:   method.action,
+			Proposal: accountKey(msg, ix.Accounts[method.proposal]),
+		}
+		if method.realm >= 0 && method.realm < len(ix.Accounts) {
+			action.Realm = accountKey(msg, ix.Accounts[method.realm])
+		}
+		if method.actor >= 0 && method.actor < len(ix.Accounts) {
+			action.Actor = accountKey(msg, ix.Accounts[method.actor])
+		}
+		if method.voteRecord >= 0 && method.voteRecord < len(ix.Accounts) {
+			action.VoteRecord = accountKey(msg, ix.Accounts[method.voteRecord])
+		}
+		out = append(out, action)
+	}
+	return out
+}
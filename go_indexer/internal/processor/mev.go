@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// jitoTipAccounts are the well-known mainnet Jito tip payment accounts.
+// A transfer to any of them marks the transaction as (part of) a bundle.
+var jitoTipAccounts = map[string]struct{}{
+	"96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5": {},
+	"HFqU5x63VTqvQss8hp11i4wVV8bD44PvwucfZ2bU7gRe": {},
+	"Cw8CFyM9FkoMi7K7Crf6HNQqf4uEMzpKw6QNghXLvLkY": {},
+	"ADaUMid9yfUytqMBgopwjb2DTLSokTSzL1zt6iGPaS49": {},
+	"DfXygSm4jCyNCybVYYK6DwvWqjKee8pbDmJGcLWNDXjh": {},
+	"ADuUkR4vqLUMWXxW9gh6D6L8pMSawimctcNZ5pGwDcEt": {},
+	"DttWaMuVvTiduZRnguLF7jNxTgiMBZ1hyAumKUiL2KRL": {},
+	"3AVi9Tg9Uo68tJfuvoKvqKNWKkC5wPdSSdeBnizKZ6jT": {},
+}
+
+func init() {
+	Register("mev", func() Processor { return &MEVProcessor{} })
+}
+
+// MEVProcessor detects Jito tip payments so bundle activity and tip volume
+// can be aggregated per slot.
+type MEVProcessor struct{}
+
+// Name implements Processor.
+func (p *MEVProcessor) Name() string { return "mev" }
+
+// Process implements Processor. It emits one jito_tip event per system
+// transfer into a known tip account.
+func (p *MEVProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return out, nil
+	}
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != SystemProgramID || len(ix.Accounts) < 2 {
+			continue
+		}
+		dest := accountKey(msg, ix.Accounts[1])
+		if _, ok := jitoTipAccounts[dest]; !ok {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 12 || binary.LittleEndian.Uint32(data[:4]) != 2 {
+			continue
+		}
+		out.Events = append(out.Events, models.Event{
+			Signature: tx.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Program:   SystemProgramID,
+			Type:      "jito_tip",
+			Data: map[string]any{
+				"tip_account": dest,
+				"lamports":    binary.LittleEndian.Uint64(data[4:12]),
+				"payer":       accountKey(msg, ix.Accounts[0]),
+			},
+		})
+	}
+	return out, nil
+}
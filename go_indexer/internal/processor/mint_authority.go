@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Token authority types relevant to mints.
+const (
+	authorityMintTokens    = 0
+	authorityFreezeAccount = 1
+)
+
+// MintAuthorityChange is one decoded SetAuthority instruction targeting
+// a mint. An empty NewAuthority means the authority was revoked, which
+// for mint authority makes the supply immutable.
+type MintAuthorityChange struct {
+	Mint      string
+	Authority string // the signing current authority
+	// Type is "mint" (who can mint supply) or "freeze" (who can freeze
+	// token accounts).
+	Type         string
+	NewAuthority string
+}
+
+// DecodeMintAuthorityChanges extracts mint and freeze authority changes
+// from a transaction's top-level instructions. It is shared by the
+// authority history tracker and the alert rules engine.
+func DecodeMintAuthorityChanges(tx *TxContext) []MintAuthorityChange {
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return nil
+	}
+	var out []MintAuthorityChange
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != TokenProgramID {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 3 || data[0] != tokenSetAuthority {
+			continue
+		}
+		// mint, current authority; data: tag, authority type, option,
+		// new key.
+		change := MintAuthorityChange{}
+		switch data[1] {
+		case authorityMintTokens:
+			change.Type = "mint"
+		case authorityFreezeAccount:
+			change.Type = "freeze"
+		default:
+			continue
+		}
+		if len(ix.Accounts) < 2 {
+			continue
+		}
+		change.Mint = accountKey(msg, ix.Accounts[0])
+		change.Authority = accountKey(msg, ix.Accounts[1])
+		if data[2] == 1 && len(data) >= 35 {
+			change.NewAuthority = solana.Base58Encode(data[3:35])
+		}
+		out = append(out, change)
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/plugin"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+func init() {
+	Register("plugins", func() Processor { return &PluginProcessor{runtime: plugin.Default()} })
+}
+
+// PluginProcessor routes instructions through the WASM decoder modules
+// loaded from PLUGINS_DIR, emitting one event per decoded instruction.
+type PluginProcessor struct {
+	runtime *plugin.Runtime
+}
+
+// Name implements Processor.
+func (p *PluginProcessor) Name() string { return "plugins" }
+
+// Process implements Processor.
+func (p *PluginProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if p.runtime == nil {
+		return out, nil
+	}
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		programID := msg.ProgramID(ix)
+		raw, err := solana.Base58Decode(ix.Data)
+		if err != nil {
+			continue
+		}
+		data, ok, err := p.runtime.Decode(ctx, programID, raw)
+		if err != nil {
+			return out, err
+		}
+		if !ok {
+			continue
+		}
+		eventType := "plugin_decoded"
+		if t, isString := data["type"].(string); isString && t != "" {
+			eventType = t
+		}
+		out.Events = append(out.Events, models.Event{
+			Signature: tx.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Program:   programID,
+			Type:      eventType,
+			Data:      data,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,114 @@
+// Package processor defines the units of work that turn a fetched
+// transaction into rows for storage. Processors are registered by name so
+// indexing profiles can reference them from config.
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// TxContext carries one transaction through a processor.
+type TxContext struct {
+	Slot      uint64
+	BlockTime time.Time
+	Signature string
+	Tx        *solana.TransactionWithMeta
+}
+
+// Output collects the rows a processor produced for one transaction.
+type Output struct {
+	Transfers           []models.Transfer
+	Events              []models.Event
+	ProgramUpdates      []models.ProgramUpdate
+	TokenAccountUpdates []models.TokenAccountUpdate
+}
+
+// Processor extracts rows from a transaction.
+type Processor interface {
+	Name() string
+	Process(ctx context.Context, tx *TxContext) (Output, error)
+}
+
+// Factory builds a named processor.
+type Factory func() Processor
+
+var registry = map[string]Factory{}
+
+// Register makes a processor constructible by name. It panics on duplicate
+// names and is intended to be called from init functions.
+func Register(name string, f Factory) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("processor: duplicate registration of %q", name))
+	}
+	registry[name] = f
+}
+
+// New builds the processor registered under name.
+func New(name string) (Processor, error) {
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("processor: unknown processor %q (registered: %s)",
+			name, strings.Join(Names(), ", "))
+	}
+	return f(), nil
+}
+
+// Configurable is implemented by processors that accept per-profile
+// options from the profile's "options" section.
+type Configurable interface {
+	// Configure applies the option map. Implementations reject unknown
+	// keys so typos surface at startup, not as silently ignored settings.
+	Configure(opts map[string]any) error
+}
+
+// NewWithOptions builds the named processor and applies opts. Options
+// passed to a processor that takes none are a configuration error.
+func NewWithOptions(name string, opts map[string]any) (Processor, error) {
+	proc, err := New(name)
+	if err != nil || len(opts) == 0 {
+		return proc, err
+	}
+	c, ok := proc.(Configurable)
+	if !ok {
+		return nil, fmt.Errorf("processor %q accepts no options", name)
+	}
+	if err := c.Configure(opts); err != nil {
+		return nil, fmt.Errorf("processor %q: %w", name, err)
+	}
+	return proc, nil
+}
+
+// Names returns the registered processor names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StringListOption decodes a JSON-sourced option value into a string
+// list, shared by Configure implementations.
+func StringListOption(key string, value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("option %q: want a list of strings", key)
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("option %q: want a list of strings", key)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// BPFLoaderUpgradeableID is the upgradeable BPF loader program.
+const BPFLoaderUpgradeableID = "BPFLoaderUpgradeab1e11111111111111111111111"
+
+// BPF Loader Upgradeable instruction indexes (u32 little-endian prefix).
+const (
+	loaderDeployWithMaxDataLen = 2
+	loaderUpgrade              = 3
+	loaderSetAuthority         = 4
+	loaderClose                = 5
+)
+
+func init() {
+	Register("programs", func() Processor { return &ProgramProcessor{} })
+}
+
+// ProgramProcessor decodes BPF Loader Upgradeable instructions to maintain
+// the program registry: deployments, upgrades, authority changes, and
+// closures with their slots.
+type ProgramProcessor struct{}
+
+// Name implements Processor.
+func (p *ProgramProcessor) Name() string { return "programs" }
+
+// Process implements Processor.
+func (p *ProgramProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return out, nil
+	}
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != BPFLoaderUpgradeableID {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 4 {
+			continue
+		}
+		update := p.decodeInstruction(binary.LittleEndian.Uint32(data[:4]), msg, ix)
+		if update == nil {
+			continue
+		}
+		update.Slot = tx.Slot
+		update.Signature = tx.Signature
+		update.BlockTime = tx.BlockTime
+		out.ProgramUpdates = append(out.ProgramUpdates, *update)
+	}
+	return out, nil
+}
+
+func (p *ProgramProcessor) decodeInstruction(tag uint32, msg *solana.Message, ix solana.Instruction) *models.ProgramUpdate {
+	switch tag {
+	case loaderDeployWithMaxDataLen:
+		// payer, programdata, program, buffer, rent, clock, system, authority.
+		if len(ix.Accounts) < 8 {
+			return nil
+		}
+		return &models.ProgramUpdate{
+			Kind:        "deployed",
+			ProgramData: accountKey(msg, ix.Accounts[1]),
+			ProgramID:   accountKey(msg, ix.Accounts[2]),
+			Authority:   accountKey(msg, ix.Accounts[7]),
+		}
+	case loaderUpgrade:
+		// programdata, program, buffer, spill, rent, clock, authority.
+		if len(ix.Accounts) < 7 {
+			return nil
+		}
+		return &models.ProgramUpdate{
+			Kind:        "upgraded",
+			ProgramData: accountKey(msg, ix.Accounts[0]),
+			ProgramID:   accountKey(msg, ix.Accounts[1]),
+			Authority:   accountKey(msg, ix.Accounts[6]),
+		}
+	case loaderSetAuthority:
+		// account (programdata), current authority, optional new authority.
+		if len(ix.Accounts) < 2 {
+			return nil
+		}
+		update := &models.ProgramUpdate{
+			Kind:        "set_authority",
+			ProgramData: accountKey(msg, ix.Accounts[0]),
+			ProgramID:   accountKey(msg, ix.Accounts[0]),
+		}
+		if len(ix.Accounts) >= 3 {
+			update.Authority = accountKey(msg, ix.Accounts[2])
+		}
+		return update
+	case loaderClose:
+		// account, recipient, optional authority, optional program.
+		if len(ix.Accounts) < 2 {
+			return nil
+		}
+		update := &models.ProgramUpdate{
+			Kind:        "closed",
+			ProgramData: accountKey(msg, ix.Accounts[0]),
+			ProgramID:   accountKey(msg, ix.Accounts[0]),
+		}
+		if len(ix.Accounts) >= 4 {
+			update.ProgramID = accountKey(msg, ix.Accounts[3])
+		}
+		return update
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Mainnet Squads multisig program IDs.
+const (
+	SquadsV3ProgramID = "SMPLecH534NA9acpos4G6x7uf3LWbCAwZQE9e8ZekMu"
+	SquadsV4ProgramID = "SQDS4ep65T869zMMBKyuUq6aD6EgTu8psMjkvj52pCf"
+)
+
+// Normalized multisig proposal actions.
+const (
+	MultisigCreated   = "created"
+	MultisigApproved  = "approved"
+	MultisigRejected  = "rejected"
+	MultisigCancelled = "cancelled"
+	MultisigExecuted  = "executed"
+)
+
+// MultisigAction is one normalized Squads proposal lifecycle step.
+type MultisigAction struct {
+	Program  string
+	Version  string // "v3" or "v4"
+	Action   string
+	Multisig string
+	Proposal string // the transaction (v3) or proposal (v4) account
+	Member   string // the acting member, "" when not identifiable
+}
+
+// squadsMethod maps one instruction to its action and the positions of
+// the multisig, proposal, and member in its account list.
+type squadsMethod struct {
+	action   string
+	multisig int
+	proposal int
+	member   int
+}
+
+// squadsV3Methods covers the v3 transaction lifecycle; accounts run
+// multisig, transaction, member throughout.
+var squadsV3Methods = map[[8]byte]squadsMethod{
+	anchorDiscriminator("create_transaction"):  {MultisigCreated, 0, 1, 2},
+	anchorDiscriminator("approve_transaction"): {MultisigApproved, 0, 1, 2},
+	anchorDiscriminator("reject_transaction"):  {MultisigRejected, 0, 1, 2},
+	anchorDiscriminator("cancel_transaction"):  {MultisigCancelled, 0, 1, 2},
+	anchorDiscriminator("execute_transaction"): {MultisigExecuted, 0, 1, 2},
+}
+
+// squadsV4Methods covers the v4 proposal lifecycle; votes list the
+// member before the proposal.
+var squadsV4Methods = map[[8]byte]squadsMethod{
+	anchorDiscriminator("proposal_create"):           {MultisigCreated, 0, 1, 2},
+	anchorDiscriminator("proposal_approve"):          {MultisigApproved, 0, 2, 1},
+	anchorDiscriminator("proposal_reject"):           {MultisigRejected, 0, 2, 1},
+	anchorDiscriminator("proposal_cancel"):           {MultisigCancelled, 0, 2, 1},
+	anchorDiscriminator("vault_transaction_execute"): {MultisigExecuted, 0, 1, 2},
+}
+
+// DecodeMultisigActions extracts Squads v3/v4 proposal lifecycle steps
+// from a transaction's top-level instructions. Exported so the proposal
+// tracker shares the decode.
+func DecodeMultisigActions(tx *TxContext) []MultisigAction {
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return nil
+	}
+	var out []MultisigAction
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		program := msg.ProgramID(ix)
+		var version string
+		var methods map[[8]byte]squadsMethod
+		switch program {
+		case SquadsV3ProgramID:
+			version, methods = "v3", squadsV3Methods
+		case SquadsV4ProgramID:
+			version, methods = "v4", squadsV4Methods
+		default:
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 8 {
+			continue
+		}
+		method, ok := methods[[8]byte(data[:8])]
+		if !ok || method.multisig >= len(ix.Accounts) || method.proposal >= len(ix.Accounts) {
+			continue
+		}
+		action := MultisigAction{
+			Program:  program,
+			Version:  version,
+			Action:   method.action,
+			Multisig: accountKey(msg, ix.Accounts[method.multisig]),
+			Proposal: accountKey(msg, ix.Accounts[method.proposal]),
+		}
+		if method.member < len(ix.Accounts) {
+			action.Member = accountKey(msg, ix.Accounts[method.member])
+		}
+		out = append(out, action)
+	}
+	return out
+}
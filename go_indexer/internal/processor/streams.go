@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"encoding/binary"
+
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Mainnet streaming-payment program IDs.
+const (
+	StreamflowProgramID = "strmRqUCoQUgGUan5YhzUZa6KqdzwX5L6FpUxfmKg5m"
+	MeanProgramID       = "MSPCUMbLfy2MeT6geLMMzrUkv1Tx88XRApaVRdyxTuu"
+)
+
+// Normalized stream actions.
+const (
+	StreamCreated   = "created"
+	StreamWithdrawn = "withdrawn"
+	StreamToppedUp  = "topped_up"
+	StreamCancelled = "cancelled"
+)
+
+// StreamAction is one normalized streaming-payment step. Schedule
+// fields are set for created actions only; Amount is the deposit,
+// withdrawal, or top-up in the stream mint's base units.
+type StreamAction struct {
+	Program   string
+	Protocol  string // "streamflow" or "mean"
+	Action    string
+	Stream    string // the stream metadata account
+	Sender    string
+	Recipient string
+	Amount    uint64
+
+	// Vesting schedule, from Streamflow create instructions.
+	Start           uint64 // unix seconds
+	Period          uint64 // seconds per release
+	AmountPerPeriod uint64
+	Cliff           uint64 // unix seconds
+}
+
+// streamMethod maps one instruction to its action and account
+// positions, -1 for accounts the instruction does not carry.
+type streamMethod struct {
+	action    string
+	stream    int
+	sender    int
+	recipient int
+}
+
+// streamflowMethods covers the Streamflow stream lifecycle; positions
+// follow the streamflow IDL.
+var streamflowMethods = map[[8]byte]streamMethod{
+	anchorDiscriminator("create"):   {StreamCreated, 4, 0, 2},
+	anchorDiscriminator("withdraw"): {StreamWithdrawn, 3, -1, 1},
+	anchorDiscriminator("topup"):    {StreamToppedUp, 2, 0, -1},
+	anchorDiscriminator("cancel"):   {StreamCancelled, 5, 1, 3},
+}
+
+// meanMethods covers the Mean streaming protocol equivalents.
+var meanMethods = map[[8]byte]streamMethod{
+	anchorDiscriminator("create_stream"): {StreamCreated, 3, 1, 2},
+	anchorDiscriminator("withdraw"):      {StreamWithdrawn, 2, -1, 1},
+	anchorDiscriminator("add_funds"):     {StreamToppedUp, 2, 0, -1},
+	anchorDiscriminator("close_stream"):  {StreamCancelled, 2, 0, 1},
+}
+
+// DecodeStreamActions extracts normalized streaming-payment steps from
+// a transaction's top-level instructions. Exported so the stream
+// tracker shares the decode.
+func DecodeStreamActions(tx *TxContext) []StreamAction {
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return nil
+	}
+	var out []StreamAction
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		program := msg.ProgramID(ix)
+		var protocol string
+		var methods map[[8]byte]streamMethod
+		switch program {
+		case StreamflowProgramID:
+			protocol, methods = "streamflow", streamflowMethods
+		case MeanProgramID:
+			protocol, methods = "mean", meanMethods
+		default:
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 8 {
+			continue
+		}
+		method, ok := methods[[8]byte(data[:8])]
+		if !ok || method.stream >= len(ix.Accounts) {
+			continue
+		}
+		action := StreamAction{
+			Program:  program,
+			Protocol: protocol,
+			Action:   method.action,
+			Stream:   accountKey(msg, ix.Accounts[method.stream]),
+		}
+		if method.sender >= 0 && method.sender < len(ix.Accounts) {
+			action.Sender = accountKey(msg, ix.Accounts[method.sender])
+		}
+		if method.recipient >= 0 && method.recipient < len(ix.Accounts) {
+			action.Recipient = accountKey(msg, ix.Accounts[method.recipient])
+		}
+		switch method.action {
+		case StreamCreated:
+			// Streamflow create data: start_time, net_amount_deposited,
+			// period, amount_per_period, cliff, cliff_amount, all u64
+			// little-endian after the discriminator. Mean creates carry
+			// a different layout; only the accounts are recorded.
+			if protocol == "streamflow" && len(data) >= 56 {
+				action.Start = binary.LittleEndian.Uint64(data[8:16])
+				action.Amount = binary.LittleEndian.Uint64(data[16:24])
+				action.Period = binary.LittleEndian.Uint64(data[24:32])
+				action.AmountPerPeriod = binary.LittleEndian.Uint64(data[32:40])
+				action.Cliff = binary.LittleEndian.Uint64(data[40:48])
+			}
+		case StreamWithdrawn, StreamToppedUp:
+			if len(data) >= 16 {
+				action.Amount = binary.LittleEndian.Uint64(data[8:16])
+			}
+		}
+		out = append(out, action)
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// SPL token instruction tags (single-byte prefix).
+const (
+	tokenInitializeAccount  = 1
+	tokenSetAuthority       = 6
+	tokenCloseAccount       = 9
+	tokenFreezeAccount      = 10
+	tokenThawAccount        = 11
+	tokenInitializeAccount2 = 16
+	tokenInitializeAccount3 = 18
+)
+
+// Token authority types relevant to token accounts.
+const (
+	authorityAccountOwner = 2
+	authorityCloseAccount = 3
+)
+
+func init() {
+	Register("token_accounts", func() Processor { return &TokenAccountProcessor{} })
+}
+
+// TokenAccountProcessor tracks SPL token account creation, ownership
+// changes, freezing/thawing, and closure, feeding the token_accounts
+// dimension table that resolves token accounts to wallet owners.
+type TokenAccountProcessor struct{}
+
+// Name implements Processor.
+func (p *TokenAccountProcessor) Name() string { return "token_accounts" }
+
+// Process implements Processor.
+func (p *TokenAccountProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return out, nil
+	}
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != TokenProgramID {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) == 0 || len(ix.Accounts) == 0 {
+			continue
+		}
+		update := p.decodeInstruction(data, msg, ix)
+		if update == nil {
+			continue
+		}
+		update.Slot = tx.Slot
+		update.Signature = tx.Signature
+		update.BlockTime = tx.BlockTime
+		out.TokenAccountUpdates = append(out.TokenAccountUpdates, *update)
+	}
+	return out, nil
+}
+
+func (p *TokenAccountProcessor) decodeInstruction(data []byte, msg *solana.Message, ix solana.Instruction) *models.TokenAccountUpdate {
+	address := accountKey(msg, ix.Accounts[0])
+	switch data[0] {
+	case tokenInitializeAccount:
+		// account, mint, owner, rent.
+		if len(ix.Accounts) < 3 {
+			return nil
+		}
+		return &models.TokenAccountUpdate{
+			Kind:    "initialized",
+			Address: address,
+			Mint:    accountKey(msg, ix.Accounts[1]),
+			Owner:   accountKey(msg, ix.Accounts[2]),
+		}
+	case tokenInitializeAccount2, tokenInitializeAccount3:
+		// account, mint[, rent]; owner is in the instruction data.
+		if len(ix.Accounts) < 2 || len(data) < 33 {
+			return nil
+		}
+		return &models.TokenAccountUpdate{
+			Kind:    "initialized",
+			Address: address,
+			Mint:    accountKey(msg, ix.Accounts[1]),
+			Owner:   solana.Base58Encode(data[1:33]),
+		}
+	case tokenSetAuthority:
+		// account, current authority; data: authority type, option, new key.
+		if len(data) < 2 || data[1] != authorityAccountOwner && data[1] != authorityCloseAccount {
+			return nil
+		}
+		update := &models.TokenAccountUpdate{Kind: "owner_changed", Address: address}
+		if data[1] == authorityAccountOwner && len(data) >= 35 && data[2] == 1 {
+			update.Owner = solana.Base58Encode(data[3:35])
+		}
+		return update
+	case tokenFreezeAccount:
+		return &models.TokenAccountUpdate{Kind: "frozen", Address: address}
+	case tokenThawAccount:
+		return &models.TokenAccountUpdate{Kind: "thawed", Address: address}
+	case tokenCloseAccount:
+		return &models.TokenAccountUpdate{Kind: "closed", Address: address}
+	}
+	return nil
+}
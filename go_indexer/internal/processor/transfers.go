@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// SystemProgramID is the native system program that owns SOL transfers.
+const SystemProgramID = "11111111111111111111111111111111"
+
+// TokenProgramID is the SPL token program.
+const TokenProgramID = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+
+func init() {
+	Register("transfers", func() Processor { return &TransferProcessor{} })
+}
+
+// TransferProcessor extracts native SOL transfers and SPL token transfers
+// from top-level instructions.
+type TransferProcessor struct {
+	// includeMints, when non-nil, restricts token transfers to the listed
+	// mints. Native SOL transfers carry no mint and are unaffected.
+	includeMints map[string]struct{}
+}
+
+// Name implements Processor.
+func (p *TransferProcessor) Name() string { return "transfers" }
+
+// Configure implements Configurable. Supported options:
+//
+//	include_mints: list of mint addresses to keep token transfers for
+func (p *TransferProcessor) Configure(opts map[string]any) error {
+	for key, value := range opts {
+		switch key {
+		case "include_mints":
+			mints, err := StringListOption(key, value)
+			if err != nil {
+				return err
+			}
+			p.includeMints = make(map[string]struct{}, len(mints))
+			for _, mint := range mints {
+				p.includeMints[mint] = struct{}{}
+			}
+		default:
+			return fmt.Errorf("unknown option %q (supported: include_mints)", key)
+		}
+	}
+	return nil
+}
+
+// Process implements Processor.
+func (p *TransferProcessor) Process(ctx context.Context, tx *TxContext) (Output, error) {
+	var out Output
+	if tx.Tx.Meta != nil && !tx.Tx.Meta.Succeeded() {
+		return out, nil
+	}
+	msg := &tx.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		switch msg.ProgramID(ix) {
+		case SystemProgramID:
+			if t, ok := p.decodeSystemTransfer(tx, msg, ix); ok {
+				out.Transfers = append(out.Transfers, t)
+			}
+		case TokenProgramID:
+			if t, ok := p.decodeTokenTransfer(tx, msg, ix); ok && p.wantMint(t.Mint) {
+				out.Transfers = append(out.Transfers, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+// wantMint applies the include_mints option to one token transfer.
+func (p *TransferProcessor) wantMint(mint string) bool {
+	if p.includeMints == nil {
+		return true
+	}
+	_, ok := p.includeMints[mint]
+	return ok
+}
+
+// decodeSystemTransfer decodes a system program Transfer instruction:
+// u32 instruction index (2) followed by u64 lamports, little-endian.
+func (p *TransferProcessor) decodeSystemTransfer(tx *TxContext, msg *solana.Message, ix solana.Instruction) (models.Transfer, bool) {
+	data, err := solana.Base58Decode(ix.Data)
+	if err != nil || len(data) < 12 || binary.LittleEndian.Uint32(data[:4]) != 2 {
+		return models.Transfer{}, false
+	}
+	if len(ix.Accounts) < 2 {
+		return models.Transfer{}, false
+	}
+	return models.Transfer{
+		Signature:   tx.Signature,
+		Slot:        tx.Slot,
+		BlockTime:   tx.BlockTime,
+		Source:      accountKey(msg, ix.Accounts[0]),
+		Destination: accountKey(msg, ix.Accounts[1]),
+		Amount:      binary.LittleEndian.Uint64(data[4:12]),
+		Decimals:    9,
+	}, true
+}
+
+// decodeTokenTransfer decodes SPL token Transfer (3) and TransferChecked
+// (12) instructions: u8 instruction tag followed by u64 amount.
+func (p *TransferProcessor) decodeTokenTransfer(tx *TxContext, msg *solana.Message, ix solana.Instruction) (models.Transfer, bool) {
+	data, err := solana.Base58Decode(ix.Data)
+	if err != nil || len(data) < 9 {
+		return models.Transfer{}, false
+	}
+	tag := data[0]
+	if tag != 3 && tag != 12 {
+		return models.Transfer{}, false
+	}
+	if len(ix.Accounts) < 3 {
+		return models.Transfer{}, false
+	}
+	t := models.Transfer{
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+		BlockTime: tx.BlockTime,
+		Source:    accountKey(msg, ix.Accounts[0]),
+		Amount:    binary.LittleEndian.Uint64(data[1:9]),
+	}
+	if tag == 12 {
+		// TransferChecked: source, mint, destination, authority.
+		if len(ix.Accounts) < 4 {
+			return models.Transfer{}, false
+		}
+		t.Mint = accountKey(msg, ix.Accounts[1])
+		t.Destination = accountKey(msg, ix.Accounts[2])
+		if len(data) >= 10 {
+			t.Decimals = data[9]
+		}
+	} else {
+		// Transfer: source, destination, authority.
+		t.Destination = accountKey(msg, ix.Accounts[1])
+	}
+	return t, true
+}
+
+func accountKey(msg *solana.Message, idx int) string {
+	if idx < 0 || idx >= len(msg.AccountKeys) {
+		return ""
+	}
+	return msg.AccountKeys[idx]
+}
@@ -0,0 +1,156 @@
+// Package redact applies data-minimization rules to indexed rows before
+// they are stored or published. Deployments with privacy requirements
+// declare per-field rules — drop a field outright or replace it with a
+// salted hash that still supports equality joins — and every redacted
+// event carries an in-band audit of which fields were touched.
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Actions a rule can apply.
+const (
+	// ActionDrop clears the field.
+	ActionDrop = "drop"
+	// ActionHash replaces the field with a salted SHA-256 digest, so
+	// equal values still correlate without revealing the original.
+	ActionHash = "hash"
+)
+
+// Redactable targets and their fields:
+//
+//	transfer.source, transfer.destination
+//	transaction.signer
+//	event.data.<key>      any decoded data field, e.g. event.data.memo
+type Rule struct {
+	Target string // transfer, transaction, or event
+	Field  string
+	Action string
+}
+
+// Redactor applies a parsed rule set.
+type Redactor struct {
+	rules []Rule
+	salt  string
+}
+
+var defaultRedactor *Redactor
+
+// SetDefault installs the redactor the processing pipeline consults;
+// see plugin.SetDefault for the same pattern.
+func SetDefault(r *Redactor) { defaultRedactor = r }
+
+// Default returns the installed redactor, or nil when redaction is off.
+func Default() *Redactor { return defaultRedactor }
+
+// Parse builds a Redactor from a comma-separated rule list of
+// "target.field=action" entries, e.g.
+// "event.data.memo=drop,transfer.source=hash".
+func Parse(spec, salt string) (*Redactor, error) {
+	r := &Redactor{salt: salt}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, action, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("redact rule %q: missing =action", entry)
+		}
+		if action != ActionDrop && action != ActionHash {
+			return nil, fmt.Errorf("redact rule %q: action must be drop or hash", entry)
+		}
+		target, field, ok := strings.Cut(path, ".")
+		if !ok {
+			return nil, fmt.Errorf("redact rule %q: field must be target.field", entry)
+		}
+		switch {
+		case target == "transfer" && (field == "source" || field == "destination"):
+		case target == "transaction" && field == "signer":
+		case target == "event" && strings.HasPrefix(field, "data.") && len(field) > len("data."):
+		default:
+			return nil, fmt.Errorf("redact rule %q: unknown field", entry)
+		}
+		r.rules = append(r.rules, Rule{Target: target, Field: field, Action: action})
+	}
+	if len(r.rules) == 0 {
+		return nil, fmt.Errorf("redact rules %q: no rules", spec)
+	}
+	return r, nil
+}
+
+// Audit lists the active rules for startup logging, so operators can
+// verify exactly what leaves the pipeline redacted.
+func (r *Redactor) Audit() []string {
+	out := make([]string, 0, len(r.rules))
+	for _, rule := range r.rules {
+		out = append(out, fmt.Sprintf("%s.%s=%s", rule.Target, rule.Field, rule.Action))
+	}
+	return out
+}
+
+// Transfer redacts one transfer row in place.
+func (r *Redactor) Transfer(t *models.Transfer) {
+	for _, rule := range r.rules {
+		if rule.Target != "transfer" {
+			continue
+		}
+		switch rule.Field {
+		case "source":
+			t.Source = r.apply(rule.Action, t.Source)
+		case "destination":
+			t.Destination = r.apply(rule.Action, t.Destination)
+		}
+	}
+}
+
+// Transaction redacts one transaction row in place.
+func (r *Redactor) Transaction(tx *models.Transaction) {
+	for _, rule := range r.rules {
+		if rule.Target == "transaction" && rule.Field == "signer" {
+			tx.Signer = r.apply(rule.Action, tx.Signer)
+		}
+	}
+}
+
+// Event redacts one event's decoded data in place, recording the
+// touched keys under data._redacted as an in-band audit trail.
+func (r *Redactor) Event(e *models.Event) {
+	if e.Data == nil {
+		return
+	}
+	var touched []string
+	for _, rule := range r.rules {
+		if rule.Target != "event" {
+			continue
+		}
+		key := strings.TrimPrefix(rule.Field, "data.")
+		value, ok := e.Data[key]
+		if !ok {
+			continue
+		}
+		if rule.Action == ActionDrop {
+			delete(e.Data, key)
+		} else {
+			e.Data[key] = r.apply(ActionHash, fmt.Sprint(value))
+		}
+		touched = append(touched, key)
+	}
+	if touched != nil {
+		e.Data["_redacted"] = touched
+	}
+}
+
+func (r *Redactor) apply(action, value string) string {
+	if value == "" || action == ActionDrop {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(r.salt + value))
+	return "redacted:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,266 @@
+// Package repair converges partially indexed blocks to complete. Slots
+// whose blocks were stored partial are queued with attempt counts and
+// exponential backoff, persisted in the repair_queue custom table so the
+// queue survives restarts, and periodically re-processed until the
+// stored block is no longer partial.
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// queueTable is the custom table queue entries are persisted in. State
+// updates are plain inserts; the newest row per slot wins on reload.
+const queueTable = "repair_queue"
+
+// checkInterval is how often due entries are retried.
+const checkInterval = time.Minute
+
+// Backoff between attempts: baseBackoff doubled per attempt up to
+// maxBackoff; after maxAttempts the slot is abandoned and surfaces only
+// through the stored partial block.
+const (
+	baseBackoff = time.Minute
+	maxBackoff  = time.Hour
+	maxAttempts = 20
+)
+
+// Entry states.
+const (
+	StatePending   = "pending"
+	StateRepaired  = "repaired"
+	StateAbandoned = "abandoned"
+)
+
+// Entry is one queued slot's repair state.
+type Entry struct {
+	Slot        uint64    `json:"slot"`
+	Attempts    int       `json:"attempts"`
+	State       string    `json:"state"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+}
+
+// Worker retries partial slots. process re-indexes one slot and is
+// normally the indexer's ProcessSlot; with the partial-replacement logic
+// in the indexer, a successful re-process replaces the partial block.
+type Worker struct {
+	store   storage.Store
+	process func(ctx context.Context, slot uint64) error
+	log     *slog.Logger
+
+	mu      sync.Mutex
+	entries map[uint64]*Entry
+}
+
+// New creates a Worker over the shared store.
+func New(store storage.Store, process func(ctx context.Context, slot uint64) error, log *slog.Logger) *Worker {
+	return &Worker{
+		store:   store,
+		process: process,
+		log:     log,
+		entries: make(map[uint64]*Entry),
+	}
+}
+
+// Enqueue schedules a slot for repair. The indexer calls it for every
+// block stored partial; re-enqueueing a known slot is a no-op.
+func (w *Worker) Enqueue(slot uint64) {
+	w.mu.Lock()
+	if e, ok := w.entries[slot]; ok && e.State == StatePending {
+		w.mu.Unlock()
+		return
+	}
+	e := &Entry{Slot: slot, State: StatePending, NextAttempt: time.Now().Add(baseBackoff)}
+	w.entries[slot] = e
+	w.mu.Unlock()
+	w.save(context.Background(), e)
+}
+
+// Run loads the persisted queue, then retries due entries until ctx is
+// canceled.
+func (w *Worker) Run(ctx context.Context) {
+	if err := w.load(ctx); err != nil {
+		w.log.Warn("repair queue not loaded", "error", err)
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, e := range w.due() {
+				if ctx.Err() != nil {
+					return
+				}
+				w.attempt(ctx, e)
+			}
+		}
+	}
+}
+
+// RepairNow retries one slot immediately, queueing it first if unknown.
+// It reports whether the stored block is complete afterwards. The API's
+// repair endpoint calls it synchronously.
+func (w *Worker) RepairNow(ctx context.Context, slot uint64) (bool, error) {
+	w.mu.Lock()
+	e, ok := w.entries[slot]
+	if !ok {
+		e = &Entry{Slot: slot, State: StatePending}
+		w.entries[slot] = e
+	}
+	e.State = StatePending
+	w.mu.Unlock()
+	return w.attempt(ctx, e)
+}
+
+// Snapshot lists the queue, pending first, then by slot.
+func (w *Worker) Snapshot() []Entry {
+	w.mu.Lock()
+	out := make([]Entry, 0, len(w.entries))
+	for _, e := range w.entries {
+		out = append(out, *e)
+	}
+	w.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].State == StatePending) != (out[j].State == StatePending) {
+			return out[i].State == StatePending
+		}
+		return out[i].Slot < out[j].Slot
+	})
+	return out
+}
+
+// due returns pending entries whose backoff has elapsed.
+func (w *Worker) due() []*Entry {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []*Entry
+	for _, e := range w.entries {
+		if e.State == StatePending && !e.NextAttempt.After(now) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// attempt re-processes the slot and checks whether the stored block is
+// still partial, updating the entry's state either way.
+func (w *Worker) attempt(ctx context.Context, e *Entry) (bool, error) {
+	err := w.process(ctx, e.Slot)
+	repaired := false
+	if err == nil {
+		if block, getErr := w.store.GetBlock(ctx, e.Slot); getErr == nil {
+			repaired = !block.Partial
+		} else if errors.Is(getErr, storage.ErrNotFound) {
+			// The slot was skipped by the cluster; nothing to repair.
+			repaired = true
+		}
+	}
+
+	w.mu.Lock()
+	e.Attempts++
+	switch {
+	case repaired:
+		e.State = StateRepaired
+		e.NextAttempt = time.Time{}
+	case e.Attempts >= maxAttempts:
+		e.State = StateAbandoned
+		e.NextAttempt = time.Time{}
+	default:
+		backoff := baseBackoff << e.Attempts
+		if backoff > maxBackoff || backoff <= 0 {
+			backoff = maxBackoff
+		}
+		e.NextAttempt = time.Now().Add(backoff)
+	}
+	saved := *e
+	w.mu.Unlock()
+
+	switch {
+	case repaired:
+		w.log.Info("slot repaired", "slot", e.Slot, "attempts", saved.Attempts)
+	case saved.State == StateAbandoned:
+		w.log.Warn("slot repair abandoned", "slot", e.Slot, "attempts", saved.Attempts)
+	default:
+		w.log.Debug("slot repair attempt failed", "slot", e.Slot,
+			"attempts", saved.Attempts, "error", err)
+	}
+	w.save(ctx, &saved)
+	return repaired, err
+}
+
+// save appends the entry's current state to the queue table.
+func (w *Worker) save(ctx context.Context, e *Entry) {
+	if err := w.store.EnsureCustomTable(ctx, queueTable); err != nil {
+		w.log.Warn("repair queue not persisted", "error", err)
+		return
+	}
+	if err := w.store.InsertCustomRow(ctx, queueTable, map[string]any{
+		"slot":       e.Slot,
+		"attempts":   e.Attempts,
+		"state":      e.State,
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		w.log.Warn("repair queue not persisted", "slot", e.Slot, "error", err)
+	}
+}
+
+// load reduces the persisted rows to the newest state per slot; repaired
+// and abandoned slots stay for Snapshot but are never retried.
+func (w *Worker) load(ctx context.Context) error {
+	rows, err := w.store.ListCustomRows(ctx, queueTable, 0, 0)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("list repair queue: %w", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, row := range rows {
+		slot := numeric(row["slot"])
+		e := w.entries[slot]
+		if e == nil {
+			e = &Entry{Slot: slot, State: StatePending}
+			w.entries[slot] = e
+		}
+		if attempts := int(numeric(row["attempts"])); attempts > e.Attempts {
+			e.Attempts = attempts
+		}
+		if state, ok := row["state"].(string); ok && state != "" {
+			e.State = state
+		}
+		if e.State == StatePending {
+			backoff := baseBackoff << e.Attempts
+			if backoff > maxBackoff || backoff <= 0 {
+				backoff = maxBackoff
+			}
+			e.NextAttempt = time.Now().Add(backoff)
+		}
+	}
+	return nil
+}
+
+// numeric decodes a custom-table number; rows reloaded through JSON
+// carry numbers as float64.
+func numeric(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		return uint64(n)
+	case float64:
+		return uint64(n)
+	}
+	return 0
+}
@@ -0,0 +1,325 @@
+// Package sched runs recurring maintenance tasks — cold tier sweeps,
+// digest warming, snapshots — on cron schedules instead of one ad-hoc
+// ticker goroutine per task. A task never overlaps itself: a firing
+// that lands while the previous run is still going is skipped. Recent
+// runs are kept per task for inspection and tasks can be triggered
+// manually through the API.
+package sched
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historyKeep is how many finished runs are kept per task.
+const historyKeep = 20
+
+// Triggers recorded on a run.
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// ErrNotFound is returned for unknown task names.
+var ErrNotFound = errors.New("sched: not found")
+
+// ErrRunning is returned when a manual trigger hits a task that is
+// already running.
+var ErrRunning = errors.New("sched: already running")
+
+// Run is the record of one task execution.
+type Run struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Trigger    string    `json:"trigger"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// TaskStatus is one task's schedule and recent history.
+type TaskStatus struct {
+	Name    string    `json:"name"`
+	Spec    string    `json:"spec"`
+	NextRun time.Time `json:"next_run"`
+	Running bool      `json:"running"`
+	Runs    []Run     `json:"runs,omitempty"` // newest first
+}
+
+type task struct {
+	name    string
+	spec    string
+	cron    cronSpec
+	fn      func(context.Context) error
+	running bool
+	next    time.Time
+	history []Run
+}
+
+// Scheduler fires registered tasks on their cron schedules.
+type Scheduler struct {
+	log *slog.Logger
+	// overrides replaces built-in specs per task name at Add time.
+	overrides map[string]string
+
+	mu    sync.Mutex
+	tasks map[string]*task
+}
+
+// New creates a Scheduler. overrides maps task names to cron specs that
+// replace the defaults passed to Add.
+func New(overrides map[string]string, log *slog.Logger) *Scheduler {
+	return &Scheduler{log: log, overrides: overrides, tasks: make(map[string]*task)}
+}
+
+// Add registers a task under a five-field cron spec (minute, hour, day
+// of month, month, day of week). It is intended for wiring at startup
+// and returns an error only for a malformed spec.
+func (s *Scheduler) Add(name, spec string, fn func(context.Context) error) error {
+	if override, ok := s.overrides[name]; ok {
+		spec = override
+	}
+	cron, err := parseCron(spec)
+	if err != nil {
+		return fmt.Errorf("sched: task %q: %w", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[name]; ok {
+		return fmt.Errorf("sched: duplicate task %q", name)
+	}
+	s.tasks[name] = &task{
+		name: name,
+		spec: spec,
+		cron: cron,
+		fn:   fn,
+		next: cron.next(time.Now()),
+	}
+	return nil
+}
+
+// Run fires due tasks until ctx is canceled, checking once per minute.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.fireDue(ctx, now)
+		}
+	}
+}
+
+// fireDue starts every due, non-running task and advances its next
+// firing. Running tasks just advance: the overlapping firing is skipped.
+func (s *Scheduler) fireDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tasks {
+		if now.Before(t.next) {
+			continue
+		}
+		t.next = t.cron.next(now)
+		if t.running {
+			s.log.Warn("scheduled task still running, skipping firing", "task", t.name)
+			continue
+		}
+		t.running = true
+		go s.execute(ctx, t, TriggerSchedule)
+	}
+}
+
+// Trigger runs a task immediately. It returns ErrRunning instead of
+// stacking a second execution on a busy task.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[name]
+	if !ok {
+		return ErrNotFound
+	}
+	if t.running {
+		return ErrRunning
+	}
+	t.running = true
+	go s.execute(ctx, t, TriggerManual)
+	return nil
+}
+
+// execute runs the task and records the run. Callers have already set
+// t.running under the lock.
+func (s *Scheduler) execute(ctx context.Context, t *task, trigger string) {
+	run := Run{StartedAt: time.Now().UTC(), Trigger: trigger}
+	err := t.fn(ctx)
+	run.FinishedAt = time.Now().UTC()
+	if err != nil {
+		run.Error = err.Error()
+		s.log.Error("scheduled task failed", "task", t.name, "trigger", trigger, "error", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.running = false
+	t.history = append([]Run{run}, t.history...)
+	if len(t.history) > historyKeep {
+		t.history = t.history[:historyKeep]
+	}
+}
+
+// Snapshot returns every task's status, sorted by name.
+func (s *Scheduler) Snapshot() []TaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, TaskStatus{
+			Name:    t.name,
+			Spec:    t.spec,
+			NextRun: t.next,
+			Running: t.running,
+			Runs:    append([]Run(nil), t.history...),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// cronSpec is a parsed five-field cron expression, one bitmask per
+// field.
+type cronSpec struct {
+	minute uint64 // 0-59
+	hour   uint64 // 0-23
+	dom    uint64 // 1-31
+	month  uint64 // 1-12
+	dow    uint64 // 0-6, Sunday = 0
+	// domStar/dowStar record whether the field was "*": per cron
+	// convention, when both day fields are restricted a day matches if
+	// either does.
+	domStar, dowStar bool
+}
+
+// parseCron parses "minute hour dom month dow" with *, lists, ranges,
+// and steps (e.g. "*/5 2-4 1,15 * 0").
+func parseCron(spec string) (cronSpec, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("want 5 fields, got %d", len(fields))
+	}
+	var (
+		c   cronSpec
+		err error
+	)
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return cronSpec{}, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return cronSpec{}, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return cronSpec{}, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return cronSpec{}, fmt.Errorf("month: %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 6); err != nil {
+		return cronSpec{}, fmt.Errorf("day of week: %w", err)
+	}
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+	return c, nil
+}
+
+// parseCronField builds the bitmask for one comma-separated field.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepStr, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			step = n
+			part = base
+		}
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			loStr, hiStr, _ := strings.Cut(part, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("bad range %q", part)
+			}
+			if hi, err = strconv.Atoi(hiStr); err != nil {
+				return 0, fmt.Errorf("bad range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			lo, hi = n, n
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d: %q", min, max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	if mask == 0 {
+		return 0, errors.New("empty field")
+	}
+	return mask, nil
+}
+
+// next returns the first firing strictly after t, stepping minutes and
+// skipping whole non-matching days.
+func (c cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Bounded: every spec matches at least one minute per matching day
+	// and one day per matching month within four years.
+	limit := t.AddDate(4, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, 1, 0)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return limit
+}
+
+// dayMatches applies cron's day rule: with both day fields restricted,
+// either matching suffices; otherwise the restricted one decides.
+func (c cronSpec) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domStar:
+		return dowOK
+	case c.dowStar:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Registrar publishes the canonical schemas to a Confluent-compatible
+// schema registry over its REST API.
+type Registrar struct {
+	baseURL   string
+	namespace string
+	httpc     *http.Client
+	log       *slog.Logger
+}
+
+// NewRegistrar creates a Registrar for the registry at baseURL.
+func NewRegistrar(baseURL string, log *slog.Logger) *Registrar {
+	return &Registrar{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpc:   &http.Client{Timeout: 10 * time.Second},
+		log:     log,
+	}
+}
+
+// SetNamespace prefixes every subject with ns + ".", matching the topic
+// naming of deployments whose Kafka bridge shares a registry across
+// indexers. Empty keeps the canonical subjects.
+func (r *Registrar) SetNamespace(ns string) { r.namespace = ns }
+
+// RegisterAll publishes every schema from All. Registration is
+// idempotent: posting an already-registered document returns its
+// existing ID.
+func (r *Registrar) RegisterAll(ctx context.Context) error {
+	for _, s := range All() {
+		if r.namespace != "" {
+			s.Subject = r.namespace + "." + s.Subject
+		}
+		id, err := r.register(ctx, s)
+		if err != nil {
+			return fmt.Errorf("register %s: %w", s.Subject, err)
+		}
+		r.log.Info("schema registered", "subject", s.Subject, "version", s.Version, "id", id)
+	}
+	return nil
+}
+
+// register posts one schema under its subject and returns the registry's
+// schema ID.
+func (r *Registrar) register(ctx context.Context, s Schema) (int, error) {
+	doc, err := json.Marshal(s.Doc)
+	if err != nil {
+		return 0, err
+	}
+	body, err := json.Marshal(map[string]string{
+		"schemaType": "JSON",
+		"schema":     string(doc),
+	})
+	if err != nil {
+		return 0, err
+	}
+	url := fmt.Sprintf("%s/subjects/%s/versions", r.baseURL, s.Subject)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	resp, err := r.httpc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return 0, fmt.Errorf("registry returned %d: %s", resp.StatusCode, raw)
+	}
+	var out struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
@@ -0,0 +1,101 @@
+// Package schema holds the canonical JSON Schemas of the event shapes
+// the decoders emit. The documents are the contract downstream consumers
+// validate against: they are versioned explicitly, served over the API,
+// and optionally published to a Confluent-compatible schema registry so
+// pipelines that bridge the outbox into Kafka get the same guarantees.
+//
+// Bump a schema's Version whenever its document changes shape; published
+// registry subjects are append-only.
+package schema
+
+// Schema is one versioned subject.
+type Schema struct {
+	Subject string         `json:"subject"`
+	Version int            `json:"version"`
+	Doc     map[string]any `json:"schema"`
+}
+
+// All returns every canonical schema, envelope first.
+func All() []Schema {
+	return []Schema{
+		{Subject: "indexer.event", Version: 1, Doc: eventEnvelope()},
+		{Subject: "indexer.event.anchor", Version: 1, Doc: anchorData()},
+		{Subject: "indexer.event.counter", Version: 1, Doc: counterData()},
+		{Subject: "indexer.event.provider", Version: 1, Doc: providerData()},
+	}
+}
+
+// eventEnvelope describes models.Event, the envelope every decoded
+// event travels in regardless of which decoder produced it.
+func eventEnvelope() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     "indexer.event.v1",
+		"title":   "Decoded event envelope",
+		"type":    "object",
+		"properties": map[string]any{
+			"signature":  map[string]any{"type": "string"},
+			"slot":       map[string]any{"type": "integer", "minimum": 0},
+			"block_time": map[string]any{"type": "string", "format": "date-time"},
+			"program":    map[string]any{"type": "string"},
+			"type":       map[string]any{"type": "string"},
+			"data":       map[string]any{"type": "object"},
+		},
+		"required": []any{"signature", "slot", "block_time", "program", "type"},
+	}
+}
+
+// anchorData describes the data field of Anchor log events. Unknown
+// discriminators are still emitted, so payload stays opaque base64.
+func anchorData() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     "indexer.event.anchor.v1",
+		"title":   "Anchor event data",
+		"type":    "object",
+		"properties": map[string]any{
+			"discriminator": map[string]any{
+				"type":        "string",
+				"pattern":     "^[0-9a-f]{16}$",
+				"description": "hex-encoded 8-byte event discriminator",
+			},
+			"payload": map[string]any{
+				"type":            "string",
+				"contentEncoding": "base64",
+				"description":     "event body after the discriminator",
+			},
+		},
+		"required": []any{"discriminator", "payload"},
+	}
+}
+
+// counterData describes the data field of the counter program's
+// counter_incremented, counter_decremented, and counter_reset events.
+func counterData() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     "indexer.event.counter.v1",
+		"title":   "Counter event data",
+		"type":    "object",
+		"properties": map[string]any{
+			"value": map[string]any{"type": "integer", "minimum": 0},
+		},
+		"required": []any{"value"},
+	}
+}
+
+// providerData describes the data field of events decoded through an
+// enhanced RPC provider's parse API.
+func providerData() map[string]any {
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     "indexer.event.provider.v1",
+		"title":   "Provider-parsed event data",
+		"type":    "object",
+		"properties": map[string]any{
+			"provider":    map[string]any{"type": "string"},
+			"description": map[string]any{"type": "string"},
+		},
+		"required": []any{"provider"},
+	}
+}
@@ -0,0 +1,184 @@
+// Package screening checks every transaction party against pluggable
+// compliance screeners. A match is recorded as a stored event and pushed
+// through the normal event pipeline, so alert rules and notifier
+// channels can act on it. The built-in screener is an address denylist
+// loaded from a file or HTTP endpoint; compliance teams plug in their
+// providers by implementing Screener.
+package screening
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// Screener flags addresses. Implementations must be safe for concurrent
+// use by the indexer workers.
+type Screener interface {
+	// Name identifies the screener in emitted events.
+	Name() string
+	// Flagged reports whether the address is flagged and why.
+	Flagged(address string) (reason string, ok bool)
+}
+
+// Engine runs every registered screener over each transaction's account
+// keys. It is wired as a transaction inspector on the indexer.
+type Engine struct {
+	store     storage.Store
+	publish   func(models.Event)
+	log       *slog.Logger
+	screeners []Screener
+}
+
+// NewEngine creates an Engine; screeners are attached with Add.
+func NewEngine(store storage.Store, publish func(models.Event), log *slog.Logger) *Engine {
+	return &Engine{store: store, publish: publish, log: log}
+}
+
+// Add registers a screener.
+func (e *Engine) Add(s Screener) { e.screeners = append(e.screeners, s) }
+
+// Inspect screens every account key of the transaction and records one
+// screening_match event per flagged party.
+func (e *Engine) Inspect(tc *processor.TxContext) {
+	for _, address := range tc.Tx.Transaction.Message.AccountKeys {
+		for _, screener := range e.screeners {
+			reason, ok := screener.Flagged(address)
+			if !ok {
+				continue
+			}
+			event := models.Event{
+				Signature: tc.Signature,
+				Slot:      tc.Slot,
+				BlockTime: tc.BlockTime,
+				Program:   "screening",
+				Type:      "screening_match",
+				Data: map[string]any{
+					"address":  address,
+					"screener": screener.Name(),
+					"reason":   reason,
+				},
+			}
+			if err := e.store.SaveEvent(context.Background(), &event); err != nil {
+				e.log.Error("screening match not stored",
+					"address", address, "signature", tc.Signature, "error", err)
+			}
+			if e.publish != nil {
+				e.publish(event)
+			}
+		}
+	}
+}
+
+// denylistRefresh is how often a URL-backed denylist is re-fetched.
+const denylistRefresh = 5 * time.Minute
+
+// Denylist is the built-in Screener: a set of flagged addresses loaded
+// from a file or an HTTP endpoint serving one "address[,reason]" entry
+// per line ("#" starts a comment).
+type Denylist struct {
+	source string
+	httpc  *http.Client
+
+	mu      sync.RWMutex
+	entries map[string]string // address -> reason
+}
+
+// NewDenylist loads the denylist from source (a path or http(s) URL).
+func NewDenylist(source string) (*Denylist, error) {
+	d := &Denylist{source: source, httpc: &http.Client{Timeout: 30 * time.Second}}
+	if err := d.reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Name implements Screener.
+func (d *Denylist) Name() string { return "denylist" }
+
+// Flagged implements Screener.
+func (d *Denylist) Flagged(address string) (string, bool) {
+	d.mu.RLock()
+	reason, ok := d.entries[address]
+	d.mu.RUnlock()
+	return reason, ok
+}
+
+// Len reports the number of listed addresses.
+func (d *Denylist) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.entries)
+}
+
+// Run periodically re-fetches URL-backed lists until ctx ends, so
+// upstream additions take effect without a restart. File-backed lists
+// are also re-read, which is cheap.
+func (d *Denylist) Run(ctx context.Context, log *slog.Logger) {
+	ticker := time.NewTicker(denylistRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.reload(); err != nil {
+				log.Warn("denylist refresh failed", "source", d.source, "error", err)
+			}
+		}
+	}
+}
+
+// reload replaces the entry set from the source.
+func (d *Denylist) reload() error {
+	var r io.ReadCloser
+	if strings.HasPrefix(d.source, "http://") || strings.HasPrefix(d.source, "https://") {
+		resp, err := d.httpc.Get(d.source)
+		if err != nil {
+			return fmt.Errorf("fetch denylist: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("fetch denylist: status %d", resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(d.source)
+		if err != nil {
+			return fmt.Errorf("open denylist: %w", err)
+		}
+		r = f
+	}
+	defer r.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		address, reason, _ := strings.Cut(line, ",")
+		if address = strings.TrimSpace(address); address != "" {
+			entries[address] = strings.TrimSpace(reason)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read denylist: %w", err)
+	}
+	d.mu.Lock()
+	d.entries = entries
+	d.mu.Unlock()
+	return nil
+}
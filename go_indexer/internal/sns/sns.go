@@ -0,0 +1,180 @@
+// Package sns maintains domain↔address mappings for .sol names by
+// observing Solana Name Service registrations as they are indexed. The
+// API layer resolves names through the registry so a .sol name is
+// accepted anywhere a pubkey is, and reverse lookups turn addresses
+// back into readable names. Mappings are persisted in a custom table so
+// restarts keep names registered while the indexer was running; history
+// from before the indexer started requires a backfill over the
+// registrar's transactions.
+package sns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Mainnet SNS program IDs.
+const (
+	// NameProgramID is the SPL Name Service that owns domain records.
+	NameProgramID = "namesLPneVptA9Z5rqUDD9Wg6cyz9a2Sk56Hh4vjx"
+	// RegistrarProgramID is the .sol registrar the registration flow
+	// goes through; its create instruction carries the plain domain
+	// string, which the name service's hashed records never expose.
+	RegistrarProgramID = "jCebN34bUfdeUYJT13J1yG16XWQpt5PDx6Mse9GUqhR"
+)
+
+// registrarCreateTag is the registrar's create instruction: u8 tag,
+// borsh string name, u32 space, followed by a domain owner reference in
+// the account list.
+const registrarCreateTag = 13
+
+// registrarBuyerIndex is the buyer's position in the create
+// instruction's account list.
+const registrarBuyerIndex = 6
+
+// snsTable is the custom table mappings are persisted in. Records are
+// plain inserts; the newest row per name wins on reload.
+const snsTable = "sns_names"
+
+// Registry is the in-memory domain↔address index. It is wired as a
+// transaction inspector and is safe for concurrent use.
+type Registry struct {
+	store storage.Store
+	log   *slog.Logger
+
+	mu        sync.Mutex
+	byName    map[string]string // "name.sol" -> owner address
+	byAddress map[string]string // owner address -> "name.sol"
+}
+
+// NewRegistry creates an empty registry persisting through store.
+func NewRegistry(store storage.Store, log *slog.Logger) *Registry {
+	return &Registry{
+		store:     store,
+		log:       log,
+		byName:    make(map[string]string),
+		byAddress: make(map[string]string),
+	}
+}
+
+// Load restores persisted mappings, oldest first so later registrations
+// win.
+func (r *Registry) Load(ctx context.Context) error {
+	if err := r.store.EnsureCustomTable(ctx, snsTable); err != nil {
+		return fmt.Errorf("sns: ensure table: %w", err)
+	}
+	rows, err := r.store.ListCustomRows(ctx, snsTable, 0, 0)
+	if err != nil {
+		return fmt.Errorf("sns: load: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(rows) - 1; i >= 0; i-- {
+		name, _ := rows[i]["name"].(string)
+		owner, _ := rows[i]["owner"].(string)
+		if name == "" || owner == "" {
+			continue
+		}
+		r.byName[name] = owner
+		r.byAddress[owner] = name
+	}
+	return nil
+}
+
+// Observe records domain registrations from registrar create
+// instructions.
+func (r *Registry) Observe(tc *processor.TxContext) {
+	if tc.Tx.Meta != nil && !tc.Tx.Meta.Succeeded() {
+		return
+	}
+	msg := &tc.Tx.Transaction.Message
+	for _, ix := range msg.Instructions {
+		if msg.ProgramID(ix) != RegistrarProgramID {
+			continue
+		}
+		data, err := solana.Base58Decode(ix.Data)
+		if err != nil || len(data) < 5 || data[0] != registrarCreateTag {
+			continue
+		}
+		name, ok := borshString(data[1:])
+		if !ok || len(ix.Accounts) <= registrarBuyerIndex {
+			continue
+		}
+		owner := ""
+		if idx := ix.Accounts[registrarBuyerIndex]; idx < len(msg.AccountKeys) {
+			owner = msg.AccountKeys[idx]
+		}
+		if owner == "" {
+			continue
+		}
+		r.record(name+".sol", owner, tc.Slot)
+	}
+}
+
+// record updates both directions and persists the mapping.
+func (r *Registry) record(name, owner string, slot uint64) {
+	r.mu.Lock()
+	if prev, ok := r.byName[name]; ok && prev == owner {
+		r.mu.Unlock()
+		return
+	}
+	r.byName[name] = owner
+	r.byAddress[owner] = name
+	r.mu.Unlock()
+
+	if err := r.store.InsertCustomRow(context.Background(), snsTable, map[string]any{
+		"name":          name,
+		"owner":         owner,
+		"slot":          slot,
+		"registered_at": time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		r.log.Warn("sns mapping not persisted", "name", name, "error", err)
+	}
+}
+
+// Resolve maps a .sol name to its owner address. The name may be given
+// with or without the .sol suffix.
+func (r *Registry) Resolve(name string) (string, bool) {
+	if !strings.HasSuffix(name, ".sol") {
+		name += ".sol"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	owner, ok := r.byName[name]
+	return owner, ok
+}
+
+// ReverseLookup maps an address back to its .sol name.
+func (r *Registry) ReverseLookup(address string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name, ok := r.byAddress[address]
+	return name, ok
+}
+
+// borshString reads a u32-length-prefixed string, rejecting anything
+// that does not look like a domain label.
+func borshString(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	n := int(uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24)
+	if n == 0 || n > 64 || len(data) < 4+n {
+		return "", false
+	}
+	s := string(data[4 : 4+n])
+	for _, c := range s {
+		if c < '!' || c > '~' {
+			return "", false
+		}
+	}
+	return s, true
+}
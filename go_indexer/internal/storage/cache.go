@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// transferColumns holds recent transfers column-wise. Hot dashboard
+// queries scan one contiguous slice per predicate column instead of
+// chasing per-row pointers, and the layout makes the memory ceiling
+// cheap to account.
+type transferColumns struct {
+	signatures   []string
+	slots        []uint64
+	blockTimes   []int64 // unix nanoseconds
+	sources      []string
+	destinations []string
+	mints        []string
+	amounts      []uint64
+	decimals     []uint8
+}
+
+func (c *transferColumns) append(t *models.Transfer) {
+	c.signatures = append(c.signatures, t.Signature)
+	c.slots = append(c.slots, t.Slot)
+	c.blockTimes = append(c.blockTimes, t.BlockTime.UnixNano())
+	c.sources = append(c.sources, t.Source)
+	c.destinations = append(c.destinations, t.Destination)
+	c.mints = append(c.mints, t.Mint)
+	c.amounts = append(c.amounts, t.Amount)
+	c.decimals = append(c.decimals, t.Decimals)
+}
+
+func (c *transferColumns) row(i int) models.Transfer {
+	return models.Transfer{
+		Signature:   c.signatures[i],
+		Slot:        c.slots[i],
+		BlockTime:   time.Unix(0, c.blockTimes[i]),
+		Source:      c.sources[i],
+		Destination: c.destinations[i],
+		Mint:        c.mints[i],
+		Amount:      c.amounts[i],
+		Decimals:    c.decimals[i],
+	}
+}
+
+func (c *transferColumns) len() int { return len(c.slots) }
+
+// dropFront removes the first n rows, re-slicing in place; the backing
+// arrays are reclaimed when the columns next grow.
+func (c *transferColumns) dropFront(n int) {
+	c.signatures = c.signatures[n:]
+	c.slots = c.slots[n:]
+	c.blockTimes = c.blockTimes[n:]
+	c.sources = c.sources[n:]
+	c.destinations = c.destinations[n:]
+	c.mints = c.mints[n:]
+	c.amounts = c.amounts[n:]
+	c.decimals = c.decimals[n:]
+}
+
+// rowBytes approximates one row's resident size for ceiling enforcement.
+func (c *transferColumns) rowBytes(i int) int {
+	return 64 + len(c.signatures[i]) + len(c.sources[i]) +
+		len(c.destinations[i]) + len(c.mints[i])
+}
+
+// CachedStore wraps a Store with an in-process cache of the most recent
+// slots: blocks by slot plus transfers in columnar form. Queries whose
+// slot range falls entirely inside the cached window are served from
+// memory; everything else passes through. A detected reorg invalidates
+// the whole cache, and both a slot-count window and a byte ceiling bound
+// its footprint.
+type CachedStore struct {
+	Store
+
+	retainSlots uint64
+	maxBytes    int
+
+	mu       sync.RWMutex
+	blocks   map[uint64]models.Block
+	cols     transferColumns
+	colBytes int
+	// minSlot/maxSlot bound the contiguously cached window; zero means
+	// the cache is cold.
+	minSlot uint64
+	maxSlot uint64
+}
+
+// NewCachedStore wraps inner, keeping the last retainSlots slots of
+// blocks and transfers in memory, bounded by maxBytes.
+func NewCachedStore(inner Store, retainSlots uint64, maxBytes int) *CachedStore {
+	return &CachedStore{
+		Store:       inner,
+		retainSlots: retainSlots,
+		maxBytes:    maxBytes,
+		blocks:      make(map[uint64]models.Block),
+	}
+}
+
+// SaveBlock implements Store, admitting the block into the cache.
+func (c *CachedStore) SaveBlock(ctx context.Context, block *models.Block) error {
+	if err := c.Store.SaveBlock(ctx, block); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks[block.Slot] = *block
+	if c.minSlot == 0 || block.Slot < c.minSlot {
+		c.minSlot = block.Slot
+	}
+	if block.Slot > c.maxSlot {
+		c.maxSlot = block.Slot
+	}
+	c.evictLocked()
+	return nil
+}
+
+// SaveTransfer implements Store, appending the row to the columns.
+func (c *CachedStore) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	if err := c.Store.SaveTransfer(ctx, transfer); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.cols.append(transfer)
+	c.colBytes += c.cols.rowBytes(c.cols.len() - 1)
+	c.evictLocked()
+	c.mu.Unlock()
+	return nil
+}
+
+// GetBlock implements Store, serving cached slots without touching the
+// database.
+func (c *CachedStore) GetBlock(ctx context.Context, slot uint64) (*models.Block, error) {
+	c.mu.RLock()
+	block, ok := c.blocks[slot]
+	c.mu.RUnlock()
+	if ok {
+		return &block, nil
+	}
+	return c.Store.GetBlock(ctx, slot)
+}
+
+// LatestSlot implements Store.
+func (c *CachedStore) LatestSlot(ctx context.Context) (uint64, error) {
+	c.mu.RLock()
+	maxSlot := c.maxSlot
+	c.mu.RUnlock()
+	if maxSlot > 0 {
+		return maxSlot, nil
+	}
+	return c.Store.LatestSlot(ctx)
+}
+
+// ListTransfers implements Store, answering from the columns when the
+// requested slot range lies entirely inside the cached window.
+func (c *CachedStore) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	c.mu.RLock()
+	if c.minSlot == 0 || filter.FromSlot == 0 || filter.FromSlot < c.minSlot {
+		c.mu.RUnlock()
+		return c.Store.ListTransfers(ctx, filter)
+	}
+	var out []models.Transfer
+	for i := 0; i < c.cols.len(); i++ {
+		if filter.FromSlot > 0 && c.cols.slots[i] < filter.FromSlot {
+			continue
+		}
+		if filter.ToSlot > 0 && c.cols.slots[i] > filter.ToSlot {
+			continue
+		}
+		if filter.Address != "" && c.cols.sources[i] != filter.Address &&
+			c.cols.destinations[i] != filter.Address {
+			continue
+		}
+		if filter.Mint != "" && c.cols.mints[i] != filter.Mint {
+			continue
+		}
+		out = append(out, c.cols.row(i))
+	}
+	c.mu.RUnlock()
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// MarkBlocksOrphaned implements Store. A reorg makes cached rows for
+// the affected range stale, so the whole cache is invalidated; it warms
+// back up within one retention window.
+func (c *CachedStore) MarkBlocksOrphaned(ctx context.Context, afterSlot, throughSlot uint64) ([]string, error) {
+	hashes, err := c.Store.MarkBlocksOrphaned(ctx, afterSlot, throughSlot)
+	if err != nil {
+		return nil, err
+	}
+	c.invalidate()
+	return hashes, nil
+}
+
+// ReplaceBlock implements Store.
+func (c *CachedStore) ReplaceBlock(ctx context.Context, block *models.Block) error {
+	if err := c.Store.ReplaceBlock(ctx, block); err != nil {
+		return err
+	}
+	c.invalidate()
+	return nil
+}
+
+// invalidate drops everything cached.
+func (c *CachedStore) invalidate() {
+	c.mu.Lock()
+	c.blocks = make(map[uint64]models.Block)
+	c.cols = transferColumns{}
+	c.colBytes = 0
+	c.minSlot, c.maxSlot = 0, 0
+	c.mu.Unlock()
+}
+
+// evictLocked enforces the slot window and the byte ceiling. Callers
+// hold c.mu.
+func (c *CachedStore) evictLocked() {
+	// Slot window.
+	if c.retainSlots > 0 && c.maxSlot > c.retainSlots {
+		if floor := c.maxSlot - c.retainSlots + 1; floor > c.minSlot {
+			c.dropBelowLocked(floor)
+		}
+	}
+	// Byte ceiling: shed the oldest transfer rows until under budget;
+	// block rows are small next to the transfer columns, so the columns
+	// drive the accounting.
+	if c.maxBytes <= 0 || c.colBytes <= c.maxBytes || c.cols.len() == 0 {
+		return
+	}
+	drop, bytes := 0, c.colBytes
+	for bytes > c.maxBytes && drop < c.cols.len() {
+		bytes -= c.cols.rowBytes(drop)
+		drop++
+	}
+	floor := c.maxSlot + 1 // everything shed
+	if drop < c.cols.len() {
+		// Finish the slot at the boundary so the remaining window stays
+		// complete and window-fit checks stay truthful.
+		floor = c.cols.slots[drop] + 1
+	}
+	c.dropBelowLocked(floor)
+}
+
+// dropBelowLocked evicts every cached block and transfer row with a
+// slot under floor. Callers hold c.mu.
+func (c *CachedStore) dropBelowLocked(floor uint64) {
+	for slot := c.minSlot; slot < floor; slot++ {
+		delete(c.blocks, slot)
+	}
+	drop := 0
+	for drop < c.cols.len() && c.cols.slots[drop] < floor {
+		c.colBytes -= c.cols.rowBytes(drop)
+		drop++
+	}
+	if drop > 0 {
+		c.cols.dropFront(drop)
+	}
+	c.minSlot = floor
+}
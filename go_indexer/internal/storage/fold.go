@@ -0,0 +1,69 @@
+package storage
+
+import "github.com/lugondev/go-indexer-solana-starter/internal/models"
+
+// The registry tables (programs, nonce accounts, token accounts) are
+// built by folding observed updates into one row per key. The fold rules
+// live here so every Store implementation applies the same semantics.
+
+// FoldProgramUpdate folds one BPF Loader Upgradeable action into the
+// program registry entry and appends it to the upgrade history.
+func FoldProgramUpdate(program *models.Program, update *models.ProgramUpdate) {
+	if update.ProgramData != "" {
+		program.ProgramDataAccount = update.ProgramData
+	}
+	switch update.Kind {
+	case "set_authority":
+		program.UpgradeAuthority = update.Authority
+	case "deployed", "upgraded":
+		if update.Authority != "" {
+			program.UpgradeAuthority = update.Authority
+		}
+	case "closed":
+		program.Closed = true
+	}
+	if update.Slot > program.LastUpdatedSlot {
+		program.LastUpdatedSlot = update.Slot
+	}
+	program.History = append(program.History, *update)
+}
+
+// FoldNonceUse folds one durable nonce advance into the nonce account
+// row. LastUsedSlot only moves forward; UseCount counts every use.
+func FoldNonceUse(nonce *models.NonceAccount, use *models.NonceAccount) {
+	if use.Authority != "" {
+		nonce.Authority = use.Authority
+	}
+	if use.LastUsedSlot > nonce.LastUsedSlot {
+		nonce.LastUsedSlot = use.LastUsedSlot
+		nonce.LastSignature = use.LastSignature
+	}
+	nonce.UseCount++
+}
+
+// FoldTokenAccountUpdate folds one SPL token account lifecycle action
+// into the token_accounts dimension row.
+func FoldTokenAccountUpdate(account *models.TokenAccount, update *models.TokenAccountUpdate) {
+	if update.Mint != "" {
+		account.Mint = update.Mint
+	}
+	if update.Owner != "" {
+		account.Owner = update.Owner
+	}
+	switch update.Kind {
+	case "initialized":
+		account.State = "initialized"
+		if account.CreatedSlot == 0 {
+			account.CreatedSlot = update.Slot
+		}
+	case "frozen":
+		account.State = "frozen"
+	case "thawed":
+		account.State = "initialized"
+	case "closed":
+		account.State = "closed"
+	}
+	if update.Slot > account.UpdatedSlot {
+		account.UpdatedSlot = update.Slot
+	}
+}
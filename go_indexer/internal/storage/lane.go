@@ -0,0 +1,20 @@
+package storage
+
+import "context"
+
+// The backfill lane marks its contexts so a Store can treat its writes
+// differently from the live lane's — the pgx store routes them through
+// a COPY bulk loader while the live lane keeps row inserts.
+
+type backfillLaneKey struct{}
+
+// WithBackfillLane marks ctx as carrying backfill-lane writes.
+func WithBackfillLane(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backfillLaneKey{}, true)
+}
+
+// IsBackfillLane reports whether ctx was marked by WithBackfillLane.
+func IsBackfillLane(ctx context.Context) bool {
+	ok, _ := ctx.Value(backfillLaneKey{}).(bool)
+	return ok
+}
@@ -0,0 +1,459 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// MemoryStore is an in-memory Store used for tests and for running the
+// template without a database.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	blocks        map[uint64]models.Block
+	transactions  map[string]models.Transaction
+	transfers     []models.Transfer
+	events        []models.Event
+	programs      map[string]*models.Program
+	tokenAccounts map[string]*models.TokenAccount
+	nonces        map[string]*models.NonceAccount
+	latestSlot    uint64
+
+	outbox          []models.OutboxEntry
+	outboxCursor    uint64
+	outboxDelivered uint64
+	consumerOffsets map[string]uint64
+	customTables    map[string][]map[string]any
+	reorgs          []models.Reorg
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		blocks:        make(map[uint64]models.Block),
+		transactions:  make(map[string]models.Transaction),
+		programs:      make(map[string]*models.Program),
+		tokenAccounts: make(map[string]*models.TokenAccount),
+		nonces:        make(map[string]*models.NonceAccount),
+
+		consumerOffsets: make(map[string]uint64),
+		customTables:    make(map[string][]map[string]any),
+	}
+}
+
+// SaveBlock implements Store. Re-saving an already indexed slot returns
+// ErrDuplicate without modifying the stored row.
+func (s *MemoryStore) SaveBlock(ctx context.Context, block *models.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.blocks[block.Slot]; ok {
+		return ErrDuplicate
+	}
+	s.blocks[block.Slot] = *block
+	if block.Slot > s.latestSlot {
+		s.latestSlot = block.Slot
+	}
+	return nil
+}
+
+// GetBlock implements Store.
+func (s *MemoryStore) GetBlock(ctx context.Context, slot uint64) (*models.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	block, ok := s.blocks[slot]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &block, nil
+}
+
+// LatestSlot implements Store.
+func (s *MemoryStore) LatestSlot(ctx context.Context) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latestSlot, nil
+}
+
+// SaveReorg implements Store.
+func (s *MemoryStore) SaveReorg(ctx context.Context, reorg *models.Reorg) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reorgs = append(s.reorgs, *reorg)
+	return nil
+}
+
+// ListReorgs implements Store, newest first.
+func (s *MemoryStore) ListReorgs(ctx context.Context, limit, offset int) ([]models.Reorg, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]models.Reorg, len(s.reorgs))
+	for i, r := range s.reorgs {
+		out[len(s.reorgs)-1-i] = r
+	}
+	return paginate(out, offset, limit), nil
+}
+
+// MarkBlocksOrphaned implements Store.
+func (s *MemoryStore) MarkBlocksOrphaned(ctx context.Context, afterSlot, throughSlot uint64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var hashes []string
+	for slot := afterSlot + 1; slot <= throughSlot; slot++ {
+		block, ok := s.blocks[slot]
+		if !ok || block.Orphaned {
+			continue
+		}
+		block.Orphaned = true
+		s.blocks[slot] = block
+		hashes = append(hashes, block.BlockHash)
+	}
+	return hashes, nil
+}
+
+// ReplaceBlock implements Store, overwriting any stored row for the slot.
+func (s *MemoryStore) ReplaceBlock(ctx context.Context, block *models.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks[block.Slot] = *block
+	if block.Slot > s.latestSlot {
+		s.latestSlot = block.Slot
+	}
+	return nil
+}
+
+// SaveTransaction implements Store. Re-saving an already indexed signature
+// returns ErrDuplicate without modifying the stored row.
+func (s *MemoryStore) SaveTransaction(ctx context.Context, tx *models.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.transactions[tx.Signature]; ok {
+		return ErrDuplicate
+	}
+	s.transactions[tx.Signature] = *tx
+	return nil
+}
+
+// GetTransaction implements Store.
+func (s *MemoryStore) GetTransaction(ctx context.Context, signature string) (*models.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tx, ok := s.transactions[signature]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &tx, nil
+}
+
+// SearchTransactions implements Store.
+func (s *MemoryStore) SearchTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var candidates map[string]struct{}
+	if filter.Signatures != nil {
+		candidates = make(map[string]struct{}, len(filter.Signatures))
+		for _, sig := range filter.Signatures {
+			candidates[sig] = struct{}{}
+		}
+	}
+	var out []models.Transaction
+	for _, tx := range s.transactions {
+		if candidates != nil {
+			if _, ok := candidates[tx.Signature]; !ok {
+				continue
+			}
+		}
+		if !signerMatches(filter, &tx) {
+			continue
+		}
+		if filter.Success != nil && tx.Success != *filter.Success {
+			continue
+		}
+		if filter.FromSlot > 0 && tx.Slot < filter.FromSlot {
+			continue
+		}
+		if filter.ToSlot > 0 && tx.Slot > filter.ToSlot {
+			continue
+		}
+		if !filter.FromTime.IsZero() && tx.BlockTime.Before(filter.FromTime) {
+			continue
+		}
+		if !filter.ToTime.IsZero() && tx.BlockTime.After(filter.ToTime) {
+			continue
+		}
+		out = append(out, tx)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Slot != out[j].Slot {
+			return out[i].Slot < out[j].Slot
+		}
+		return out[i].Signature < out[j].Signature
+	})
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// SaveTransfer implements Store.
+func (s *MemoryStore) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transfers = append(s.transfers, *transfer)
+	return nil
+}
+
+// ListTransfers implements Store.
+func (s *MemoryStore) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []models.Transfer
+	for _, t := range s.transfers {
+		if filter.Address != "" && t.Source != filter.Address && t.Destination != filter.Address {
+			continue
+		}
+		if filter.Mint != "" && t.Mint != filter.Mint {
+			continue
+		}
+		if filter.FromSlot > 0 && t.Slot < filter.FromSlot {
+			continue
+		}
+		if filter.ToSlot > 0 && t.Slot > filter.ToSlot {
+			continue
+		}
+		out = append(out, t)
+	}
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// SaveEvent implements Store.
+func (s *MemoryStore) SaveEvent(ctx context.Context, event *models.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, *event)
+	return nil
+}
+
+// ListEvents implements Store.
+func (s *MemoryStore) ListEvents(ctx context.Context, filter EventFilter) ([]models.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []models.Event
+	for _, e := range s.events {
+		if filter.Program != "" && e.Program != filter.Program {
+			continue
+		}
+		if filter.Type != "" && e.Type != filter.Type {
+			continue
+		}
+		if filter.FromSlot > 0 && e.Slot < filter.FromSlot {
+			continue
+		}
+		if filter.ToSlot > 0 && e.Slot > filter.ToSlot {
+			continue
+		}
+		out = append(out, e)
+	}
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// SaveProgramUpdate implements Store, folding the update into the program
+// registry entry and appending it to the upgrade history.
+func (s *MemoryStore) SaveProgramUpdate(ctx context.Context, update *models.ProgramUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	program, ok := s.programs[update.ProgramID]
+	if !ok {
+		program = &models.Program{ProgramID: update.ProgramID}
+		s.programs[update.ProgramID] = program
+	}
+	FoldProgramUpdate(program, update)
+	return nil
+}
+
+// GetProgram implements Store.
+func (s *MemoryStore) GetProgram(ctx context.Context, programID string) (*models.Program, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	program, ok := s.programs[programID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *program
+	clone.History = append([]models.ProgramUpdate(nil), program.History...)
+	return &clone, nil
+}
+
+// SaveNonceUse implements Store, recording one durable nonce advance.
+func (s *MemoryStore) SaveNonceUse(ctx context.Context, use *models.NonceAccount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nonce, ok := s.nonces[use.Address]
+	if !ok {
+		nonce = &models.NonceAccount{Address: use.Address}
+		s.nonces[use.Address] = nonce
+	}
+	FoldNonceUse(nonce, use)
+	return nil
+}
+
+// GetNonceAccount implements Store.
+func (s *MemoryStore) GetNonceAccount(ctx context.Context, address string) (*models.NonceAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	nonce, ok := s.nonces[address]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *nonce
+	return &clone, nil
+}
+
+// AppendOutbox implements Store. The memory store shares one lock across
+// all tables, so the append is atomic with the indexed rows the way a
+// database transaction would be.
+func (s *MemoryStore) AppendOutbox(ctx context.Context, event *models.Event) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outboxCursor++
+	s.outbox = append(s.outbox, models.OutboxEntry{
+		Cursor:    s.outboxCursor,
+		Event:     *event,
+		CreatedAt: time.Now().UTC(),
+	})
+	return s.outboxCursor, nil
+}
+
+// ListOutbox implements Store, returning entries with a cursor greater
+// than afterCursor in cursor order.
+func (s *MemoryStore) ListOutbox(ctx context.Context, afterCursor uint64, limit int) ([]models.OutboxEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start := sort.Search(len(s.outbox), func(i int) bool { return s.outbox[i].Cursor > afterCursor })
+	entries := s.outbox[start:]
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return append([]models.OutboxEntry(nil), entries...), nil
+}
+
+// MarkOutboxDelivered implements Store. The delivered cursor only moves
+// forward.
+func (s *MemoryStore) MarkOutboxDelivered(ctx context.Context, throughCursor uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if throughCursor > s.outboxDelivered {
+		s.outboxDelivered = throughCursor
+	}
+	return nil
+}
+
+// OutboxDeliveredCursor implements Store.
+func (s *MemoryStore) OutboxDeliveredCursor(ctx context.Context) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.outboxDelivered, nil
+}
+
+// EnsureCustomTable implements Store; the table starts empty.
+func (s *MemoryStore) EnsureCustomTable(ctx context.Context, table string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.customTables[table]; !ok {
+		s.customTables[table] = []map[string]any{}
+	}
+	return nil
+}
+
+// InsertCustomRow implements Store, creating the table on first insert.
+func (s *MemoryStore) InsertCustomRow(ctx context.Context, table string, row map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customTables[table] = append(s.customTables[table], row)
+	return nil
+}
+
+// ListCustomRows implements Store. Unknown tables return ErrNotFound.
+func (s *MemoryStore) ListCustomRows(ctx context.Context, table string, limit, offset int) ([]map[string]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows, ok := s.customTables[table]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return paginate(rows, offset, limit), nil
+}
+
+// SaveConsumerOffset implements Store.
+func (s *MemoryStore) SaveConsumerOffset(ctx context.Context, consumer string, cursor uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumerOffsets[consumer] = cursor
+	return nil
+}
+
+// GetConsumerOffset implements Store. Unknown consumers start at 0.
+func (s *MemoryStore) GetConsumerOffset(ctx context.Context, consumer string) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.consumerOffsets[consumer], nil
+}
+
+// SaveTokenAccountUpdate implements Store, folding the lifecycle action
+// into the token_accounts dimension row.
+func (s *MemoryStore) SaveTokenAccountUpdate(ctx context.Context, update *models.TokenAccountUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account, ok := s.tokenAccounts[update.Address]
+	if !ok {
+		account = &models.TokenAccount{Address: update.Address}
+		s.tokenAccounts[update.Address] = account
+	}
+	FoldTokenAccountUpdate(account, update)
+	return nil
+}
+
+// GetTokenAccount implements Store.
+func (s *MemoryStore) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	account, ok := s.tokenAccounts[address]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *account
+	return &clone, nil
+}
+
+// ListTokenAccounts implements Store.
+func (s *MemoryStore) ListTokenAccounts(ctx context.Context, filter TokenAccountFilter) ([]models.TokenAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []models.TokenAccount
+	for _, account := range s.tokenAccounts {
+		if filter.Owner != "" && account.Owner != filter.Owner {
+			continue
+		}
+		if filter.Mint != "" && account.Mint != filter.Mint {
+			continue
+		}
+		out = append(out, *account)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// Close implements Store.
+func (s *MemoryStore) Close(ctx context.Context) error { return nil }
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return nil
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Row is a single query result row, matching both database/sql and pgx.
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// RowQuerier runs a query expected to return one row.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) Row
+}
+
+// AccountDB is the database surface the account dictionary needs.
+type AccountDB interface {
+	Execer
+	RowQuerier
+}
+
+// dictCacheLimit bounds the in-process pubkey<->id caches. When either
+// side grows past it, both are reset; misses just refetch.
+const dictCacheLimit = 100_000
+
+// AccountDict interns base58 account pubkeys into the accounts dimension
+// table, so the high-volume tables store 8-byte IDs instead of repeating
+// 44-character strings. It keeps a bounded in-process cache and is safe
+// for concurrent use.
+type AccountDict struct {
+	db   AccountDB
+	mu   sync.Mutex
+	ids  map[string]int64
+	keys map[int64]string
+}
+
+// NewAccountDict creates a dictionary over db.
+func NewAccountDict(db AccountDB) *AccountDict {
+	return &AccountDict{
+		db:   db,
+		ids:  make(map[string]int64),
+		keys: make(map[int64]string),
+	}
+}
+
+// ID returns the dictionary ID for pubkey, inserting it on first sight.
+func (d *AccountDict) ID(ctx context.Context, pubkey string) (int64, error) {
+	d.mu.Lock()
+	if id, ok := d.ids[pubkey]; ok {
+		d.mu.Unlock()
+		return id, nil
+	}
+	d.mu.Unlock()
+
+	// The no-op DO UPDATE makes RETURNING yield the existing row's id
+	// on conflict, keeping the upsert a single round trip.
+	var id int64
+	row := d.db.QueryRow(ctx,
+		`INSERT INTO accounts (pubkey) VALUES ($1)
+		 ON CONFLICT (pubkey) DO UPDATE SET pubkey = EXCLUDED.pubkey
+		 RETURNING id`, pubkey)
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("intern account %s: %w", pubkey, err)
+	}
+	d.remember(pubkey, id)
+	return id, nil
+}
+
+// Pubkey resolves a dictionary ID back to its base58 pubkey, used when a
+// read path cannot go through the *_resolved views.
+func (d *AccountDict) Pubkey(ctx context.Context, id int64) (string, error) {
+	d.mu.Lock()
+	if pubkey, ok := d.keys[id]; ok {
+		d.mu.Unlock()
+		return pubkey, nil
+	}
+	d.mu.Unlock()
+
+	var pubkey string
+	row := d.db.QueryRow(ctx, `SELECT pubkey FROM accounts WHERE id = $1`, id)
+	if err := row.Scan(&pubkey); err != nil {
+		return "", fmt.Errorf("resolve account %d: %w", id, err)
+	}
+	d.remember(pubkey, id)
+	return pubkey, nil
+}
+
+func (d *AccountDict) remember(pubkey string, id int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.ids) >= dictCacheLimit {
+		d.ids = make(map[string]int64)
+		d.keys = make(map[int64]string)
+	}
+	d.ids[pubkey] = id
+	d.keys[id] = pubkey
+}
+
+// AccountDictionaryMigration upgrades a pre-dictionary database in
+// place: it creates the accounts table, backfills it from the existing
+// transfer rows, rewrites transfers to integer references, and leaves
+// the transfers_resolved view serving the old column shape.
+const AccountDictionaryMigration = `
+CREATE TABLE IF NOT EXISTS accounts (
+    id     BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    pubkey TEXT NOT NULL UNIQUE
+);
+
+INSERT INTO accounts (pubkey)
+SELECT DISTINCT source FROM transfers
+UNION
+SELECT DISTINCT destination FROM transfers
+ON CONFLICT (pubkey) DO NOTHING;
+
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS source_id BIGINT;
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS destination_id BIGINT;
+
+UPDATE transfers t
+SET source_id      = src.id,
+    destination_id = dst.id
+FROM accounts src, accounts dst
+WHERE src.pubkey = t.source
+  AND dst.pubkey = t.destination
+  AND (t.source_id IS NULL OR t.destination_id IS NULL);
+
+ALTER TABLE transfers ALTER COLUMN source_id SET NOT NULL;
+ALTER TABLE transfers ALTER COLUMN destination_id SET NOT NULL;
+ALTER TABLE transfers DROP COLUMN source;
+ALTER TABLE transfers DROP COLUMN destination;
+
+CREATE OR REPLACE VIEW transfers_resolved AS
+SELECT t.signature, t.slot, t.block_time,
+       src.pubkey AS source, dst.pubkey AS destination,
+       t.mint, t.amount, t.decimals
+FROM transfers t
+JOIN accounts src ON src.id = t.source_id
+JOIN accounts dst ON dst.id = t.destination_id;
+`
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// CopyExecer extends Execer with the COPY protocol, matching the shape of
+// pgx's CopyFrom.
+type CopyExecer interface {
+	Execer
+	CopyFrom(ctx context.Context, table string, columns []string, rows [][]any) (int64, error)
+}
+
+// BulkIndexes are the secondary indexes deliberately left out of Schema.
+// Building them once after a bulk load is far cheaper than maintaining
+// them during it; BuildIndexes runs these when the backfill completes.
+var BulkIndexes = [...]string{
+	"CREATE INDEX IF NOT EXISTS transactions_signature_idx ON transactions (signature)",
+	"CREATE INDEX IF NOT EXISTS transfers_source_idx ON transfers (source_id)",
+	"CREATE INDEX IF NOT EXISTS transfers_destination_idx ON transfers (destination_id)",
+	"CREATE INDEX IF NOT EXISTS transfers_mint_idx ON transfers (mint)",
+	"CREATE INDEX IF NOT EXISTS events_program_type_idx ON events (program, type)",
+}
+
+// BuildIndexes creates the secondary indexes, the post-backfill step of a
+// COPY-based load.
+func BuildIndexes(ctx context.Context, db Execer) error {
+	for _, stmt := range BulkIndexes {
+		if err := db.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("postgres: build index: %w", err)
+		}
+	}
+	return nil
+}
+
+// BulkLoader buffers rows per table and writes them with COPY in large
+// batches. The backfill lane routes its storage writes through a loader
+// instead of row INSERTs; Flush must be called when the lane finishes.
+type BulkLoader struct {
+	db        CopyExecer
+	batchSize int
+
+	mu      sync.Mutex
+	columns map[string][]string
+	pending map[string][][]any
+}
+
+// NewBulkLoader creates a BulkLoader flushing every batchSize rows per
+// table (default 5000).
+func NewBulkLoader(db CopyExecer, batchSize int) *BulkLoader {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	return &BulkLoader{
+		db:        db,
+		batchSize: batchSize,
+		columns:   make(map[string][]string),
+		pending:   make(map[string][][]any),
+	}
+}
+
+// Add buffers one row, flushing the table's batch when it is full. The
+// column list must be identical across calls for the same table.
+func (l *BulkLoader) Add(ctx context.Context, table string, columns []string, row []any) error {
+	l.mu.Lock()
+	if _, ok := l.columns[table]; !ok {
+		l.columns[table] = columns
+	}
+	l.pending[table] = append(l.pending[table], row)
+	var full [][]any
+	if len(l.pending[table]) >= l.batchSize {
+		full = l.pending[table]
+		l.pending[table] = nil
+	}
+	cols := l.columns[table]
+	l.mu.Unlock()
+	if full == nil {
+		return nil
+	}
+	return l.copyBatch(ctx, table, cols, full)
+}
+
+// Flush writes every buffered batch.
+func (l *BulkLoader) Flush(ctx context.Context) error {
+	l.mu.Lock()
+	batches := make(map[string][][]any, len(l.pending))
+	columns := make(map[string][]string, len(l.pending))
+	for table, rows := range l.pending {
+		if len(rows) == 0 {
+			continue
+		}
+		batches[table] = rows
+		columns[table] = l.columns[table]
+		l.pending[table] = nil
+	}
+	l.mu.Unlock()
+	for table, rows := range batches {
+		if err := l.copyBatch(ctx, table, columns[table], rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *BulkLoader) copyBatch(ctx context.Context, table string, columns []string, rows [][]any) error {
+	n, err := l.db.CopyFrom(ctx, table, columns, rows)
+	if err != nil {
+		return fmt.Errorf("postgres: copy into %s: %w", table, err)
+	}
+	if n != int64(len(rows)) {
+		return fmt.Errorf("postgres: copy into %s wrote %d of %d rows", table, n, len(rows))
+	}
+	return nil
+}
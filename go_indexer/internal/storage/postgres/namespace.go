@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// EnsureNamespace creates the deployment's Postgres schema and routes
+// the session's unqualified table names into it, so multiple indexer
+// deployments (per network or per team) share one database without
+// table collisions. Schema and its views, the partition manager, and
+// custom tables all create and resolve relations through the
+// search_path, so no DDL or query needs rewriting. Idempotent; call it
+// before Schema on every startup. The namespace must already be
+// validated (config.Load rejects anything but [a-z][a-z0-9_]*), as it
+// is interpolated into DDL.
+func EnsureNamespace(ctx context.Context, db Execer, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if err := db.Exec(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", namespace)); err != nil {
+		return fmt.Errorf("create namespace %s: %w", namespace, err)
+	}
+	if err := db.Exec(ctx, fmt.Sprintf("SET search_path TO %s, public", namespace)); err != nil {
+		return fmt.Errorf("set search_path to %s: %w", namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// partitionedTables are the tables managed by the PartitionManager.
+var partitionedTables = [...]string{"transactions", "transfers", "events"}
+
+// Execer is the subset of the database connection the partition manager
+// needs, satisfied by pgx pools and database/sql alike.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) error
+}
+
+// PartitionOptions control partition sizing and retention; zero values
+// select the defaults.
+type PartitionOptions struct {
+	// SlotsPerPartition is the width of one slot-range partition.
+	// The default of 432000 slots is roughly two days of mainnet.
+	SlotsPerPartition uint64
+	// PreCreate is how many partitions beyond the current slot to keep
+	// created ahead of the indexer.
+	PreCreate int
+	// RetainPartitions drops partitions older than this many behind the
+	// current one; 0 keeps everything.
+	RetainPartitions int
+	// Interval is how often Run performs maintenance.
+	Interval time.Duration
+}
+
+// PartitionManager creates slot-range partitions ahead of the indexer and
+// drops expired ones, keeping per-partition sizes bounded as the tables
+// grow into billions of rows.
+type PartitionManager struct {
+	db   Execer
+	opts PartitionOptions
+	log  *slog.Logger
+}
+
+// NewPartitionManager creates a PartitionManager over db.
+func NewPartitionManager(db Execer, opts PartitionOptions, log *slog.Logger) *PartitionManager {
+	if opts.SlotsPerPartition == 0 {
+		opts.SlotsPerPartition = 432000
+	}
+	if opts.PreCreate <= 0 {
+		opts.PreCreate = 2
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	return &PartitionManager{db: db, opts: opts, log: log}
+}
+
+// EnsureFor creates the partitions covering slot and the configured
+// number of partitions ahead of it, for every partitioned table.
+func (m *PartitionManager) EnsureFor(ctx context.Context, slot uint64) error {
+	start := slot - slot%m.opts.SlotsPerPartition
+	for i := 0; i <= m.opts.PreCreate; i++ {
+		from := start + uint64(i)*m.opts.SlotsPerPartition
+		to := from + m.opts.SlotsPerPartition
+		for _, table := range partitionedTables {
+			stmt := fmt.Sprintf(
+				"CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%d) TO (%d)",
+				partitionName(table, from), table, from, to)
+			if err := m.db.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("postgres: create partition %s: %w", partitionName(table, from), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DropExpired drops partitions whose entire range is more than
+// RetainPartitions partitions behind slot. It is a no-op when retention
+// is disabled.
+func (m *PartitionManager) DropExpired(ctx context.Context, slot uint64) error {
+	if m.opts.RetainPartitions <= 0 {
+		return nil
+	}
+	width := m.opts.SlotsPerPartition
+	keepBack := uint64(m.opts.RetainPartitions) * width
+	current := slot - slot%width
+	if current <= keepBack {
+		return nil
+	}
+	// Walk back a bounded window of candidate partitions; anything older
+	// was dropped on a previous pass.
+	oldest := current - keepBack
+	for i := 1; i <= m.opts.RetainPartitions+m.opts.PreCreate; i++ {
+		if oldest < uint64(i)*width {
+			break
+		}
+		from := oldest - uint64(i)*width
+		for _, table := range partitionedTables {
+			stmt := fmt.Sprintf("DROP TABLE IF EXISTS %s", partitionName(table, from))
+			if err := m.db.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("postgres: drop partition %s: %w", partitionName(table, from), err)
+			}
+		}
+	}
+	return nil
+}
+
+// Run performs partition maintenance on a timer until ctx is canceled.
+// current supplies the slot each active lane is writing at, so the
+// backfill lane's historic partitions are created alongside the live
+// head's. Retention only runs while one lane is writing: dropping
+// behind the head would destroy what the backfill lane just wrote.
+func (m *PartitionManager) Run(ctx context.Context, current func() []uint64) {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		var active []uint64
+		for _, slot := range current() {
+			if slot == 0 {
+				continue
+			}
+			active = append(active, slot)
+			if err := m.EnsureFor(ctx, slot); err != nil {
+				m.log.Error("partition creation failed", "error", err)
+			}
+		}
+		if len(active) == 1 {
+			if err := m.DropExpired(ctx, active[0]); err != nil {
+				m.log.Error("partition retention failed", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// partitionName names the child partition starting at fromSlot, e.g.
+// transfers_p000000432000.
+func partitionName(table string, fromSlot uint64) string {
+	return fmt.Sprintf("%s_p%012d", table, fromSlot)
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// PoolConfig sizes the pgx connection pool. Zero values select defaults
+// tuned for several concurrent writer goroutines plus API reads.
+type PoolConfig struct {
+	MaxConns          int           // pool_max_conns
+	MinConns          int           // pool_min_conns
+	MaxConnIdleTime   time.Duration // pool_max_conn_idle_time
+	MaxConnLifetime   time.Duration // pool_max_conn_lifetime
+	HealthCheckPeriod time.Duration // pool_health_check_period
+	// StatementCacheSize caps the per-connection prepared statement
+	// cache; 0 selects the default of 128.
+	StatementCacheSize int
+}
+
+func (c PoolConfig) withDefaults() PoolConfig {
+	if c.MaxConns <= 0 {
+		c.MaxConns = 16
+	}
+	if c.MinConns <= 0 {
+		c.MinConns = 2
+	}
+	if c.MaxConnIdleTime <= 0 {
+		c.MaxConnIdleTime = 5 * time.Minute
+	}
+	if c.MaxConnLifetime <= 0 {
+		c.MaxConnLifetime = time.Hour
+	}
+	if c.HealthCheckPeriod <= 0 {
+		c.HealthCheckPeriod = time.Minute
+	}
+	if c.StatementCacheSize <= 0 {
+		c.StatementCacheSize = 128
+	}
+	return c
+}
+
+// DSN appends the pool settings to databaseURL as the query parameters
+// pgxpool.ParseConfig understands, leaving parameters already present in
+// the URL untouched.
+func (c PoolConfig) DSN(databaseURL string) (string, error) {
+	c = c.withDefaults()
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", fmt.Errorf("postgres: parse database url: %w", err)
+	}
+	q := u.Query()
+	set := func(key, value string) {
+		if !q.Has(key) {
+			q.Set(key, value)
+		}
+	}
+	set("pool_max_conns", fmt.Sprintf("%d", c.MaxConns))
+	set("pool_min_conns", fmt.Sprintf("%d", c.MinConns))
+	set("pool_max_conn_idle_time", c.MaxConnIdleTime.String())
+	set("pool_max_conn_lifetime", c.MaxConnLifetime.String())
+	set("pool_health_check_period", c.HealthCheckPeriod.String())
+	set("statement_cache_capacity", fmt.Sprintf("%d", c.StatementCacheSize))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// PoolStats is a point-in-time snapshot of the pool, served by the API.
+type PoolStats struct {
+	TotalConns    int32  `json:"total_conns"`
+	IdleConns     int32  `json:"idle_conns"`
+	AcquiredConns int32  `json:"acquired_conns"`
+	MaxConns      int32  `json:"max_conns"`
+	AcquireCount  int64  `json:"acquire_count"`
+	AcquireWaitNS uint64 `json:"acquire_wait_ns"`
+}
+
+// Prepared statement caching is handled by pgx itself: DSN sets
+// statement_cache_capacity, which caps the per-connection cache pgx
+// keys by SQL text, covering the dynamically built statements too.
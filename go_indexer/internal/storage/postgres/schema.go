@@ -0,0 +1,128 @@
+// Package postgres holds the Postgres side of the storage layer: the
+// schema DDL and the slot-range partition manager for the high-volume
+// tables. The SQL runs through the small Execer interface so the package
+// stays independent of the database driver; the pgx-backed Store that
+// uses it is compiled in with -tags pgx.
+package postgres
+
+// Schema creates the indexer tables. transactions, transfers, and events
+// are declared PARTITION BY RANGE (slot); the PartitionManager creates
+// the child partitions ahead of the indexer and drops expired ones.
+//
+// Account pubkeys are stored once in the accounts dimension table and
+// referenced by integer ID from the high-volume tables; reads go through
+// the *_resolved views so API responses still carry base58 strings. See
+// AccountDict for the write path and AccountDictionaryMigration for
+// upgrading pre-dictionary databases.
+//
+// The registry-style tables (programs, nonce_accounts, token_accounts)
+// keep their folded model as a JSONB document beside the columns reads
+// filter on; the Store rewrites the document under a row lock as updates
+// fold in, mirroring the MemoryStore fold semantics.
+const Schema = `
+CREATE TABLE IF NOT EXISTS blocks (
+    slot         BIGINT PRIMARY KEY,
+    block_hash   TEXT NOT NULL,
+    parent_slot  BIGINT NOT NULL,
+    block_time   TIMESTAMPTZ,
+    raw_block_time TIMESTAMPTZ,
+    time_source  TEXT,
+    tx_count     INTEGER NOT NULL,
+    indexed_at   TIMESTAMPTZ NOT NULL,
+    orphaned     BOOLEAN NOT NULL DEFAULT FALSE,
+    partial      BOOLEAN NOT NULL DEFAULT FALSE,
+    failed_txs   TEXT[]
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    signature     TEXT NOT NULL,
+    signer        TEXT,
+    signers       TEXT[],
+    slot          BIGINT NOT NULL,
+    block_time    TIMESTAMPTZ,
+    success       BOOLEAN NOT NULL,
+    fee           BIGINT NOT NULL,
+    error         TEXT,
+    durable_nonce BOOLEAN NOT NULL DEFAULT FALSE,
+    nonce_account TEXT,
+    program_ids   TEXT[],
+    indexed_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (slot, signature)
+) PARTITION BY RANGE (slot);
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id     BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    pubkey TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    signature      TEXT NOT NULL,
+    slot           BIGINT NOT NULL,
+    block_time     TIMESTAMPTZ,
+    source_id      BIGINT NOT NULL REFERENCES accounts (id),
+    destination_id BIGINT NOT NULL REFERENCES accounts (id),
+    mint           TEXT,
+    amount         NUMERIC NOT NULL,
+    decimals       SMALLINT NOT NULL
+) PARTITION BY RANGE (slot);
+
+CREATE OR REPLACE VIEW transfers_resolved AS
+SELECT t.signature, t.slot, t.block_time,
+       src.pubkey AS source, dst.pubkey AS destination,
+       t.mint, t.amount, t.decimals
+FROM transfers t
+JOIN accounts src ON src.id = t.source_id
+JOIN accounts dst ON dst.id = t.destination_id;
+
+CREATE TABLE IF NOT EXISTS events (
+    signature  TEXT NOT NULL,
+    slot       BIGINT NOT NULL,
+    block_time TIMESTAMPTZ,
+    program    TEXT,
+    type       TEXT NOT NULL,
+    data       JSONB,
+    enrichment TEXT
+) PARTITION BY RANGE (slot);
+
+CREATE TABLE IF NOT EXISTS reorgs (
+    id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    fork_slot   BIGINT NOT NULL,
+    abandoned   TEXT[],
+    replacement TEXT[],
+    detected_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS programs (
+    program_id        TEXT PRIMARY KEY,
+    last_updated_slot BIGINT NOT NULL,
+    doc               JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS nonce_accounts (
+    address TEXT PRIMARY KEY,
+    doc     JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS token_accounts (
+    address TEXT PRIMARY KEY,
+    mint    TEXT,
+    owner   TEXT,
+    doc     JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outbox (
+    cursor     BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    event      JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outbox_delivered (
+    singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    cursor    BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS consumer_offsets (
+    consumer TEXT PRIMARY KEY,
+    cursor   BIGINT NOT NULL
+);
+`
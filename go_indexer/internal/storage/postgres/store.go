@@ -0,0 +1,22 @@
+package postgres
+
+// Options configure Open. The store itself is compiled in with -tags
+// pgx (see store_pgx.go); the default build's Open returns an error so
+// a binary without the driver fails fast when DATABASE_URL is set
+// instead of silently indexing into memory.
+type Options struct {
+	// URL is the postgres:// connection string; Pool's settings are
+	// appended to it as pgxpool query parameters (see PoolConfig.DSN).
+	URL  string
+	Pool PoolConfig
+	// Namespace, when set, routes the deployment's tables into its own
+	// Postgres schema; it must already be validated by config.Load.
+	Namespace string
+	// BulkBackfill routes the backfill lane's writes through a COPY
+	// loader and defers the secondary indexes until FinishBackfill;
+	// set when a backfill range is configured.
+	BulkBackfill bool
+	// ReadOnly skips all DDL, for connections to streaming replicas
+	// that reject writes; the primary owns the schema.
+	ReadOnly bool
+}
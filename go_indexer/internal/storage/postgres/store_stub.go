@@ -0,0 +1,16 @@
+//go:build !pgx
+
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/storage"
+)
+
+// Open reports that the Postgres store is compiled out. Build with
+// -tags pgx to link the pgx driver and the real store.
+func Open(ctx context.Context, opts Options) (storage.Store, error) {
+	return nil, errors.New("postgres: store not compiled in; rebuild with -tags pgx")
+}
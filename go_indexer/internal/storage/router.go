@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// ReplicaRouter is a Store that writes to the primary and serves reads
+// from a replica while the replica is close enough to the primary. The
+// Run loop compares the two latest indexed slots; when the replica falls
+// more than maxLagSlots behind, reads fail over to the primary until it
+// catches up. Outbox and consumer offset reads always hit the primary —
+// the change feed's delivery guarantees need read-your-writes.
+type ReplicaRouter struct {
+	primary Store
+	replica Store
+	maxLag  uint64
+	log     *slog.Logger
+
+	replicaOK atomic.Bool
+}
+
+// NewReplicaRouter creates a router over the two stores. maxLagSlots 0
+// selects the default of 100.
+func NewReplicaRouter(primary, replica Store, maxLagSlots uint64, log *slog.Logger) *ReplicaRouter {
+	if maxLagSlots == 0 {
+		maxLagSlots = 100
+	}
+	r := &ReplicaRouter{primary: primary, replica: replica, maxLag: maxLagSlots, log: log}
+	r.replicaOK.Store(true)
+	return r
+}
+
+// Run re-measures replica lag every few seconds until ctx is canceled.
+func (r *ReplicaRouter) Run(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		r.measure(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReplicaRouter) measure(ctx context.Context) {
+	primarySlot, err := r.primary.LatestSlot(ctx)
+	if err != nil {
+		return
+	}
+	replicaSlot, err := r.replica.LatestSlot(ctx)
+	if err != nil {
+		// An unreachable replica cannot serve reads.
+		if r.replicaOK.Swap(false) {
+			r.log.Warn("replica unreachable, reads failing over to primary", "error", err)
+		}
+		return
+	}
+	var lag uint64
+	if primarySlot > replicaSlot {
+		lag = primarySlot - replicaSlot
+	}
+	ok := lag <= r.maxLag
+	if r.replicaOK.Swap(ok) != ok {
+		if ok {
+			r.log.Info("replica caught up, reads back on replica", "lag_slots", lag)
+		} else {
+			r.log.Warn("replica lag too high, reads failing over to primary", "lag_slots", lag)
+		}
+	}
+}
+
+// reads returns the store serving read queries right now.
+func (r *ReplicaRouter) reads() Store {
+	if r.replicaOK.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Writes go to the primary.
+
+func (r *ReplicaRouter) SaveBlock(ctx context.Context, block *models.Block) error {
+	return r.primary.SaveBlock(ctx, block)
+}
+
+func (r *ReplicaRouter) SaveReorg(ctx context.Context, reorg *models.Reorg) error {
+	return r.primary.SaveReorg(ctx, reorg)
+}
+
+func (r *ReplicaRouter) MarkBlocksOrphaned(ctx context.Context, afterSlot, throughSlot uint64) ([]string, error) {
+	return r.primary.MarkBlocksOrphaned(ctx, afterSlot, throughSlot)
+}
+
+func (r *ReplicaRouter) ReplaceBlock(ctx context.Context, block *models.Block) error {
+	return r.primary.ReplaceBlock(ctx, block)
+}
+
+func (r *ReplicaRouter) SaveTransaction(ctx context.Context, tx *models.Transaction) error {
+	return r.primary.SaveTransaction(ctx, tx)
+}
+
+func (r *ReplicaRouter) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	return r.primary.SaveTransfer(ctx, transfer)
+}
+
+func (r *ReplicaRouter) SaveEvent(ctx context.Context, event *models.Event) error {
+	return r.primary.SaveEvent(ctx, event)
+}
+
+func (r *ReplicaRouter) SaveProgramUpdate(ctx context.Context, update *models.ProgramUpdate) error {
+	return r.primary.SaveProgramUpdate(ctx, update)
+}
+
+func (r *ReplicaRouter) SaveNonceUse(ctx context.Context, use *models.NonceAccount) error {
+	return r.primary.SaveNonceUse(ctx, use)
+}
+
+func (r *ReplicaRouter) SaveTokenAccountUpdate(ctx context.Context, update *models.TokenAccountUpdate) error {
+	return r.primary.SaveTokenAccountUpdate(ctx, update)
+}
+
+func (r *ReplicaRouter) EnsureCustomTable(ctx context.Context, table string) error {
+	return r.primary.EnsureCustomTable(ctx, table)
+}
+
+func (r *ReplicaRouter) InsertCustomRow(ctx context.Context, table string, row map[string]any) error {
+	return r.primary.InsertCustomRow(ctx, table, row)
+}
+
+// Reads route to the replica while it is healthy.
+
+func (r *ReplicaRouter) GetBlock(ctx context.Context, slot uint64) (*models.Block, error) {
+	return r.reads().GetBlock(ctx, slot)
+}
+
+func (r *ReplicaRouter) LatestSlot(ctx context.Context) (uint64, error) {
+	return r.reads().LatestSlot(ctx)
+}
+
+func (r *ReplicaRouter) GetTransaction(ctx context.Context, signature string) (*models.Transaction, error) {
+	return r.reads().GetTransaction(ctx, signature)
+}
+
+func (r *ReplicaRouter) SearchTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	return r.reads().SearchTransactions(ctx, filter)
+}
+
+func (r *ReplicaRouter) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	return r.reads().ListTransfers(ctx, filter)
+}
+
+func (r *ReplicaRouter) ListEvents(ctx context.Context, filter EventFilter) ([]models.Event, error) {
+	return r.reads().ListEvents(ctx, filter)
+}
+
+func (r *ReplicaRouter) GetProgram(ctx context.Context, programID string) (*models.Program, error) {
+	return r.reads().GetProgram(ctx, programID)
+}
+
+func (r *ReplicaRouter) GetNonceAccount(ctx context.Context, address string) (*models.NonceAccount, error) {
+	return r.reads().GetNonceAccount(ctx, address)
+}
+
+func (r *ReplicaRouter) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	return r.reads().GetTokenAccount(ctx, address)
+}
+
+func (r *ReplicaRouter) ListTokenAccounts(ctx context.Context, filter TokenAccountFilter) ([]models.TokenAccount, error) {
+	return r.reads().ListTokenAccounts(ctx, filter)
+}
+
+func (r *ReplicaRouter) ListCustomRows(ctx context.Context, table string, limit, offset int) ([]map[string]any, error) {
+	return r.reads().ListCustomRows(ctx, table, limit, offset)
+}
+
+func (r *ReplicaRouter) ListReorgs(ctx context.Context, limit, offset int) ([]models.Reorg, error) {
+	return r.reads().ListReorgs(ctx, limit, offset)
+}
+
+// Outbox and consumer offsets stay on the primary for exactness.
+
+func (r *ReplicaRouter) AppendOutbox(ctx context.Context, event *models.Event) (uint64, error) {
+	return r.primary.AppendOutbox(ctx, event)
+}
+
+func (r *ReplicaRouter) ListOutbox(ctx context.Context, afterCursor uint64, limit int) ([]models.OutboxEntry, error) {
+	return r.primary.ListOutbox(ctx, afterCursor, limit)
+}
+
+func (r *ReplicaRouter) MarkOutboxDelivered(ctx context.Context, throughCursor uint64) error {
+	return r.primary.MarkOutboxDelivered(ctx, throughCursor)
+}
+
+func (r *ReplicaRouter) OutboxDeliveredCursor(ctx context.Context) (uint64, error) {
+	return r.primary.OutboxDeliveredCursor(ctx)
+}
+
+func (r *ReplicaRouter) SaveConsumerOffset(ctx context.Context, consumer string, cursor uint64) error {
+	return r.primary.SaveConsumerOffset(ctx, consumer, cursor)
+}
+
+func (r *ReplicaRouter) GetConsumerOffset(ctx context.Context, consumer string) (uint64, error) {
+	return r.primary.GetConsumerOffset(ctx, consumer)
+}
+
+// Close closes both stores.
+func (r *ReplicaRouter) Close(ctx context.Context) error {
+	err := r.replica.Close(ctx)
+	if perr := r.primary.Close(ctx); perr != nil {
+		err = perr
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Table groups routable to their own database. Raw holds the
+// heavyweight block/transaction payloads, decoded the transfer-level
+// serving tables, and analytics the custom mapped tables. Outbox and
+// consumer offsets always stay on the default store: the change feed's
+// delivery guarantees need one database's transaction ordering.
+const (
+	GroupRaw       = "raw"
+	GroupDecoded   = "decoded"
+	GroupAnalytics = "analytics"
+)
+
+// ShardedStore is a Store that routes each table group to its own
+// backing store, so raw block storage can live on cheaper hardware than
+// the latency-sensitive serving tables. Groups without an override use
+// the default store. Cross-group writes lose single-database atomicity;
+// on crash the re-indexed slot overwrites both sides, the same
+// idempotence the retry path already relies on.
+type ShardedStore struct {
+	def       Store
+	raw       Store
+	decoded   Store
+	analytics Store
+}
+
+// NewShardedStore creates a router over def with the given per-group
+// overrides. Unknown group names error so a typo fails startup instead
+// of silently landing everything on the default.
+func NewShardedStore(def Store, groups map[string]Store) (*ShardedStore, error) {
+	s := &ShardedStore{def: def, raw: def, decoded: def, analytics: def}
+	for name, store := range groups {
+		switch name {
+		case GroupRaw:
+			s.raw = store
+		case GroupDecoded:
+			s.decoded = store
+		case GroupAnalytics:
+			s.analytics = store
+		default:
+			return nil, fmt.Errorf("unknown table group %q (known: %s, %s, %s)",
+				name, GroupRaw, GroupDecoded, GroupAnalytics)
+		}
+	}
+	return s, nil
+}
+
+// Raw group: blocks, the reorg audit log, and transaction payloads.
+
+func (s *ShardedStore) SaveBlock(ctx context.Context, block *models.Block) error {
+	return s.raw.SaveBlock(ctx, block)
+}
+
+func (s *ShardedStore) GetBlock(ctx context.Context, slot uint64) (*models.Block, error) {
+	return s.raw.GetBlock(ctx, slot)
+}
+
+func (s *ShardedStore) LatestSlot(ctx context.Context) (uint64, error) {
+	return s.raw.LatestSlot(ctx)
+}
+
+func (s *ShardedStore) SaveReorg(ctx context.Context, reorg *models.Reorg) error {
+	return s.raw.SaveReorg(ctx, reorg)
+}
+
+func (s *ShardedStore) ListReorgs(ctx context.Context, limit, offset int) ([]models.Reorg, error) {
+	return s.raw.ListReorgs(ctx, limit, offset)
+}
+
+func (s *ShardedStore) MarkBlocksOrphaned(ctx context.Context, afterSlot, throughSlot uint64) ([]string, error) {
+	return s.raw.MarkBlocksOrphaned(ctx, afterSlot, throughSlot)
+}
+
+func (s *ShardedStore) ReplaceBlock(ctx context.Context, block *models.Block) error {
+	return s.raw.ReplaceBlock(ctx, block)
+}
+
+func (s *ShardedStore) SaveTransaction(ctx context.Context, tx *models.Transaction) error {
+	return s.raw.SaveTransaction(ctx, tx)
+}
+
+func (s *ShardedStore) GetTransaction(ctx context.Context, signature string) (*models.Transaction, error) {
+	return s.raw.GetTransaction(ctx, signature)
+}
+
+func (s *ShardedStore) SearchTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	return s.raw.SearchTransactions(ctx, filter)
+}
+
+// Decoded group: the transfer-level serving tables.
+
+func (s *ShardedStore) SaveTransfer(ctx context.Context, transfer *models.Transfer) error {
+	return s.decoded.SaveTransfer(ctx, transfer)
+}
+
+func (s *ShardedStore) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	return s.decoded.ListTransfers(ctx, filter)
+}
+
+func (s *ShardedStore) SaveEvent(ctx context.Context, event *models.Event) error {
+	return s.decoded.SaveEvent(ctx, event)
+}
+
+func (s *ShardedStore) ListEvents(ctx context.Context, filter EventFilter) ([]models.Event, error) {
+	return s.decoded.ListEvents(ctx, filter)
+}
+
+func (s *ShardedStore) SaveProgramUpdate(ctx context.Context, update *models.ProgramUpdate) error {
+	return s.decoded.SaveProgramUpdate(ctx, update)
+}
+
+func (s *ShardedStore) GetProgram(ctx context.Context, programID string) (*models.Program, error) {
+	return s.decoded.GetProgram(ctx, programID)
+}
+
+func (s *ShardedStore) SaveNonceUse(ctx context.Context, use *models.NonceAccount) error {
+	return s.decoded.SaveNonceUse(ctx, use)
+}
+
+func (s *ShardedStore) GetNonceAccount(ctx context.Context, address string) (*models.NonceAccount, error) {
+	return s.decoded.GetNonceAccount(ctx, address)
+}
+
+func (s *ShardedStore) SaveTokenAccountUpdate(ctx context.Context, update *models.TokenAccountUpdate) error {
+	return s.decoded.SaveTokenAccountUpdate(ctx, update)
+}
+
+func (s *ShardedStore) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	return s.decoded.GetTokenAccount(ctx, address)
+}
+
+func (s *ShardedStore) ListTokenAccounts(ctx context.Context, filter TokenAccountFilter) ([]models.TokenAccount, error) {
+	return s.decoded.ListTokenAccounts(ctx, filter)
+}
+
+// Analytics group: the custom mapped tables.
+
+func (s *ShardedStore) EnsureCustomTable(ctx context.Context, table string) error {
+	return s.analytics.EnsureCustomTable(ctx, table)
+}
+
+func (s *ShardedStore) InsertCustomRow(ctx context.Context, table string, row map[string]any) error {
+	return s.analytics.InsertCustomRow(ctx, table, row)
+}
+
+func (s *ShardedStore) ListCustomRows(ctx context.Context, table string, limit, offset int) ([]map[string]any, error) {
+	return s.analytics.ListCustomRows(ctx, table, limit, offset)
+}
+
+// Outbox and consumer offsets stay on the default store for exactness.
+
+func (s *ShardedStore) AppendOutbox(ctx context.Context, event *models.Event) (uint64, error) {
+	return s.def.AppendOutbox(ctx, event)
+}
+
+func (s *ShardedStore) ListOutbox(ctx context.Context, afterCursor uint64, limit int) ([]models.OutboxEntry, error) {
+	return s.def.ListOutbox(ctx, afterCursor, limit)
+}
+
+func (s *ShardedStore) MarkOutboxDelivered(ctx context.Context, throughCursor uint64) error {
+	return s.def.MarkOutboxDelivered(ctx, throughCursor)
+}
+
+func (s *ShardedStore) OutboxDeliveredCursor(ctx context.Context) (uint64, error) {
+	return s.def.OutboxDeliveredCursor(ctx)
+}
+
+func (s *ShardedStore) SaveConsumerOffset(ctx context.Context, consumer string, cursor uint64) error {
+	return s.def.SaveConsumerOffset(ctx, consumer, cursor)
+}
+
+func (s *ShardedStore) GetConsumerOffset(ctx context.Context, consumer string) (uint64, error) {
+	return s.def.GetConsumerOffset(ctx, consumer)
+}
+
+// Close closes every distinct backing store once.
+func (s *ShardedStore) Close(ctx context.Context) error {
+	closed := make(map[Store]bool, 4)
+	var err error
+	for _, store := range []Store{s.raw, s.decoded, s.analytics, s.def} {
+		if closed[store] {
+			continue
+		}
+		closed[store] = true
+		if cerr := store.Close(ctx); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+// Package storage defines the persistence layer behind the indexer and the
+// API server. The Store interface is implemented by the in-memory store
+// (tests, demos) and by database-backed stores.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// ErrNotFound is returned when a requested row does not exist.
+var ErrNotFound = errors.New("storage: not found")
+
+// ErrDuplicate is returned when a row with the same primary key already
+// exists. Callers that re-process blocks treat it as benign.
+var ErrDuplicate = errors.New("storage: duplicate")
+
+// TransferFilter narrows ListTransfers results. Zero values match everything.
+type TransferFilter struct {
+	Address  string // matches source or destination
+	Mint     string
+	FromSlot uint64
+	ToSlot   uint64
+	Limit    int
+	Offset   int
+}
+
+// EventFilter narrows ListEvents results. Zero values match everything.
+type EventFilter struct {
+	Program  string
+	Type     string
+	FromSlot uint64
+	ToSlot   uint64
+	Limit    int
+	Offset   int
+}
+
+// TransactionFilter narrows SearchTransactions results. Zero values
+// match everything; a non-nil Signatures slice restricts the search to a
+// candidate set built by a more selective pre-filter.
+type TransactionFilter struct {
+	Signer string
+	// SignerRole selects how Signer matches: "" or "fee_payer" matches
+	// the fee payer only, "signer" matches any signing account.
+	SignerRole string
+	Success    *bool
+	FromSlot   uint64
+	ToSlot     uint64
+	FromTime   time.Time
+	ToTime     time.Time
+	Signatures []string
+	Limit      int
+	Offset     int
+}
+
+// signerMatches applies a transaction filter's signer predicate to one
+// row, honoring SignerRole. Rows indexed before the signer list was
+// stored fall back to the fee payer.
+func signerMatches(filter TransactionFilter, tx *models.Transaction) bool {
+	if filter.Signer == "" {
+		return true
+	}
+	if filter.SignerRole == "signer" {
+		for _, signer := range tx.Signers {
+			if signer == filter.Signer {
+				return true
+			}
+		}
+	}
+	return tx.Signer == filter.Signer
+}
+
+// TokenAccountFilter narrows ListTokenAccounts results.
+type TokenAccountFilter struct {
+	Owner  string
+	Mint   string
+	Limit  int
+	Offset int
+}
+
+// Store is the persistence interface used by the indexer and the API server.
+type Store interface {
+	SaveBlock(ctx context.Context, block *models.Block) error
+	GetBlock(ctx context.Context, slot uint64) (*models.Block, error)
+	LatestSlot(ctx context.Context) (uint64, error)
+
+	// Reorg audit log. MarkBlocksOrphaned flags every stored block in
+	// (afterSlot, throughSlot] and returns their hashes.
+	SaveReorg(ctx context.Context, reorg *models.Reorg) error
+	ListReorgs(ctx context.Context, limit, offset int) ([]models.Reorg, error)
+	MarkBlocksOrphaned(ctx context.Context, afterSlot, throughSlot uint64) ([]string, error)
+	ReplaceBlock(ctx context.Context, block *models.Block) error
+
+	SaveTransaction(ctx context.Context, tx *models.Transaction) error
+	GetTransaction(ctx context.Context, signature string) (*models.Transaction, error)
+	SearchTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error)
+
+	SaveTransfer(ctx context.Context, transfer *models.Transfer) error
+	ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error)
+
+	SaveEvent(ctx context.Context, event *models.Event) error
+	ListEvents(ctx context.Context, filter EventFilter) ([]models.Event, error)
+
+	SaveProgramUpdate(ctx context.Context, update *models.ProgramUpdate) error
+	GetProgram(ctx context.Context, programID string) (*models.Program, error)
+
+	SaveNonceUse(ctx context.Context, use *models.NonceAccount) error
+	GetNonceAccount(ctx context.Context, address string) (*models.NonceAccount, error)
+
+	// Outbox rows are written in the same transaction as the indexed data
+	// and drained by the outbox dispatcher; see internal/outbox.
+	AppendOutbox(ctx context.Context, event *models.Event) (uint64, error)
+	ListOutbox(ctx context.Context, afterCursor uint64, limit int) ([]models.OutboxEntry, error)
+	MarkOutboxDelivered(ctx context.Context, throughCursor uint64) error
+	OutboxDeliveredCursor(ctx context.Context) (uint64, error)
+
+	// Consumer offsets are the durable cursors of pull-based change feed
+	// consumers; an unknown consumer reads as offset 0.
+	SaveConsumerOffset(ctx context.Context, consumer string, cursor uint64) error
+	GetConsumerOffset(ctx context.Context, consumer string) (uint64, error)
+
+	// Custom rows back the declarative table mapping; see internal/mapping.
+	EnsureCustomTable(ctx context.Context, table string) error
+	InsertCustomRow(ctx context.Context, table string, row map[string]any) error
+	ListCustomRows(ctx context.Context, table string, limit, offset int) ([]map[string]any, error)
+
+	SaveTokenAccountUpdate(ctx context.Context, update *models.TokenAccountUpdate) error
+	GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error)
+	ListTokenAccounts(ctx context.Context, filter TokenAccountFilter) ([]models.TokenAccount, error)
+
+	Close(ctx context.Context) error
+}
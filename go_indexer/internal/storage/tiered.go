@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// tierPageSize is how many rows one sweep page reads.
+const tierPageSize = 10_000
+
+// tierStateFile persists how far the cold tier reaches.
+const tierStateFile = "state.json"
+
+// TieredStore splits storage into a hot and a cold tier. The wrapped
+// store stays the hot tier for recent slots; a background sweep migrates
+// the high-volume rows (transactions, transfers, events) older than the
+// retention window into slot-ranged JSON-line archive files under dir,
+// ready for object storage. Range queries entirely behind the migration
+// boundary are served from the archive, so the API keeps answering after
+// the Postgres partition manager drops the expired hot partitions.
+//
+// Blocks and the other low-volume tables stay hot: they are not
+// partition-pruned, so there is nothing to tier.
+type TieredStore struct {
+	Store  // hot tier
+	dir    string
+	retain uint64 // slots kept hot behind the latest
+	log    *slog.Logger
+
+	// archivedThrough is the highest slot migrated to the cold tier.
+	archivedThrough atomic.Uint64
+}
+
+// NewTieredStore wraps hot with cold archiving into dir, keeping the
+// most recent retainSlots in the hot tier only.
+func NewTieredStore(hot Store, dir string, retainSlots uint64, log *slog.Logger) (*TieredStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cold tier dir: %w", err)
+	}
+	t := &TieredStore{Store: hot, dir: dir, retain: retainSlots, log: log}
+	var state struct {
+		ArchivedThrough uint64 `json:"archived_through"`
+	}
+	if raw, err := os.ReadFile(filepath.Join(dir, tierStateFile)); err == nil {
+		if err := json.Unmarshal(raw, &state); err == nil {
+			t.archivedThrough.Store(state.ArchivedThrough)
+		}
+	}
+	return t, nil
+}
+
+// Sweep migrates rows in (archivedThrough, latest-retain] to archive
+// files and advances the boundary. It is run on a schedule by the
+// scheduler.
+func (t *TieredStore) Sweep(ctx context.Context) error {
+	latest, err := t.Store.LatestSlot(ctx)
+	if err != nil || latest <= t.retain {
+		return err
+	}
+	boundary := latest - t.retain
+	from := t.archivedThrough.Load() + 1
+	if boundary < from {
+		return nil
+	}
+
+	transfers, err := t.collectTransfers(ctx, from, boundary)
+	if err != nil {
+		return err
+	}
+	events, err := t.collectEvents(ctx, from, boundary)
+	if err != nil {
+		return err
+	}
+	transactions, err := t.collectTransactions(ctx, from, boundary)
+	if err != nil {
+		return err
+	}
+	if err := writeArchive(t.archivePath("transfers", from, boundary), transfers); err != nil {
+		return err
+	}
+	if err := writeArchive(t.archivePath("events", from, boundary), events); err != nil {
+		return err
+	}
+	if err := writeArchive(t.archivePath("transactions", from, boundary), transactions); err != nil {
+		return err
+	}
+
+	t.archivedThrough.Store(boundary)
+	raw, _ := json.Marshal(map[string]uint64{"archived_through": boundary})
+	if err := os.WriteFile(filepath.Join(t.dir, tierStateFile), raw, 0o644); err != nil {
+		return fmt.Errorf("cold tier state: %w", err)
+	}
+	t.log.Info("cold tier advanced", "through_slot", boundary,
+		"transfers", len(transfers), "events", len(events), "transactions", len(transactions))
+	return nil
+}
+
+func (t *TieredStore) archivePath(table string, from, to uint64) string {
+	return filepath.Join(t.dir, fmt.Sprintf("%s-%d-%d.jsonl", table, from, to))
+}
+
+func (t *TieredStore) collectTransfers(ctx context.Context, from, to uint64) ([]models.Transfer, error) {
+	var out []models.Transfer
+	filter := TransferFilter{FromSlot: from, ToSlot: to, Limit: tierPageSize}
+	for {
+		page, err := t.Store.ListTransfers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < tierPageSize {
+			return out, nil
+		}
+		filter.Offset += tierPageSize
+	}
+}
+
+func (t *TieredStore) collectEvents(ctx context.Context, from, to uint64) ([]models.Event, error) {
+	var out []models.Event
+	filter := EventFilter{FromSlot: from, ToSlot: to, Limit: tierPageSize}
+	for {
+		page, err := t.Store.ListEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < tierPageSize {
+			return out, nil
+		}
+		filter.Offset += tierPageSize
+	}
+}
+
+func (t *TieredStore) collectTransactions(ctx context.Context, from, to uint64) ([]models.Transaction, error) {
+	var out []models.Transaction
+	filter := TransactionFilter{FromSlot: from, ToSlot: to, Limit: tierPageSize}
+	for {
+		page, err := t.Store.SearchTransactions(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < tierPageSize {
+			return out, nil
+		}
+		filter.Offset += tierPageSize
+	}
+}
+
+// writeArchive appends rows as JSON lines; an empty page writes nothing.
+func writeArchive[T any](path string, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for i := range rows {
+		if err := enc.Encode(rows[i]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// cold reports whether a slot-ranged query lies entirely in the cold
+// tier. Open-ended and recent queries stay hot.
+func (t *TieredStore) cold(toSlot uint64) bool {
+	return toSlot > 0 && toSlot <= t.archivedThrough.Load()
+}
+
+// ListTransfers implements Store, serving fully-cold ranges from the
+// archive.
+func (t *TieredStore) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	if !t.cold(filter.ToSlot) {
+		return t.Store.ListTransfers(ctx, filter)
+	}
+	var out []models.Transfer
+	err := scanArchive(t.dir, "transfers-", func(row models.Transfer) {
+		if filter.Address != "" && row.Source != filter.Address && row.Destination != filter.Address {
+			return
+		}
+		if filter.Mint != "" && row.Mint != filter.Mint {
+			return
+		}
+		if inSlotRange(row.Slot, filter.FromSlot, filter.ToSlot) {
+			out = append(out, row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot < out[j].Slot })
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// ListEvents implements Store, serving fully-cold ranges from the
+// archive.
+func (t *TieredStore) ListEvents(ctx context.Context, filter EventFilter) ([]models.Event, error) {
+	if !t.cold(filter.ToSlot) {
+		return t.Store.ListEvents(ctx, filter)
+	}
+	var out []models.Event
+	err := scanArchive(t.dir, "events-", func(row models.Event) {
+		if filter.Program != "" && row.Program != filter.Program {
+			return
+		}
+		if filter.Type != "" && row.Type != filter.Type {
+			return
+		}
+		if inSlotRange(row.Slot, filter.FromSlot, filter.ToSlot) {
+			out = append(out, row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slot < out[j].Slot })
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+// SearchTransactions implements Store, serving fully-cold ranges from
+// the archive.
+func (t *TieredStore) SearchTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	if !t.cold(filter.ToSlot) {
+		return t.Store.SearchTransactions(ctx, filter)
+	}
+	var candidates map[string]struct{}
+	if filter.Signatures != nil {
+		candidates = make(map[string]struct{}, len(filter.Signatures))
+		for _, sig := range filter.Signatures {
+			candidates[sig] = struct{}{}
+		}
+	}
+	var out []models.Transaction
+	err := scanArchive(t.dir, "transactions-", func(row models.Transaction) {
+		if candidates != nil {
+			if _, ok := candidates[row.Signature]; !ok {
+				return
+			}
+		}
+		if !signerMatches(filter, &row) {
+			return
+		}
+		if filter.Success != nil && row.Success != *filter.Success {
+			return
+		}
+		if !filter.FromTime.IsZero() && row.BlockTime.Before(filter.FromTime) {
+			return
+		}
+		if !filter.ToTime.IsZero() && row.BlockTime.After(filter.ToTime) {
+			return
+		}
+		if inSlotRange(row.Slot, filter.FromSlot, filter.ToSlot) {
+			out = append(out, row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Slot != out[j].Slot {
+			return out[i].Slot < out[j].Slot
+		}
+		return out[i].Signature < out[j].Signature
+	})
+	return paginate(out, filter.Offset, filter.Limit), nil
+}
+
+func inSlotRange(slot, from, to uint64) bool {
+	if from > 0 && slot < from {
+		return false
+	}
+	if to > 0 && slot > to {
+		return false
+	}
+	return true
+}
+
+// scanArchive streams every row of the table's archive files through fn.
+func scanArchive[T any](dir, prefix string, fn func(T)) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) ||
+			!strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		for scanner.Scan() {
+			var row T
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				f.Close()
+				return fmt.Errorf("archive %s: %w", entry.Name(), err)
+			}
+			fn(row)
+		}
+		if err := scanner.Err(); err != nil {
+			f.Close()
+			return fmt.Errorf("archive %s: %w", entry.Name(), err)
+		}
+		f.Close()
+	}
+	return nil
+}
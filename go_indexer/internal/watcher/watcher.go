@@ -0,0 +1,173 @@
+// Package watcher implements signature confirmation tracking: dapps
+// register a signature once and get notified when it lands in a block,
+// instead of polling the RPC node themselves.
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// Notification is the payload delivered to the webhook and the event
+// stream when a watched signature resolves.
+type Notification struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot,omitempty"`
+	Status    string `json:"status"` // confirmed, finalized, or failed
+	Error     string `json:"error,omitempty"`
+}
+
+type watch struct {
+	webhookURL string
+	registered time.Time
+}
+
+// SignatureWatcher resolves watched signatures from the live block stream
+// and falls back to getSignatureStatuses for signatures the stream misses
+// (e.g. ones that landed before the watch was registered).
+type SignatureWatcher struct {
+	client  *solana.Client
+	publish func(models.Event)
+	httpc   *http.Client
+	log     *slog.Logger
+
+	mu      sync.Mutex
+	watches map[string]watch
+}
+
+// New creates a SignatureWatcher. publish, when non-nil, receives a
+// signature_resolved event for the API event stream on every notification.
+func New(client *solana.Client, publish func(models.Event), log *slog.Logger) *SignatureWatcher {
+	return &SignatureWatcher{
+		client:  client,
+		publish: publish,
+		httpc:   &http.Client{Timeout: 10 * time.Second},
+		log:     log,
+		watches: make(map[string]watch),
+	}
+}
+
+// Watch registers a signature. webhookURL may be empty; the notification is
+// still delivered to the event stream.
+func (w *SignatureWatcher) Watch(signature, webhookURL string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watches[signature] = watch{webhookURL: webhookURL, registered: time.Now()}
+}
+
+// Pending returns the number of unresolved watches.
+func (w *SignatureWatcher) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.watches)
+}
+
+// ObserveTransaction resolves a watch when its signature shows up in the
+// live block stream. The indexer calls this for every stored transaction.
+func (w *SignatureWatcher) ObserveTransaction(tx models.Transaction) {
+	w.mu.Lock()
+	entry, ok := w.watches[tx.Signature]
+	if ok {
+		delete(w.watches, tx.Signature)
+	}
+	w.mu.Unlock()
+	if !ok {
+		return
+	}
+	status := "confirmed"
+	if !tx.Success {
+		status = "failed"
+	}
+	w.notify(entry, Notification{
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+		Status:    status,
+		Error:     tx.Error,
+	})
+}
+
+// Run periodically resolves stale watches through getSignatureStatuses
+// until ctx is canceled.
+func (w *SignatureWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.pollStale(ctx)
+		}
+	}
+}
+
+// pollStale checks watches older than one polling period against the RPC
+// node, catching signatures that landed outside the indexed slot range.
+func (w *SignatureWatcher) pollStale(ctx context.Context) {
+	w.mu.Lock()
+	var stale []string
+	for sig, entry := range w.watches {
+		if time.Since(entry.registered) > 5*time.Second {
+			stale = append(stale, sig)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, sig := range stale {
+		status, err := w.client.GetSignatureStatus(ctx, sig)
+		if ctx.Err() != nil {
+			return
+		}
+		notification := Notification{Signature: sig}
+		switch {
+		case err != nil:
+			notification.Status = "failed"
+			notification.Error = err.Error()
+		case status == "confirmed" || status == "finalized":
+			notification.Status = status
+		default:
+			continue // still pending
+		}
+		w.mu.Lock()
+		entry, ok := w.watches[sig]
+		if ok {
+			delete(w.watches, sig)
+		}
+		w.mu.Unlock()
+		if ok {
+			w.notify(entry, notification)
+		}
+	}
+}
+
+func (w *SignatureWatcher) notify(entry watch, n Notification) {
+	if w.publish != nil {
+		w.publish(models.Event{
+			Signature: n.Signature,
+			Slot:      n.Slot,
+			Type:      "signature_resolved",
+			Data:      map[string]any{"status": n.Status, "error": n.Error},
+		})
+	}
+	if entry.webhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return
+	}
+	resp, err := w.httpc.Post(entry.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		w.log.Warn("signature webhook delivery failed", "signature", n.Signature, "error", err)
+		return
+	}
+	resp.Body.Close()
+}
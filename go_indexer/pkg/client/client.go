@@ -0,0 +1,209 @@
+// Package client provides a typed Go client for the indexer's REST API,
+// so other services can consume the index without hand-rolling HTTP calls.
+//
+// Usage:
+//
+//	c := client.New("http://localhost:8080")
+//	block, err := c.GetBlock(ctx, 1234)
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a running indexer API server.
+type Client struct {
+	baseURL string
+	httpc   *http.Client
+}
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithHTTPClient replaces the underlying *http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpc = hc }
+}
+
+// WithTimeout sets the request timeout on the underlying HTTP client.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.httpc.Timeout = d }
+}
+
+// New creates a Client for the API server at baseURL,
+// e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpc:   &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned when the server responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("indexer api: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Health calls GET /health.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var out Health
+	if err := c.get(ctx, "/health", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LatestSlot calls GET /api/v1/slot/latest and returns the most recently
+// indexed slot.
+func (c *Client) LatestSlot(ctx context.Context) (uint64, error) {
+	var out struct {
+		Slot uint64 `json:"slot"`
+	}
+	if err := c.get(ctx, "/api/v1/slot/latest", nil, &out); err != nil {
+		return 0, err
+	}
+	return out.Slot, nil
+}
+
+// GetBlock calls GET /api/v1/blocks/{slot}.
+func (c *Client) GetBlock(ctx context.Context, slot uint64) (*Block, error) {
+	var out Block
+	path := "/api/v1/blocks/" + strconv.FormatUint(slot, 10)
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTransfers calls GET /api/v1/transfers with the given filters.
+func (c *Client) ListTransfers(ctx context.Context, params ListTransfersParams) ([]Transfer, error) {
+	q := url.Values{}
+	if params.Address != "" {
+		q.Set("address", params.Address)
+	}
+	if params.Mint != "" {
+		q.Set("mint", params.Mint)
+	}
+	if params.FromSlot > 0 {
+		q.Set("from_slot", strconv.FormatUint(params.FromSlot, 10))
+	}
+	if params.ToSlot > 0 {
+		q.Set("to_slot", strconv.FormatUint(params.ToSlot, 10))
+	}
+	if params.Limit > 0 {
+		q.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Offset > 0 {
+		q.Set("offset", strconv.Itoa(params.Offset))
+	}
+	var out []Transfer
+	if err := c.get(ctx, "/api/v1/transfers", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamEvents subscribes to GET /api/v1/events/stream (server-sent events)
+// and invokes handler for every decoded event until the context is canceled,
+// the server closes the stream, or the handler returns an error.
+func (c *Client) StreamEvents(ctx context.Context, params StreamEventsParams, handler func(Event) error) error {
+	q := url.Values{}
+	if params.Program != "" {
+		q.Set("program", params.Program)
+	}
+	if params.Type != "" {
+		q.Set("type", params.Type)
+	}
+	u := c.baseURL + "/api/v1/events/stream"
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		if err := handler(ev); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (c *Client) get(ctx context.Context, path string, q url.Values, out any) error {
+	u := c.baseURL + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	msg := strings.TrimSpace(string(body))
+	var wire struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &wire) == nil && wire.Error != "" {
+		msg = wire.Error
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
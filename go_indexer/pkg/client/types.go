@@ -0,0 +1,58 @@
+package client
+
+import "time"
+
+// Health is the response of GET /health.
+type Health struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Block is a summary of an indexed block as returned by the API.
+type Block struct {
+	Slot              uint64    `json:"slot"`
+	BlockHash         string    `json:"block_hash"`
+	ParentSlot        uint64    `json:"parent_slot"`
+	BlockTime         time.Time `json:"block_time"`
+	TransactionsCount int       `json:"transactions_count"`
+	IndexedAt         time.Time `json:"indexed_at"`
+}
+
+// Transfer is a single SOL or SPL token transfer indexed from a transaction.
+type Transfer struct {
+	Signature   string    `json:"signature"`
+	Slot        uint64    `json:"slot"`
+	BlockTime   time.Time `json:"block_time"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Mint        string    `json:"mint,omitempty"`
+	Amount      uint64    `json:"amount"`
+	Decimals    uint8     `json:"decimals"`
+}
+
+// Event is a decoded program event (Anchor event or parsed log line).
+type Event struct {
+	Signature string         `json:"signature"`
+	Slot      uint64         `json:"slot"`
+	BlockTime time.Time      `json:"block_time"`
+	Program   string         `json:"program"`
+	Type      string         `json:"type"`
+	Data      map[string]any `json:"data,omitempty"`
+}
+
+// ListTransfersParams are the optional filters for ListTransfers.
+// Zero values are omitted from the query string.
+type ListTransfersParams struct {
+	Address  string // matches source or destination
+	Mint     string // SPL mint; empty matches native SOL transfers too
+	FromSlot uint64
+	ToSlot   uint64
+	Limit    int
+	Offset   int
+}
+
+// StreamEventsParams are the optional filters for StreamEvents.
+type StreamEventsParams struct {
+	Program string // only events emitted by this program ID
+	Type    string // only events of this type
+}
@@ -0,0 +1,175 @@
+package solana
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// SOLDecimals is the decimal precision of native SOL: one SOL is 1e9
+// lamports.
+const SOLDecimals = 9
+
+// UIAmount formats a raw token amount as a decimal string using the
+// mint's decimals, e.g. UIAmount(1500000, 6) == "1.5". Trailing
+// fractional zeros are trimmed; a whole amount carries no decimal
+// point. Formatting in strings keeps full precision — float64 cannot
+// represent every u64 amount exactly.
+func UIAmount(raw uint64, decimals uint8) string {
+	return uiDigits(strconv.FormatUint(raw, 10), decimals)
+}
+
+// UIAmountSigned is UIAmount for signed amounts such as net balance
+// deltas.
+func UIAmountSigned(raw int64, decimals uint8) string {
+	if raw < 0 {
+		// Negate via uint64 so math.MinInt64 stays representable.
+		return "-" + uiDigits(strconv.FormatUint(uint64(-raw), 10), decimals)
+	}
+	return uiDigits(strconv.FormatUint(uint64(raw), 10), decimals)
+}
+
+// LamportsToSOL formats a lamport amount as a SOL decimal string.
+func LamportsToSOL(lamports uint64) string {
+	return UIAmount(lamports, SOLDecimals)
+}
+
+// Amount is a signed 128-bit base-unit amount for aggregation. Chain
+// amounts are unsigned 64-bit, so any single value fits, and summing
+// them cannot overflow 128 bits in practice — unlike the uint64 and
+// int64 accumulators it replaces, which wrap silently on large token
+// volumes. The zero value is zero; Amount is a plain value type, safe
+// to copy and to compare with ==. JSON encodes as a decimal string,
+// since amounts past 2^53 lose precision in a JSON number.
+type Amount struct {
+	hi int64 // high word; value is hi*2^64 + lo (two's complement)
+	lo uint64
+}
+
+// AmountFromUint64 returns v as an Amount.
+func AmountFromUint64(v uint64) Amount {
+	return Amount{lo: v}
+}
+
+// AddUint64 adds v to the amount.
+func (a *Amount) AddUint64(v uint64) {
+	var carry uint64
+	a.lo, carry = bits.Add64(a.lo, v, 0)
+	a.hi += int64(carry)
+}
+
+// SubUint64 subtracts v from the amount.
+func (a *Amount) SubUint64(v uint64) {
+	var borrow uint64
+	a.lo, borrow = bits.Sub64(a.lo, v, 0)
+	a.hi -= int64(borrow)
+}
+
+// Add adds other to the amount.
+func (a *Amount) Add(other Amount) {
+	var carry uint64
+	a.lo, carry = bits.Add64(a.lo, other.lo, 0)
+	a.hi += other.hi + int64(carry)
+}
+
+// Sign returns -1, 0, or 1 for negative, zero, and positive amounts.
+func (a Amount) Sign() int {
+	switch {
+	case a.hi < 0:
+		return -1
+	case a.hi == 0 && a.lo == 0:
+		return 0
+	}
+	return 1
+}
+
+// Cmp compares a to other, returning -1, 0, or 1.
+func (a Amount) Cmp(other Amount) int {
+	if a.hi != other.hi {
+		if a.hi < other.hi {
+			return -1
+		}
+		return 1
+	}
+	if a.lo != other.lo {
+		if a.lo < other.lo {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Float64 returns the amount as a float64 for price arithmetic, with
+// the precision loss that implies.
+func (a Amount) Float64() float64 {
+	return math.Ldexp(float64(a.hi), 64) + float64(a.lo)
+}
+
+// String returns the amount in decimal.
+func (a Amount) String() string {
+	return a.bigInt().String()
+}
+
+// UI returns the amount as a decimal string scaled by the mint's
+// decimals, like UIAmountSigned.
+func (a Amount) UI(decimals uint8) string {
+	digits := a.String()
+	if neg, ok := strings.CutPrefix(digits, "-"); ok {
+		return "-" + uiDigits(neg, decimals)
+	}
+	return uiDigits(digits, decimals)
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, a.String()), nil
+}
+
+// UnmarshalJSON accepts a decimal string or, for lenience with older
+// payloads, a bare number.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %q", s)
+	}
+	if n.BitLen() > 127 {
+		return fmt.Errorf("amount %s overflows 128 bits", n)
+	}
+	a.lo = new(big.Int).And(n, maxUint64).Uint64()
+	a.hi = new(big.Int).Rsh(n, 64).Int64()
+	return nil
+}
+
+// bigInt converts the two-word representation for formatting.
+func (a Amount) bigInt() *big.Int {
+	n := big.NewInt(a.hi)
+	n.Lsh(n, 64)
+	return n.Add(n, new(big.Int).SetUint64(a.lo))
+}
+
+var maxUint64 = new(big.Int).SetUint64(math.MaxUint64)
+
+// uiDigits places the decimal point in an unsigned digit string.
+func uiDigits(digits string, decimals uint8) string {
+	d := int(decimals)
+	if d == 0 {
+		return digits
+	}
+	if len(digits) <= d {
+		digits = strings.Repeat("0", d-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-d], digits[len(digits)-d:]
+	frac = strings.TrimRight(frac, "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
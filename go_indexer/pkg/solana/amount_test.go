@@ -0,0 +1,205 @@
+package solana
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// amountFromString builds an Amount through UnmarshalJSON so tests can
+// state values wider than uint64 in decimal.
+func amountFromString(t *testing.T, s string) Amount {
+	t.Helper()
+	var a Amount
+	if err := json.Unmarshal([]byte(`"`+s+`"`), &a); err != nil {
+		t.Fatalf("unmarshal %q: %v", s, err)
+	}
+	return a
+}
+
+func TestAmountCarryAcross64Bits(t *testing.T) {
+	// Summing two max uint64 values crosses the low-word boundary.
+	a := AmountFromUint64(math.MaxUint64)
+	a.AddUint64(math.MaxUint64)
+	if want := "36893488147419103230"; a.String() != want { // 2*(2^64-1)
+		t.Errorf("String() = %q, want %q", a.String(), want)
+	}
+	if a.Sign() != 1 {
+		t.Errorf("Sign() = %d, want 1", a.Sign())
+	}
+
+	// Subtracting back down borrows across the boundary.
+	a.SubUint64(math.MaxUint64)
+	if want := AmountFromUint64(math.MaxUint64); a != want {
+		t.Errorf("after sub = %q, want %q", a.String(), want.String())
+	}
+}
+
+func TestAmountNegative(t *testing.T) {
+	var a Amount
+	a.SubUint64(1)
+	if a.String() != "-1" {
+		t.Errorf("String() = %q, want %q", a.String(), "-1")
+	}
+	if a.Sign() != -1 {
+		t.Errorf("Sign() = %d, want -1", a.Sign())
+	}
+	a.AddUint64(1)
+	if a != (Amount{}) {
+		t.Errorf("back to zero = %q, want 0", a.String())
+	}
+	if a.Sign() != 0 {
+		t.Errorf("Sign() = %d, want 0", a.Sign())
+	}
+
+	// Going negative past the low word and coming back.
+	a.SubUint64(math.MaxUint64)
+	a.SubUint64(math.MaxUint64)
+	if want := "-36893488147419103230"; a.String() != want {
+		t.Errorf("String() = %q, want %q", a.String(), want)
+	}
+	var b Amount
+	b.AddUint64(math.MaxUint64)
+	b.AddUint64(math.MaxUint64)
+	a.Add(b)
+	if a.Sign() != 0 {
+		t.Errorf("negative plus mirror = %q, want 0", a.String())
+	}
+}
+
+func TestAmountCmp(t *testing.T) {
+	big := amountFromString(t, "36893488147419103230")
+	neg := amountFromString(t, "-36893488147419103230")
+	one := AmountFromUint64(1)
+	two := AmountFromUint64(2)
+	tests := []struct {
+		a, b Amount
+		want int
+	}{
+		{one, two, -1},
+		{two, one, 1},
+		{one, one, 0},
+		{neg, one, -1},
+		{big, one, 1},
+		{neg, big, -1},
+		{big, big, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("Cmp(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSONRoundTrip(t *testing.T) {
+	values := []string{
+		"0",
+		"1",
+		"-1",
+		"18446744073709551615",  // max uint64
+		"18446744073709551616",  // 2^64, needs the high word
+		"-18446744073709551616", // negative past the low word
+		"9007199254740993",      // 2^53+1, lost by a JSON number
+		"170141183460469231731687303715884105727", // max int128
+	}
+	for _, want := range values {
+		a := amountFromString(t, want)
+		data, err := json.Marshal(a)
+		if err != nil {
+			t.Errorf("marshal %s: %v", want, err)
+			continue
+		}
+		if string(data) != `"`+want+`"` {
+			t.Errorf("marshal %s = %s", want, data)
+		}
+		var back Amount
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Errorf("unmarshal %s: %v", data, err)
+			continue
+		}
+		if back != a {
+			t.Errorf("round trip %s = %s", want, back.String())
+		}
+	}
+}
+
+func TestAmountUnmarshalBareNumber(t *testing.T) {
+	var a Amount
+	if err := json.Unmarshal([]byte(`5000`), &a); err != nil {
+		t.Fatalf("bare number: %v", err)
+	}
+	if a != AmountFromUint64(5000) {
+		t.Errorf("bare number = %s, want 5000", a.String())
+	}
+}
+
+func TestAmountUnmarshalRejects(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr string
+	}{
+		{`"not-a-number"`, "invalid amount"},
+		{`"1.5"`, "invalid amount"},
+		{`""`, "invalid amount"},
+		{`"170141183460469231731687303715884105728"`, "overflows 128 bits"}, // 2^127
+	}
+	for _, tt := range tests {
+		var a Amount
+		err := json.Unmarshal([]byte(tt.data), &a)
+		if err == nil {
+			t.Errorf("unmarshal %s: expected error", tt.data)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("unmarshal %s = %v, want containing %q", tt.data, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAmountUI(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals uint8
+		want     string
+	}{
+		{"1500000", 6, "1.5"},
+		{"1000000", 6, "1"},
+		{"1", 9, "0.000000001"},
+		{"-1500000", 6, "-1.5"},
+		{"0", 6, "0"},
+		{"42", 0, "42"},
+		{"18446744073709551616", 9, "18446744073.709551616"},
+	}
+	for _, tt := range tests {
+		a := amountFromString(t, tt.value)
+		if got := a.UI(tt.decimals); got != tt.want {
+			t.Errorf("UI(%s, %d) = %q, want %q", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestUIAmount(t *testing.T) {
+	if got := UIAmount(1500000, 6); got != "1.5" {
+		t.Errorf("UIAmount = %q, want %q", got, "1.5")
+	}
+	if got := UIAmountSigned(-1500000, 6); got != "-1.5" {
+		t.Errorf("UIAmountSigned = %q, want %q", got, "-1.5")
+	}
+	if got := UIAmountSigned(math.MinInt64, 0); got != "-9223372036854775808" {
+		t.Errorf("UIAmountSigned(MinInt64) = %q", got)
+	}
+	if got := LamportsToSOL(1_000_000_000); got != "1" {
+		t.Errorf("LamportsToSOL = %q, want %q", got, "1")
+	}
+}
+
+func TestAmountFloat64(t *testing.T) {
+	if got := AmountFromUint64(1000).Float64(); got != 1000 {
+		t.Errorf("Float64 = %v, want 1000", got)
+	}
+	neg := amountFromString(t, "-18446744073709551616") // -2^64
+	if got := neg.Float64(); got != -math.Ldexp(1, 64) {
+		t.Errorf("Float64 = %v, want %v", got, -math.Ldexp(1, 64))
+	}
+}
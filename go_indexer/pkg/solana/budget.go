@@ -0,0 +1,142 @@
+package solana
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CostTable maps an RPC method to its estimated provider credit cost.
+// Methods missing from the table fall back to the "*" entry, or 1.
+type CostTable map[string]float64
+
+// DefaultCostTable returns an approximate credit cost table for a known
+// provider ("helius", "quicknode", "triton"). Unknown providers get a flat
+// table where every call costs one credit.
+func DefaultCostTable(provider string) CostTable {
+	switch provider {
+	case "helius":
+		return CostTable{
+			"getBlock":                10,
+			"getTransaction":          10,
+			"getSignaturesForAddress": 10,
+			"getSlot":                 1,
+			"*":                       1,
+		}
+	case "quicknode":
+		return CostTable{
+			"getBlock":       50,
+			"getTransaction": 50,
+			"getSlot":        20,
+			"*":              20,
+		}
+	case "triton":
+		return CostTable{
+			"getBlock":       5,
+			"getTransaction": 5,
+			"getSlot":        1,
+			"*":              1,
+		}
+	default:
+		return CostTable{"*": 1}
+	}
+}
+
+// Cost returns the credit cost of one call to method.
+func (t CostTable) Cost(method string) float64 {
+	if c, ok := t[method]; ok {
+		return c
+	}
+	if c, ok := t["*"]; ok {
+		return c
+	}
+	return 1
+}
+
+// Budget tracks per-method RPC call counts and estimated credit spend, and
+// projects monthly usage so the scheduler can slow down before the
+// provider's budget is exhausted.
+type Budget struct {
+	mu           sync.Mutex
+	table        CostTable
+	monthlyLimit float64 // 0 disables the limit
+	counts       map[string]uint64
+	credits      float64
+	started      time.Time
+	warned       bool
+}
+
+// NewBudget creates a Budget with the given cost table and monthly credit
+// limit. A limit of 0 tracks usage without ever reporting NearLimit.
+func NewBudget(table CostTable, monthlyLimit float64) *Budget {
+	return &Budget{
+		table:        table,
+		monthlyLimit: monthlyLimit,
+		counts:       make(map[string]uint64),
+		started:      time.Now(),
+	}
+}
+
+// Record accounts for one call to method.
+func (b *Budget) Record(method string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts[method]++
+	b.credits += b.table.Cost(method)
+	if b.monthlyLimit > 0 && !b.warned && b.projectedMonthlyLocked() > b.monthlyLimit {
+		b.warned = true
+		slog.Warn("projected monthly RPC usage exceeds budget",
+			"projected_credits", b.projectedMonthlyLocked(),
+			"monthly_limit", b.monthlyLimit)
+	}
+}
+
+// Snapshot is a point-in-time view of the budget.
+type Snapshot struct {
+	Counts           map[string]uint64 `json:"counts"`
+	CreditsUsed      float64           `json:"credits_used"`
+	ProjectedMonthly float64           `json:"projected_monthly"`
+	MonthlyLimit     float64           `json:"monthly_limit"`
+	NearLimit        bool              `json:"near_limit"`
+}
+
+// Snapshot returns the current usage, projection, and limit state.
+func (b *Budget) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts := make(map[string]uint64, len(b.counts))
+	for k, v := range b.counts {
+		counts[k] = v
+	}
+	projected := b.projectedMonthlyLocked()
+	return Snapshot{
+		Counts:           counts,
+		CreditsUsed:      b.credits,
+		ProjectedMonthly: projected,
+		MonthlyLimit:     b.monthlyLimit,
+		NearLimit:        b.nearLimitLocked(projected),
+	}
+}
+
+// NearLimit reports whether projected monthly usage is within 10% of the
+// configured limit. The indexer's poll loop slows down while this is true.
+func (b *Budget) NearLimit() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nearLimitLocked(b.projectedMonthlyLocked())
+}
+
+func (b *Budget) nearLimitLocked(projected float64) bool {
+	return b.monthlyLimit > 0 && projected >= 0.9*b.monthlyLimit
+}
+
+// projectedMonthlyLocked extrapolates credit spend since start to a
+// 30-day month.
+func (b *Budget) projectedMonthlyLocked() float64 {
+	elapsed := time.Since(b.started)
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+	const month = 30 * 24 * time.Hour
+	return b.credits * float64(month) / float64(elapsed)
+}
@@ -0,0 +1,397 @@
+// Package solana implements a minimal JSON-RPC client for the subset of the
+// Solana RPC API the indexer needs. It depends only on the standard library.
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Client is a Solana JSON-RPC client.
+type Client struct {
+	url        string
+	commitment string
+	httpc      *http.Client
+	nextID     atomic.Uint64
+	budget     *Budget
+	provider   Provider
+	scorer     *Scorer
+	archival   *Client
+	// archivalFloor is the highest slot the standard endpoints reported
+	// purged; slots at or below it go straight to the archival endpoint.
+	archivalFloor atomic.Uint64
+}
+
+// SetScorer routes the client's calls through a multi-endpoint health
+// scorer instead of the single construction URL.
+func (c *Client) SetScorer(s *Scorer) { c.scorer = s }
+
+// SetBudget attaches a Budget that records every RPC call the client makes.
+func (c *Client) SetBudget(b *Budget) { c.budget = b }
+
+// SetArchival attaches an archival RPC endpoint. GetBlock and
+// GetTransaction calls failing with ErrBlockPurged retry there, and
+// slots at or below the observed retention frontier route there
+// directly, so backfills older than the standard provider's retention
+// work without hand-routing. Call after SetBudget so archival calls
+// count against the same budget.
+func (c *Client) SetArchival(url string) {
+	archival := NewClient(url)
+	archival.commitment = c.commitment
+	archival.budget = c.budget
+	c.archival = archival
+}
+
+// Budget returns the attached Budget, or nil if none was set.
+func (c *Client) Budget() *Budget { return c.budget }
+
+// NewClient creates a client for the RPC endpoint at url, e.g.
+// "http://localhost:8899".
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		commitment: "confirmed",
+		httpc:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// RPCError is a JSON-RPC error returned by the node.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// GetSlot returns the current slot at the client's commitment level.
+func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
+	var slot uint64
+	params := []any{map[string]string{"commitment": c.commitment}}
+	if err := c.Call(ctx, "getSlot", params, &slot); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
+
+// GetBlock fetches a confirmed block with full transaction details using
+// the json encoding. Some providers reject it on very large blocks, so
+// on failure the call falls back to the base64 encoding and decodes the
+// wire-format transactions locally, retrying any transaction that still
+// will not decode individually via getTransaction. Transactions that
+// survive every fallback are listed in Block.FailedTransactions.
+func (c *Client) GetBlock(ctx context.Context, slot uint64) (*Block, error) {
+	if c.archival != nil && slot <= c.archivalFloor.Load() {
+		return c.archival.GetBlock(ctx, slot)
+	}
+	block, err := c.getBlock(ctx, slot)
+	if err != nil && c.archival != nil && errors.Is(err, ErrBlockPurged) {
+		c.raiseArchivalFloor(slot)
+		return c.archival.GetBlock(ctx, slot)
+	}
+	return block, err
+}
+
+func (c *Client) getBlock(ctx context.Context, slot uint64) (*Block, error) {
+	block, err := c.getBlockJSON(ctx, slot)
+	if err == nil || errors.Is(err, ErrSlotSkipped) || errors.Is(err, ErrBlockPurged) ||
+		errors.Is(err, ErrBlockNotAvailable) || errors.Is(err, ErrRateLimited) || ctx.Err() != nil {
+		return block, err
+	}
+	fallback, fbErr := c.getBlockBase64(ctx, slot)
+	if fbErr != nil {
+		// Report the original failure; the fallback failing too adds
+		// nothing actionable.
+		return nil, err
+	}
+	return fallback, nil
+}
+
+// raiseArchivalFloor records that the standard endpoints have purged
+// slot, so later reads at or below it skip the doomed attempt.
+func (c *Client) raiseArchivalFloor(slot uint64) {
+	for {
+		cur := c.archivalFloor.Load()
+		if slot <= cur || c.archivalFloor.CompareAndSwap(cur, slot) {
+			return
+		}
+	}
+}
+
+func (c *Client) getBlockJSON(ctx context.Context, slot uint64) (*Block, error) {
+	params := []any{slot, map[string]any{
+		"encoding":                       "json",
+		"transactionDetails":             "full",
+		"maxSupportedTransactionVersion": 0,
+		"rewards":                        false,
+		"commitment":                     c.commitment,
+	}}
+	var block Block
+	if err := c.Call(ctx, "getBlock", params, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// getBlockBase64 refetches a block with the base64 encoding and decodes
+// each wire-format transaction locally.
+func (c *Client) getBlockBase64(ctx context.Context, slot uint64) (*Block, error) {
+	params := []any{slot, map[string]any{
+		"encoding":                       "base64",
+		"transactionDetails":             "full",
+		"maxSupportedTransactionVersion": 0,
+		"rewards":                        false,
+		"commitment":                     c.commitment,
+	}}
+	var raw struct {
+		Blockhash         string  `json:"blockhash"`
+		PreviousBlockhash string  `json:"previousBlockhash"`
+		ParentSlot        uint64  `json:"parentSlot"`
+		BlockTime         *int64  `json:"blockTime"`
+		BlockHeight       *uint64 `json:"blockHeight"`
+		Transactions      []struct {
+			Transaction []string `json:"transaction"` // [payload, encoding]
+			Meta        *Meta    `json:"meta"`
+		} `json:"transactions"`
+	}
+	if err := c.Call(ctx, "getBlock", params, &raw); err != nil {
+		return nil, err
+	}
+	block := &Block{
+		Blockhash:         raw.Blockhash,
+		PreviousBlockhash: raw.PreviousBlockhash,
+		ParentSlot:        raw.ParentSlot,
+		BlockTime:         raw.BlockTime,
+		BlockHeight:       raw.BlockHeight,
+	}
+	for _, entry := range raw.Transactions {
+		if len(entry.Transaction) == 0 {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(entry.Transaction[0])
+		if err != nil {
+			continue
+		}
+		tx, err := ParseWireTransaction(data)
+		if err == nil {
+			block.Transactions = append(block.Transactions, TransactionWithMeta{Transaction: *tx, Meta: entry.Meta})
+			continue
+		}
+		// The wire format did not decode (typically a versioned message);
+		// retry this one transaction through the json encoding.
+		sig, ok := WireSignature(data)
+		if !ok {
+			continue
+		}
+		refetched, err := c.GetTransaction(ctx, sig)
+		if err != nil {
+			block.FailedTransactions = append(block.FailedTransactions, sig)
+			continue
+		}
+		refetched.Meta = entry.Meta
+		block.Transactions = append(block.Transactions, *refetched)
+	}
+	return block, nil
+}
+
+// GetTransaction fetches one confirmed transaction by signature with the
+// json encoding.
+func (c *Client) GetTransaction(ctx context.Context, signature string) (*TransactionWithMeta, error) {
+	tx, err := c.getTransaction(ctx, signature)
+	if err != nil && c.archival != nil && errors.Is(err, ErrBlockPurged) {
+		return c.archival.GetTransaction(ctx, signature)
+	}
+	return tx, err
+}
+
+func (c *Client) getTransaction(ctx context.Context, signature string) (*TransactionWithMeta, error) {
+	params := []any{signature, map[string]any{
+		"encoding":                       "json",
+		"maxSupportedTransactionVersion": 0,
+		"commitment":                     c.commitment,
+	}}
+	var result struct {
+		Transaction Transaction `json:"transaction"`
+		Meta        *Meta       `json:"meta"`
+	}
+	if err := c.Call(ctx, "getTransaction", params, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Transaction.Signatures) == 0 {
+		return nil, fmt.Errorf("getTransaction: %s not found", signature)
+	}
+	return &TransactionWithMeta{Transaction: result.Transaction, Meta: result.Meta}, nil
+}
+
+// ProgramAccount is one account owned by a program, as returned by
+// GetProgramAccounts. Data is the raw account data.
+type ProgramAccount struct {
+	Address  string
+	Owner    string
+	Lamports uint64
+	Data     []byte
+}
+
+// GetProgramAccounts returns every account owned by the program together
+// with the slot the snapshot is consistent at.
+func (c *Client) GetProgramAccounts(ctx context.Context, programID string) ([]ProgramAccount, uint64, error) {
+	params := []any{programID, map[string]any{
+		"encoding":    "base64",
+		"commitment":  c.commitment,
+		"withContext": true,
+	}}
+	var result struct {
+		Context struct {
+			Slot uint64 `json:"slot"`
+		} `json:"context"`
+		Value []struct {
+			Pubkey  string `json:"pubkey"`
+			Account struct {
+				Lamports uint64   `json:"lamports"`
+				Owner    string   `json:"owner"`
+				Data     []string `json:"data"` // [payload, encoding]
+			} `json:"account"`
+		} `json:"value"`
+	}
+	if err := c.Call(ctx, "getProgramAccounts", params, &result); err != nil {
+		return nil, 0, err
+	}
+	accounts := make([]ProgramAccount, 0, len(result.Value))
+	for _, entry := range result.Value {
+		acct := ProgramAccount{
+			Address:  entry.Pubkey,
+			Owner:    entry.Account.Owner,
+			Lamports: entry.Account.Lamports,
+		}
+		if len(entry.Account.Data) > 0 {
+			data, err := base64.StdEncoding.DecodeString(entry.Account.Data[0])
+			if err != nil {
+				return nil, 0, fmt.Errorf("account %s: decode data: %w", entry.Pubkey, err)
+			}
+			acct.Data = data
+		}
+		accounts = append(accounts, acct)
+	}
+	return accounts, result.Context.Slot, nil
+}
+
+// RequestAirdrop requests lamports for the base58 address and returns the
+// airdrop transaction signature. Only supported on test clusters.
+func (c *Client) RequestAirdrop(ctx context.Context, address string, lamports uint64) (string, error) {
+	var sig string
+	if err := c.Call(ctx, "requestAirdrop", []any{address, lamports}, &sig); err != nil {
+		return "", err
+	}
+	return sig, nil
+}
+
+// GetLatestBlockhash returns a recent blockhash for building transactions.
+func (c *Client) GetLatestBlockhash(ctx context.Context) (string, error) {
+	var result struct {
+		Value struct {
+			Blockhash string `json:"blockhash"`
+		} `json:"value"`
+	}
+	params := []any{map[string]string{"commitment": c.commitment}}
+	if err := c.Call(ctx, "getLatestBlockhash", params, &result); err != nil {
+		return "", err
+	}
+	return result.Value.Blockhash, nil
+}
+
+// SendTransaction submits a signed wire-format transaction and returns its
+// signature.
+func (c *Client) SendTransaction(ctx context.Context, tx []byte) (string, error) {
+	var sig string
+	params := []any{base64.StdEncoding.EncodeToString(tx), map[string]string{"encoding": "base64"}}
+	if err := c.Call(ctx, "sendTransaction", params, &sig); err != nil {
+		return "", err
+	}
+	return sig, nil
+}
+
+// GetSignatureStatus returns the confirmation status of a signature, or ""
+// if the cluster does not know it yet.
+func (c *Client) GetSignatureStatus(ctx context.Context, signature string) (string, error) {
+	var result struct {
+		Value []*struct {
+			ConfirmationStatus string          `json:"confirmationStatus"`
+			Err                json.RawMessage `json:"err"`
+		} `json:"value"`
+	}
+	params := []any{[]string{signature}, map[string]bool{"searchTransactionHistory": true}}
+	if err := c.Call(ctx, "getSignatureStatuses", params, &result); err != nil {
+		return "", err
+	}
+	if len(result.Value) == 0 || result.Value[0] == nil {
+		return "", nil
+	}
+	if len(result.Value[0].Err) > 0 && string(result.Value[0].Err) != "null" {
+		return "", fmt.Errorf("transaction %s failed: %s", signature, result.Value[0].Err)
+	}
+	return result.Value[0].ConfirmationStatus, nil
+}
+
+// Call performs a raw JSON-RPC call and unmarshals the result into out.
+func (c *Client) Call(ctx context.Context, method string, params []any, out any) error {
+	if c.budget != nil {
+		c.budget.Record(method)
+	}
+	reqBody, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      c.nextID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	url := c.url
+	if c.scorer != nil {
+		url = c.scorer.Pick()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	resp, err := c.httpc.Do(req)
+	if c.scorer != nil {
+		c.scorer.Observe(url, time.Since(start), err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%s: status 429: %w", method, ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", method, resp.StatusCode)
+	}
+	var wire struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wire); err != nil {
+		return fmt.Errorf("%s: decode response: %w", method, err)
+	}
+	if wire.Error != nil {
+		return fmt.Errorf("%s: %w", method, wire.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(wire.Result, out); err != nil {
+			return fmt.Errorf("%s: decode result: %w", method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package solana
+
+import "errors"
+
+// Error classes the indexer branches on for retry, skip, and alerting
+// decisions. RPC errors returned by Client wrap one of these sentinels, so
+// callers use errors.Is to classify and errors.As to reach the raw
+// *RPCError when they need the code or message.
+var (
+	// ErrBlockNotAvailable means the node does not (yet) have the block;
+	// the slot should be retried, possibly against an archival node.
+	ErrBlockNotAvailable = errors.New("solana: block not available")
+
+	// ErrSlotSkipped means the slot produced no block and never will;
+	// the indexer skips it permanently.
+	ErrSlotSkipped = errors.New("solana: slot skipped")
+
+	// ErrBlockPurged means the block exists but has aged out of this
+	// node's retention; only an archival node can still serve it. With
+	// SetArchival configured the client retries there transparently.
+	ErrBlockPurged = errors.New("solana: block purged from node retention")
+
+	// ErrRateLimited means the provider rejected the request for rate or
+	// quota reasons; the caller should back off before retrying.
+	ErrRateLimited = errors.New("solana: rate limited")
+
+	// ErrReorgDetected means a previously indexed block is no longer on
+	// the canonical chain and its slot must be re-indexed.
+	ErrReorgDetected = errors.New("solana: reorg detected")
+)
+
+// Unwrap maps well-known RPC error codes onto the error classes above, so
+// errors.Is(err, ErrSlotSkipped) works on wrapped *RPCError values.
+func (e *RPCError) Unwrap() error {
+	switch e.Code {
+	case -32004:
+		// Block not available for slot.
+		return ErrBlockNotAvailable
+	case -32007:
+		// Slot was skipped or missing in ledger: no block was produced.
+		return ErrSlotSkipped
+	case -32009, -32011:
+		// Slot or transaction history missing in long-term storage: the
+		// block existed but this node no longer retains it.
+		return ErrBlockPurged
+	case 429:
+		return ErrRateLimited
+	}
+	return nil
+}
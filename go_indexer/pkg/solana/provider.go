@@ -0,0 +1,201 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupported is returned by provider methods the configured RPC
+// vendor does not implement. Callers treat it as "feature absent", not
+// as a failure.
+var ErrUnsupported = errors.New("solana: not supported by this rpc provider")
+
+// ParsedTransaction is a vendor-decoded transaction summary from an
+// enhanced API (e.g. the Helius parsed-transaction API). It complements,
+// rather than replaces, the indexer's own decoders.
+type ParsedTransaction struct {
+	Signature   string `json:"signature"`
+	Type        string `json:"type"`
+	Source      string `json:"source,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Provider exposes the optional enhanced APIs some RPC vendors layer on
+// top of the standard JSON-RPC surface. Methods return ErrUnsupported
+// when the vendor has no equivalent, so callers can fall back silently.
+type Provider interface {
+	// Name identifies the vendor: helius, triton, or quicknode.
+	Name() string
+	// ParseTransaction returns the vendor's decoded summary of a
+	// signature, used to improve decode coverage for programs the
+	// built-in decoders do not know.
+	ParseTransaction(ctx context.Context, signature string) (*ParsedTransaction, error)
+	// PriorityFeeEstimate returns a recommended priority fee in
+	// micro-lamports per compute unit for transactions touching the
+	// given accounts.
+	PriorityFeeEstimate(ctx context.Context, accounts []string) (uint64, error)
+}
+
+// SetProvider attaches a vendor adapter to the client.
+func (c *Client) SetProvider(p Provider) { c.provider = p }
+
+// Provider returns the attached vendor adapter, or nil for generic RPC.
+func (c *Client) Provider() Provider { return c.provider }
+
+// NewProvider creates the adapter for the named vendor over the client's
+// RPC endpoint, or nil for "generic" and unknown names.
+func NewProvider(name string, c *Client) Provider {
+	switch name {
+	case "helius":
+		return &heliusProvider{c: c}
+	case "triton":
+		return &tritonProvider{c: c}
+	case "quicknode":
+		return &quickNodeProvider{c: c}
+	default:
+		return nil
+	}
+}
+
+// heliusProvider uses the Helius getPriorityFeeEstimate RPC method and
+// the v0 parsed-transaction REST API. The REST endpoint is derived from
+// the api-key query parameter of the configured RPC URL.
+type heliusProvider struct {
+	c *Client
+}
+
+func (p *heliusProvider) Name() string { return "helius" }
+
+func (p *heliusProvider) ParseTransaction(ctx context.Context, signature string) (*ParsedTransaction, error) {
+	u, err := url.Parse(p.c.url)
+	if err != nil {
+		return nil, fmt.Errorf("parse rpc url: %w", err)
+	}
+	apiKey := u.Query().Get("api-key")
+	if apiKey == "" {
+		return nil, ErrUnsupported
+	}
+	endpoint := "https://api.helius.xyz/v0/transactions?api-key=" + url.QueryEscape(apiKey)
+	body, err := json.Marshal(map[string]any{"transactions": []string{signature}})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.c.httpc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("helius parse: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("helius parse: unexpected status %d", resp.StatusCode)
+	}
+	var parsed []ParsedTransaction
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("helius parse: decode response: %w", err)
+	}
+	if len(parsed) == 0 {
+		return nil, ErrUnsupported
+	}
+	return &parsed[0], nil
+}
+
+func (p *heliusProvider) PriorityFeeEstimate(ctx context.Context, accounts []string) (uint64, error) {
+	var result struct {
+		PriorityFeeEstimate float64 `json:"priorityFeeEstimate"`
+	}
+	params := []any{map[string]any{"accountKeys": accounts}}
+	if err := p.c.Call(ctx, "getPriorityFeeEstimate", params, &result); err != nil {
+		return 0, err
+	}
+	return uint64(result.PriorityFeeEstimate), nil
+}
+
+// tritonProvider has no parsed-transaction API; priority fees come from
+// the standard getRecentPrioritizationFees method Triton serves with
+// per-account filtering.
+type tritonProvider struct {
+	c *Client
+}
+
+func (p *tritonProvider) Name() string { return "triton" }
+
+func (p *tritonProvider) ParseTransaction(ctx context.Context, signature string) (*ParsedTransaction, error) {
+	return nil, ErrUnsupported
+}
+
+func (p *tritonProvider) PriorityFeeEstimate(ctx context.Context, accounts []string) (uint64, error) {
+	return recentPrioritizationFee(ctx, p.c, accounts)
+}
+
+// quickNodeProvider uses the qn_estimatePriorityFees add-on when it is
+// enabled on the endpoint, falling back to the standard method.
+type quickNodeProvider struct {
+	c *Client
+}
+
+func (p *quickNodeProvider) Name() string { return "quicknode" }
+
+func (p *quickNodeProvider) ParseTransaction(ctx context.Context, signature string) (*ParsedTransaction, error) {
+	return nil, ErrUnsupported
+}
+
+func (p *quickNodeProvider) PriorityFeeEstimate(ctx context.Context, accounts []string) (uint64, error) {
+	var result struct {
+		PerComputeUnit struct {
+			Medium uint64 `json:"medium"`
+		} `json:"per_compute_unit"`
+	}
+	params := []any{map[string]any{"last_n_blocks": 100}}
+	if err := p.c.Call(ctx, "qn_estimatePriorityFees", params, &result); err == nil {
+		return result.PerComputeUnit.Medium, nil
+	}
+	return recentPrioritizationFee(ctx, p.c, accounts)
+}
+
+// recentPrioritizationFee returns the highest fee seen in the standard
+// getRecentPrioritizationFees response for the given accounts.
+func recentPrioritizationFee(ctx context.Context, c *Client, accounts []string) (uint64, error) {
+	var result []struct {
+		PrioritizationFee uint64 `json:"prioritizationFee"`
+	}
+	if err := c.Call(ctx, "getRecentPrioritizationFees", []any{accounts}, &result); err != nil {
+		return 0, err
+	}
+	var max uint64
+	for _, r := range result {
+		if r.PrioritizationFee > max {
+			max = r.PrioritizationFee
+		}
+	}
+	return max, nil
+}
+
+var (
+	defaultProviderMu sync.RWMutex
+	defaultProvider   Provider
+)
+
+// SetDefaultProvider installs the process-wide adapter the processors
+// consult; see plugin.SetDefault for the same pattern.
+func SetDefaultProvider(p Provider) {
+	defaultProviderMu.Lock()
+	defer defaultProviderMu.Unlock()
+	defaultProvider = p
+}
+
+// DefaultProvider returns the process-wide adapter, or nil.
+func DefaultProvider() Provider {
+	defaultProviderMu.RLock()
+	defer defaultProviderMu.RUnlock()
+	return defaultProvider
+}
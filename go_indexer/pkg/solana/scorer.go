@@ -0,0 +1,247 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// probeInterval is how often the scorer polls every endpoint's slot.
+	probeInterval = 10 * time.Second
+	// skewPenalty is the score cost per slot an endpoint lags the leader.
+	skewPenalty = 2.0
+	// latencyBudgetMS is the response time that costs one score point.
+	latencyBudgetMS = 50.0
+	// errorPenalty is the score cost per percent of failed requests.
+	errorPenalty = 1.0
+)
+
+// EndpointHealth is one endpoint's score snapshot for /debug/rpc.
+type EndpointHealth struct {
+	URL       string  `json:"url"`
+	Score     float64 `json:"score"`
+	Slot      uint64  `json:"slot"`
+	SlotSkew  int64   `json:"slot_skew"`
+	LatencyMS float64 `json:"latency_ms"`
+	Requests  uint64  `json:"requests"`
+	Errors    uint64  `json:"errors"`
+	Active    bool    `json:"active"`
+}
+
+// endpoint tracks one RPC URL's observed slot and latency.
+type endpoint struct {
+	url       string
+	slot      uint64
+	latencyMS float64 // EWMA over observed request latencies
+	requests  uint64
+	errors    uint64
+}
+
+// Scorer continuously compares the configured RPC endpoints' reported
+// slots and response latencies, scores each one, and routes the client's
+// traffic to the healthiest. A provider that lags the cluster or starts
+// timing out loses score and traffic shifts away without a restart.
+type Scorer struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	httpc     *http.Client
+
+	// Cluster-level probe history for halt detection: the highest slot any
+	// endpoint has reported, when it last advanced, and when any endpoint
+	// last answered a probe at all.
+	tip           uint64
+	tipAdvance    time.Time
+	lastReachable time.Time
+}
+
+// NewScorer creates a Scorer over the given RPC URLs.
+func NewScorer(urls []string) *Scorer {
+	s := &Scorer{httpc: &http.Client{Timeout: 10 * time.Second}}
+	for _, u := range urls {
+		s.endpoints = append(s.endpoints, &endpoint{url: u})
+	}
+	return s
+}
+
+// Run probes every endpoint's slot on a fixed interval until ctx is
+// canceled, keeping skew measurements fresh even when traffic is routed
+// elsewhere.
+func (s *Scorer) Run(ctx context.Context) {
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+	s.probe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.probe(ctx)
+		}
+	}
+}
+
+func (s *Scorer) probe(ctx context.Context) {
+	s.mu.Lock()
+	urls := make([]string, len(s.endpoints))
+	for i, ep := range s.endpoints {
+		urls[i] = ep.url
+	}
+	s.mu.Unlock()
+	for _, url := range urls {
+		start := time.Now()
+		slot, err := s.probeSlot(ctx, url)
+		s.Observe(url, time.Since(start), err)
+		if err == nil {
+			s.mu.Lock()
+			s.lastReachable = time.Now()
+			for _, ep := range s.endpoints {
+				if ep.url == url && slot > ep.slot {
+					ep.slot = slot
+				}
+			}
+			if slot > s.tip {
+				s.tip = slot
+				s.tipAdvance = time.Now()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// TipStatus reports the highest slot seen across all endpoints, how long
+// ago it last advanced, and how long ago any endpoint answered a probe.
+// Slots frozen while endpoints still answer indicates a cluster halt;
+// slots frozen because nothing answers indicates an RPC outage.
+func (s *Scorer) TipStatus() (tip uint64, sinceAdvance, sinceReachable time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tipAdvance.IsZero() {
+		return s.tip, 0, 0
+	}
+	return s.tip, time.Since(s.tipAdvance), time.Since(s.lastReachable)
+}
+
+// probeSlot issues a bare getSlot against one endpoint, outside the
+// Client so probes are not themselves routed.
+func (s *Scorer) probeSlot(ctx context.Context, url string) (uint64, error) {
+	body, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "getSlot", "params": []any{},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	var wire struct {
+		Result uint64    `json:"result"`
+		Error  *RPCError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wire); err != nil {
+		return 0, err
+	}
+	if wire.Error != nil {
+		return 0, wire.Error
+	}
+	return wire.Result, nil
+}
+
+// Observe records one request's outcome against an endpoint. The Client
+// calls this after every routed call so scores reflect real traffic, not
+// just probes.
+func (s *Scorer) Observe(url string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ep := range s.endpoints {
+		if ep.url != url {
+			continue
+		}
+		ep.requests++
+		if err != nil {
+			ep.errors++
+		}
+		ms := float64(latency.Milliseconds())
+		if ep.latencyMS == 0 {
+			ep.latencyMS = ms
+		} else {
+			ep.latencyMS = (3*ep.latencyMS + ms) / 4
+		}
+	}
+}
+
+// Pick returns the URL of the highest-scoring endpoint.
+func (s *Scorer) Pick() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	best := s.endpoints[0]
+	bestScore := s.score(best)
+	for _, ep := range s.endpoints[1:] {
+		if sc := s.score(ep); sc > bestScore {
+			best, bestScore = ep, sc
+		}
+	}
+	return best.url
+}
+
+// score computes an endpoint's health out of 100, penalizing slot skew
+// against the leader, latency over budget, and the error rate. Callers
+// hold s.mu.
+func (s *Scorer) score(ep *endpoint) float64 {
+	var leader uint64
+	for _, other := range s.endpoints {
+		if other.slot > leader {
+			leader = other.slot
+		}
+	}
+	score := 100.0
+	score -= float64(leader-ep.slot) * skewPenalty
+	if ep.latencyMS > latencyBudgetMS {
+		score -= (ep.latencyMS - latencyBudgetMS) / latencyBudgetMS
+	}
+	if ep.requests > 0 {
+		score -= float64(ep.errors) / float64(ep.requests) * 100 * errorPenalty
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// Snapshot reports every endpoint's current score for /debug/rpc.
+func (s *Scorer) Snapshot() []EndpointHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var leader uint64
+	active := s.endpoints[0]
+	activeScore := s.score(active)
+	for _, ep := range s.endpoints {
+		if ep.slot > leader {
+			leader = ep.slot
+		}
+		if sc := s.score(ep); sc > activeScore {
+			active, activeScore = ep, sc
+		}
+	}
+	out := make([]EndpointHealth, 0, len(s.endpoints))
+	for _, ep := range s.endpoints {
+		out = append(out, EndpointHealth{
+			URL:       ep.url,
+			Score:     s.score(ep),
+			Slot:      ep.slot,
+			SlotSkew:  int64(leader) - int64(ep.slot),
+			LatencyMS: ep.latencyMS,
+			Requests:  ep.requests,
+			Errors:    ep.errors,
+			Active:    ep == active,
+		})
+	}
+	return out
+}
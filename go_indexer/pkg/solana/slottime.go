@@ -0,0 +1,127 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// getBlocksLimit bounds one getBlocks probe; the RPC caps the range at
+// 500,000 slots, and a probe only needs the first produced slot nearby.
+const getBlocksLimit = 1000
+
+// GetBlockTime returns the estimated production time of a slot.
+func (c *Client) GetBlockTime(ctx context.Context, slot uint64) (time.Time, error) {
+	var unix *int64
+	if err := c.Call(ctx, "getBlockTime", []any{slot}, &unix); err != nil {
+		return time.Time{}, err
+	}
+	if unix == nil {
+		return time.Time{}, fmt.Errorf("getBlockTime: no time for slot %d", slot)
+	}
+	return time.Unix(*unix, 0).UTC(), nil
+}
+
+// GetFirstAvailableBlock returns the lowest slot the node still serves.
+func (c *Client) GetFirstAvailableBlock(ctx context.Context) (uint64, error) {
+	var slot uint64
+	if err := c.Call(ctx, "getFirstAvailableBlock", nil, &slot); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
+
+// GetBlocks returns the slots in [start, end] that produced a block.
+func (c *Client) GetBlocks(ctx context.Context, start, end uint64) ([]uint64, error) {
+	var slots []uint64
+	params := []any{start, end, map[string]string{"commitment": c.commitment}}
+	if err := c.Call(ctx, "getBlocks", params, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// FindSlotByTime resolves the first produced slot whose block time is at
+// or after t, by binary search over the node's available range with
+// getBlockTime probes. Skipped slots are stepped over with getBlocks.
+// It returns an error when t is before the node's history or after its
+// current tip.
+func (c *Client) FindSlotByTime(ctx context.Context, t time.Time) (uint64, error) {
+	lo, err := c.GetFirstAvailableBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	hi, err := c.GetSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if when, err := c.blockTimeNear(ctx, lo, hi); err != nil {
+		return 0, err
+	} else if t.Before(when) {
+		return 0, fmt.Errorf("solana: %s predates the node's history (first block %s)",
+			t.Format(time.RFC3339), when.Format(time.RFC3339))
+	}
+	if when, err := c.blockTimeNear(ctx, hi, hi); err == nil && when.Before(t) {
+		return 0, fmt.Errorf("solana: %s is after the current tip (slot %d at %s)",
+			t.Format(time.RFC3339), hi, when.Format(time.RFC3339))
+	}
+
+	// Invariant: the answer is in (lo, hi]; lo's block is before t.
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		slot, when, err := c.producedSlotNear(ctx, mid, hi)
+		if err != nil {
+			return 0, err
+		}
+		if slot >= hi {
+			// Everything in [mid, hi) was skipped; the answer is hi.
+			break
+		}
+		if when.Before(t) {
+			lo = slot
+		} else {
+			hi = slot
+		}
+	}
+	return hi, nil
+}
+
+// blockTimeNear returns the block time of slot, or of the first produced
+// slot after it up to limit.
+func (c *Client) blockTimeNear(ctx context.Context, slot, limit uint64) (time.Time, error) {
+	_, when, err := c.producedSlotNear(ctx, slot, limit)
+	return when, err
+}
+
+// producedSlotNear returns the first produced slot in [slot, limit] and
+// its block time, probing getBlockTime directly and falling back to
+// getBlocks when slot itself was skipped.
+func (c *Client) producedSlotNear(ctx context.Context, slot, limit uint64) (uint64, time.Time, error) {
+	when, err := c.GetBlockTime(ctx, slot)
+	if err == nil {
+		return slot, when, nil
+	}
+	if !errors.Is(err, ErrSlotSkipped) && !errors.Is(err, ErrBlockNotAvailable) &&
+		!errors.Is(err, ErrBlockPurged) {
+		return 0, time.Time{}, err
+	}
+	for start := slot; start <= limit; start += getBlocksLimit {
+		end := start + getBlocksLimit - 1
+		if end > limit {
+			end = limit
+		}
+		produced, err := c.GetBlocks(ctx, start, end)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		if len(produced) > 0 {
+			when, err := c.GetBlockTime(ctx, produced[0])
+			if err != nil {
+				return 0, time.Time{}, err
+			}
+			return produced[0], when, nil
+		}
+	}
+	return 0, time.Time{}, fmt.Errorf("solana: no block produced in slots %d-%d", slot, limit)
+}
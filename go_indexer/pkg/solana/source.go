@@ -0,0 +1,106 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChainSource is the slice of the RPC surface the ingestion loop reads
+// blocks through. Client implements it directly for Solana clusters;
+// SVM-compatible networks whose RPC diverges in small ways (error
+// codes, slot semantics) are supported by thin adapters selected with
+// NewChainSource, so indexing a new network means implementing or
+// wrapping this one interface.
+type ChainSource interface {
+	// GetSlot returns the network's current slot.
+	GetSlot(ctx context.Context) (uint64, error)
+	// GetBlock fetches one block. Errors must classify as the package
+	// sentinels (ErrSlotSkipped, ErrBlockNotAvailable, ErrRateLimited)
+	// where those conditions apply, whatever the chain's native codes.
+	GetBlock(ctx context.Context, slot uint64) (*Block, error)
+	// Budget returns the RPC budget shared with the rest of the process,
+	// or nil.
+	Budget() *Budget
+}
+
+// NewChainSource returns the source for the named SVM network over the
+// client. "solana" (or empty) is the client itself; "eclipse" and "svm"
+// wrap it with the network's quirks. Unknown names are an error so a
+// typo does not silently index with the wrong semantics.
+func NewChainSource(name string, c *Client) (ChainSource, error) {
+	switch name {
+	case "", "solana":
+		return c, nil
+	case "eclipse":
+		return &quirkSource{c: c, classify: classifyEclipse}, nil
+	case "svm":
+		return &quirkSource{c: c, classify: classifySVM}, nil
+	default:
+		return nil, fmt.Errorf("solana: unknown chain %q", name)
+	}
+}
+
+// quirkSource adapts a network whose RPC matches Solana's except for
+// error reporting: classify reattaches the package sentinels to errors
+// the network returns under non-standard codes.
+type quirkSource struct {
+	c        *Client
+	classify func(error) error
+}
+
+func (s *quirkSource) GetSlot(ctx context.Context) (uint64, error) { return s.c.GetSlot(ctx) }
+func (s *quirkSource) Budget() *Budget                             { return s.c.Budget() }
+
+func (s *quirkSource) GetBlock(ctx context.Context, slot uint64) (*Block, error) {
+	block, err := s.c.GetBlock(ctx, slot)
+	if err != nil {
+		return nil, s.classify(err)
+	}
+	return block, nil
+}
+
+// classifyEclipse maps Eclipse RPC failures onto the package sentinels.
+// Eclipse runs a single sequencer, so slots are never skipped: a block
+// its RPC reports missing — under generic codes mainnet does not use —
+// is one that has not propagated yet and should be retried.
+func classifyEclipse(err error) error {
+	if classified(err) {
+		return err
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && mentionsMissingBlock(rpcErr.Message) {
+		return fmt.Errorf("%w: %w", ErrBlockNotAvailable, err)
+	}
+	return err
+}
+
+// classifySVM maps generic SVM rollup and localnet RPC failures onto
+// the package sentinels. Test validators prune history aggressively and
+// report pruned slots under -32001 (or a bare "not found" message);
+// those slots will never be served, so they classify as skipped.
+func classifySVM(err error) error {
+	if classified(err) {
+		return err
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		if rpcErr.Code == -32001 || mentionsMissingBlock(rpcErr.Message) {
+			return fmt.Errorf("%w: %w", ErrSlotSkipped, err)
+		}
+	}
+	return err
+}
+
+// classified reports whether err already carries one of the package
+// sentinels.
+func classified(err error) bool {
+	return errors.Is(err, ErrSlotSkipped) || errors.Is(err, ErrBlockNotAvailable) ||
+		errors.Is(err, ErrBlockPurged) || errors.Is(err, ErrRateLimited)
+}
+
+func mentionsMissingBlock(message string) bool {
+	m := strings.ToLower(message)
+	return strings.Contains(m, "not found") || strings.Contains(m, "not available")
+}
@@ -0,0 +1,97 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+)
+
+// Keypair is an ed25519 signing key with its base58 public key.
+type Keypair struct {
+	priv ed25519.PrivateKey
+}
+
+// NewKeypair generates a random keypair.
+func NewKeypair() (*Keypair, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Keypair{priv: priv}, nil
+}
+
+// Pubkey returns the base58-encoded public key.
+func (k *Keypair) Pubkey() string {
+	return Base58Encode(k.priv.Public().(ed25519.PublicKey))
+}
+
+// Sign signs msg and returns the raw 64-byte signature.
+func (k *Keypair) Sign(msg []byte) []byte {
+	return ed25519.Sign(k.priv, msg)
+}
+
+// BuildTransferTransaction builds and signs a legacy transaction containing
+// a single system program transfer of lamports from the keypair to the
+// base58 address to. It returns the wire-format transaction bytes.
+func BuildTransferTransaction(from *Keypair, to string, lamports uint64, recentBlockhash string) ([]byte, error) {
+	fromKey, err := Base58Decode(from.Pubkey())
+	if err != nil {
+		return nil, err
+	}
+	toKey, err := Base58Decode(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	programKey, err := Base58Decode(SystemProgramIDStr)
+	if err != nil {
+		return nil, err
+	}
+	blockhash, err := Base58Decode(recentBlockhash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blockhash: %w", err)
+	}
+
+	// System program Transfer: u32 instruction index (2), u64 lamports.
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[:4], 2)
+	binary.LittleEndian.PutUint64(data[4:], lamports)
+
+	// Legacy message: header, account keys, blockhash, instructions.
+	var msg []byte
+	msg = append(msg, 1, 0, 1) // 1 signer, 0 readonly signed, 1 readonly unsigned
+	msg = appendCompactU16(msg, 3)
+	msg = append(msg, fromKey...)
+	msg = append(msg, toKey...)
+	msg = append(msg, programKey...)
+	msg = append(msg, blockhash...)
+	msg = appendCompactU16(msg, 1)
+	msg = append(msg, 2) // program ID index
+	msg = appendCompactU16(msg, 2)
+	msg = append(msg, 0, 1) // account indexes: from, to
+	msg = appendCompactU16(msg, uint16(len(data)))
+	msg = append(msg, data...)
+
+	signature := from.Sign(msg)
+	var tx []byte
+	tx = appendCompactU16(tx, 1)
+	tx = append(tx, signature...)
+	tx = append(tx, msg...)
+	return tx, nil
+}
+
+// SystemProgramIDStr is the system program address used when building
+// transactions.
+const SystemProgramIDStr = "11111111111111111111111111111111"
+
+// appendCompactU16 appends v in the compact-u16 encoding used by legacy
+// transaction serialization.
+func appendCompactU16(b []byte, v uint16) []byte {
+	for {
+		if v < 0x80 {
+			return append(b, byte(v))
+		}
+		b = append(b, byte(v&0x7f)|0x80)
+		v >>= 7
+	}
+}
@@ -0,0 +1,132 @@
+package solana
+
+import (
+	"encoding/binary"
+	"encoding/json"
+)
+
+// Block mirrors the result of the getBlock RPC call with "json" encoding.
+type Block struct {
+	Blockhash         string                `json:"blockhash"`
+	PreviousBlockhash string                `json:"previousBlockhash"`
+	ParentSlot        uint64                `json:"parentSlot"`
+	BlockTime         *int64                `json:"blockTime"`
+	BlockHeight       *uint64               `json:"blockHeight"`
+	Transactions      []TransactionWithMeta `json:"transactions"`
+	// FailedTransactions lists signatures the client could not decode
+	// through any encoding fallback; the block is stored marked partial
+	// so the missing transactions can be repaired later.
+	FailedTransactions []string `json:"-"`
+}
+
+// TransactionWithMeta is one transaction in a block together with its meta.
+type TransactionWithMeta struct {
+	Transaction Transaction `json:"transaction"`
+	Meta        *Meta       `json:"meta"`
+}
+
+// Transaction is the parsed ("json" encoding) transaction envelope.
+type Transaction struct {
+	Signatures []string `json:"signatures"`
+	Message    Message  `json:"message"`
+}
+
+// Message is the transaction message.
+type Message struct {
+	AccountKeys     []string      `json:"accountKeys"`
+	RecentBlockhash string        `json:"recentBlockhash"`
+	Instructions    []Instruction `json:"instructions"`
+}
+
+// Instruction is a compiled instruction; Data is base58-encoded.
+type Instruction struct {
+	ProgramIDIndex int    `json:"programIdIndex"`
+	Accounts       []int  `json:"accounts"`
+	Data           string `json:"data"`
+}
+
+// Meta is the transaction status metadata.
+type Meta struct {
+	Err               json.RawMessage    `json:"err"`
+	Fee               uint64             `json:"fee"`
+	PreBalances       []uint64           `json:"preBalances"`
+	PostBalances      []uint64           `json:"postBalances"`
+	PreTokenBalances  []TokenBalance     `json:"preTokenBalances"`
+	PostTokenBalances []TokenBalance     `json:"postTokenBalances"`
+	LogMessages       []string           `json:"logMessages"`
+	InnerInstructions []InnerInstruction `json:"innerInstructions"`
+	// ComputeUnitsConsumed is zero on nodes predating its addition to
+	// transaction meta.
+	ComputeUnitsConsumed uint64 `json:"computeUnitsConsumed"`
+	// LoadedAddresses holds accounts resolved through address lookup
+	// tables. Instruction account indices extend past the static keys
+	// into these, writable first then readonly.
+	LoadedAddresses LoadedAddresses `json:"loadedAddresses"`
+}
+
+// LoadedAddresses lists the lookup-table-resolved accounts of a
+// versioned transaction.
+type LoadedAddresses struct {
+	Writable []string `json:"writable"`
+	Readonly []string `json:"readonly"`
+}
+
+// Succeeded reports whether the transaction executed without error.
+func (m *Meta) Succeeded() bool {
+	return m == nil || len(m.Err) == 0 || string(m.Err) == "null"
+}
+
+// TokenBalance is an SPL token balance entry from transaction meta.
+type TokenBalance struct {
+	AccountIndex  int           `json:"accountIndex"`
+	Mint          string        `json:"mint"`
+	Owner         string        `json:"owner"`
+	UITokenAmount UITokenAmount `json:"uiTokenAmount"`
+}
+
+// UITokenAmount is the raw and decimal-adjusted token amount.
+type UITokenAmount struct {
+	Amount   string `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// InnerInstruction groups CPI instructions by the outer instruction index.
+type InnerInstruction struct {
+	Index        int           `json:"index"`
+	Instructions []Instruction `json:"instructions"`
+}
+
+// DurableNonce reports whether the transaction is a durable-nonce
+// transaction: its first instruction is a system program
+// AdvanceNonceAccount. It returns the nonce account and nonce authority.
+// Such transactions can land long after their blockhash aged out, so
+// replay and ordering assumptions differ for them.
+func (m *Message) DurableNonce() (nonceAccount, authority string, ok bool) {
+	if len(m.Instructions) == 0 {
+		return "", "", false
+	}
+	ix := m.Instructions[0]
+	if m.ProgramID(ix) != SystemProgramIDStr || len(ix.Accounts) < 3 {
+		return "", "", false
+	}
+	data, err := Base58Decode(ix.Data)
+	// AdvanceNonceAccount is system instruction index 4.
+	if err != nil || len(data) < 4 || binary.LittleEndian.Uint32(data[:4]) != 4 {
+		return "", "", false
+	}
+	nonce := ix.Accounts[0]
+	auth := ix.Accounts[2]
+	if nonce >= len(m.AccountKeys) || auth >= len(m.AccountKeys) {
+		return "", "", false
+	}
+	return m.AccountKeys[nonce], m.AccountKeys[auth], true
+}
+
+// ProgramID returns the program ID string for ix, or "" if the index is out
+// of range.
+func (m *Message) ProgramID(ix Instruction) string {
+	if ix.ProgramIDIndex < 0 || ix.ProgramIDIndex >= len(m.AccountKeys) {
+		return ""
+	}
+	return m.AccountKeys[ix.ProgramIDIndex]
+}
@@ -0,0 +1,122 @@
+package solana
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// VoteProgramIDStr is the native vote program address.
+const VoteProgramIDStr = "Vote111111111111111111111111111111111111111"
+
+// VoteInfo summarizes one vote instruction: the vote account casting it
+// and the newest slot it votes on.
+type VoteInfo struct {
+	VoteAccount string
+	VotedSlot   uint64
+}
+
+// VoteInfo extracts the vote cast by a vote program transaction. It
+// decodes the Vote/VoteSwitch, CompactUpdateVoteState and TowerSync
+// instruction layouts; other vote program instructions (authorize,
+// withdraw, ...) return ok=false.
+func (m *Message) VoteInfo() (*VoteInfo, bool) {
+	for _, ix := range m.Instructions {
+		if m.ProgramID(ix) != VoteProgramIDStr || len(ix.Accounts) == 0 {
+			continue
+		}
+		acct := ix.Accounts[0]
+		if acct < 0 || acct >= len(m.AccountKeys) {
+			continue
+		}
+		data, err := Base58Decode(ix.Data)
+		if err != nil || len(data) < 4 {
+			continue
+		}
+		slot, ok := votedSlot(data)
+		if !ok {
+			continue
+		}
+		return &VoteInfo{VoteAccount: m.AccountKeys[acct], VotedSlot: slot}, true
+	}
+	return nil, false
+}
+
+// votedSlot returns the newest slot voted on by the bincode-encoded vote
+// instruction data.
+func votedSlot(data []byte) (uint64, bool) {
+	kind := binary.LittleEndian.Uint32(data[:4])
+	rest := data[4:]
+	switch kind {
+	case 2, 6:
+		// Vote / VoteSwitch: Vote { slots: Vec<u64>, hash, timestamp }.
+		// Slots are ascending, so the last entry is the newest.
+		if len(rest) < 8 {
+			return 0, false
+		}
+		n := binary.LittleEndian.Uint64(rest[:8])
+		rest = rest[8:]
+		if n == 0 || uint64(len(rest)) < n*8 {
+			return 0, false
+		}
+		return binary.LittleEndian.Uint64(rest[(n-1)*8:]), true
+	case 12, 13, 14, 15:
+		// CompactUpdateVoteState[Switch] / TowerSync[Switch]: a u64 root
+		// (MaxUint64 for none) followed by a compact-u16 list of
+		// (varint slot offset, u8 confirmation count) lockouts whose
+		// offsets are cumulative from the root.
+		if len(rest) < 8 {
+			return 0, false
+		}
+		slot := binary.LittleEndian.Uint64(rest[:8])
+		if slot == math.MaxUint64 {
+			slot = 0
+		}
+		rest = rest[8:]
+		n, rest, ok := readCompactU16(rest)
+		if !ok || n == 0 {
+			return 0, false
+		}
+		for i := 0; i < n; i++ {
+			var offset uint64
+			offset, rest, ok = readVarintU64(rest)
+			if !ok || len(rest) < 1 {
+				return 0, false
+			}
+			rest = rest[1:] // confirmation count
+			slot += offset
+		}
+		return slot, true
+	}
+	return 0, false
+}
+
+// readCompactU16 decodes the compact-u16 prefix appendCompactU16 writes.
+func readCompactU16(b []byte) (int, []byte, bool) {
+	var v uint
+	for i := 0; i < 3; i++ {
+		if i >= len(b) {
+			return 0, nil, false
+		}
+		v |= uint(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return int(v), b[i+1:], true
+		}
+	}
+	return 0, nil, false
+}
+
+// readVarintU64 decodes the serde_varint encoding used by the compact
+// vote state layouts.
+func readVarintU64(b []byte) (uint64, []byte, bool) {
+	var v uint64
+	for i := 0; i < 10; i++ {
+		if i >= len(b) {
+			return 0, nil, false
+		}
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, b[i+1:], true
+		}
+	}
+	return 0, nil, false
+}
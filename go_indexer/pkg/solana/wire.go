@@ -0,0 +1,117 @@
+package solana
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionedTransaction is returned by ParseWireTransaction for v0 and
+// later messages, whose account keys cannot be resolved without the
+// referenced address lookup tables. Callers refetch such transactions
+// individually with the json encoding instead.
+var ErrVersionedTransaction = errors.New("solana: versioned transaction")
+
+// wireSignatureLen is the length of one ed25519 signature on the wire.
+const wireSignatureLen = 64
+
+// ParseWireTransaction decodes a wire-format (base64 encoding)
+// transaction into the json-encoding Transaction shape, used as the
+// fallback when a provider rejects getBlock with parsed encodings. Only
+// legacy messages are decoded; see ErrVersionedTransaction.
+func ParseWireTransaction(data []byte) (*Transaction, error) {
+	sigs, rest, err := wireSignatures(data)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := parseWireMessage(rest)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{Signatures: sigs, Message: *msg}, nil
+}
+
+// WireSignature extracts just the first signature from a wire-format
+// transaction, so a transaction whose message cannot be decoded can
+// still be identified and refetched.
+func WireSignature(data []byte) (string, bool) {
+	sigs, _, err := wireSignatures(data)
+	if err != nil || len(sigs) == 0 {
+		return "", false
+	}
+	return sigs[0], true
+}
+
+func wireSignatures(data []byte) ([]string, []byte, error) {
+	n, rest, ok := readCompactU16(data)
+	if !ok || len(rest) < n*wireSignatureLen {
+		return nil, nil, fmt.Errorf("wire transaction: truncated signatures")
+	}
+	sigs := make([]string, n)
+	for i := range sigs {
+		sigs[i] = Base58Encode(rest[:wireSignatureLen])
+		rest = rest[wireSignatureLen:]
+	}
+	return sigs, rest, nil
+}
+
+func parseWireMessage(data []byte) (*Message, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("wire transaction: empty message")
+	}
+	if data[0]&0x80 != 0 {
+		return nil, ErrVersionedTransaction
+	}
+	// Legacy header: required signatures, readonly signed, readonly
+	// unsigned. Only the account list and instructions matter here.
+	if len(data) < 3 {
+		return nil, fmt.Errorf("wire transaction: truncated header")
+	}
+	data = data[3:]
+
+	n, data, ok := readCompactU16(data)
+	if !ok || len(data) < n*32 {
+		return nil, fmt.Errorf("wire transaction: truncated account keys")
+	}
+	msg := &Message{AccountKeys: make([]string, n)}
+	for i := range msg.AccountKeys {
+		msg.AccountKeys[i] = Base58Encode(data[:32])
+		data = data[32:]
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("wire transaction: truncated blockhash")
+	}
+	msg.RecentBlockhash = Base58Encode(data[:32])
+	data = data[32:]
+
+	n, data, ok = readCompactU16(data)
+	if !ok {
+		return nil, fmt.Errorf("wire transaction: truncated instruction count")
+	}
+	msg.Instructions = make([]Instruction, 0, n)
+	for i := 0; i < n; i++ {
+		if len(data) < 1 {
+			return nil, fmt.Errorf("wire transaction: truncated instruction %d", i)
+		}
+		ix := Instruction{ProgramIDIndex: int(data[0])}
+		data = data[1:]
+		var accounts int
+		accounts, data, ok = readCompactU16(data)
+		if !ok || len(data) < accounts {
+			return nil, fmt.Errorf("wire transaction: truncated instruction %d accounts", i)
+		}
+		ix.Accounts = make([]int, accounts)
+		for j := 0; j < accounts; j++ {
+			ix.Accounts[j] = int(data[j])
+		}
+		data = data[accounts:]
+		var dataLen int
+		dataLen, data, ok = readCompactU16(data)
+		if !ok || len(data) < dataLen {
+			return nil, fmt.Errorf("wire transaction: truncated instruction %d data", i)
+		}
+		ix.Data = Base58Encode(data[:dataLen])
+		data = data[dataLen:]
+		msg.Instructions = append(msg.Instructions, ix)
+	}
+	return msg, nil
+}